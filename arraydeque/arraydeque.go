@@ -4,7 +4,11 @@ package arraydeque
 import (
 	"fmt"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/cursor"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
+	"slices"
 	"strings"
 )
 
@@ -26,16 +30,40 @@ var _ collections.MutableDeque[int] = (*ArrayDeque[int])(nil)
 
 // ArrayDeque represents a deque of elements of type T backed by an array.
 // The zero value for ArrayDeque is an empty deque ready to use.
+//
+// An ArrayDeque created with WithMaxCapacity is a bounded ring buffer: it
+// never grows its backing array beyond the maximum capacity, so once the
+// buffer has filled, adding and removing elements allocates nothing.
 type ArrayDeque[T any] struct {
-	slice []T
-	front int
-	back  int
-	size  int
+	slice       []T
+	front       int
+	back        int
+	size        int
+	modCount    int
+	maxCapacity int
+	policy      Policy
 }
 
+// Policy determines what a bounded ArrayDeque does when an element is added
+// while it is full.
+type Policy int
+
+const (
+	// OverwriteOldest discards the element at the opposite end of the deque
+	// to make room for the new one. For a deque used as a queue, adding to
+	// the back discards the oldest element at the front.
+	OverwriteOldest Policy = iota
+	// RejectNew keeps the deque as it is. Offer and OfferFront return false,
+	// and the Add methods panic.
+	RejectNew
+)
+
 // config holds the values for configuring an ArrayDeque.
 type config struct {
-	capacity int
+	capacity    int
+	bounded     bool
+	maxCapacity int
+	policy      Policy
 }
 
 // Option configures an ArrayDeque config
@@ -48,14 +76,70 @@ func WithCapacity(capacity int) Option {
 	}
 }
 
+// WithMaxCapacity bounds the ArrayDeque to at most maxCapacity elements, and
+// configures what happens when an element is added while it is full. The
+// initial capacity is reduced to maxCapacity if it is larger.
+func WithMaxCapacity(maxCapacity int, policy Policy) Option {
+	return func(c *config) {
+		c.bounded = true
+		c.maxCapacity = maxCapacity
+		c.policy = policy
+	}
+}
+
 // New creates an empty ArrayDeque whose initial size is 0.
 func New[T any](opts ...Option) *ArrayDeque[T] {
 	config := defaultConfig()
 	for _, option := range opts {
 		option(config)
 	}
+	return newDeque[T](config)
+}
+
+func newDeque[T any](config *config) *ArrayDeque[T] {
+	capacity := config.capacity
+	if config.bounded {
+		if config.maxCapacity < 1 {
+			panic("max capacity must be at least 1")
+		}
+		capacity = min(capacity, config.maxCapacity)
+	}
+	return &ArrayDeque[T]{
+		slice:       make([]T, capacity),
+		maxCapacity: config.maxCapacity,
+		policy:      config.policy,
+	}
+}
+
+// FromSeq creates an ArrayDeque containing the elements of the given sequence,
+// from front to back in the order they are produced. The configured capacity is
+// used as a size hint for the backing array. If the deque is bounded, each
+// element is added as by Offer, so a sequence longer than the maximum capacity
+// leaves only its last elements under OverwriteOldest and only its first under
+// RejectNew.
+func FromSeq[T any](sequence iter.Seq[T], opts ...Option) *ArrayDeque[T] {
+	config := defaultConfig()
+	for _, option := range opts {
+		option(config)
+	}
+	if config.bounded {
+		d := newDeque[T](config)
+		for t := range sequence {
+			d.Offer(t)
+		}
+		return d
+	}
+	s := slices.AppendSeq(make([]T, 0, config.capacity), sequence)
+	size := len(s)
+	s = s[:cap(s)]
+	back := size
+	if back == len(s) {
+		back = 0
+	}
 	return &ArrayDeque[T]{
-		slice: make([]T, config.capacity),
+		slice: s,
+		back:  back,
+		size:  size,
 	}
 }
 
@@ -93,9 +177,14 @@ func (d *ArrayDeque[T]) PeekFront() T {
 	return d.slice[d.front]
 }
 
-// AddFront adds the given element to the front of this deque.
+// AddFront adds the given element to the front of this deque. If the deque
+// is full, AddFront discards the element at the back under OverwriteOldest
+// and panics under RejectNew.
 func (d *ArrayDeque[T]) AddFront(t T) {
-	if d.size == len(d.slice) {
+	if d.Full() {
+		d.mustOverwrite()
+		d.RemoveBack()
+	} else if d.size == len(d.slice) {
 		d.resize()
 	}
 	d.front--
@@ -104,6 +193,7 @@ func (d *ArrayDeque[T]) AddFront(t T) {
 	}
 	d.slice[d.front] = t
 	d.size++
+	d.modCount++
 }
 
 // RemoveFront removes the given element at the front of this deque
@@ -120,6 +210,7 @@ func (d *ArrayDeque[T]) RemoveFront() T {
 		d.front = 0
 	}
 	d.size--
+	d.modCount++
 	return t
 }
 
@@ -136,9 +227,14 @@ func (d *ArrayDeque[T]) PeekBack() T {
 	return d.slice[i]
 }
 
-// AddBack adds the given element to the back of this deque.
+// AddBack adds the given element to the back of this deque. If the deque is
+// full, AddBack discards the element at the front under OverwriteOldest and
+// panics under RejectNew.
 func (d *ArrayDeque[T]) AddBack(t T) {
-	if d.size == len(d.slice) {
+	if d.Full() {
+		d.mustOverwrite()
+		d.RemoveFront()
+	} else if d.size == len(d.slice) {
 		d.resize()
 	}
 	d.slice[d.back] = t
@@ -147,6 +243,7 @@ func (d *ArrayDeque[T]) AddBack(t T) {
 		d.back = 0
 	}
 	d.size++
+	d.modCount++
 }
 
 // RemoveBack removes the given element at the back of this deque
@@ -163,9 +260,50 @@ func (d *ArrayDeque[T]) RemoveBack() T {
 	t := d.slice[d.back]
 	d.slice[d.back] = zero
 	d.size--
+	d.modCount++
 	return t
 }
 
+// Offer adds the given element to the back of this deque as AddBack does, and
+// reports whether it was added. It returns false only when the deque is full
+// and its policy is RejectNew.
+func (d *ArrayDeque[T]) Offer(t T) bool {
+	if d.Full() && d.policy == RejectNew {
+		return false
+	}
+	d.AddBack(t)
+	return true
+}
+
+// OfferFront adds the given element to the front of this deque as AddFront
+// does, and reports whether it was added. It returns false only when the deque
+// is full and its policy is RejectNew.
+func (d *ArrayDeque[T]) OfferFront(t T) bool {
+	if d.Full() && d.policy == RejectNew {
+		return false
+	}
+	d.AddFront(t)
+	return true
+}
+
+// Full returns true if this deque is bounded and holds its maximum number of
+// elements. An unbounded deque is never full.
+func (d *ArrayDeque[T]) Full() bool {
+	return d.maxCapacity > 0 && d.size == d.maxCapacity
+}
+
+// MaxCapacity returns the maximum number of elements this deque can hold, or 0
+// if it is unbounded.
+func (d *ArrayDeque[T]) MaxCapacity() int {
+	return d.maxCapacity
+}
+
+func (d *ArrayDeque[T]) mustOverwrite() {
+	if d.policy == RejectNew {
+		panic("cannot add to a full ArrayDeque")
+	}
+}
+
 // AddAll adds all elements from the given sequence to the back of this deque.
 func (d *ArrayDeque[T]) AddAll(sequence iter.Seq[T]) {
 	for t := range sequence {
@@ -184,14 +322,25 @@ func (d *ArrayDeque[T]) Empty() bool {
 	return d.size == 0
 }
 
-// Clear removes all elements from this deque and
-// releases any memory in use by this deque for garbage
-// collection.
+// Clear removes all elements from this deque. An unbounded deque releases
+// any memory in use by this deque for garbage collection, while a bounded
+// deque keeps its backing array, so that refilling it allocates nothing.
 func (d *ArrayDeque[T]) Clear() {
-	d.slice = nil
+	if d.maxCapacity > 0 {
+		// zero the live range to avoid memory leaks
+		if end := d.front + d.size; end <= len(d.slice) {
+			clear(d.slice[d.front:end])
+		} else {
+			clear(d.slice[d.front:])
+			clear(d.slice[:end-len(d.slice)])
+		}
+	} else {
+		d.slice = nil
+	}
 	d.front = 0
 	d.back = 0
 	d.size = 0
+	d.modCount++
 }
 
 // String returns a string representation of this deque.
@@ -203,16 +352,32 @@ func (d *ArrayDeque[T]) String() string {
 	return "[" + strings.Join(str, ", ") + "]"
 }
 
+// MarshalJSON encodes the deque as a JSON array of its elements from front to
+// back.
+func (d *ArrayDeque[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(d.All())
+}
+
+// UnmarshalJSON replaces the contents of the deque with the elements of a JSON
+// array, from front to back. A bounded deque adds the elements as by Offer.
+func (d *ArrayDeque[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalSeq(data, d.Clear, func(t T) { d.Offer(t) })
+}
+
 // All returns an iterator over all elements,
-// going from front to back in this deque.
+// going from front to back in this deque. The iterator panics
+// with collections.ErrConcurrentModification if the deque is
+// structurally modified during iteration.
 func (d *ArrayDeque[T]) All() iter.Seq[T] {
 	return func(yield func(T) bool) {
+		modCount := d.modCount
 		count := 0
 		i := d.front
 		for count < d.size {
 			if !yield(d.slice[i]) {
 				return
 			}
+			failfast.Check(modCount, d.modCount)
 			i++
 			if i == len(d.slice) {
 				i = 0
@@ -223,9 +388,12 @@ func (d *ArrayDeque[T]) All() iter.Seq[T] {
 }
 
 // Backward returns an iterator over all elements,
-// going from back to front in this deque.
+// going from back to front in this deque. The iterator panics
+// with collections.ErrConcurrentModification if the deque is
+// structurally modified during iteration.
 func (d *ArrayDeque[T]) Backward() iter.Seq[T] {
 	return func(yield func(T) bool) {
+		modCount := d.modCount
 		count := 0
 		i := d.back - 1
 		if i < 0 {
@@ -235,6 +403,7 @@ func (d *ArrayDeque[T]) Backward() iter.Seq[T] {
 			if !yield(d.slice[i]) {
 				return
 			}
+			failfast.Check(modCount, d.modCount)
 			i--
 			if i < 0 {
 				i = len(d.slice) - 1
@@ -244,6 +413,110 @@ func (d *ArrayDeque[T]) Backward() iter.Seq[T] {
 	}
 }
 
+// Cursor returns a cursor positioned outside the deque. Remove and the insert
+// methods shift the elements on the shorter side of the cursor and so take
+// O(n) time, except at either end of the deque. Inserting with the cursor into
+// a full bounded deque panics whatever its policy, since discarding an element
+// would move the cursor.
+func (d *ArrayDeque[T]) Cursor() collections.ListCursor[T] {
+	return cursor.New[T](storage[T]{d})
+}
+
+// storage adapts an ArrayDeque to cursor.Storage.
+type storage[T any] struct {
+	d *ArrayDeque[T]
+}
+
+func (s storage[T]) Len() int {
+	return s.d.size
+}
+
+func (s storage[T]) At(i int) T {
+	return s.d.slice[s.d.physical(i)]
+}
+
+func (s storage[T]) SetAt(i int, t T) {
+	s.d.slice[s.d.physical(i)] = t
+}
+
+func (s storage[T]) InsertAt(i int, t T) {
+	s.d.insertAt(i, t)
+}
+
+func (s storage[T]) DeleteAt(i int) {
+	s.d.deleteAt(i)
+}
+
+func (s storage[T]) ModCount() int {
+	return s.d.modCount
+}
+
+// physical converts an index relative to the front of the deque into an
+// index into the backing array.
+func (d *ArrayDeque[T]) physical(i int) int {
+	i += d.front
+	if i >= len(d.slice) {
+		i -= len(d.slice)
+	}
+	return i
+}
+
+// insertAt inserts t so that it ends up at index i relative to the front,
+// shifting whichever side of i is shorter.
+func (d *ArrayDeque[T]) insertAt(i int, t T) {
+	if d.Full() {
+		panic("cannot insert into a full ArrayDeque")
+	}
+	if i == 0 {
+		d.AddFront(t)
+		return
+	}
+	if i == d.size {
+		d.AddBack(t)
+		return
+	}
+	if d.size == len(d.slice) {
+		d.resize()
+	}
+	if i < d.size>>1 {
+		d.front--
+		if d.front == -1 {
+			d.front = len(d.slice) - 1
+		}
+		d.size++
+		for j := 0; j < i; j++ {
+			d.slice[d.physical(j)] = d.slice[d.physical(j+1)]
+		}
+	} else {
+		d.back++
+		if d.back == len(d.slice) {
+			d.back = 0
+		}
+		d.size++
+		for j := d.size - 1; j > i; j-- {
+			d.slice[d.physical(j)] = d.slice[d.physical(j-1)]
+		}
+	}
+	d.slice[d.physical(i)] = t
+	d.modCount++
+}
+
+// deleteAt removes the element at index i relative to the front, shifting
+// whichever side of i is shorter.
+func (d *ArrayDeque[T]) deleteAt(i int) {
+	if i < d.size>>1 {
+		for j := i; j > 0; j-- {
+			d.slice[d.physical(j)] = d.slice[d.physical(j-1)]
+		}
+		d.RemoveFront()
+	} else {
+		for j := i; j < d.size-1; j++ {
+			d.slice[d.physical(j)] = d.slice[d.physical(j+1)]
+		}
+		d.RemoveBack()
+	}
+}
+
 func (d *ArrayDeque[T]) resize() {
 	var newCap int
 	if len(d.slice) == 0 {
@@ -257,6 +530,9 @@ func (d *ArrayDeque[T]) resize() {
 		newCap = len(d.slice)
 		newCap += len(d.slice) >> 2
 	}
+	if d.maxCapacity > 0 {
+		newCap = min(newCap, d.maxCapacity)
+	}
 	s := make([]T, newCap)
 	m := copy(s, d.slice[d.front:])
 	n := copy(s[m:], d.slice[0:d.front])
@@ -123,3 +123,12 @@ func BenchmarkArrayDeque_ClearAndReuse_Existing(b *testing.B) {
 		d.Clear() // Actually reuse it next iteration
 	}
 }
+
+func BenchmarkArrayDeque_Offer_Bounded(b *testing.B) {
+	b.ReportAllocs()
+	d := New[int](WithMaxCapacity(1024, OverwriteOldest))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Offer(i)
+	}
+}
@@ -0,0 +1,22 @@
+package arraydeque_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+	"github.com/lock14/collections/collectionstest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableDeque(t, func() collections.MutableDeque[int] {
+		return arraydeque.New[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.MutableQueue(t, func() collections.MutableQueue[int] {
+		return arraydeque.New[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.MutableStack(t, func() collections.MutableStack[int] {
+		return arraydeque.New[int]()
+	}, collectionstest.Ints(10))
+}
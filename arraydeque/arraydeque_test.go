@@ -1,7 +1,10 @@
 package arraydeque
 
 import (
+	"encoding/json"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
 	"slices"
 	"testing"
 )
@@ -922,3 +925,378 @@ func TestArrayDeque_CoveragePanics(t *testing.T) {
 	assertPanics(func() { ad.PeekBack() })
 	_ = ad.String()
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		input []int
+		opts  []Option
+	}{
+		{name: "empty", input: nil},
+		{name: "empty_zero_capacity", input: nil, opts: []Option{WithCapacity(0)}},
+		{name: "fewer_than_capacity", input: []int{1, 2, 3}},
+		{name: "exactly_capacity", input: []int{1, 2, 3, 4}, opts: []Option{WithCapacity(4)}},
+		{name: "more_than_capacity", input: []int{1, 2, 3, 4, 5, 6, 7}, opts: []Option{WithCapacity(2)}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := FromSeq(slices.Values(tc.input), tc.opts...)
+			if actual := slices.Collect(d.All()); !slices.Equal(actual, tc.input) {
+				t.Errorf("expected %v, got %v", tc.input, actual)
+			}
+			// The deque must remain fully usable at both ends.
+			d.AddBack(100)
+			d.AddFront(-100)
+			expected := append(append([]int{-100}, tc.input...), 100)
+			if actual := slices.Collect(d.All()); !slices.Equal(actual, expected) {
+				t.Errorf("expected %v, got %v", expected, actual)
+			}
+			slices.Reverse(expected)
+			if actual := slices.Collect(d.Backward()); !slices.Equal(actual, expected) {
+				t.Errorf("expected %v, got %v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(d *ArrayDeque[int])
+		panics bool
+	}{
+		{name: "add front", modify: func(d *ArrayDeque[int]) { d.AddFront(0) }, panics: true},
+		{name: "add back", modify: func(d *ArrayDeque[int]) { d.AddBack(4) }, panics: true},
+		{name: "remove front", modify: func(d *ArrayDeque[int]) { d.RemoveFront() }, panics: true},
+		{name: "remove back", modify: func(d *ArrayDeque[int]) { d.RemoveBack() }, panics: true},
+		{name: "clear", modify: func(d *ArrayDeque[int]) { d.Clear() }, panics: true},
+		{name: "peek", modify: func(d *ArrayDeque[int]) { d.PeekBack() }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for _, dir := range []struct {
+				name string
+				seq  func(d *ArrayDeque[int]) iter.Seq[int]
+			}{
+				{name: "all", seq: (*ArrayDeque[int]).All},
+				{name: "backward", seq: (*ArrayDeque[int]).Backward},
+			} {
+				d := FromSeq(slices.Values([]int{1, 2, 3}))
+				t.Run(dir.name, func(t *testing.T) {
+					assertConcurrentModification(t, tc.panics, func() {
+						for range dir.seq(d) {
+							tc.modify(d)
+						}
+					})
+				})
+			}
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+func TestInsertDeleteAt(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		front int
+	}{
+		{name: "no wrap", front: 0},
+		{name: "wrapped", front: 5},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for i := 0; i <= 8; i++ {
+				for _, del := range []bool{false, true} {
+					d := New[int](WithCapacity(8))
+					// shift the ring so that the contents may wrap around the end of the
+					// array; the deque is full, so inserting also exercises resizing
+					for j := 0; j < tc.front; j++ {
+						d.AddBack(0)
+						d.RemoveFront()
+					}
+					model := []int{0, 1, 2, 3, 4, 5, 6, 7}
+					d.AddAll(slices.Values(model))
+					if del {
+						if i == len(model) {
+							continue
+						}
+						d.deleteAt(i)
+						model = slices.Delete(model, i, i+1)
+					} else {
+						d.insertAt(i, 100)
+						model = slices.Insert(model, i, 100)
+					}
+					if got := slices.Collect(d.All()); !slices.Equal(got, model) {
+						t.Errorf("index %d, delete %t: got %v, want %v", i, del, got, model)
+					}
+					if got := slices.Collect(d.Backward()); !slices.Equal(got, reversed(model)) {
+						t.Errorf("index %d, delete %t: backward got %v", i, del, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func reversed(s []int) []int {
+	r := slices.Clone(s)
+	slices.Reverse(r)
+	return r
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		deque func() *ArrayDeque[int]
+		json  string
+	}{
+		{name: "empty", deque: func() *ArrayDeque[int] { return New[int]() }, json: "[]"},
+		{
+			name: "wrapped",
+			deque: func() *ArrayDeque[int] {
+				d := New[int](WithCapacity(4))
+				d.AddBack(2)
+				d.AddBack(3)
+				d.AddFront(1)
+				return d
+			},
+			json: "[1,2,3]",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := tc.deque()
+			data, err := json.Marshal(d)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			var got ArrayDeque[int]
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if want := slices.Collect(d.All()); !slices.Equal(slices.Collect(got.All()), want) {
+				t.Errorf("expected %v, got %v", want, &got)
+			}
+		})
+	}
+}
+
+func TestMaxCapacity(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		policy Policy
+		ops    func(*ArrayDeque[int]) []bool
+		want   []int
+		offers []bool
+	}{
+		{
+			name:   "overwrite_back",
+			policy: OverwriteOldest,
+			ops: func(d *ArrayDeque[int]) []bool {
+				d.AddAll(slices.Values([]int{1, 2, 3, 4, 5}))
+				return []bool{d.Offer(6)}
+			},
+			want:   []int{4, 5, 6},
+			offers: []bool{true},
+		},
+		{
+			name:   "overwrite_front",
+			policy: OverwriteOldest,
+			ops: func(d *ArrayDeque[int]) []bool {
+				d.AddAll(slices.Values([]int{1, 2, 3}))
+				d.AddFront(0)
+				return []bool{d.OfferFront(-1)}
+			},
+			want:   []int{-1, 0, 1},
+			offers: []bool{true},
+		},
+		{
+			name:   "reject",
+			policy: RejectNew,
+			ops: func(d *ArrayDeque[int]) []bool {
+				return []bool{d.Offer(1), d.Offer(2), d.Offer(3), d.Offer(4), d.OfferFront(0)}
+			},
+			want:   []int{1, 2, 3},
+			offers: []bool{true, true, true, false, false},
+		},
+		{
+			name:   "reject_then_remove",
+			policy: RejectNew,
+			ops: func(d *ArrayDeque[int]) []bool {
+				d.AddAll(slices.Values([]int{1, 2, 3}))
+				d.RemoveFront()
+				return []bool{d.Offer(4), d.Offer(5)}
+			},
+			want:   []int{2, 3, 4},
+			offers: []bool{true, false},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := New[int](WithCapacity(1), WithMaxCapacity(3, tc.policy))
+			if offers := tc.ops(d); !slices.Equal(offers, tc.offers) {
+				t.Errorf("expected offers %v, got %v", tc.offers, offers)
+			}
+			if got := slices.Collect(d.All()); !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+			if !d.Full() {
+				t.Error("expected deque to be full")
+			}
+			if len(d.slice) != 3 {
+				t.Errorf("expected backing array of length 3, got %d", len(d.slice))
+			}
+		})
+	}
+}
+
+func TestMaxCapacity_Panics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{name: "zero_max_capacity", f: func() { New[int](WithMaxCapacity(0, OverwriteOldest)) }},
+		{
+			name: "add_back_rejected",
+			f: func() {
+				d := New[int](WithMaxCapacity(1, RejectNew))
+				d.Add(1)
+				d.Add(2)
+			},
+		},
+		{
+			name: "add_front_rejected",
+			f: func() {
+				d := New[int](WithMaxCapacity(1, RejectNew))
+				d.Push(1)
+				d.Push(2)
+			},
+		},
+		{
+			name: "cursor_insert_when_full",
+			f: func() {
+				d := New[int](WithMaxCapacity(2, OverwriteOldest))
+				d.AddAll(slices.Values([]int{1, 2}))
+				c := d.Cursor()
+				c.Next()
+				c.InsertAfter(3)
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestMaxCapacity_FromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		policy Policy
+		want   []int
+	}{
+		{name: "overwrite", policy: OverwriteOldest, want: []int{4, 5}},
+		{name: "reject", policy: RejectNew, want: []int{1, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := FromSeq(slices.Values([]int{1, 2, 3, 4, 5}), WithMaxCapacity(2, tc.policy))
+			if got := slices.Collect(d.All()); !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+			if d.MaxCapacity() != 2 {
+				t.Errorf("expected max capacity 2, got %d", d.MaxCapacity())
+			}
+		})
+	}
+}
+
+func TestMaxCapacity_SteadyStateAllocs(t *testing.T) {
+	d := New[int](WithMaxCapacity(64, OverwriteOldest))
+	for i := 0; i < 64; i++ {
+		d.Add(i)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		d.Offer(1)
+		d.AddFront(2)
+		d.RemoveBack()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations once full, got %v", allocs)
+	}
+}
+
+func TestMaxCapacity_ClearKeepsBuffer(t *testing.T) {
+	d := New[*int](WithMaxCapacity(64, OverwriteOldest))
+	for i := 0; i < 100; i++ {
+		d.Add(new(int))
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := 0; i < 64; i++ {
+			d.Add(nil)
+		}
+		d.Remove()
+		d.RemoveBack()
+		d.Clear()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations across Add, Remove and Clear, got %v", allocs)
+	}
+	d.AddAll(slices.Values([]*int{new(int), new(int), new(int)}))
+	d.RemoveFront()
+	d.Clear()
+	if len(d.slice) != 64 || !d.Empty() {
+		t.Errorf("expected an empty deque keeping its backing array of 64, got %d elements in %d", d.Size(), len(d.slice))
+	}
+	for i, p := range d.slice {
+		if p != nil {
+			t.Fatalf("expected Clear to zero the backing array, got %v at %d", p, i)
+		}
+	}
+}
@@ -231,3 +231,20 @@ func ExampleArrayDeque_Backward() {
 	// 2
 	// 1
 }
+
+func ExampleWithMaxCapacity() {
+	// A bounded ArrayDeque is a ring buffer that keeps the most recent samples.
+	window := arraydeque.New[int](arraydeque.WithMaxCapacity(3, arraydeque.OverwriteOldest))
+	for _, sample := range []int{10, 20, 30, 40, 50} {
+		window.Offer(sample)
+	}
+	fmt.Println(slices.Collect(window.All()), window.Full())
+
+	// With RejectNew, Offer reports whether there was room.
+	buffer := arraydeque.New[string](arraydeque.WithMaxCapacity(2, arraydeque.RejectNew))
+	fmt.Println(buffer.Offer("a"), buffer.Offer("b"), buffer.Offer("c"))
+
+	// Output:
+	// [30 40 50] true
+	// true true false
+}
@@ -4,20 +4,27 @@ package arraylist
 import (
 	"fmt"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"slices"
 	"strings"
 )
 
 var (
-	_ collections.MutableList[int]  = (*SliceWrapper[int])(nil)
-	_ collections.MutableStack[int] = (*SliceWrapper[int])(nil)
+	_ collections.MutableIndexedList[int] = (*SliceWrapper[int])(nil)
+	_ collections.MutableStack[int]       = (*SliceWrapper[int])(nil)
 )
 
-// SliceWrapper is a wrapper around the built-in slice that implements collections.MutableList and collections.MutableStack.
+// SliceWrapper is a wrapper around the built-in slice that implements collections.MutableIndexedList and collections.MutableStack.
 // The zero value for SliceWrapper is an empty list ready to use.
 type SliceWrapper[T any] struct {
-	slice []T
+	slice    []T
+	modCount int
+	// pending is the cursor, if any, whose removed elements leave a hole in
+	// slice. Methods that read the list skip over the hole, and those that
+	// modify it close it first; see span and settle.
+	pending *listCursor[T]
 }
 
 // config holds the configuration options for a SliceWrapper.
@@ -46,6 +53,18 @@ func New[T any](opts ...Option) *SliceWrapper[T] {
 	}
 }
 
+// FromSeq creates a new SliceWrapper containing the elements of the given sequence in order.
+// Use WithCapacity to pre-size the backing slice when the length of the sequence is known.
+func FromSeq[T any](sequence iter.Seq[T], opts ...Option) *SliceWrapper[T] {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &SliceWrapper[T]{
+		slice: slices.AppendSeq(make([]T, 0, cfg.capacity), sequence),
+	}
+}
+
 // Wrap creates a new SliceWrapper around the given slice.
 func Wrap[T any](slice []T) *SliceWrapper[T] {
 	return &SliceWrapper[T]{
@@ -55,12 +74,15 @@ func Wrap[T any](slice []T) *SliceWrapper[T] {
 
 // Add appends the given element to the end of the list.
 func (l *SliceWrapper[T]) Add(t T) {
+	l.settle()
 	l.slice = append(l.slice, t)
+	l.modCount++
 }
 
 // Remove removes and returns the last element from the list.
 // If the list is empty, Remove panics.
 func (l *SliceWrapper[T]) Remove() T {
+	l.settle()
 	if len(l.slice) == 0 {
 		panic("cannot remove from an empty list")
 	}
@@ -69,6 +91,7 @@ func (l *SliceWrapper[T]) Remove() T {
 	var zero T
 	l.slice[idx] = zero // avoid memory leak
 	l.slice = l.slice[:idx]
+	l.modCount++
 	return t
 }
 
@@ -86,20 +109,22 @@ func (l *SliceWrapper[T]) Pop() T {
 // Peek returns the element at the top of the stack (end of the list) without removing it.
 // If the stack is empty, Peek panics.
 func (l *SliceWrapper[T]) Peek() T {
-	if len(l.slice) == 0 {
+	if l.Empty() {
 		panic("cannot peek from an empty list")
 	}
-	return l.slice[len(l.slice)-1]
+	return l.Get(l.Size() - 1)
 }
 
 // Clear removes all elements from the list while retaining its capacity.
 func (l *SliceWrapper[T]) Clear() {
+	l.settle()
 	// Zero out to avoid memory leaks
 	var zero T
 	for i := range l.slice {
 		l.slice[i] = zero
 	}
 	l.slice = l.slice[:0]
+	l.modCount++
 }
 
 // AddAll appends all elements from the given sequence to the end of the list.
@@ -111,22 +136,90 @@ func (l *SliceWrapper[T]) AddAll(sequence iter.Seq[T]) {
 
 // Size returns the number of elements in the list.
 func (l *SliceWrapper[T]) Size() int {
+	if p := l.pending; p != nil {
+		return len(l.slice) - p.removed
+	}
 	return len(l.slice)
 }
 
 // Empty returns true if the list contains no elements.
 func (l *SliceWrapper[T]) Empty() bool {
-	return len(l.slice) == 0
+	return l.Size() == 0
 }
 
 // Get returns the element at the specified index.
 func (l *SliceWrapper[T]) Get(index int) T {
-	return l.slice[index]
+	return l.slice[l.physical(index)]
 }
 
 // Set replaces the element at the specified index with the given item.
 func (l *SliceWrapper[T]) Set(index int, item T) {
-	l.slice[index] = item
+	l.slice[l.physical(index)] = item
+}
+
+// Insert inserts the given element at the specified index, shifting the elements at and after it up.
+// Panics if the index is not in [0, Size()].
+func (l *SliceWrapper[T]) Insert(index int, t T) {
+	checkPosition(index, l.Size())
+	l.insertAt(index, t)
+}
+
+// InsertAll inserts all elements from the given sequence, in order, at the specified index.
+// Panics if the index is not in [0, Size()].
+func (l *SliceWrapper[T]) InsertAll(index int, sequence iter.Seq[T]) {
+	checkPosition(index, l.Size())
+	l.insertAt(index, slices.Collect(sequence)...)
+}
+
+// RemoveAt removes and returns the element at the specified index, shifting the elements after it down.
+// Panics if the index is not in [0, Size()).
+func (l *SliceWrapper[T]) RemoveAt(index int) T {
+	checkIndex(index, l.Size())
+	t := l.Get(index)
+	l.deleteRange(index, index+1)
+	return t
+}
+
+// RemoveRange removes the elements with indices in [from, to), shifting the elements after them down.
+// Panics if 0 <= from <= to <= Size() does not hold.
+func (l *SliceWrapper[T]) RemoveRange(from, to int) {
+	checkRange(from, to, l.Size())
+	l.deleteRange(from, to)
+}
+
+// RemoveIf removes every element satisfying pred, keeping the order of the rest,
+// and returns the number of elements removed.
+func (l *SliceWrapper[T]) RemoveIf(pred func(T) bool) int {
+	return l.deleteFunc(0, l.Size(), pred)
+}
+
+// IndexOf returns the index of the first element satisfying pred, or -1 if there is none.
+func (l *SliceWrapper[T]) IndexOf(pred func(T) bool) int {
+	head, tail := l.span(0, l.Size())
+	return indexFunc(head, tail, pred)
+}
+
+// LastIndexOf returns the index of the last element satisfying pred, or -1 if there is none.
+func (l *SliceWrapper[T]) LastIndexOf(pred func(T) bool) int {
+	head, tail := l.span(0, l.Size())
+	return lastIndexFunc(head, tail, pred)
+}
+
+// ContainsFunc returns true if any element of the list satisfies pred.
+func (l *SliceWrapper[T]) ContainsFunc(pred func(T) bool) bool {
+	return l.IndexOf(pred) >= 0
+}
+
+// SubList returns a view of the elements with indices in [from, to). See SubList.
+// Panics if 0 <= from <= to <= Size() does not hold.
+func (l *SliceWrapper[T]) SubList(from, to int) collections.MutableIndexedList[T] {
+	checkRange(from, to, l.Size())
+	return &SubList[T]{
+		root:     l,
+		offset:   from,
+		size:     to - from,
+		modCount: l.modCount,
+	}
 }
 
 // String returns a string representation of the list.
@@ -138,7 +231,139 @@ func (l *SliceWrapper[T]) String() string {
 	return "[" + strings.Join(vals, ", ") + "]"
 }
 
+// MarshalJSON encodes the list as a JSON array of its elements in order.
+func (l *SliceWrapper[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(l.All())
+}
+
+// UnmarshalJSON replaces the contents of the list with the elements of a JSON
+// array.
+func (l *SliceWrapper[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalSeq(data, l.Clear, l.Add)
+}
+
 // All returns an iterator over all elements in the list from first to last.
+// The iterator panics with collections.ErrConcurrentModification if the list is
+// structurally modified during iteration.
 func (l *SliceWrapper[T]) All() iter.Seq[T] {
-	return slices.Values(l.slice)
+	return func(yield func(T) bool) {
+		modCount := l.modCount
+		for i := 0; i < l.Size(); i++ {
+			if !yield(l.Get(i)) {
+				return
+			}
+			failfast.Check(modCount, l.modCount)
+		}
+	}
+}
+
+// settle closes up the elements removed by a cursor that is still walking the
+// list, so that the backing slice holds exactly the elements of the list. Only
+// methods that modify the list call it.
+func (l *SliceWrapper[T]) settle() {
+	if l.pending != nil {
+		l.pending.compact()
+	}
+}
+
+// physical returns the index in the backing slice of the element at index i of
+// the list, skipping the hole left by a walking cursor.
+func (l *SliceWrapper[T]) physical(i int) int {
+	if p := l.pending; p != nil && i >= p.holeStart() {
+		return i + p.removed
+	}
+	return i
+}
+
+// span returns the parts of the backing slice holding the elements with indices
+// in [from, to) of the list, which are split in two by the hole left by a
+// walking cursor if it falls inside the range.
+func (l *SliceWrapper[T]) span(from, to int) (head, tail []T) {
+	p := l.pending
+	if p == nil || to <= p.holeStart() {
+		return l.slice[from:to], nil
+	}
+	start, n := p.holeStart(), p.removed
+	if from >= start {
+		return l.slice[from+n : to+n], nil
+	}
+	return l.slice[from:start], l.slice[start+n : to+n]
+}
+
+// insertAt inserts ts at index i of the list.
+func (l *SliceWrapper[T]) insertAt(i int, ts ...T) {
+	if len(ts) == 0 {
+		return
+	}
+	l.settle()
+	l.slice = slices.Insert(l.slice, i, ts...)
+	l.modCount++
+}
+
+// deleteRange removes the elements in [i, j) of the list. slices.Delete zeroes
+// the vacated tail to avoid memory leaks.
+func (l *SliceWrapper[T]) deleteRange(i, j int) {
+	if i == j {
+		return
+	}
+	l.settle()
+	l.slice = slices.Delete(l.slice, i, j)
+	l.modCount++
+}
+
+// deleteFunc removes the elements in [i, j) of the list that satisfy pred, and
+// returns the number removed.
+func (l *SliceWrapper[T]) deleteFunc(i, j int, pred func(T) bool) int {
+	l.settle()
+	// DeleteFunc compacts the kept elements to the front of [i, j) and zeroes the
+	// rest, which deleteRange then closes up.
+	kept := len(slices.DeleteFunc(l.slice[i:j], pred))
+	l.deleteRange(i+kept, j)
+	return j - i - kept
+}
+
+// indexFunc returns the index of the first element of head followed by tail
+// satisfying pred, or -1 if there is none.
+func indexFunc[T any](head, tail []T, pred func(T) bool) int {
+	if i := slices.IndexFunc(head, pred); i >= 0 {
+		return i
+	}
+	if i := slices.IndexFunc(tail, pred); i >= 0 {
+		return len(head) + i
+	}
+	return -1
+}
+
+// lastIndexFunc returns the index of the last element of head followed by tail
+// satisfying pred, or -1 if there is none.
+func lastIndexFunc[T any](head, tail []T, pred func(T) bool) int {
+	for i := len(tail) - 1; i >= 0; i-- {
+		if pred(tail[i]) {
+			return len(head) + i
+		}
+	}
+	for i := len(head) - 1; i >= 0; i-- {
+		if pred(head[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func checkIndex(index, size int) {
+	if index < 0 || index >= size {
+		panic(fmt.Sprintf("index %d out of range [0, %d)", index, size))
+	}
+}
+
+func checkPosition(index, size int) {
+	if index < 0 || index > size {
+		panic(fmt.Sprintf("index %d out of range [0, %d]", index, size))
+	}
+}
+
+func checkRange(from, to, size int) {
+	if from < 0 || from > to || to > size {
+		panic(fmt.Sprintf("range [%d, %d) out of range [0, %d]", from, to, size))
+	}
 }
@@ -53,3 +53,19 @@ func BenchmarkSliceWrapper_IterateAll(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkSliceWrapper_CursorRemoveIf(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		l := arraylist.New[int](arraylist.WithCapacity(1000))
+		for j := 0; j < 1000; j++ {
+			l.Add(j)
+		}
+		b.StartTimer()
+		for c := l.Cursor(); c.Next(); {
+			if c.Value()%2 == 0 {
+				c.Remove()
+			}
+		}
+	}
+}
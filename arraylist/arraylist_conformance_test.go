@@ -0,0 +1,30 @@
+package arraylist_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/collectionstest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableList(t, func() collections.MutableList[int] {
+		return arraylist.New[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.MutableStack(t, func() collections.MutableStack[int] {
+		return arraylist.New[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.MutableIndexedList(t, func() collections.MutableIndexedList[int] {
+		return arraylist.New[int]()
+	}, collectionstest.Ints(10))
+	// views in the middle of a list, directly and through another view, so that
+	// every operation has elements on both sides of it
+	collectionstest.MutableIndexedList(t, func() collections.MutableIndexedList[int] {
+		return arraylist.Wrap([]int{-1, -2}).SubList(1, 1)
+	}, collectionstest.Ints(10))
+	collectionstest.MutableIndexedList(t, func() collections.MutableIndexedList[int] {
+		return arraylist.Wrap([]int{-1, -2, -3, -4}).SubList(1, 3).SubList(1, 1)
+	}, collectionstest.Ints(10))
+}
@@ -1,7 +1,11 @@
 package arraylist
 
 import (
+	"encoding/json"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
 	"slices"
+	"sync"
 	"testing"
 )
 
@@ -244,3 +248,390 @@ func TestSliceWrapper_BulkOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		input    []int
+		opts     []Option
+		expected []int
+	}{
+		{name: "empty", input: nil, expected: nil},
+		{name: "elements", input: []int{3, 1, 2}, expected: []int{3, 1, 2}},
+		{name: "capacity_hint", input: []int{1, 2}, opts: []Option{WithCapacity(10)}, expected: []int{1, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			l := FromSeq(slices.Values(tc.input), tc.opts...)
+			if actual := slices.Collect(l.All()); !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+			if len(tc.opts) > 0 && cap(l.slice) < 10 {
+				t.Errorf("expected capacity hint to be used, got %d", cap(l.slice))
+			}
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(l *SliceWrapper[int])
+		panics bool
+	}{
+		{name: "add", modify: func(l *SliceWrapper[int]) { l.Add(4) }, panics: true},
+		{name: "remove", modify: func(l *SliceWrapper[int]) { l.Remove() }, panics: true},
+		{name: "clear", modify: func(l *SliceWrapper[int]) { l.Clear() }, panics: true},
+		{name: "set", modify: func(l *SliceWrapper[int]) { l.Set(0, 10) }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			l := Wrap([]int{1, 2, 3})
+			assertConcurrentModification(t, tc.panics, func() {
+				for range l.All() {
+					tc.modify(l)
+				}
+			})
+		})
+	}
+	t.Run("modify then break", func(t *testing.T) {
+		t.Parallel()
+		l := Wrap([]int{1, 2, 3})
+		assertConcurrentModification(t, false, func() {
+			for range l.All() {
+				l.Add(4)
+				break
+			}
+		})
+	})
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		elems []int
+		json  string
+	}{
+		{name: "empty", elems: nil, json: "[]"},
+		{name: "elements", elems: []int{3, 1, 2}, json: "[3,1,2]"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeq(slices.Values(tc.elems)))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			var l SliceWrapper[int]
+			if err := json.Unmarshal(data, &l); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := slices.Collect(l.All()); !slices.Equal(got, tc.elems) {
+				t.Errorf("expected %v, got %v", tc.elems, got)
+			}
+		})
+	}
+}
+
+func TestSliceWrapper_PositionalOperations(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		op   func(l *SliceWrapper[int])
+		want []int
+	}{
+		{name: "insert_front", op: func(l *SliceWrapper[int]) { l.Insert(0, 9) }, want: []int{9, 1, 2, 3, 2}},
+		{name: "insert_middle", op: func(l *SliceWrapper[int]) { l.Insert(2, 9) }, want: []int{1, 2, 9, 3, 2}},
+		{name: "insert_end", op: func(l *SliceWrapper[int]) { l.Insert(4, 9) }, want: []int{1, 2, 3, 2, 9}},
+		{name: "insert_all", op: func(l *SliceWrapper[int]) { l.InsertAll(1, slices.Values([]int{7, 8})) }, want: []int{1, 7, 8, 2, 3, 2}},
+		{name: "insert_all_empty", op: func(l *SliceWrapper[int]) { l.InsertAll(1, slices.Values([]int{})) }, want: []int{1, 2, 3, 2}},
+		{name: "remove_at", op: func(l *SliceWrapper[int]) { l.RemoveAt(1) }, want: []int{1, 3, 2}},
+		{name: "remove_range", op: func(l *SliceWrapper[int]) { l.RemoveRange(1, 3) }, want: []int{1, 2}},
+		{name: "remove_empty_range", op: func(l *SliceWrapper[int]) { l.RemoveRange(2, 2) }, want: []int{1, 2, 3, 2}},
+		{name: "remove_if", op: func(l *SliceWrapper[int]) { l.RemoveIf(func(v int) bool { return v == 2 }) }, want: []int{1, 3}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			l := Wrap([]int{1, 2, 3, 2})
+			tc.op(l)
+			if got := slices.Collect(l.All()); !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSliceWrapper_Search(t *testing.T) {
+	t.Parallel()
+	l := Wrap([]int{1, 2, 3, 2})
+	isTwo := func(v int) bool { return v == 2 }
+	isFour := func(v int) bool { return v == 4 }
+	if got := l.IndexOf(isTwo); got != 1 {
+		t.Errorf("IndexOf = %d, want 1", got)
+	}
+	if got := l.LastIndexOf(isTwo); got != 3 {
+		t.Errorf("LastIndexOf = %d, want 3", got)
+	}
+	if got := l.IndexOf(isFour); got != -1 {
+		t.Errorf("IndexOf missing = %d, want -1", got)
+	}
+	if got := l.LastIndexOf(isFour); got != -1 {
+		t.Errorf("LastIndexOf missing = %d, want -1", got)
+	}
+	if !l.ContainsFunc(isTwo) || l.ContainsFunc(isFour) {
+		t.Error("ContainsFunc reported the wrong result")
+	}
+	if got := l.RemoveIf(isTwo); got != 2 {
+		t.Errorf("RemoveIf = %d, want 2", got)
+	}
+}
+
+func TestSliceWrapper_RemoveZeroesSlots(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		op   func(l *SliceWrapper[*int])
+	}{
+		{name: "remove_at", op: func(l *SliceWrapper[*int]) { l.RemoveAt(0) }},
+		{name: "remove_range", op: func(l *SliceWrapper[*int]) { l.RemoveRange(1, 3) }},
+		{name: "remove_if", op: func(l *SliceWrapper[*int]) { l.RemoveIf(func(p *int) bool { return *p%2 == 0 }) }},
+		{name: "sublist_remove_if", op: func(l *SliceWrapper[*int]) { l.SubList(1, 4).RemoveIf(func(p *int) bool { return *p%2 == 0 }) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			backing := make([]*int, 5)
+			for i := range backing {
+				backing[i] = new(int)
+				*backing[i] = i
+			}
+			l := Wrap(backing)
+			tc.op(l)
+			for i := l.Size(); i < len(backing); i++ {
+				if backing[i] != nil {
+					t.Errorf("slot %d still references %d after removal", i, *backing[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSliceWrapper_IndexPanics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func(l *SliceWrapper[int])
+	}{
+		{name: "insert_negative", f: func(l *SliceWrapper[int]) { l.Insert(-1, 0) }},
+		{name: "insert_past_end", f: func(l *SliceWrapper[int]) { l.Insert(4, 0) }},
+		{name: "insert_all_past_end", f: func(l *SliceWrapper[int]) { l.InsertAll(4, slices.Values([]int{0})) }},
+		{name: "remove_at_end", f: func(l *SliceWrapper[int]) { l.RemoveAt(3) }},
+		{name: "remove_range_reversed", f: func(l *SliceWrapper[int]) { l.RemoveRange(2, 1) }},
+		{name: "remove_range_past_end", f: func(l *SliceWrapper[int]) { l.RemoveRange(1, 4) }},
+		{name: "sublist_past_end", f: func(l *SliceWrapper[int]) { l.SubList(0, 4) }},
+		{name: "sublist_get_past_view", f: func(l *SliceWrapper[int]) { l.SubList(0, 2).Get(2) }},
+		{name: "sublist_remove_empty", f: func(l *SliceWrapper[int]) { l.SubList(1, 1).Remove() }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f(Wrap([]int{1, 2, 3}))
+		})
+	}
+}
+
+func TestSubList(t *testing.T) {
+	t.Parallel()
+	l := Wrap([]int{0, 1, 2, 3, 4, 5})
+	outer := l.SubList(1, 5)
+	inner := outer.SubList(1, 3)
+	if got := slices.Collect(inner.All()); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("inner view = %v, want [2 3]", got)
+	}
+	inner.Set(0, 20)
+	inner.Add(9)
+	inner.Insert(0, 8)
+	if got, want := slices.Collect(l.All()), []int{0, 1, 8, 20, 3, 9, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("list = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(outer.All()), []int{1, 8, 20, 3, 9, 4}; !slices.Equal(got, want) {
+		t.Errorf("outer view = %v, want %v", got, want)
+	}
+	inner.Clear()
+	if got, want := slices.Collect(l.All()), []int{0, 1, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("list after clearing inner view = %v, want %v", got, want)
+	}
+	if outer.Size() != 2 || !inner.Empty() {
+		t.Errorf("expected outer size 2 and empty inner view, got %d and %d", outer.Size(), inner.Size())
+	}
+	if got := outer.(*SubList[int]).String(); got != "[1, 4]" {
+		t.Errorf("String = %s, want [1, 4]", got)
+	}
+}
+
+func TestSubList_ConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(l *SliceWrapper[int], outer, inner collections.MutableIndexedList[int])
+		panics bool
+	}{
+		{
+			name:   "list_add",
+			modify: func(l *SliceWrapper[int], _, _ collections.MutableIndexedList[int]) { l.Add(9) },
+			panics: true,
+		},
+		{
+			name:   "sibling_view_add",
+			modify: func(l *SliceWrapper[int], _, _ collections.MutableIndexedList[int]) { l.SubList(0, 1).Add(9) },
+			panics: true,
+		},
+		{
+			name:   "outer_view_add",
+			modify: func(_ *SliceWrapper[int], outer, _ collections.MutableIndexedList[int]) { outer.Add(9) },
+			panics: true,
+		},
+		{
+			name:   "list_set",
+			modify: func(l *SliceWrapper[int], _, _ collections.MutableIndexedList[int]) { l.Set(0, 9) },
+			panics: false,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			l := Wrap([]int{0, 1, 2, 3})
+			outer := l.SubList(1, 4)
+			inner := outer.SubList(0, 2)
+			tc.modify(l, outer, inner)
+			assertConcurrentModification(t, tc.panics, func() {
+				inner.Size()
+			})
+		})
+	}
+	t.Run("inner_view_keeps_outer_valid", func(t *testing.T) {
+		t.Parallel()
+		l := Wrap([]int{0, 1, 2, 3})
+		outer := l.SubList(1, 4)
+		outer.SubList(0, 2).RemoveAt(0)
+		assertConcurrentModification(t, false, func() {
+			if got := slices.Collect(outer.All()); !slices.Equal(got, []int{2, 3}) {
+				t.Errorf("outer view = %v, want [2 3]", got)
+			}
+		})
+	})
+}
+
+func TestCursor_RemoveClosesUpLazily(t *testing.T) {
+	t.Parallel()
+	backing := []int{1, 2, 3, 4, 5, 6}
+	l := Wrap(backing)
+	c := l.Cursor()
+	c.Next()
+	c.Next()
+	c.Remove()
+	c.Next()
+	c.Remove()
+	// reads skip over the hole the removals left without writing to the list
+	before := slices.Clone(backing)
+	if got, want := slices.Collect(l.All()), []int{1, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("list while walking = %v, want %v", got, want)
+	}
+	if l.Size() != 4 || l.Get(1) != 4 || l.Peek() != 6 {
+		t.Errorf("expected Size 4, Get(1) 4 and Peek 6, got %d, %d and %d", l.Size(), l.Get(1), l.Peek())
+	}
+	even := func(n int) bool { return n%2 == 0 }
+	if l.IndexOf(even) != 1 || l.LastIndexOf(even) != 3 || !l.ContainsFunc(func(n int) bool { return n == 5 }) {
+		t.Errorf("expected IndexOf 1 and LastIndexOf 3, got %d and %d", l.IndexOf(even), l.LastIndexOf(even))
+	}
+	if got, want := slices.Collect(l.SubList(0, 3).All()), []int{1, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("sublist while walking = %v, want %v", got, want)
+	}
+	if !slices.Equal(backing, before) {
+		t.Errorf("expected reads to leave the backing slice %v, got %v", before, backing)
+	}
+	if !c.Next() || c.Value() != 4 {
+		t.Fatalf("expected Next after reading the list to visit 4")
+	}
+	c.Remove()
+	if l.Get(1) != 5 || l.Size() != 3 {
+		t.Errorf("expected Get and Size to see the removal, got %v", l)
+	}
+	l.Set(0, 7)
+	for c.Next() {
+		c.Set(c.Value() * 10)
+	}
+	if got, want := slices.Collect(l.All()), []int{7, 50, 60}; !slices.Equal(got, want) {
+		t.Errorf("list = %v, want %v", got, want)
+	}
+	if got, want := backing[3:], []int{0, 0, 0}; !slices.Equal(got, want) {
+		t.Errorf("vacated slots = %v, want %v", got, want)
+	}
+	// modifying the list other than through the cursor closes the hole first
+	l = Wrap([]int{1, 2, 3})
+	c = l.Cursor()
+	c.Next()
+	c.Remove()
+	l.Insert(1, 9)
+	if got, want := slices.Collect(l.All()), []int{2, 9, 3}; !slices.Equal(got, want) {
+		t.Errorf("list after Insert = %v, want %v", got, want)
+	}
+}
+
+func TestCursor_ConcurrentReadsDuringWalk(t *testing.T) {
+	t.Parallel()
+	l := Wrap([]int{1, 2, 3, 4, 5, 6})
+	c := l.Cursor()
+	c.Next()
+	c.Remove()
+	// reads hold no write lock, so they must not close up the hole themselves
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, want := slices.Collect(l.All()), []int{2, 3, 4, 5, 6}; !slices.Equal(got, want) || l.Size() != 5 {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		}()
+	}
+	wg.Wait()
+}
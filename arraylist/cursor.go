@@ -0,0 +1,170 @@
+package arraylist
+
+import (
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+)
+
+var _ collections.ListCursor[int] = (*listCursor[int])(nil)
+
+type cursorState int
+
+const (
+	outside cursorState = iota
+	element
+	gap
+)
+
+// Cursor returns a cursor positioned outside the list. Removing elements while
+// moving forward with Next takes O(n) time over the whole pass, as each element
+// is shifted at most once. Prev and the insert methods close up removed
+// elements first, and the insert methods shift the elements after the cursor,
+// so they take O(n) time.
+func (l *SliceWrapper[T]) Cursor() collections.ListCursor[T] {
+	return &listCursor[T]{
+		l:        l,
+		modCount: l.modCount,
+	}
+}
+
+// listCursor is a collections.ListCursor over a SliceWrapper. When the cursor
+// is on an element, pos is its index; in a gap, pos is the index of the element
+// that follows the gap.
+//
+// Remove leaves the removed slot in the backing slice. The removed slots form a
+// hole of the given size just after the cursor's element, or at pos in a gap,
+// and Next moves the following element down across the hole. The hole is closed
+// when the cursor moves past the last element, or by compact before the list is
+// modified in any other way. Methods that only read the list skip over it.
+type listCursor[T any] struct {
+	l        *SliceWrapper[T]
+	state    cursorState
+	pos      int
+	removed  int
+	modCount int
+}
+
+// Next moves the cursor to the next element and reports whether there is one.
+func (c *listCursor[T]) Next() bool {
+	c.check()
+	next := c.holeStart()
+	if c.state == outside {
+		next = 0
+	}
+	src := next + c.removed
+	if src >= len(c.l.slice) {
+		c.compact()
+		c.state = outside
+		return false
+	}
+	if c.removed > 0 {
+		c.l.slice[next] = c.l.slice[src]
+	}
+	c.state = element
+	c.pos = next
+	return true
+}
+
+// Prev moves the cursor to the previous element and reports whether there is one.
+func (c *listCursor[T]) Prev() bool {
+	c.check()
+	c.compact()
+	prev := len(c.l.slice) - 1
+	if c.state != outside {
+		prev = c.pos - 1
+	}
+	if prev < 0 {
+		c.state = outside
+		return false
+	}
+	c.state = element
+	c.pos = prev
+	return true
+}
+
+// Value returns the element at the cursor.
+func (c *listCursor[T]) Value() T {
+	c.checkElement()
+	return c.l.slice[c.pos]
+}
+
+// Set replaces the element at the cursor.
+func (c *listCursor[T]) Set(t T) {
+	c.checkElement()
+	c.l.slice[c.pos] = t
+}
+
+// Remove removes the element at the cursor, leaving the cursor in its place.
+func (c *listCursor[T]) Remove() {
+	c.checkElement()
+	c.removed++
+	c.state = gap
+	c.l.pending = c
+	c.l.modCount++
+	c.modCount = c.l.modCount
+}
+
+// InsertBefore inserts t immediately before the cursor.
+func (c *listCursor[T]) InsertBefore(t T) {
+	c.check()
+	c.compact()
+	if c.state == outside {
+		c.l.insertAt(len(c.l.slice), t)
+	} else {
+		c.l.insertAt(c.pos, t)
+		c.pos++
+	}
+	c.modCount = c.l.modCount
+}
+
+// InsertAfter inserts t immediately after the cursor.
+func (c *listCursor[T]) InsertAfter(t T) {
+	c.check()
+	c.compact()
+	switch c.state {
+	case outside:
+		c.l.insertAt(0, t)
+	case element:
+		c.l.insertAt(c.pos+1, t)
+	case gap:
+		c.l.insertAt(c.pos, t)
+	}
+	c.modCount = c.l.modCount
+}
+
+// holeStart returns the index of the first removed slot.
+func (c *listCursor[T]) holeStart() int {
+	if c.state == element {
+		return c.pos + 1
+	}
+	return c.pos
+}
+
+// compact closes the hole left by removed elements, shifting the elements
+// after it down and zeroing the vacated tail to avoid memory leaks.
+func (c *listCursor[T]) compact() {
+	if c.removed == 0 {
+		return
+	}
+	s := c.l.slice
+	start := c.holeStart()
+	n := copy(s[start:], s[start+c.removed:])
+	clear(s[start+n:])
+	c.l.slice = s[:start+n]
+	c.removed = 0
+	c.l.pending = nil
+}
+
+func (c *listCursor[T]) check() {
+	if c.l.pending != c {
+		c.l.settle()
+	}
+	failfast.Check(c.modCount, c.l.modCount)
+}
+
+func (c *listCursor[T]) checkElement() {
+	c.check()
+	if c.state != element {
+		panic("cursor is not positioned on an element")
+	}
+}
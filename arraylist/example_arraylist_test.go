@@ -161,3 +161,26 @@ func ExampleSliceWrapper_All() {
 	// 20
 	// 30
 }
+
+func ExampleSliceWrapper_Insert() {
+	list := arraylist.Wrap([]string{"a", "c", "e"})
+	list.Insert(1, "b")
+	list.InsertAll(3, slices.Values([]string{"d"}))
+	fmt.Println(list.String())
+	fmt.Println(list.RemoveAt(0), list.String())
+	// Output:
+	// [a, b, c, d, e]
+	// a [b, c, d, e]
+}
+
+func ExampleSliceWrapper_SubList() {
+	list := arraylist.Wrap([]int{1, 2, 3, 4, 5, 6})
+	// Operations on a view apply to its range of the list.
+	middle := list.SubList(1, 5)
+	middle.RemoveIf(func(v int) bool { return v%2 == 0 })
+	fmt.Println(list.String())
+	fmt.Println(list.IndexOf(func(v int) bool { return v > 3 }))
+	// Output:
+	// [1, 3, 5, 6]
+	// 2
+}
@@ -0,0 +1,196 @@
+package arraylist
+
+import (
+	"fmt"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
+	"slices"
+	"strings"
+)
+
+var _ collections.MutableIndexedList[int] = (*SubList[int])(nil)
+
+// SubList is a view of a contiguous range of a SliceWrapper, returned by
+// SliceWrapper.SubList. Indices are relative to the start of the range, and
+// changes made through the view, including insertions and removals that grow
+// or shrink it, are made to the underlying list. A SubList of a SubList is a
+// view of the same list, and changes made through it keep its ancestors valid.
+//
+// Structurally modifying the list other than through the view, or through a
+// view that is not the SubList or one of its descendants, invalidates it, and
+// its next operation panics with collections.ErrConcurrentModification.
+type SubList[T any] struct {
+	root     *SliceWrapper[T]
+	parent   *SubList[T]
+	offset   int
+	size     int
+	modCount int
+}
+
+// Add appends the given element to the end of the view.
+func (s *SubList[T]) Add(t T) {
+	s.Insert(s.Size(), t)
+}
+
+// Remove removes and returns the last element of the view.
+// If the view is empty, Remove panics.
+func (s *SubList[T]) Remove() T {
+	if s.Size() == 0 {
+		panic("cannot remove from an empty list")
+	}
+	return s.RemoveAt(s.size - 1)
+}
+
+// AddAll appends all elements from the given sequence to the end of the view.
+func (s *SubList[T]) AddAll(sequence iter.Seq[T]) {
+	s.InsertAll(s.Size(), sequence)
+}
+
+// Clear removes all elements of the view from the underlying list.
+func (s *SubList[T]) Clear() {
+	s.RemoveRange(0, s.Size())
+}
+
+// Size returns the number of elements in the view.
+func (s *SubList[T]) Size() int {
+	s.check()
+	return s.size
+}
+
+// Empty returns true if the view contains no elements.
+func (s *SubList[T]) Empty() bool {
+	return s.Size() == 0
+}
+
+// Get returns the element at the specified index of the view.
+func (s *SubList[T]) Get(index int) T {
+	checkIndex(index, s.Size())
+	return s.root.Get(s.offset + index)
+}
+
+// Set replaces the element at the specified index of the view with the given item.
+func (s *SubList[T]) Set(index int, item T) {
+	checkIndex(index, s.Size())
+	s.root.Set(s.offset+index, item)
+}
+
+// Insert inserts the given element at the specified index of the view.
+// Panics if the index is not in [0, Size()].
+func (s *SubList[T]) Insert(index int, t T) {
+	checkPosition(index, s.Size())
+	s.root.insertAt(s.offset+index, t)
+	s.resized(1)
+}
+
+// InsertAll inserts all elements from the given sequence, in order, at the specified index of the view.
+// Panics if the index is not in [0, Size()].
+func (s *SubList[T]) InsertAll(index int, sequence iter.Seq[T]) {
+	checkPosition(index, s.Size())
+	ts := slices.Collect(sequence)
+	s.root.insertAt(s.offset+index, ts...)
+	s.resized(len(ts))
+}
+
+// RemoveAt removes and returns the element at the specified index of the view.
+// Panics if the index is not in [0, Size()).
+func (s *SubList[T]) RemoveAt(index int) T {
+	checkIndex(index, s.Size())
+	t := s.root.Get(s.offset + index)
+	s.root.deleteRange(s.offset+index, s.offset+index+1)
+	s.resized(-1)
+	return t
+}
+
+// RemoveRange removes the elements of the view with indices in [from, to).
+// Panics if 0 <= from <= to <= Size() does not hold.
+func (s *SubList[T]) RemoveRange(from, to int) {
+	checkRange(from, to, s.Size())
+	s.root.deleteRange(s.offset+from, s.offset+to)
+	s.resized(from - to)
+}
+
+// RemoveIf removes every element of the view satisfying pred, keeping the order
+// of the rest, and returns the number of elements removed.
+func (s *SubList[T]) RemoveIf(pred func(T) bool) int {
+	s.check()
+	n := s.root.deleteFunc(s.offset, s.offset+s.size, pred)
+	s.resized(-n)
+	return n
+}
+
+// IndexOf returns the index in the view of the first element satisfying pred, or -1 if there is none.
+func (s *SubList[T]) IndexOf(pred func(T) bool) int {
+	head, tail := s.span()
+	return indexFunc(head, tail, pred)
+}
+
+// LastIndexOf returns the index in the view of the last element satisfying pred, or -1 if there is none.
+func (s *SubList[T]) LastIndexOf(pred func(T) bool) int {
+	head, tail := s.span()
+	return lastIndexFunc(head, tail, pred)
+}
+
+// ContainsFunc returns true if any element of the view satisfies pred.
+func (s *SubList[T]) ContainsFunc(pred func(T) bool) bool {
+	return s.IndexOf(pred) >= 0
+}
+
+// SubList returns a view of the elements of this view with indices in [from, to).
+// Panics if 0 <= from <= to <= Size() does not hold.
+func (s *SubList[T]) SubList(from, to int) collections.MutableIndexedList[T] {
+	checkRange(from, to, s.Size())
+	return &SubList[T]{
+		root:     s.root,
+		parent:   s,
+		offset:   s.offset + from,
+		size:     to - from,
+		modCount: s.modCount,
+	}
+}
+
+// All returns an iterator over the elements of the view from first to last.
+// The iterator panics with collections.ErrConcurrentModification if the
+// underlying list is structurally modified during iteration.
+func (s *SubList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.check()
+		modCount := s.modCount
+		for i := 0; i < s.size; i++ {
+			if !yield(s.root.Get(s.offset + i)) {
+				return
+			}
+			failfast.Check(modCount, s.root.modCount)
+		}
+	}
+}
+
+// String returns a string representation of the view.
+func (s *SubList[T]) String() string {
+	vals := make([]string, 0, s.Size())
+	for v := range s.All() {
+		vals = append(vals, fmt.Sprintf("%+v", v))
+	}
+	return "[" + strings.Join(vals, ", ") + "]"
+}
+
+// span returns the parts of the backing slice the view covers.
+func (s *SubList[T]) span() (head, tail []T) {
+	s.check()
+	return s.root.span(s.offset, s.offset+s.size)
+}
+
+// check panics with collections.ErrConcurrentModification if the list has been
+// structurally modified other than through the view.
+func (s *SubList[T]) check() {
+	failfast.Check(s.modCount, s.root.modCount)
+}
+
+// resized records a change of delta elements made through the view, in the view
+// and every view it was taken from.
+func (s *SubList[T]) resized(delta int) {
+	for v := s; v != nil; v = v.parent {
+		v.size += delta
+		v.modCount = s.root.modCount
+	}
+}
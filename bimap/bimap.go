@@ -0,0 +1,227 @@
+// Package bimap provides a bidirectional map, which maps keys to values and
+// values back to keys, and keeps both directions in sync.
+//
+// A BiMap enforces the uniqueness of its values as well as its keys. Put panics
+// with ErrDuplicateValue when asked to map a second key to a value, and ForcePut
+// removes the conflicting entry instead. Inverse returns a live view of the
+// same entries with keys and values swapped.
+package bimap
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/internal/anymap"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/linkedhashmap"
+	"github.com/lock14/collections/pair"
+	"github.com/lock14/collections/treemap"
+)
+
+var _ collections.MutableMap[int, string] = (*BiMap[int, string])(nil)
+
+// ErrDuplicateValue is the value Put panics with, wrapped with the offending
+// value, when the value is already mapped to a different key.
+var ErrDuplicateValue = errors.New("bimap: value already present")
+
+// BiMap is a map whose values are unique, backed by one map from keys to
+// values and another from values to keys.
+type BiMap[K any, V any] struct {
+	forward  collections.MutableMap[K, V]
+	inverse  collections.MutableMap[V, K]
+	keyEqual func(K, K) bool
+	inv      *BiMap[V, K]
+}
+
+func newBiMap[K any, V any](
+	forward collections.MutableMap[K, V], keyEqual func(K, K) bool,
+	inverse collections.MutableMap[V, K], valueEqual func(V, V) bool,
+) *BiMap[K, V] {
+	b := &BiMap[K, V]{forward: forward, inverse: inverse, keyEqual: keyEqual}
+	b.inv = &BiMap[V, K]{forward: inverse, inverse: forward, keyEqual: valueEqual, inv: b}
+	return b
+}
+
+func equal[T comparable](a, b T) bool {
+	return a == b
+}
+
+// New creates an empty BiMap backed by hashmaps.
+func New[K comparable, V comparable]() *BiMap[K, V] {
+	return newBiMap(hashmap.New[K, V](), equal[K], hashmap.New[V, K](), equal[V])
+}
+
+// NewLinked creates an empty BiMap backed by linkedhashmaps, which iterates
+// entries in insertion order. Its inverse iterates values in the order they
+// were added, so replacing the value of a key moves the new value to the end
+// of the inverse without moving the key.
+func NewLinked[K comparable, V comparable]() *BiMap[K, V] {
+	return newBiMap(linkedhashmap.New[K, V](), equal[K], linkedhashmap.New[V, K](), equal[V])
+}
+
+// NewSorted creates an empty BiMap backed by treemaps, which iterates entries
+// in the order imposed by keyComp, and whose inverse iterates them in the order
+// imposed by valueComp.
+func NewSorted[K any, V any](keyComp comparator.Comparator[K], valueComp comparator.Comparator[V]) *BiMap[K, V] {
+	return newBiMap(
+		treemap.New[K, V](treemap.WithComparator(keyComp)), func(a, b K) bool { return keyComp(a, b) == 0 },
+		treemap.New[V, K](treemap.WithComparator(valueComp)), func(a, b V) bool { return valueComp(a, b) == 0 },
+	)
+}
+
+// Inverse returns a view of this map with keys and values swapped. The view
+// shares its entries with this map, so changes through either are visible in
+// both. Inverse of the view returns this map.
+func (b *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return b.inv
+}
+
+// Get returns the value associated with the specified key, and a boolean
+// indicating if it was found.
+func (b *BiMap[K, V]) Get(key K) (V, bool) {
+	return b.forward.Get(key)
+}
+
+// Put associates the specified value with the specified key, replacing the
+// previous value of the key. Put panics with an error wrapping
+// ErrDuplicateValue if the value is already associated with a different key;
+// use ForcePut to replace that entry instead.
+func (b *BiMap[K, V]) Put(key K, value V) {
+	if err := b.tryPut(key, value); err != nil {
+		panic(err)
+	}
+}
+
+// tryPut is Put, but returns the error instead of panicking.
+func (b *BiMap[K, V]) tryPut(key K, value V) error {
+	if k, ok := b.inverse.Get(value); ok {
+		if b.keyEqual(k, key) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrDuplicateValue, value)
+	}
+	b.put(key, value)
+	return nil
+}
+
+// ForcePut associates the specified value with the specified key, removing any
+// entry that associates the value with a different key.
+func (b *BiMap[K, V]) ForcePut(key K, value V) {
+	if k, ok := b.inverse.Get(value); ok {
+		if b.keyEqual(k, key) {
+			return
+		}
+		b.forward.Remove(k)
+		b.inverse.Remove(value)
+	}
+	b.put(key, value)
+}
+
+// put associates value with key, which requires that value is not present.
+func (b *BiMap[K, V]) put(key K, value V) {
+	if old, ok := b.forward.Get(key); ok {
+		b.inverse.Remove(old)
+	}
+	b.forward.Put(key, value)
+	b.inverse.Put(value, key)
+}
+
+// Remove removes the entry for the specified key if present.
+func (b *BiMap[K, V]) Remove(key K) {
+	if value, ok := b.forward.Get(key); ok {
+		b.forward.Remove(key)
+		b.inverse.Remove(value)
+	}
+}
+
+// Clear removes all entries from the map.
+func (b *BiMap[K, V]) Clear() {
+	b.forward.Clear()
+	b.inverse.Clear()
+}
+
+// Size returns the number of entries in the map.
+func (b *BiMap[K, V]) Size() int {
+	return b.forward.Size()
+}
+
+// Empty returns true if the map contains no entries.
+func (b *BiMap[K, V]) Empty() bool {
+	return b.forward.Empty()
+}
+
+// ContainsKey returns true if the map contains an entry for the specified key.
+func (b *BiMap[K, V]) ContainsKey(key K) bool {
+	return b.forward.ContainsKey(key)
+}
+
+// ContainsValue returns true if the map contains an entry with the specified
+// value.
+func (b *BiMap[K, V]) ContainsValue(value V) bool {
+	return b.inverse.ContainsKey(value)
+}
+
+// All returns an iterator over all entries in the map.
+func (b *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return b.forward.All()
+}
+
+// Keys returns an iterator over all keys in the map.
+func (b *BiMap[K, V]) Keys() iter.Seq[K] {
+	return b.forward.Keys()
+}
+
+// Values returns an iterator over all values in the map.
+func (b *BiMap[K, V]) Values() iter.Seq[V] {
+	return b.forward.Values()
+}
+
+// MarshalJSON encodes the map as a JSON object, or as a JSON array of
+// [key, value] pairs if K cannot be an object key.
+func (b *BiMap[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(b.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object or array of [key, value] pairs. It returns an error wrapping
+// ErrDuplicateValue, and leaves the map unchanged, if two keys have the same
+// value. When called on the zero value, the map is backed by hash maps, and
+// decoding reports an error if K or V is not comparable.
+func (b *BiMap[K, V]) UnmarshalJSON(data []byte) error {
+	if b.forward == nil {
+		forward, ok := anymap.New[K, V]()
+		inverse, invOK := anymap.New[V, K]()
+		if !ok || !invOK {
+			return fmt.Errorf("bimap: cannot unmarshal into a BiMap whose keys or values are not comparable")
+		}
+		b.forward, b.inverse, b.keyEqual = forward, inverse, anymap.Equal[K]
+		b.inv = &BiMap[V, K]{forward: inverse, inverse: forward, keyEqual: anymap.Equal[V], inv: b}
+	}
+	var entries []pair.Pair[K, V]
+	decoded := false
+	err := codec.UnmarshalMap(data, func() { decoded = true }, func(k K, v V) {
+		entries = append(entries, pair.New(k, v))
+	})
+	if err != nil || !decoded {
+		return err
+	}
+	var old []pair.Pair[K, V]
+	for k, v := range b.All() {
+		old = append(old, pair.New(k, v))
+	}
+	b.Clear()
+	for _, e := range entries {
+		if err := b.tryPut(e.Unwrap()); err != nil {
+			b.Clear()
+			for _, p := range old {
+				b.put(p.Unwrap())
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package bimap_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections/bimap"
+)
+
+func BenchmarkBiMap_Put(b *testing.B) {
+	m := bimap.New[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Put(i, i)
+	}
+}
+
+func BenchmarkBiMap_ForcePut(b *testing.B) {
+	m := bimap.New[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.ForcePut(i%1000, i%997)
+	}
+}
+
+func BenchmarkBiMap_InverseGet(b *testing.B) {
+	m := bimap.New[int, int]()
+	for i := 0; i < 1000; i++ {
+		m.Put(i, -i)
+	}
+	inv := m.Inverse()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		inv.Get(-(i % 1000))
+	}
+}
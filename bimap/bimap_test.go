@@ -0,0 +1,196 @@
+package bimap
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPut(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		b    func() *BiMap[int, string]
+	}{
+		{name: "hash", b: New[int, string]},
+		{name: "linked", b: NewLinked[int, string]},
+		{name: "sorted", b: func() *BiMap[int, string] { return NewSorted(cmp.Compare[int], cmp.Compare[string]) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			b := tc.b()
+			b.Put(1, "a")
+			b.Put(2, "b")
+			b.Put(1, "a")
+			b.Put(2, "c")
+			if got, want := maps.Collect(b.All()), map[int]string{1: "a", 2: "c"}; !maps.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+			if got, want := maps.Collect(b.Inverse().All()), map[string]int{"a": 1, "c": 2}; !maps.Equal(got, want) {
+				t.Errorf("expected inverse %v, got %v", want, got)
+			}
+			if b.ContainsValue("b") || !b.ContainsValue("c") || b.Size() != 2 || b.Inverse().Size() != 2 {
+				t.Errorf("expected the old value of 2 to be removed, got %v", maps.Collect(b.All()))
+			}
+
+			func() {
+				defer func() {
+					r := recover()
+					if err, ok := r.(error); !ok || !errors.Is(err, ErrDuplicateValue) {
+						t.Errorf("expected a panic wrapping ErrDuplicateValue, got %v", r)
+					}
+				}()
+				b.Put(3, "a")
+			}()
+			if b.ContainsKey(3) || b.Size() != 2 {
+				t.Errorf("expected a rejected Put to leave the map unchanged, got %v", maps.Collect(b.All()))
+			}
+
+			b.ForcePut(3, "a")
+			if got, want := maps.Collect(b.All()), map[int]string{2: "c", 3: "a"}; !maps.Equal(got, want) {
+				t.Errorf("expected ForcePut to replace the entry for a, got %v, want %v", got, want)
+			}
+			if k, ok := b.Inverse().Get("a"); !ok || k != 3 {
+				t.Errorf("expected a -> 3 in the inverse, got %d, %t", k, ok)
+			}
+
+			b.Remove(2)
+			b.Remove(9)
+			if b.ContainsValue("c") || b.Size() != 1 {
+				t.Errorf("expected Remove to remove the value from the inverse, got %v", maps.Collect(b.Inverse().All()))
+			}
+			b.Clear()
+			if !b.Empty() || !b.Inverse().Empty() {
+				t.Errorf("expected both directions to be empty")
+			}
+		})
+	}
+}
+
+func TestInverse(t *testing.T) {
+	t.Parallel()
+	b := NewLinked[string, int]()
+	inv := b.Inverse()
+	if inv.Inverse() != b {
+		t.Errorf("expected the inverse of the inverse to be the map itself")
+	}
+	inv.Put(1, "one")
+	inv.Put(2, "two")
+	b.Put("three", 3)
+	if got, want := slices.Collect(b.Keys()), []string{"one", "two", "three"}; !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(inv.Keys()), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("expected inverse keys %v, got %v", want, got)
+	}
+	inv.ForcePut(1, "two")
+	if got, want := maps.Collect(b.All()), map[string]int{"two": 1, "three": 3}; !maps.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	inv.Remove(3)
+	if b.ContainsKey("three") {
+		t.Errorf("expected removal through the inverse to be visible")
+	}
+}
+
+func TestSorted(t *testing.T) {
+	t.Parallel()
+	b := NewSorted(cmp.Compare[string], func(a, c string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(c)) })
+	b.Put("y", "B")
+	b.Put("x", "c")
+	b.Put("z", "a")
+	if got, want := slices.Collect(b.Keys()), []string{"x", "y", "z"}; !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(b.Inverse().Keys()), []string{"a", "B", "c"}; !slices.Equal(got, want) {
+		t.Errorf("expected values in value order %v, got %v", want, got)
+	}
+	b.Put("y", "b")
+	if v, _ := b.Get("y"); v != "B" {
+		t.Errorf("expected a value equal under the comparator to be kept, got %s", v)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a value equal under the comparator to conflict")
+		}
+	}()
+	b.Put("w", "C")
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	b := NewSorted(cmp.Compare[string], cmp.Compare[int])
+	b.Put("b", 2)
+	b.Put("a", 1)
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":1,"b":2}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	data, err = json.Marshal(b.Inverse())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"1":"a","2":"b"}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	got := New[string, int]()
+	got.Put("stale", 0)
+	if err := json.Unmarshal([]byte(`{"x":1,"y":2}`), got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if want := map[int]string{1: "x", 2: "y"}; !maps.Equal(maps.Collect(got.Inverse().All()), want) {
+		t.Errorf("expected inverse %v, got %v", want, maps.Collect(got.Inverse().All()))
+	}
+	err = json.Unmarshal([]byte(`{"p":1,"q":1}`), got)
+	if !errors.Is(err, ErrDuplicateValue) {
+		t.Errorf("expected an error wrapping ErrDuplicateValue, got %v", err)
+	}
+	if want := map[string]int{"x": 1, "y": 2}; !maps.Equal(maps.Collect(got.All()), want) {
+		t.Errorf("expected a failed decode to leave %v, got %v", want, maps.Collect(got.All()))
+	}
+	if want := map[int]string{1: "x", 2: "y"}; !maps.Equal(maps.Collect(got.Inverse().All()), want) {
+		t.Errorf("expected a failed decode to leave inverse %v, got %v", want, maps.Collect(got.Inverse().All()))
+	}
+}
+
+func TestUnmarshalJSONZeroValue(t *testing.T) {
+	t.Parallel()
+	var b BiMap[string, int]
+	if err := json.Unmarshal([]byte(`{"x":1,"y":2}`), &b); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if want := map[int]string{1: "x", 2: "y"}; !maps.Equal(maps.Collect(b.Inverse().All()), want) {
+		t.Errorf("expected inverse %v, got %v", want, maps.Collect(b.Inverse().All()))
+	}
+	if b.Inverse().Inverse() != &b {
+		t.Error("expected the inverse of the inverse to be the decoded map")
+	}
+	b.Inverse().Put(3, "z")
+	if v, ok := b.Get("z"); !ok || v != 3 {
+		t.Errorf("expected a put through the inverse to be visible, got %d, %t", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`{"p":1,"q":1}`), &b); !errors.Is(err, ErrDuplicateValue) {
+		t.Errorf("expected an error wrapping ErrDuplicateValue, got %v", err)
+	}
+
+	var unhashable BiMap[string, []int]
+	if err := json.Unmarshal([]byte(`{"a":[1]}`), &unhashable); err == nil {
+		t.Error("expected an error decoding into a BiMap with values that are not comparable")
+	}
+	var fields struct {
+		Codes *BiMap[string, int] `json:"codes"`
+	}
+	if err := json.Unmarshal([]byte(`{"codes":{"a":1}}`), &fields); err != nil || !fields.Codes.ContainsKey("a") {
+		t.Errorf("expected a struct field to decode, got %v, %v", fields.Codes, err)
+	}
+}
@@ -0,0 +1,35 @@
+package bimap_test
+
+import (
+	"fmt"
+
+	"github.com/lock14/collections/bimap"
+)
+
+func ExampleBiMap_Inverse() {
+	// A BiMap keeps lookups in both directions in sync.
+	ids := bimap.NewLinked[int, string]()
+	ids.Put(1, "alice")
+	ids.Put(2, "bob")
+
+	names := ids.Inverse()
+	id, _ := names.Get("bob")
+	fmt.Println("bob:", id)
+
+	// Renaming through either direction updates both.
+	ids.Put(2, "robert")
+	_, ok := names.Get("bob")
+	fmt.Println("bob present:", ok)
+
+	// ForcePut moves a value to a new key instead of rejecting it.
+	ids.ForcePut(3, "alice")
+	for id, name := range ids.All() {
+		fmt.Println(id, name)
+	}
+
+	// Output:
+	// bob: 2
+	// bob present: false
+	// 2 robert
+	// 3 alice
+}
@@ -2,6 +2,7 @@
 package bitset
 
 import (
+	"encoding/json"
 	"fmt"
 	"iter"
 	"math/bits"
@@ -9,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 )
 
 const (
@@ -22,6 +25,7 @@ type BitSet struct {
 	bits         []uint64
 	maxWordInUse int
 	size         int
+	modCount     int
 }
 
 var _ collections.MutableNavigableSet[int] = (*BitSet)(nil)
@@ -58,6 +62,14 @@ func New(opts ...Option) *BitSet {
 	}
 }
 
+// FromSeq creates a BitSet with the bits at the indices produced by the given sequence set to true.
+// The configured capacity is used as a size hint for the number of bits.
+func FromSeq(sequence iter.Seq[int], opts ...Option) *BitSet {
+	b := New(opts...)
+	b.AddAll(sequence)
+	return b
+}
+
 // ClearBit sets the bit specified by the index to false.
 func (b *BitSet) ClearBit(bit int) {
 	index, shift := convert(bit)
@@ -69,6 +81,7 @@ func (b *BitSet) ClearBit(bit int) {
 			b.size--
 		}
 		b.bits[index] &= ^(1 << shift)
+		b.modCount++
 		if index == b.maxWordInUse-1 && b.bits[index] == 0 {
 			b.maxWordInUse = b.lastNonZeroWord() + 1
 		}
@@ -84,6 +97,7 @@ func (b *BitSet) SetBit(bit int) {
 			b.size++
 		}
 		b.bits[index] |= 1 << shift
+		b.modCount++
 		if index+1 > b.maxWordInUse {
 			b.maxWordInUse = index + 1
 		}
@@ -128,8 +142,7 @@ func (b *BitSet) Flip() {
 	for i := 0; i < len(b.bits); i++ {
 		b.bits[i] = ^b.bits[i]
 	}
-	b.maxWordInUse = b.lastNonZeroWord() + 1
-	b.size = -1
+	b.rewritten(len(b.bits) > 0)
 }
 
 // FlipRange sets each bit from the specified start bit (inclusive) to the
@@ -172,8 +185,7 @@ func (b *BitSet) FlipRange(start int, end int) {
 			b.bits[endIndex] = upperBits | lowerBits
 		}
 	}
-	b.maxWordInUse = b.lastNonZeroWord() + 1
-	b.size = -1
+	b.rewritten(start < end)
 }
 
 // FromBytes returns new BitSet containing all the bits in the given byte array.
@@ -229,10 +241,65 @@ func (b *BitSet) String() string {
 	return "[" + strings.Join(vals, ", ") + "]"
 }
 
+// MarshalJSON encodes the bit set as a JSON array of the indices of its set
+// bits in ascending order.
+func (b *BitSet) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(b.SetBits())
+}
+
+// UnmarshalJSON replaces the contents of the bit set with the indices of a JSON
+// array.
+func (b *BitSet) UnmarshalJSON(data []byte) error {
+	var bits []int
+	if err := json.Unmarshal(data, &bits); err != nil || bits == nil {
+		return err
+	}
+	for _, bit := range bits {
+		if bit < 0 {
+			return fmt.Errorf("bitset: cannot unmarshal negative index %d", bit)
+		}
+	}
+	b.Clear()
+	for _, bit := range bits {
+		b.SetBit(bit)
+	}
+	return nil
+}
+
+var binaryFormat = codec.Format{Name: "bitset", Magic: "BSET", Version: 1}
+
+// MarshalBinary encodes the bit set in a compact, versioned binary format
+// holding the bytes returned by ToBytes.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	e := binaryFormat.NewEncoder()
+	e.Encode(b.ToBytes())
+	return e.Bytes()
+}
+
+// UnmarshalBinary replaces the contents of the bit set with data produced by
+// MarshalBinary.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	d, err := binaryFormat.NewDecoder(data)
+	if err != nil {
+		return err
+	}
+	var bytes []byte
+	if err := d.Decode(&bytes); err != nil {
+		return err
+	}
+	decoded := FromBytes(bytes)
+	decoded.modCount = b.modCount + 1
+	*b = *decoded
+	return nil
+}
+
 // SetBits returns an iterator that iterates over the set bits of this BitSet.
-// It uses word-level iteration with bits.TrailingZeros64 for efficiency.
+// It uses word-level iteration with bits.TrailingZeros64 for efficiency. The
+// iterator panics with collections.ErrConcurrentModification if a bit is set or
+// cleared during iteration.
 func (b *BitSet) SetBits() iter.Seq[int] {
 	return func(yield func(int) bool) {
+		modCount := b.modCount
 		for i := 0; i < b.maxWordInUse; i++ {
 			word := b.bits[i]
 			for word != 0 {
@@ -240,6 +307,7 @@ func (b *BitSet) SetBits() iter.Seq[int] {
 				if !yield(i*wordSize + tz) {
 					return
 				}
+				failfast.Check(modCount, b.modCount)
 				word &= word - 1 // clear lowest set bit
 			}
 		}
@@ -280,6 +348,17 @@ func ensureNonNegative(i int) {
 	}
 }
 
+// rewritten updates the bit set after an operation that rewrote whole words,
+// counting it as a structural modification only if changed reports that a
+// word actually changed.
+func (b *BitSet) rewritten(changed bool) {
+	b.maxWordInUse = b.lastNonZeroWord() + 1
+	b.size = -1
+	if changed {
+		b.modCount++
+	}
+}
+
 func (b *BitSet) recomputeSize() {
 	size := 0
 	for i := 0; i < b.maxWordInUse; i++ {
@@ -316,6 +395,9 @@ func (b *BitSet) All() iter.Seq[int] {
 // Clear removes all elements from the bit set.
 func (b *BitSet) Clear() {
 	b.bits = make([]uint64, len(b.bits))
+	if b.maxWordInUse > 0 {
+		b.modCount++
+	}
 	b.maxWordInUse = 0
 	b.size = 0
 }
@@ -346,11 +428,14 @@ func (b *BitSet) AddAll(seq iter.Seq[int]) {
 // RemoveAll removes all elements of the specified collection from this set.
 func (b *BitSet) RemoveAll(col collections.Collection[int]) {
 	if other, ok := col.(*BitSet); ok {
+		changed := false
 		for i := 0; i < b.maxWordInUse && i < other.maxWordInUse; i++ {
-			b.bits[i] &= ^other.bits[i]
+			if w := b.bits[i] &^ other.bits[i]; w != b.bits[i] {
+				b.bits[i] = w
+				changed = true
+			}
 		}
-		b.maxWordInUse = b.lastNonZeroWord() + 1
-		b.size = -1
+		b.rewritten(changed)
 		return
 	}
 	for v := range col.All() {
@@ -361,23 +446,33 @@ func (b *BitSet) RemoveAll(col collections.Collection[int]) {
 // RetainAll retains only the elements in this set that are contained in the specified collection.
 func (b *BitSet) RetainAll(col collections.Collection[int]) {
 	if other, ok := col.(*BitSet); ok {
+		changed := false
 		for i := 0; i < b.maxWordInUse; i++ {
+			w := uint64(0)
 			if i < other.maxWordInUse {
-				b.bits[i] &= other.bits[i]
-			} else {
-				b.bits[i] = 0
+				w = b.bits[i] & other.bits[i]
+			}
+			if w != b.bits[i] {
+				b.bits[i] = w
+				changed = true
 			}
 		}
-		b.maxWordInUse = b.lastNonZeroWord() + 1
-		b.size = -1
+		b.rewritten(changed)
 		return
 	}
 	if set, ok := col.(collections.Set[int]); ok {
-		for v := range b.All() {
-			if !set.Contains(v) {
-				b.ClearBit(v)
+		// clear the bits in place, as ClearBit would panic the iterator
+		changed := false
+		for i := 0; i < b.maxWordInUse; i++ {
+			for w := b.bits[i]; w != 0; w &= w - 1 {
+				tz := bits.TrailingZeros64(w)
+				if !set.Contains(i*wordSize + tz) {
+					b.bits[i] &^= 1 << tz
+					changed = true
+				}
 			}
 		}
+		b.rewritten(changed)
 		return
 	}
 
@@ -388,6 +483,9 @@ func (b *BitSet) RetainAll(col collections.Collection[int]) {
 			temp.SetBit(v)
 		}
 	}
+	if temp.Size() != b.Size() {
+		b.modCount++
+	}
 	b.bits = temp.bits
 	b.maxWordInUse = temp.maxWordInUse
 	b.size = temp.size
@@ -601,6 +699,7 @@ func (b *BitSet) Higher(t int) (int, bool) {
 // Backward returns a reverse iterator over the elements of this set in descending order.
 func (b *BitSet) Backward() iter.Seq[int] {
 	return func(yield func(int) bool) {
+		modCount := b.modCount
 		for i := b.maxWordInUse - 1; i >= 0; i-- {
 			w := b.bits[i]
 			for w != 0 {
@@ -609,6 +708,7 @@ func (b *BitSet) Backward() iter.Seq[int] {
 				if !yield(i*wordSize + bit) {
 					return
 				}
+				failfast.Check(modCount, b.modCount)
 				w &= ^(1 << bit)
 			}
 		}
@@ -618,6 +718,7 @@ func (b *BitSet) Backward() iter.Seq[int] {
 // From returns an iterator over the elements of this set greater than or equal to from.
 func (b *BitSet) From(from int) iter.Seq[int] {
 	return func(yield func(int) bool) {
+		modCount := b.modCount
 		if from < 0 {
 			from = 0
 		}
@@ -632,6 +733,7 @@ func (b *BitSet) From(from int) iter.Seq[int] {
 			if !yield(index*wordSize + tz) {
 				return
 			}
+			failfast.Check(modCount, b.modCount)
 			w &= w - 1
 		}
 
@@ -642,6 +744,7 @@ func (b *BitSet) From(from int) iter.Seq[int] {
 				if !yield(i*wordSize + tz) {
 					return
 				}
+				failfast.Check(modCount, b.modCount)
 				w &= w - 1
 			}
 		}
@@ -651,6 +754,7 @@ func (b *BitSet) From(from int) iter.Seq[int] {
 // To returns an iterator over the elements of this set strictly less than to.
 func (b *BitSet) To(to int) iter.Seq[int] {
 	return func(yield func(int) bool) {
+		modCount := b.modCount
 		if to <= 0 {
 			return
 		}
@@ -669,6 +773,7 @@ func (b *BitSet) To(to int) iter.Seq[int] {
 				if !yield(i*wordSize + tz) {
 					return
 				}
+				failfast.Check(modCount, b.modCount)
 				w &= w - 1
 			}
 		}
@@ -678,6 +783,7 @@ func (b *BitSet) To(to int) iter.Seq[int] {
 // Between returns an iterator over the elements of this set in the half-open range [from, to).
 func (b *BitSet) Between(from, to int) iter.Seq[int] {
 	return func(yield func(int) bool) {
+		modCount := b.modCount
 		if from >= to || to <= 0 {
 			return
 		}
@@ -706,6 +812,7 @@ func (b *BitSet) Between(from, to int) iter.Seq[int] {
 				if !yield(i*wordSize + tz) {
 					return
 				}
+				failfast.Check(modCount, b.modCount)
 				w &= w - 1
 			}
 		}
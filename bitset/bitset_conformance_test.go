@@ -0,0 +1,17 @@
+package bitset_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/bitset"
+	"github.com/lock14/collections/collectionstest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableNavigableSet(t, func() collections.MutableNavigableSet[int] {
+		return bitset.New()
+	}, cmp.Compare[int], collectionstest.Ints(200))
+}
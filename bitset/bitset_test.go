@@ -2,12 +2,17 @@ package bitset
 
 import (
 	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"slices"
 	"testing"
 
+	"github.com/lock14/collections"
 	"github.com/lock14/collections/arraylist"
 	"github.com/lock14/collections/hashset"
+	"github.com/lock14/collections/internal/failfast"
 	linkedlist "github.com/lock14/collections/linkedlist"
 )
 
@@ -1413,3 +1418,206 @@ func TestBitSet_IteratorsTableDriven(t *testing.T) {
 		t.Run(tc.name, tc.run)
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		input    []int
+		opts     []Option
+		expected []int
+	}{
+		{name: "empty", input: nil, expected: nil},
+		{name: "unsorted_with_duplicates", input: []int{130, 3, 64, 3}, expected: []int{3, 64, 130}},
+		{name: "capacity_hint", input: []int{1, 500}, opts: []Option{WithCapacity(512)}, expected: []int{1, 500}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			b := FromSeq(slices.Values(tc.input), tc.opts...)
+			if actual := slices.Collect(b.All()); !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+			if b.Size() != len(tc.expected) {
+				t.Errorf("expected size %d, got %d", len(tc.expected), b.Size())
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		bits []int
+		json string
+	}{
+		{name: "empty", bits: nil, json: "[]"},
+		{name: "across_words", bits: []int{1, 64, 130}, json: "[1,64,130]"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeq(slices.Values(tc.bits)))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			var b BitSet
+			if err := json.Unmarshal(data, &b); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := slices.Collect(b.SetBits()); !slices.Equal(got, tc.bits) {
+				t.Errorf("expected %v, got %v", tc.bits, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONNegative(t *testing.T) {
+	t.Parallel()
+	b := FromSeq(slices.Values([]int{5}))
+	if err := json.Unmarshal([]byte("[1,-1]"), b); err == nil {
+		t.Errorf("expected an error for a negative index")
+	}
+	if got := slices.Collect(b.SetBits()); !slices.Equal(got, []int{5}) {
+		t.Errorf("expected the bit set to be unchanged, got %v", got)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		bits []int
+	}{
+		{name: "empty", bits: nil},
+		{name: "single_word", bits: []int{0, 5, 63}},
+		{name: "multi_word", bits: []int{1, 64, 200, 1000}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			b := New()
+			for _, bit := range tc.bits {
+				b.SetBit(bit)
+			}
+			data, err := b.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			got := New()
+			got.SetBit(7)
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			if bits := slices.Collect(got.SetBits()); !slices.Equal(bits, tc.bits) {
+				t.Errorf("expected %v, got %v", tc.bits, bits)
+			}
+		})
+	}
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+	type doc struct {
+		Primes *BitSet
+	}
+	in := doc{Primes: New()}
+	for _, p := range []int{2, 3, 5, 7, 11} {
+		in.Primes.SetBit(p)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var out doc
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got, want := slices.Collect(out.Primes.SetBits()), []int{2, 3, 5, 7, 11}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	iterators := []struct {
+		name string
+		seq  func(b *BitSet) iter.Seq[int]
+	}{
+		{name: "set_bits", seq: func(b *BitSet) iter.Seq[int] { return b.SetBits() }},
+		{name: "backward", seq: func(b *BitSet) iter.Seq[int] { return b.Backward() }},
+		{name: "from", seq: func(b *BitSet) iter.Seq[int] { return b.From(1) }},
+		{name: "to", seq: func(b *BitSet) iter.Seq[int] { return b.To(100) }},
+		{name: "between", seq: func(b *BitSet) iter.Seq[int] { return b.Between(1, 100) }},
+	}
+	cases := []struct {
+		name   string
+		modify func(b *BitSet)
+		panics bool
+	}{
+		{name: "set_bit", modify: func(b *BitSet) { b.SetBit(70) }, panics: true},
+		{name: "clear_bit", modify: func(b *BitSet) { b.ClearBit(65) }, panics: true},
+		{name: "flip_range", modify: func(b *BitSet) { b.FlipRange(0, 8) }, panics: true},
+		{name: "clear", modify: func(b *BitSet) { b.Clear() }, panics: true},
+		{name: "retain_all", modify: func(b *BitSet) { b.RetainAll(hashset.New[int]()) }, panics: true},
+		{name: "set_bit_already_set", modify: func(b *BitSet) { b.SetBit(2) }, panics: false},
+		{name: "clear_bit_not_set", modify: func(b *BitSet) { b.ClearBit(3) }, panics: false},
+		{name: "flip_empty_range", modify: func(b *BitSet) { b.FlipRange(4, 4) }, panics: false},
+		{name: "remove_all_disjoint", modify: func(b *BitSet) { b.RemoveAll(FromSeq(slices.Values([]int{3, 64}))) }, panics: false},
+		{name: "retain_all_superset", modify: func(b *BitSet) { b.RetainAll(FromSeq(slices.Values([]int{2, 5, 65, 99, 100}))) }, panics: false},
+		{name: "retain_all_set_superset", modify: func(b *BitSet) {
+			b.RetainAll(hashset.FromSeq(slices.Values([]int{2, 5, 65, 99})))
+		}, panics: false},
+		{name: "retain_all_list_superset", modify: func(b *BitSet) {
+			b.RetainAll(arraylist.Wrap([]int{99, 65, 5, 2}))
+		}, panics: false},
+		{name: "remove_all_overlapping", modify: func(b *BitSet) { b.RemoveAll(FromSeq(slices.Values([]int{65}))) }, panics: true},
+	}
+	for _, it := range iterators {
+		it := it
+		for _, tc := range cases {
+			tc := tc
+			t.Run(it.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+				b := FromSeq(slices.Values([]int{2, 5, 65, 99}))
+				assertConcurrentModification(t, tc.panics, func() {
+					for range it.seq(b) {
+						tc.modify(b)
+					}
+				})
+			})
+		}
+	}
+	t.Run("retain_all_set", func(t *testing.T) {
+		t.Parallel()
+		b := FromSeq(slices.Values([]int{1, 2, 3, 64, 65}))
+		b.RetainAll(hashset.FromSeq(slices.Values([]int{2, 65, 7})))
+		if got, want := slices.Collect(b.All()), []int{2, 65}; !slices.Equal(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
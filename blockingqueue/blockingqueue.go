@@ -0,0 +1,397 @@
+// Package blockingqueue provides queues that are safe for concurrent use and
+// block goroutines until elements or space become available, for handing off
+// work between goroutines.
+//
+// BlockingQueue is a first-in-first-out queue, BlockingDeque supports
+// insertion and removal at both ends, and PriorityBlockingQueue removes
+// elements in the order of a comparator. Each is unbounded unless created with
+// WithMaxCapacity, and each implements the matching mutable collection
+// interface.
+//
+// Put and Take block until they can complete, the context is done, or the
+// queue is closed. Offer and Poll never block, and OfferTimeout and
+// PollTimeout block for at most the given duration. The Add, Remove and Peek
+// methods of the collection interfaces do not block either: like their
+// counterparts on the unsynchronized collections, they panic when the queue
+// is full, closed or empty.
+//
+// Close wakes every blocked goroutine. Once a queue is closed, adding to it
+// fails, while the elements already in it can still be taken; Take returns
+// ErrClosed only once the queue is both closed and empty.
+//
+// Iterators returned by All iterate over a snapshot of the queue taken when
+// iteration begins.
+package blockingqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+)
+
+// ErrClosed is returned when putting into a closed queue, or taking from a
+// closed queue that is empty.
+var ErrClosed = errors.New("blockingqueue: queue is closed")
+
+// config holds the values for configuring a blocking queue.
+type config[T any] struct {
+	bounded     bool
+	maxCapacity int
+	comparator  comparator.Comparator[T]
+}
+
+// Option configures a blocking queue config.
+type Option[T any] func(*config[T])
+
+// WithMaxCapacity bounds the queue to at most maxCapacity elements. Put blocks
+// while a bounded queue is full.
+func WithMaxCapacity[T any](maxCapacity int) Option[T] {
+	return func(c *config[T]) {
+		c.bounded = true
+		c.maxCapacity = maxCapacity
+	}
+}
+
+// WithComparator configures the comparator used to order the elements of a
+// PriorityBlockingQueue. It is ignored by the other queues.
+func WithComparator[T any](comp comparator.Comparator[T]) Option[T] {
+	return func(c *config[T]) {
+		c.comparator = comp
+	}
+}
+
+func newConfig[T any](opts []Option[T]) *config[T] {
+	config := &config[T]{}
+	for _, option := range opts {
+		option(config)
+	}
+	if config.bounded && config.maxCapacity < 1 {
+		panic("max capacity must be at least 1")
+	}
+	return config
+}
+
+// signal wakes every goroutine waiting on it. A waiter receives from ch, which
+// broadcast closes, so waiting can be combined with a context in a select.
+type signal struct {
+	ch chan struct{}
+}
+
+func (s *signal) wait() <-chan struct{} {
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+func (s *signal) broadcast() {
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
+
+// queue holds the methods shared by all the blocking queues. Every change to
+// the backing collection is made with mu held. Adding an element wakes all the
+// goroutines waiting for one and removing an element wakes all those waiting
+// for space, each of which then rechecks the queue; waking only one could lose
+// the wakeup to a waiter whose context is already done.
+type queue[T any] struct {
+	mu          sync.Mutex
+	c           collections.MutableQueue[T]
+	maxCapacity int
+	closed      bool
+	notEmpty    signal
+	notFull     signal
+}
+
+func (q *queue[T]) init(c collections.MutableQueue[T], config *config[T]) {
+	q.c = c
+	q.maxCapacity = config.maxCapacity
+}
+
+// Put adds the element to the queue, blocking while the queue is full. It
+// returns ErrClosed if the queue is closed, or the context's error if the
+// context is done before there is space.
+func (q *queue[T]) Put(ctx context.Context, t T) error {
+	return q.put(ctx, t, q.c.Add)
+}
+
+// Take removes and returns the element at the head of the queue, blocking
+// while the queue is empty. It returns ErrClosed if the queue is closed and
+// empty, or the context's error if the context is done before there is an
+// element.
+func (q *queue[T]) Take(ctx context.Context) (T, error) {
+	return q.take(ctx, q.c.Remove)
+}
+
+// Offer adds the element to the queue if it has space and is not closed, and
+// reports whether it did.
+func (q *queue[T]) Offer(t T) bool {
+	return q.offer(t, q.c.Add)
+}
+
+// Poll removes and returns the element at the head of the queue, and false if
+// the queue is empty.
+func (q *queue[T]) Poll() (T, bool) {
+	return q.poll(q.c.Remove)
+}
+
+// OfferTimeout adds the element to the queue, waiting up to the given timeout
+// for space, and reports whether it did.
+func (q *queue[T]) OfferTimeout(t T, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return q.Put(ctx, t) == nil
+}
+
+// PollTimeout removes and returns the element at the head of the queue,
+// waiting up to the given timeout for one, and false if none arrived.
+func (q *queue[T]) PollTimeout(timeout time.Duration) (T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	t, err := q.Take(ctx)
+	return t, err == nil
+}
+
+// Peek returns the element at the head of the queue without removing it.
+// Panics if the queue is empty.
+func (q *queue[T]) Peek() T {
+	return q.mustPeek(q.c.Peek)
+}
+
+// Add adds the element to the queue without blocking. Panics if the queue is
+// full or closed.
+func (q *queue[T]) Add(t T) {
+	q.mustAdd(t, q.c.Add)
+}
+
+// Remove removes and returns the element at the head of the queue without
+// blocking. Panics if the queue is empty.
+func (q *queue[T]) Remove() T {
+	return q.mustRemove(q.c.Remove)
+}
+
+// AddAll adds all elements from the given sequence to the queue as Add does.
+// The sequence is consumed before any element is added, so it may be produced
+// by this queue.
+func (q *queue[T]) AddAll(sequence iter.Seq[T]) {
+	for _, t := range slices.Collect(sequence) {
+		q.Add(t)
+	}
+}
+
+// Clear removes all elements from the queue.
+func (q *queue[T]) Clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.c.Clear()
+	q.notFull.broadcast()
+}
+
+// Size returns the number of elements in the queue.
+func (q *queue[T]) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.c.Size()
+}
+
+// Empty returns true if the queue contains no elements.
+func (q *queue[T]) Empty() bool {
+	return q.Size() == 0
+}
+
+// RemainingCapacity returns the number of elements that can be added to the
+// queue without blocking, or math.MaxInt if it is unbounded.
+func (q *queue[T]) RemainingCapacity() int {
+	if q.maxCapacity == 0 {
+		return math.MaxInt
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.maxCapacity - q.c.Size()
+}
+
+// DrainTo removes up to max elements from the queue, or all of them if max is
+// negative, adds them to c in the order they were removed, and returns how
+// many it moved. The elements are removed with the lock held and added to c
+// after it is released, so c may be this queue. If adding to c panics, as it
+// does for a full bounded BlockingQueue or an ArrayDeque with RejectNew, the
+// panic propagates and the drained elements that c did not accept are lost.
+func (q *queue[T]) DrainTo(c collections.MutableCollection[T], max int) int {
+	q.mu.Lock()
+	n := q.c.Size()
+	if max >= 0 {
+		n = min(n, max)
+	}
+	drained := make([]T, n)
+	for i := range drained {
+		drained[i] = q.c.Remove()
+	}
+	if n > 0 {
+		q.notFull.broadcast()
+	}
+	q.mu.Unlock()
+	c.AddAll(slices.Values(drained))
+	return n
+}
+
+// Close closes the queue and wakes every goroutine blocked on it. Puts then
+// fail with ErrClosed, and takes fail with ErrClosed once the queue is empty.
+// Closing a closed queue has no effect.
+func (q *queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.broadcast()
+	q.notFull.broadcast()
+}
+
+// Closed returns true if the queue has been closed.
+func (q *queue[T]) Closed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// All returns an iterator over a snapshot of the elements of the queue taken
+// when iteration begins.
+func (q *queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		q.mu.Lock()
+		items := slices.AppendSeq(make([]T, 0, q.c.Size()), q.c.All())
+		q.mu.Unlock()
+		for _, t := range items {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// String returns a string representation of the queue.
+func (q *queue[T]) String() string {
+	str := make([]string, 0)
+	for t := range q.All() {
+		str = append(str, fmt.Sprintf("%+v", t))
+	}
+	return "[" + strings.Join(str, ", ") + "]"
+}
+
+func (q *queue[T]) full() bool {
+	return q.maxCapacity > 0 && q.c.Size() >= q.maxCapacity
+}
+
+// wait releases the lock until s is broadcast or ctx is done, and reacquires
+// it before returning.
+func (q *queue[T]) wait(ctx context.Context, s *signal) error {
+	ch := s.wait()
+	q.mu.Unlock()
+	defer q.mu.Lock()
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *queue[T]) put(ctx context.Context, t T, add func(T)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		if q.closed {
+			return ErrClosed
+		}
+		if !q.full() {
+			add(t)
+			q.notEmpty.broadcast()
+			return nil
+		}
+		if err := q.wait(ctx, &q.notFull); err != nil {
+			return err
+		}
+	}
+}
+
+func (q *queue[T]) take(ctx context.Context, remove func() T) (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		if !q.c.Empty() {
+			t := remove()
+			q.notFull.broadcast()
+			return t, nil
+		}
+		if q.closed {
+			var zero T
+			return zero, ErrClosed
+		}
+		if err := q.wait(ctx, &q.notEmpty); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+func (q *queue[T]) offer(t T, add func(T)) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.full() {
+		return false
+	}
+	add(t)
+	q.notEmpty.broadcast()
+	return true
+}
+
+func (q *queue[T]) poll(remove func() T) (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.c.Empty() {
+		var zero T
+		return zero, false
+	}
+	t := remove()
+	q.notFull.broadcast()
+	return t, true
+}
+
+func (q *queue[T]) mustAdd(t T, add func(T)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		panic("cannot add to a closed queue")
+	}
+	if q.full() {
+		panic("cannot add to a full queue")
+	}
+	add(t)
+	q.notEmpty.broadcast()
+}
+
+func (q *queue[T]) mustRemove(remove func() T) T {
+	t, ok := q.poll(remove)
+	if !ok {
+		panic("cannot remove from an empty queue")
+	}
+	return t
+}
+
+func (q *queue[T]) mustPeek(peek func() T) T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.c.Empty() {
+		panic("cannot peek from an empty queue")
+	}
+	return peek()
+}
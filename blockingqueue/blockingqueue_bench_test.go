@@ -0,0 +1,64 @@
+package blockingqueue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lock14/collections/blockingqueue"
+)
+
+func BenchmarkBlockingQueue_OfferPoll(b *testing.B) {
+	b.ReportAllocs()
+	q := blockingqueue.New[int](blockingqueue.WithMaxCapacity[int](1024))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Offer(i)
+		q.Poll()
+	}
+}
+
+func BenchmarkBlockingQueue_Handoff(b *testing.B) {
+	b.ReportAllocs()
+	q := blockingqueue.New[int](blockingqueue.WithMaxCapacity[int](64))
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < b.N; i++ {
+			if _, err := q.Take(ctx); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	}()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := q.Put(ctx, i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	<-done
+}
+
+func BenchmarkPriorityBlockingQueue_Handoff(b *testing.B) {
+	b.ReportAllocs()
+	q := blockingqueue.NewOrderedPriority[int](blockingqueue.WithMaxCapacity[int](64))
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < b.N; i++ {
+			if _, err := q.Take(ctx); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	}()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := q.Put(ctx, i); err != nil {
+			b.Fatal(err)
+		}
+	}
+	<-done
+}
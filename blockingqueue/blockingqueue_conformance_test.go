@@ -0,0 +1,23 @@
+package blockingqueue_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/blockingqueue"
+	"github.com/lock14/collections/collectionstest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableQueue(t, func() collections.MutableQueue[int] {
+		return blockingqueue.New[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.MutableDeque(t, func() collections.MutableDeque[int] {
+		return blockingqueue.NewDeque[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.PriorityQueue(t, func() collections.MutableQueue[int] {
+		return blockingqueue.NewOrderedPriority[int]()
+	}, cmp.Compare[int], collectionstest.Ints(10))
+}
@@ -0,0 +1,371 @@
+package blockingqueue
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/comparator"
+)
+
+// blocking is the interface shared by the three queues.
+type blocking interface {
+	Put(context.Context, int) error
+	Take(context.Context) (int, error)
+	Offer(int) bool
+	Poll() (int, bool)
+	Close()
+	Size() int
+}
+
+var queues = []struct {
+	name    string
+	bounded func(max int) blocking
+}{
+	{name: "queue", bounded: func(max int) blocking { return New[int](WithMaxCapacity[int](max)) }},
+	{name: "deque", bounded: func(max int) blocking { return NewDeque[int](WithMaxCapacity[int](max)) }},
+	{name: "priority", bounded: func(max int) blocking { return NewOrderedPriority[int](WithMaxCapacity[int](max)) }},
+}
+
+func TestPutBlocksWhileFull(t *testing.T) {
+	t.Parallel()
+	for _, tc := range queues {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			q := tc.bounded(1)
+			ctx := context.Background()
+			if err := q.Put(ctx, 1); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			done := make(chan error)
+			go func() { done <- q.Put(ctx, 2) }()
+			select {
+			case err := <-done:
+				t.Fatalf("Put into a full queue returned %v without blocking", err)
+			case <-time.After(10 * time.Millisecond):
+			}
+			if v, err := q.Take(ctx); err != nil || v != 1 {
+				t.Errorf("Take = %d, %v, want 1, nil", v, err)
+			}
+			if err := <-done; err != nil {
+				t.Errorf("blocked Put: %v", err)
+			}
+			if v, err := q.Take(ctx); err != nil || v != 2 {
+				t.Errorf("Take = %d, %v, want 2, nil", v, err)
+			}
+		})
+	}
+}
+
+func TestContextDone(t *testing.T) {
+	t.Parallel()
+	for _, tc := range queues {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			q := tc.bounded(1)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Take on empty queue: got %v, want %v", err, context.DeadlineExceeded)
+			}
+			q.Offer(1)
+			canceled, cancel := context.WithCancel(context.Background())
+			cancel()
+			if err := q.Put(canceled, 2); !errors.Is(err, context.Canceled) {
+				t.Errorf("Put on full queue: got %v, want %v", err, context.Canceled)
+			}
+			// A done context does not prevent an operation that need not block.
+			if v, err := q.Take(canceled); err != nil || v != 1 {
+				t.Errorf("Take = %d, %v, want 1, nil", v, err)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+	for _, tc := range queues {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			empty := tc.bounded(1)
+			full := tc.bounded(1)
+			full.Offer(1)
+			var wg sync.WaitGroup
+			errs := make(chan error, 4)
+			for i := 0; i < 2; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					_, err := empty.Take(ctx)
+					errs <- err
+				}()
+				go func() {
+					defer wg.Done()
+					errs <- full.Put(ctx, 2)
+				}()
+			}
+			time.Sleep(10 * time.Millisecond)
+			empty.Close()
+			full.Close()
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if !errors.Is(err, ErrClosed) {
+					t.Errorf("woken waiter: got %v, want %v", err, ErrClosed)
+				}
+			}
+			if full.Offer(3) {
+				t.Error("Offer to a closed queue succeeded")
+			}
+			// The elements of a closed queue can still be taken.
+			if v, err := full.Take(ctx); err != nil || v != 1 {
+				t.Errorf("Take = %d, %v, want 1, nil", v, err)
+			}
+			if _, err := full.Take(ctx); !errors.Is(err, ErrClosed) {
+				t.Errorf("Take on closed, empty queue: got %v, want %v", err, ErrClosed)
+			}
+		})
+	}
+}
+
+func TestOfferPollTimeout(t *testing.T) {
+	t.Parallel()
+	q := New[int](WithMaxCapacity[int](1))
+	if !q.OfferTimeout(1, time.Millisecond) {
+		t.Error("OfferTimeout into an empty queue failed")
+	}
+	if q.OfferTimeout(2, time.Millisecond) {
+		t.Error("OfferTimeout into a full queue succeeded")
+	}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		q.Poll()
+	}()
+	if !q.OfferTimeout(3, time.Second) {
+		t.Error("OfferTimeout failed although space was made")
+	}
+	if v, ok := q.PollTimeout(time.Millisecond); !ok || v != 3 {
+		t.Errorf("PollTimeout = %d, %t, want 3, true", v, ok)
+	}
+	if _, ok := q.PollTimeout(time.Millisecond); ok {
+		t.Error("PollTimeout on an empty queue succeeded")
+	}
+	if got := q.RemainingCapacity(); got != 1 {
+		t.Errorf("RemainingCapacity = %d, want 1", got)
+	}
+}
+
+func TestDrainTo(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		max   int
+		want  []int
+		left  int
+		input []int
+	}{
+		{name: "all", max: -1, input: []int{1, 2, 3}, want: []int{1, 2, 3}},
+		{name: "limited", max: 2, input: []int{1, 2, 3}, want: []int{1, 2}, left: 1},
+		{name: "zero", max: 0, input: []int{1, 2, 3}, want: []int{}, left: 3},
+		{name: "more_than_size", max: 10, input: []int{1, 2}, want: []int{1, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			q := New[int]()
+			q.AddAll(slices.Values(tc.input))
+			dst := arraylist.New[int]()
+			if n := q.DrainTo(dst, tc.max); n != len(tc.want) {
+				t.Errorf("DrainTo returned %d, want %d", n, len(tc.want))
+			}
+			if got := slices.Collect(dst.All()); !slices.Equal(got, tc.want) {
+				t.Errorf("drained %v, want %v", got, tc.want)
+			}
+			if q.Size() != tc.left {
+				t.Errorf("expected %d elements left, got %d", tc.left, q.Size())
+			}
+		})
+	}
+}
+
+func TestDrainToSelf(t *testing.T) {
+	t.Parallel()
+	q := New[int]()
+	q.AddAll(slices.Values([]int{1, 2, 3}))
+	if n := q.DrainTo(q, -1); n != 3 {
+		t.Errorf("DrainTo returned %d, want 3", n)
+	}
+	if got := slices.Collect(q.All()); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("got %v, want [1 2 3]", got)
+	}
+}
+
+func TestDrainToFullCollection(t *testing.T) {
+	t.Parallel()
+	q := New[int]()
+	q.AddAll(slices.Values([]int{1, 2, 3}))
+	dst := New(WithMaxCapacity[int](2))
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		q.DrainTo(dst, -1)
+	}()
+	if got := slices.Collect(dst.All()); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("drained %v, want [1 2]", got)
+	}
+	if !q.Empty() {
+		t.Errorf("expected the element dst rejected to be lost, got %v", slices.Collect(q.All()))
+	}
+}
+
+func TestDeque(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := NewDeque[int](WithMaxCapacity[int](3))
+	if err := d.PutFront(ctx, 2); err != nil {
+		t.Fatalf("PutFront: %v", err)
+	}
+	d.OfferFront(1)
+	d.Offer(3)
+	if d.OfferFront(0) {
+		t.Error("OfferFront into a full deque succeeded")
+	}
+	if got := d.String(); got != "[1, 2, 3]" {
+		t.Errorf("String = %s, want [1, 2, 3]", got)
+	}
+	if v, err := d.TakeBack(ctx); err != nil || v != 3 {
+		t.Errorf("TakeBack = %d, %v, want 3, nil", v, err)
+	}
+	if v, ok := d.PollBack(); !ok || v != 2 {
+		t.Errorf("PollBack = %d, %t, want 2, true", v, ok)
+	}
+	if v, err := d.Take(ctx); err != nil || v != 1 {
+		t.Errorf("Take = %d, %v, want 1, nil", v, err)
+	}
+	if _, ok := d.PollBack(); ok {
+		t.Error("PollBack on an empty deque succeeded")
+	}
+}
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+	q := NewPriority[string](WithComparator(comparator.Reverse(comparator.NaturalOrder[string]())))
+	q.AddAll(slices.Values([]string{"b", "d", "a", "c"}))
+	var got []string
+	for !q.Empty() {
+		v, _ := q.Poll()
+		got = append(got, v)
+	}
+	if want := []string{"d", "c", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{name: "zero_max_capacity", f: func() { New[int](WithMaxCapacity[int](0)) }},
+		{name: "no_comparator", f: func() { NewPriority[int]() }},
+		{name: "remove_empty", f: func() { New[int]().Remove() }},
+		{name: "peek_empty", f: func() { New[int]().Peek() }},
+		{name: "peek_back_empty", f: func() { NewDeque[int]().PeekBack() }},
+		{
+			name: "add_full",
+			f: func() {
+				q := New[int](WithMaxCapacity[int](1))
+				q.Add(1)
+				q.Add(2)
+			},
+		},
+		{
+			name: "add_closed",
+			f: func() {
+				q := NewDeque[int]()
+				q.Close()
+				q.AddFront(1)
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestProducersConsumers(t *testing.T) {
+	t.Parallel()
+	const producers, consumers, perProducer = 4, 4, 500
+	for _, tc := range queues {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			q := tc.bounded(8)
+			ctx := context.Background()
+			var producing, consuming sync.WaitGroup
+			sums := make([]int, consumers)
+			for p := 0; p < producers; p++ {
+				producing.Add(1)
+				go func() {
+					defer producing.Done()
+					for i := 1; i <= perProducer; i++ {
+						if err := q.Put(ctx, i); err != nil {
+							t.Errorf("Put: %v", err)
+						}
+					}
+				}()
+			}
+			for c := 0; c < consumers; c++ {
+				consuming.Add(1)
+				go func(c int) {
+					defer consuming.Done()
+					for {
+						v, err := q.Take(ctx)
+						if err != nil {
+							if !errors.Is(err, ErrClosed) {
+								t.Errorf("Take: %v", err)
+							}
+							return
+						}
+						sums[c] += v
+					}
+				}(c)
+			}
+			producing.Wait()
+			q.Close()
+			consuming.Wait()
+			total := 0
+			for _, s := range sums {
+				total += s
+			}
+			if want := producers * perProducer * (perProducer + 1) / 2; total != want {
+				t.Errorf("consumed a total of %d, want %d", total, want)
+			}
+			if q.Size() != 0 {
+				t.Errorf("expected an empty queue, got size %d", q.Size())
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package blockingqueue
+
+import (
+	"context"
+
+	"github.com/lock14/collections"
+)
+
+var _ collections.MutableDeque[int] = (*BlockingDeque[int])(nil)
+
+// BlockingDeque is a double-ended queue that is safe for concurrent use. The
+// queue methods Put, Take, Offer and Poll add at the back and remove from the
+// front, and each has a counterpart operating at the other end.
+type BlockingDeque[T any] struct {
+	queue[T]
+	d collections.MutableDeque[T]
+}
+
+// NewDeque creates an empty BlockingDeque with the given options.
+func NewDeque[T any](opts ...Option[T]) *BlockingDeque[T] {
+	config := newConfig(opts)
+	d := &BlockingDeque[T]{d: newArrayDeque[T](config)}
+	d.init(d.d, config)
+	return d
+}
+
+// PutFront adds the element to the front of the deque, blocking while the
+// deque is full. It returns ErrClosed if the deque is closed, or the context's
+// error if the context is done before there is space.
+func (d *BlockingDeque[T]) PutFront(ctx context.Context, t T) error {
+	return d.put(ctx, t, d.d.AddFront)
+}
+
+// TakeBack removes and returns the element at the back of the deque, blocking
+// while the deque is empty. It returns ErrClosed if the deque is closed and
+// empty, or the context's error if the context is done before there is an
+// element.
+func (d *BlockingDeque[T]) TakeBack(ctx context.Context) (T, error) {
+	return d.take(ctx, d.d.RemoveBack)
+}
+
+// OfferFront adds the element to the front of the deque if it has space and is
+// not closed, and reports whether it did.
+func (d *BlockingDeque[T]) OfferFront(t T) bool {
+	return d.offer(t, d.d.AddFront)
+}
+
+// PollBack removes and returns the element at the back of the deque, and false
+// if the deque is empty.
+func (d *BlockingDeque[T]) PollBack() (T, bool) {
+	return d.poll(d.d.RemoveBack)
+}
+
+// PeekFront returns the element at the front of the deque without removing it.
+// Panics if the deque is empty.
+func (d *BlockingDeque[T]) PeekFront() T {
+	return d.mustPeek(d.d.PeekFront)
+}
+
+// PeekBack returns the element at the back of the deque without removing it.
+// Panics if the deque is empty.
+func (d *BlockingDeque[T]) PeekBack() T {
+	return d.mustPeek(d.d.PeekBack)
+}
+
+// AddFront adds the element to the front of the deque without blocking. Panics
+// if the deque is full or closed.
+func (d *BlockingDeque[T]) AddFront(t T) {
+	d.mustAdd(t, d.d.AddFront)
+}
+
+// AddBack adds the element to the back of the deque without blocking. Panics
+// if the deque is full or closed.
+func (d *BlockingDeque[T]) AddBack(t T) {
+	d.mustAdd(t, d.d.AddBack)
+}
+
+// RemoveFront removes and returns the element at the front of the deque
+// without blocking. Panics if the deque is empty.
+func (d *BlockingDeque[T]) RemoveFront() T {
+	return d.mustRemove(d.d.RemoveFront)
+}
+
+// RemoveBack removes and returns the element at the back of the deque without
+// blocking. Panics if the deque is empty.
+func (d *BlockingDeque[T]) RemoveBack() T {
+	return d.mustRemove(d.d.RemoveBack)
+}
+
+// Push is an alias for AddFront.
+func (d *BlockingDeque[T]) Push(t T) {
+	d.AddFront(t)
+}
+
+// Pop is an alias for RemoveFront.
+func (d *BlockingDeque[T]) Pop() T {
+	return d.RemoveFront()
+}
@@ -0,0 +1,66 @@
+package blockingqueue_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lock14/collections/blockingqueue"
+)
+
+func ExampleBlockingQueue() {
+	// A bounded queue hands jobs from a producer to a pool of workers. Put
+	// blocks while the workers are behind, and Close tells them to stop once
+	// every job has been taken.
+	jobs := blockingqueue.New[int](blockingqueue.WithMaxCapacity[int](2))
+	results := blockingqueue.NewOrderedPriority[int]()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 3; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := jobs.Take(ctx)
+				if err != nil {
+					return
+				}
+				results.Add(job * job)
+			}
+		}()
+	}
+	for job := 1; job <= 5; job++ {
+		if err := jobs.Put(ctx, job); err != nil {
+			fmt.Println(err)
+		}
+	}
+	jobs.Close()
+	wg.Wait()
+
+	for !results.Empty() {
+		fmt.Println(results.Remove())
+	}
+	// Output:
+	// 1
+	// 4
+	// 9
+	// 16
+	// 25
+}
+
+func ExampleBlockingQueue_PollTimeout() {
+	q := blockingqueue.New[string]()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Offer("ready")
+	}()
+	fmt.Println(q.PollTimeout(time.Second))
+
+	_, ok := q.PollTimeout(time.Millisecond)
+	fmt.Println(ok)
+	// Output:
+	// ready true
+	// false
+}
@@ -0,0 +1,36 @@
+package blockingqueue
+
+import (
+	"cmp"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/heap"
+)
+
+var _ collections.MutableQueue[int] = (*PriorityBlockingQueue[int])(nil)
+
+// PriorityBlockingQueue is a priority queue that is safe for concurrent use.
+// Take, Poll and Remove return the least element according to the queue's
+// comparator, and All iterates in no particular order.
+type PriorityBlockingQueue[T any] struct {
+	queue[T]
+}
+
+// NewPriority creates an empty PriorityBlockingQueue with the given options.
+// A comparator must be provided with WithComparator.
+func NewPriority[T any](opts ...Option[T]) *PriorityBlockingQueue[T] {
+	config := newConfig(opts)
+	if config.comparator == nil {
+		panic("comparator must be provided or use an Ordered constructor")
+	}
+	q := &PriorityBlockingQueue[T]{}
+	q.init(heap.New[T](heap.WithComparator(config.comparator)), config)
+	return q
+}
+
+// NewOrderedPriority creates an empty PriorityBlockingQueue for types that
+// satisfy cmp.Ordered, ordered from least to greatest by natural ordering.
+func NewOrderedPriority[T cmp.Ordered](opts ...Option[T]) *PriorityBlockingQueue[T] {
+	return NewPriority(append(opts, WithComparator(comparator.NaturalOrder[T]()))...)
+}
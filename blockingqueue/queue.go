@@ -0,0 +1,31 @@
+package blockingqueue
+
+import (
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+)
+
+var _ collections.MutableQueue[int] = (*BlockingQueue[int])(nil)
+
+// BlockingQueue is a first-in-first-out queue that is safe for concurrent use.
+// Elements are added at the tail and taken from the head.
+type BlockingQueue[T any] struct {
+	queue[T]
+}
+
+// New creates an empty BlockingQueue with the given options.
+func New[T any](opts ...Option[T]) *BlockingQueue[T] {
+	config := newConfig(opts)
+	q := &BlockingQueue[T]{}
+	q.init(newArrayDeque[T](config), config)
+	return q
+}
+
+// newArrayDeque creates the ring buffer backing a BlockingQueue or
+// BlockingDeque, which never grows beyond the capacity of a bounded queue.
+func newArrayDeque[T any](config *config[T]) *arraydeque.ArrayDeque[T] {
+	if config.bounded {
+		return arraydeque.New[T](arraydeque.WithMaxCapacity(config.maxCapacity, arraydeque.RejectNew))
+	}
+	return arraydeque.New[T]()
+}
@@ -2,9 +2,16 @@
 package collections
 
 import (
+	"errors"
 	"iter"
 )
 
+// ErrConcurrentModification is the value iterators panic with when the
+// collection they are iterating over is structurally modified by anything
+// other than the iterator itself. The check can be compiled out with the
+// nofailfast build tag.
+var ErrConcurrentModification = errors.New("collection modified during iteration")
+
 // Iterable denotes a type that can be iterated over
 // by using the Iterator supplied using the All method.
 type Iterable[T any] interface {
@@ -49,6 +56,33 @@ type MutableList[T any] interface {
 	Set(idx int, t T)
 }
 
+// MutableIndexedList represents a mutable list that also supports insertion,
+// removal and search at arbitrary positions. Index arguments out of range panic.
+type MutableIndexedList[T any] interface {
+	MutableList[T]
+	// Insert inserts the specified element at the specified index, shifting later elements up.
+	Insert(idx int, t T)
+	// InsertAll inserts all elements from the given sequence, in order, at the specified index.
+	InsertAll(idx int, seq iter.Seq[T])
+	// RemoveAt removes and returns the element at the specified index, shifting later elements down.
+	RemoveAt(idx int) T
+	// RemoveRange removes the elements with indices in [from, to).
+	RemoveRange(from, to int)
+	// RemoveIf removes every element satisfying pred and returns the number removed.
+	RemoveIf(pred func(T) bool) int
+	// IndexOf returns the index of the first element satisfying pred, or -1 if there is none.
+	IndexOf(pred func(T) bool) int
+	// LastIndexOf returns the index of the last element satisfying pred, or -1 if there is none.
+	LastIndexOf(pred func(T) bool) int
+	// ContainsFunc returns true if any element satisfies pred.
+	ContainsFunc(pred func(T) bool) bool
+	// SubList returns a view of the elements with indices in [from, to). Changes
+	// made through the view are made to the list. Structurally modifying the list
+	// other than through the view invalidates it, and its next operation panics
+	// with ErrConcurrentModification.
+	SubList(from, to int) MutableIndexedList[T]
+}
+
 // Queue represents a collection designed for holding elements prior to processing.
 type Queue[T any] interface {
 	Collection[T]
@@ -280,3 +314,54 @@ type MutableNavigableMap[K any, V any] interface {
 	NavigableMap[K, V]
 	MutableSortedMap[K, V]
 }
+
+// Cursor is a position within a sequenced collection that can move in both
+// directions and remove the element it is on. A new cursor is positioned
+// outside the collection, where Next moves to the first element and Prev moves
+// to the last. Moving past either end returns the cursor to the outside.
+//
+// After Remove the cursor is left in the gap where the element was, so Next and
+// Prev move to the elements that followed and preceded it. Modifying the
+// collection other than through the cursor invalidates it, and its next
+// operation panics with ErrConcurrentModification.
+type Cursor[T any] interface {
+	// Next moves the cursor to the next element and reports whether there is one.
+	Next() bool
+	// Prev moves the cursor to the previous element and reports whether there is one.
+	Prev() bool
+	// Value returns the element at the cursor. Panics if the cursor is not on an element.
+	Value() T
+	// Remove removes the element at the cursor. Panics if the cursor is not on an element.
+	Remove()
+}
+
+// ListCursor is a Cursor that can also replace and insert elements. Elements
+// inserted before the cursor are visited by Prev and elements inserted after it
+// are visited by Next. When the cursor is outside the collection, InsertBefore
+// adds at the end and InsertAfter adds at the start.
+type ListCursor[T any] interface {
+	Cursor[T]
+	// Set replaces the element at the cursor. Panics if the cursor is not on an element.
+	Set(T)
+	// InsertBefore inserts the specified element immediately before the cursor.
+	InsertBefore(T)
+	// InsertAfter inserts the specified element immediately after the cursor.
+	InsertAfter(T)
+}
+
+// MapCursor is a Cursor over the entries of a sequenced map. It follows the
+// same positioning rules as Cursor.
+type MapCursor[K any, V any] interface {
+	// Next moves the cursor to the next entry and reports whether there is one.
+	Next() bool
+	// Prev moves the cursor to the previous entry and reports whether there is one.
+	Prev() bool
+	// Key returns the key of the entry at the cursor. Panics if the cursor is not on an entry.
+	Key() K
+	// Value returns the value of the entry at the cursor. Panics if the cursor is not on an entry.
+	Value() V
+	// SetValue replaces the value of the entry at the cursor. Panics if the cursor is not on an entry.
+	SetValue(V)
+	// Remove removes the entry at the cursor. Panics if the cursor is not on an entry.
+	Remove()
+}
@@ -0,0 +1,201 @@
+// Package collectionstest provides randomized, model-based conformance suites
+// for implementations of the interfaces in package collections.
+//
+// Each suite drives an implementation through random sequences of operations,
+// applies every operation to a simple reference model as well, and checks
+// that results and contents agree after every step. When a sequence fails, the
+// suite shrinks it to a minimal failing sequence before reporting it, together
+// with the seed needed to reproduce the run:
+//
+//	func TestConformance(t *testing.T) {
+//		collectionstest.MutableSet(t, func() collections.MutableSet[int] {
+//			return myset.New[int]()
+//		}, collectionstest.Ints(100))
+//	}
+//
+// As throughout this module, operations that have no element to return from an
+// empty collection, and index operations that are out of range, are expected to
+// panic.
+package collectionstest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// DefaultRuns is the number of random operation sequences each suite runs.
+	DefaultRuns = 100
+	// DefaultSteps is the number of operations in each sequence.
+	DefaultSteps = 100
+)
+
+// config holds the values for configuring a suite run.
+type config struct {
+	seed  int64
+	runs  int
+	steps int
+}
+
+// Option configures a suite run.
+type Option func(*config)
+
+// WithSeed configures the seed of the random source. By default the seed is
+// derived from the current time and reported on failure.
+func WithSeed(seed int64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+// WithRuns configures the number of random operation sequences to run.
+func WithRuns(runs int) Option {
+	return func(c *config) {
+		c.runs = runs
+	}
+}
+
+// WithSteps configures the number of operations in each sequence.
+func WithSteps(steps int) Option {
+	return func(c *config) {
+		c.steps = steps
+	}
+}
+
+// Gen generates random values for a suite.
+type Gen[T any] func(r *rand.Rand) T
+
+// Ints returns a Gen of ints in [0, n). Small ranges produce more collisions
+// between operations, which exercises more interesting cases.
+func Ints(n int) Gen[int] {
+	return func(r *rand.Rand) int {
+		return r.Intn(n)
+	}
+}
+
+// step is a single operation applied to an implementation and its model.
+type step[C any, M any] struct {
+	desc  string
+	apply func(c C, m M) error
+}
+
+// stepGen generates a random step.
+type stepGen[C any, M any] func(r *rand.Rand) step[C, M]
+
+// suite describes a conformance suite for implementations of type C checked
+// against models of type M.
+type suite[C any, M any] struct {
+	name  string
+	newC  func() C
+	newM  func() M
+	steps []stepGen[C, M]
+	check func(c C, m M) error
+}
+
+func run[C any, M any](tb testing.TB, s suite[C, M], opts []Option) {
+	tb.Helper()
+	config := &config{
+		seed:  time.Now().UnixNano(),
+		runs:  DefaultRuns,
+		steps: DefaultSteps,
+	}
+	for _, option := range opts {
+		option(config)
+	}
+	r := rand.New(rand.NewSource(config.seed))
+	for i := 0; i < config.runs; i++ {
+		ops := make([]step[C, M], config.steps)
+		for j := range ops {
+			ops[j] = s.steps[r.Intn(len(s.steps))](r)
+		}
+		if n, err := s.execute(ops); err != nil {
+			ops = s.shrink(ops[:n+1])
+			_, err = s.execute(ops)
+			tb.Errorf("%s contract violated (seed %d, run %d): %v\nminimal failing sequence:\n%s",
+				s.name, config.seed, i, err, format(ops))
+			return
+		}
+	}
+}
+
+// execute applies ops to a fresh implementation and model, and returns the
+// index of the first failing step along with its error.
+func (s suite[C, M]) execute(ops []step[C, M]) (i int, err error) {
+	c, m := s.newC(), s.newM()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unexpected panic in %s: %v", ops[i].desc, r)
+		}
+	}()
+	for i = range ops {
+		if err = ops[i].apply(c, m); err != nil {
+			return i, fmt.Errorf("%s: %w", ops[i].desc, err)
+		}
+		if err = s.check(c, m); err != nil {
+			return i, fmt.Errorf("after %s: %w", ops[i].desc, err)
+		}
+	}
+	return len(ops), nil
+}
+
+// shrink removes steps from a failing sequence for as long as it keeps failing,
+// first in large chunks and then one step at a time.
+func (s suite[C, M]) shrink(ops []step[C, M]) []step[C, M] {
+	for chunk := len(ops) / 2; chunk >= 1; {
+		removed := false
+		for i := 0; i+chunk <= len(ops); {
+			candidate := append(ops[:i:i], ops[i+chunk:]...)
+			if n, err := s.execute(candidate); err != nil {
+				ops = candidate[:n+1]
+				removed = true
+			} else {
+				i += chunk
+			}
+		}
+		if !removed {
+			chunk /= 2
+		}
+	}
+	return ops
+}
+
+func format[C any, M any](ops []step[C, M]) string {
+	var sb strings.Builder
+	for i, op := range ops {
+		fmt.Fprintf(&sb, "\t%d. %s\n", i+1, op.desc)
+	}
+	return sb.String()
+}
+
+// mustPanic returns an error if f does not panic.
+func mustPanic(f func()) (err error) {
+	defer func() {
+		if recover() == nil {
+			err = fmt.Errorf("expected a panic")
+		}
+	}()
+	f()
+	return nil
+}
+
+// index picks an index in [-1, size], so that both ends of the valid range and
+// one invalid index on each side are exercised.
+func index(raw, size int) int {
+	return raw%(size+2) - 1
+}
+
+// Strings returns a Gen of strings of up to maxLen bytes drawn from alphabet,
+// including the empty string. Short strings over a small alphabet share many
+// prefixes.
+func Strings(alphabet string, maxLen int) Gen[string] {
+	return func(r *rand.Rand) string {
+		b := make([]byte, r.Intn(maxLen+1))
+		for i := range b {
+			b[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+}
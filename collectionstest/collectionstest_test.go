@@ -0,0 +1,143 @@
+package collectionstest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+	"github.com/lock14/collections/hashset"
+	"github.com/lock14/collections/treemap"
+)
+
+// recorder is a testing.TB that records errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// forgetfulSet ignores requests to remove 7.
+type forgetfulSet struct {
+	*hashset.HashSet[int]
+}
+
+func (s forgetfulSet) RemoveElement(t int) {
+	if t != 7 {
+		s.HashSet.RemoveElement(t)
+	}
+}
+
+// backwardDeque removes from the front when asked to remove from the back.
+type backwardDeque struct {
+	*arraydeque.ArrayDeque[int]
+}
+
+func (d backwardDeque) RemoveBack() int {
+	return d.RemoveFront()
+}
+
+// offByOneMap returns the entry above the expected one from Floor.
+type offByOneMap struct {
+	*treemap.TreeMap[int, int]
+}
+
+func (m offByOneMap) Floor(k int) (int, int, bool) {
+	return m.Ceiling(k)
+}
+
+func TestReportsMinimalSequence(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		run      func(tb testing.TB, opts ...Option)
+		maxSteps int
+		wantLast string
+	}{
+		{
+			name: "correct set",
+			run: func(tb testing.TB, opts ...Option) {
+				MutableSet(tb, func() collections.MutableSet[int] { return hashset.New[int]() }, Ints(10), opts...)
+			},
+		},
+		{
+			name: "set ignoring removal",
+			run: func(tb testing.TB, opts ...Option) {
+				MutableSet(tb, func() collections.MutableSet[int] { return forgetfulSet{hashset.New[int]()} }, Ints(10), opts...)
+			},
+			maxSteps: 2,
+			wantLast: "RemoveElement(7)",
+		},
+		{
+			name: "deque removing from wrong end",
+			run: func(tb testing.TB, opts ...Option) {
+				MutableDeque(tb, func() collections.MutableDeque[int] { return backwardDeque{arraydeque.New[int]()} }, Ints(10), opts...)
+			},
+			maxSteps: 3,
+			wantLast: "RemoveBack()",
+		},
+		{
+			name: "map with wrong floor",
+			run: func(tb testing.TB, opts ...Option) {
+				MutableNavigableMap(tb, func() collections.MutableNavigableMap[int, int] {
+					return offByOneMap{treemap.NewOrdered[int, int]()}
+				}, func(a, b int) int { return a - b }, Ints(10), Ints(10), opts...)
+			},
+			maxSteps: 2,
+			wantLast: "Floor(",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := &recorder{TB: t}
+			tc.run(r, WithSeed(1))
+			if tc.wantLast == "" {
+				if len(r.errors) != 0 {
+					t.Fatalf("unexpected failure: %s", r.errors[0])
+				}
+				return
+			}
+			if len(r.errors) != 1 {
+				t.Fatalf("got %d errors, want 1", len(r.errors))
+			}
+			report := r.errors[0]
+			if !strings.Contains(report, "seed 1") {
+				t.Errorf("report does not include the seed:\n%s", report)
+			}
+			steps := strings.Split(strings.TrimSpace(report[strings.Index(report, "\n\t"):]), "\n")
+			if len(steps) > tc.maxSteps {
+				t.Errorf("got %d steps, want at most %d:\n%s", len(steps), tc.maxSteps, report)
+			}
+			if last := steps[len(steps)-1]; !strings.Contains(last, tc.wantLast) {
+				t.Errorf("last step is %q, want %q:\n%s", last, tc.wantLast, report)
+			}
+		})
+	}
+}
+
+func TestRunsAndSteps(t *testing.T) {
+	t.Parallel()
+	var sets, adds int
+	MutableSet(t, func() collections.MutableSet[int] {
+		sets++
+		return hashset.New[int]()
+	}, func(r *rand.Rand) int {
+		adds++
+		return r.Intn(10)
+	}, WithSeed(1), WithRuns(3), WithSteps(5))
+	if sets != 3 {
+		t.Errorf("created %d sets, want 3", sets)
+	}
+	if adds == 0 {
+		t.Errorf("generator was never called")
+	}
+}
@@ -0,0 +1,604 @@
+package collectionstest
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+)
+
+// sequence is the reference model for lists, queues, stacks and deques.
+type sequence[T any] struct {
+	elems []T
+}
+
+func newSequence[T any]() *sequence[T] {
+	return &sequence[T]{}
+}
+
+// MutableCollection checks the MutableCollection contract for collections that
+// allow duplicate elements. Neither the element returned by Remove nor the order
+// of All is checked, only that the collection holds the right elements.
+func MutableCollection[T comparable](t testing.TB, newCollection func() collections.MutableCollection[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableCollection[T], *sequence[T]]{
+		name:  "MutableCollection",
+		newC:  newCollection,
+		newM:  newSequence[T],
+		steps: collectionSteps[T, collections.MutableCollection[T]](gen),
+		check: checkBag[T, collections.MutableCollection[T]],
+	}, opts)
+}
+
+// MutableList checks the MutableList contract: Add and AddAll append, Get and
+// Set address elements by index and panic when it is out of range, and All
+// returns the elements in index order.
+func MutableList[T comparable](t testing.TB, newList func() collections.MutableList[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableList[T], *sequence[T]]{
+		name:  "MutableList",
+		newC:  newList,
+		newM:  newSequence[T],
+		steps: listSteps[T, collections.MutableList[T]](gen),
+		check: checkList[T, collections.MutableList[T]],
+	}, opts)
+}
+
+// MutableIndexedList checks the MutableIndexedList contract in addition to the
+// MutableList contract: Insert, InsertAll, RemoveAt and RemoveRange shift the
+// elements after the index, RemoveIf keeps the order of the rest, the search
+// methods find the first or last match, and SubList holds the elements of its
+// range.
+func MutableIndexedList[T comparable](t testing.TB, newList func() collections.MutableIndexedList[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableIndexedList[T], *sequence[T]]{
+		name:  "MutableIndexedList",
+		newC:  newList,
+		newM:  newSequence[T],
+		steps: indexedListSteps(gen),
+		check: checkList[T, collections.MutableIndexedList[T]],
+	}, opts)
+}
+
+// MutableStack checks the MutableStack contract: Pop and Peek return the most
+// recently pushed element that has not been popped.
+func MutableStack[T comparable](t testing.TB, newStack func() collections.MutableStack[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableStack[T], *sequence[T]]{
+		name:  "MutableStack",
+		newC:  newStack,
+		newM:  newSequence[T],
+		steps: stackSteps[T, collections.MutableStack[T]](gen),
+		check: checkBag[T, collections.MutableStack[T]],
+	}, opts)
+}
+
+// MutableQueue checks the MutableQueue contract for first-in-first-out queues:
+// Remove and Peek return the least recently added element that has not been
+// removed.
+func MutableQueue[T comparable](t testing.TB, newQueue func() collections.MutableQueue[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableQueue[T], *sequence[T]]{
+		name:  "MutableQueue",
+		newC:  newQueue,
+		newM:  newSequence[T],
+		steps: queueSteps[T, collections.MutableQueue[T]](gen),
+		check: checkBag[T, collections.MutableQueue[T]],
+	}, opts)
+}
+
+// PriorityQueue checks the MutableQueue contract for priority queues: Remove
+// and Peek return an element that is least according to comp.
+func PriorityQueue[T any](t testing.TB, newQueue func() collections.MutableQueue[T], comp comparator.Comparator[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableQueue[T], *sequence[T]]{
+		name:  "PriorityQueue",
+		newC:  newQueue,
+		newM:  newSequence[T],
+		steps: priorityQueueSteps(comp, gen),
+		check: func(c collections.MutableQueue[T], m *sequence[T]) error {
+			if err := checkSize(c, len(m.elems)); err != nil {
+				return err
+			}
+			got := slices.SortedFunc(c.All(), comp)
+			want := slices.SortedFunc(slices.Values(m.elems), comp)
+			if err := equalElements(got, want, func(a, b T) bool { return comp(a, b) == 0 }); err != nil {
+				return fmt.Errorf("All() in priority order: %w", err)
+			}
+			return nil
+		},
+	}, opts)
+}
+
+// MutableDeque checks the MutableDeque contract: elements are added and removed
+// at both ends, Add appends at the back, Push prepends at the front, Remove, Pop
+// and Peek operate on the front, and All returns the elements from front to back.
+func MutableDeque[T comparable](t testing.TB, newDeque func() collections.MutableDeque[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableDeque[T], *sequence[T]]{
+		name:  "MutableDeque",
+		newC:  newDeque,
+		newM:  newSequence[T],
+		steps: dequeSteps[T, collections.MutableDeque[T]](gen),
+		check: checkOrdered[T, collections.MutableDeque[T]],
+	}, opts)
+}
+
+func collectionSteps[T comparable, C collections.MutableCollection[T]](gen Gen[T]) []stepGen[C, *sequence[T]] {
+	add := func(r *rand.Rand) step[C, *sequence[T]] {
+		t := gen(r)
+		return step[C, *sequence[T]]{
+			desc: fmt.Sprintf("Add(%v)", t),
+			apply: func(c C, m *sequence[T]) error {
+				c.Add(t)
+				m.elems = append(m.elems, t)
+				return nil
+			},
+		}
+	}
+	return []stepGen[C, *sequence[T]]{
+		add,
+		add,
+		addAllStep[T, C](gen),
+		clearStep[T, C],
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			return step[C, *sequence[T]]{
+				desc: "Remove()",
+				apply: func(c C, m *sequence[T]) error {
+					if len(m.elems) == 0 {
+						return mustPanic(func() { c.Remove() })
+					}
+					t := c.Remove()
+					i := slices.Index(m.elems, t)
+					if i < 0 {
+						return fmt.Errorf("returned %v, which is not in the collection", t)
+					}
+					m.elems = slices.Delete(m.elems, i, i+1)
+					return nil
+				},
+			}
+		},
+	}
+}
+
+func listSteps[T comparable, C collections.MutableList[T]](gen Gen[T]) []stepGen[C, *sequence[T]] {
+	steps := collectionSteps[T, C](gen)
+	// Remove may take any element from a list, so find the one it took by
+	// comparing against the list's contents afterwards.
+	steps[len(steps)-1] = func(r *rand.Rand) step[C, *sequence[T]] {
+		return step[C, *sequence[T]]{
+			desc: "Remove()",
+			apply: func(c C, m *sequence[T]) error {
+				if len(m.elems) == 0 {
+					return mustPanic(func() { c.Remove() })
+				}
+				t := c.Remove()
+				got := slices.Collect(c.All())
+				for i, u := range m.elems {
+					if u == t && slices.Equal(got, slices.Delete(slices.Clone(m.elems), i, i+1)) {
+						m.elems = slices.Delete(m.elems, i, i+1)
+						return nil
+					}
+				}
+				return fmt.Errorf("returned %v, but the list went from %v to %v", t, m.elems, got)
+			},
+		}
+	}
+	return append(steps,
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			raw := r.Int()
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("Get(#%d)", raw),
+				apply: func(c C, m *sequence[T]) error {
+					i := index(raw, len(m.elems))
+					if i < 0 || i >= len(m.elems) {
+						return mustPanic(func() { c.Get(i) })
+					}
+					if got, want := c.Get(i), m.elems[i]; got != want {
+						return fmt.Errorf("Get(%d) = %v, want %v", i, got, want)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			raw, t := r.Int(), gen(r)
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("Set(#%d, %v)", raw, t),
+				apply: func(c C, m *sequence[T]) error {
+					i := index(raw, len(m.elems))
+					if i < 0 || i >= len(m.elems) {
+						return mustPanic(func() { c.Set(i, t) })
+					}
+					c.Set(i, t)
+					m.elems[i] = t
+					return nil
+				},
+			}
+		},
+	)
+}
+
+func indexedListSteps[T comparable](gen Gen[T]) []stepGen[collections.MutableIndexedList[T], *sequence[T]] {
+	type C = collections.MutableIndexedList[T]
+	is := func(t T) func(T) bool {
+		return func(u T) bool { return u == t }
+	}
+	search := func(name string, got func(C, func(T) bool) int, want func([]T, T) int) stepGen[C, *sequence[T]] {
+		return func(r *rand.Rand) step[C, *sequence[T]] {
+			t := gen(r)
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("%s(== %v)", name, t),
+				apply: func(c C, m *sequence[T]) error {
+					if got, want := got(c, is(t)), want(m.elems, t); got != want {
+						return fmt.Errorf("returned %d, want %d", got, want)
+					}
+					return nil
+				},
+			}
+		}
+	}
+	lastIndex := func(s []T, t T) int {
+		for i := len(s) - 1; i >= 0; i-- {
+			if s[i] == t {
+				return i
+			}
+		}
+		return -1
+	}
+	return append(listSteps[T, C](gen),
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			raw, t := r.Int(), gen(r)
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("Insert(#%d, %v)", raw, t),
+				apply: func(c C, m *sequence[T]) error {
+					i := index(raw, len(m.elems)+1)
+					if i < 0 || i > len(m.elems) {
+						return mustPanic(func() { c.Insert(i, t) })
+					}
+					c.Insert(i, t)
+					m.elems = slices.Insert(m.elems, i, t)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			raw, ts := r.Int(), values(r, gen)
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("InsertAll(#%d, %v)", raw, ts),
+				apply: func(c C, m *sequence[T]) error {
+					i := index(raw, len(m.elems)+1)
+					if i < 0 || i > len(m.elems) {
+						return mustPanic(func() { c.InsertAll(i, slices.Values(ts)) })
+					}
+					c.InsertAll(i, slices.Values(ts))
+					m.elems = slices.Insert(m.elems, i, ts...)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			raw := r.Int()
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("RemoveAt(#%d)", raw),
+				apply: func(c C, m *sequence[T]) error {
+					i := index(raw, len(m.elems))
+					if i < 0 || i >= len(m.elems) {
+						return mustPanic(func() { c.RemoveAt(i) })
+					}
+					if got, want := c.RemoveAt(i), m.elems[i]; got != want {
+						return fmt.Errorf("returned %v, want %v", got, want)
+					}
+					m.elems = slices.Delete(m.elems, i, i+1)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			rawFrom, rawTo := r.Int(), r.Int()
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("RemoveRange(#%d, #%d)", rawFrom, rawTo),
+				apply: func(c C, m *sequence[T]) error {
+					from, to := index(rawFrom, len(m.elems)+1), index(rawTo, len(m.elems)+1)
+					if from < 0 || from > to || to > len(m.elems) {
+						return mustPanic(func() { c.RemoveRange(from, to) })
+					}
+					c.RemoveRange(from, to)
+					m.elems = slices.Delete(m.elems, from, to)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			t := gen(r)
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("RemoveIf(== %v)", t),
+				apply: func(c C, m *sequence[T]) error {
+					before := len(m.elems)
+					m.elems = slices.DeleteFunc(m.elems, is(t))
+					if got, want := c.RemoveIf(is(t)), before-len(m.elems); got != want {
+						return fmt.Errorf("returned %d, want %d", got, want)
+					}
+					return nil
+				},
+			}
+		},
+		search("IndexOf", C.IndexOf, slices.Index[[]T]),
+		search("LastIndexOf", C.LastIndexOf, lastIndex),
+		search("ContainsFunc", func(c C, pred func(T) bool) int {
+			if c.ContainsFunc(pred) {
+				return 1
+			}
+			return 0
+		}, func(s []T, t T) int {
+			if slices.Contains(s, t) {
+				return 1
+			}
+			return 0
+		}),
+		func(r *rand.Rand) step[C, *sequence[T]] {
+			rawFrom, rawTo := r.Int(), r.Int()
+			return step[C, *sequence[T]]{
+				desc: fmt.Sprintf("SubList(#%d, #%d)", rawFrom, rawTo),
+				apply: func(c C, m *sequence[T]) error {
+					from, to := index(rawFrom, len(m.elems)+1), index(rawTo, len(m.elems)+1)
+					if from < 0 || from > to || to > len(m.elems) {
+						return mustPanic(func() { c.SubList(from, to) })
+					}
+					sub := c.SubList(from, to)
+					if err := checkList(sub, &sequence[T]{elems: m.elems[from:to]}); err != nil {
+						return fmt.Errorf("view: %w", err)
+					}
+					return nil
+				},
+			}
+		},
+	)
+}
+
+func stackSteps[T comparable, C collections.MutableStack[T]](gen Gen[T]) []stepGen[C, *sequence[T]] {
+	push := func(r *rand.Rand) step[C, *sequence[T]] {
+		t := gen(r)
+		return step[C, *sequence[T]]{
+			desc: fmt.Sprintf("Push(%v)", t),
+			apply: func(c C, m *sequence[T]) error {
+				c.Push(t)
+				m.elems = append(m.elems, t)
+				return nil
+			},
+		}
+	}
+	top := func(m *sequence[T]) T {
+		return m.elems[len(m.elems)-1]
+	}
+	return []stepGen[C, *sequence[T]]{
+		push,
+		push,
+		clearStep[T, C],
+		takeStep("Pop()", C.Pop, top, func(m *sequence[T]) {
+			m.elems = m.elems[:len(m.elems)-1]
+		}),
+		takeStep("Peek()", C.Peek, top, func(*sequence[T]) {}),
+	}
+}
+
+func queueSteps[T comparable, C collections.MutableQueue[T]](gen Gen[T]) []stepGen[C, *sequence[T]] {
+	steps := collectionSteps[T, C](gen)
+	front := func(m *sequence[T]) T {
+		return m.elems[0]
+	}
+	steps[len(steps)-1] = takeStep("Remove()", C.Remove, front, removeFront[T])
+	return append(steps, takeStep("Peek()", C.Peek, front, func(*sequence[T]) {}))
+}
+
+func priorityQueueSteps[T any](comp comparator.Comparator[T], gen Gen[T]) []stepGen[collections.MutableQueue[T], *sequence[T]] {
+	type C = collections.MutableQueue[T]
+	add := func(r *rand.Rand) step[C, *sequence[T]] {
+		t := gen(r)
+		return step[C, *sequence[T]]{
+			desc: fmt.Sprintf("Add(%v)", t),
+			apply: func(c C, m *sequence[T]) error {
+				c.Add(t)
+				m.elems = append(m.elems, t)
+				return nil
+			},
+		}
+	}
+	least := func(desc string, take func(C) T, remove bool) stepGen[C, *sequence[T]] {
+		return func(r *rand.Rand) step[C, *sequence[T]] {
+			return step[C, *sequence[T]]{
+				desc: desc,
+				apply: func(c C, m *sequence[T]) error {
+					if len(m.elems) == 0 {
+						return mustPanic(func() { take(c) })
+					}
+					got := take(c)
+					want := slices.MinFunc(m.elems, comp)
+					if comp(got, want) != 0 {
+						return fmt.Errorf("returned %v, want %v", got, want)
+					}
+					if remove {
+						i := slices.IndexFunc(m.elems, func(t T) bool { return comp(t, got) == 0 })
+						m.elems = slices.Delete(m.elems, i, i+1)
+					}
+					return nil
+				},
+			}
+		}
+	}
+	return []stepGen[C, *sequence[T]]{
+		add,
+		add,
+		addAllStep[T, C](gen),
+		clearStep[T, C],
+		least("Remove()", C.Remove, true),
+		least("Peek()", C.Peek, false),
+	}
+}
+
+func dequeSteps[T comparable, C collections.MutableDeque[T]](gen Gen[T]) []stepGen[C, *sequence[T]] {
+	front := func(m *sequence[T]) T {
+		return m.elems[0]
+	}
+	back := func(m *sequence[T]) T {
+		return m.elems[len(m.elems)-1]
+	}
+	removeBack := func(m *sequence[T]) {
+		m.elems = m.elems[:len(m.elems)-1]
+	}
+	peek := func(*sequence[T]) {}
+	return []stepGen[C, *sequence[T]]{
+		addStep("Add", C.Add, gen, appendBack[T]),
+		addStep("AddBack", C.AddBack, gen, appendBack[T]),
+		addStep("AddFront", C.AddFront, gen, prependFront[T]),
+		addStep("Push", C.Push, gen, prependFront[T]),
+		addAllStep[T, C](gen),
+		clearStep[T, C],
+		takeStep("Remove()", C.Remove, front, removeFront[T]),
+		takeStep("RemoveFront()", C.RemoveFront, front, removeFront[T]),
+		takeStep("RemoveBack()", C.RemoveBack, back, removeBack),
+		takeStep("Pop()", C.Pop, front, removeFront[T]),
+		takeStep("Peek()", C.Peek, front, peek),
+		takeStep("PeekFront()", C.PeekFront, front, peek),
+		takeStep("PeekBack()", C.PeekBack, back, peek),
+	}
+}
+
+func appendBack[T any](m *sequence[T], t T) {
+	m.elems = append(m.elems, t)
+}
+
+func prependFront[T any](m *sequence[T], t T) {
+	m.elems = slices.Insert(m.elems, 0, t)
+}
+
+func removeFront[T any](m *sequence[T]) {
+	m.elems = slices.Delete(m.elems, 0, 1)
+}
+
+// addStep generates a step that adds one element with add, and applies model to
+// the reference model.
+func addStep[T any, C any](name string, add func(C, T), gen Gen[T], model func(*sequence[T], T)) stepGen[C, *sequence[T]] {
+	return func(r *rand.Rand) step[C, *sequence[T]] {
+		t := gen(r)
+		return step[C, *sequence[T]]{
+			desc: fmt.Sprintf("%s(%v)", name, t),
+			apply: func(c C, m *sequence[T]) error {
+				add(c, t)
+				model(m, t)
+				return nil
+			},
+		}
+	}
+}
+
+// takeStep generates a step that calls take, which must panic on an empty
+// collection and otherwise return the element want picks from the model. The
+// model is then updated with remove.
+func takeStep[T comparable, C any](desc string, take func(C) T, want func(*sequence[T]) T, remove func(*sequence[T])) stepGen[C, *sequence[T]] {
+	return func(r *rand.Rand) step[C, *sequence[T]] {
+		return step[C, *sequence[T]]{
+			desc: desc,
+			apply: func(c C, m *sequence[T]) error {
+				if len(m.elems) == 0 {
+					return mustPanic(func() { take(c) })
+				}
+				if got, want := take(c), want(m); got != want {
+					return fmt.Errorf("returned %v, want %v", got, want)
+				}
+				remove(m)
+				return nil
+			},
+		}
+	}
+}
+
+func addAllStep[T any, C collections.MutableCollection[T]](gen Gen[T]) stepGen[C, *sequence[T]] {
+	return func(r *rand.Rand) step[C, *sequence[T]] {
+		ts := values(r, gen)
+		return step[C, *sequence[T]]{
+			desc: fmt.Sprintf("AddAll(%v)", ts),
+			apply: func(c C, m *sequence[T]) error {
+				c.AddAll(slices.Values(ts))
+				m.elems = append(m.elems, ts...)
+				return nil
+			},
+		}
+	}
+}
+
+func clearStep[T any, C collections.MutableCollection[T]](*rand.Rand) step[C, *sequence[T]] {
+	return step[C, *sequence[T]]{
+		desc: "Clear()",
+		apply: func(c C, m *sequence[T]) error {
+			c.Clear()
+			m.elems = nil
+			return nil
+		},
+	}
+}
+
+func checkSize[T any](c collections.Collection[T], want int) error {
+	if got := c.Size(); got != want {
+		return fmt.Errorf("Size() = %d, want %d", got, want)
+	}
+	if got := c.Empty(); got != (want == 0) {
+		return fmt.Errorf("Empty() = %t with %d elements", got, want)
+	}
+	return nil
+}
+
+// checkBag checks that c holds the elements of m in any order.
+func checkBag[T comparable, C collections.MutableCollection[T]](c C, m *sequence[T]) error {
+	if err := checkSize(c, len(m.elems)); err != nil {
+		return err
+	}
+	counts := make(map[T]int, len(m.elems))
+	for _, t := range m.elems {
+		counts[t]++
+	}
+	for t := range c.All() {
+		if counts[t]--; counts[t] < 0 {
+			return fmt.Errorf("All() returned %v more times than it was added", t)
+		}
+	}
+	for t, n := range counts {
+		if n > 0 {
+			return fmt.Errorf("All() is missing %v", t)
+		}
+	}
+	return nil
+}
+
+// checkOrdered checks that c holds the elements of m in the same order.
+func checkOrdered[T comparable, C collections.MutableCollection[T]](c C, m *sequence[T]) error {
+	if err := checkSize(c, len(m.elems)); err != nil {
+		return err
+	}
+	if err := equalElements(slices.Collect(c.All()), m.elems, equal[T]); err != nil {
+		return fmt.Errorf("All(): %w", err)
+	}
+	return nil
+}
+
+func checkList[T comparable, C collections.MutableList[T]](c C, m *sequence[T]) error {
+	if err := checkOrdered[T](c, m); err != nil {
+		return err
+	}
+	for i, want := range m.elems {
+		if got := c.Get(i); got != want {
+			return fmt.Errorf("Get(%d) = %v, want %v", i, got, want)
+		}
+	}
+	return nil
+}
+
+func equalElements[T any](got, want []T, eq func(a, b T) bool) error {
+	if !slices.EqualFunc(got, want, eq) {
+		return fmt.Errorf("iterated %v, want %v", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,469 @@
+package collectionstest
+
+import (
+	"fmt"
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/pair"
+)
+
+// mapModel is the reference model for maps.
+type mapModel[K any, V any] interface {
+	get(k K) (V, bool)
+	put(k K, v V)
+	remove(k K)
+	clear()
+	size() int
+	// entries returns the entries of the map, in order if the model is sorted.
+	entries() []pair.Pair[K, V]
+}
+
+type hashMapModel[K comparable, V any] map[K]V
+
+func (m hashMapModel[K, V]) get(k K) (V, bool) {
+	v, ok := m[k]
+	return v, ok
+}
+
+func (m hashMapModel[K, V]) put(k K, v V) { m[k] = v }
+func (m hashMapModel[K, V]) remove(k K)   { delete(m, k) }
+func (m hashMapModel[K, V]) clear()       { clear(m) }
+func (m hashMapModel[K, V]) size() int    { return len(m) }
+
+func (m hashMapModel[K, V]) entries() []pair.Pair[K, V] {
+	entries := make([]pair.Pair[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, pair.New(k, v))
+	}
+	return entries
+}
+
+type sortedMapModel[K any, V any] struct {
+	comp  comparator.Comparator[K]
+	pairs []pair.Pair[K, V]
+}
+
+func (m *sortedMapModel[K, V]) search(k K) (int, bool) {
+	return slices.BinarySearchFunc(m.pairs, k, func(e pair.Pair[K, V], k K) int {
+		return m.comp(e.Fst(), k)
+	})
+}
+
+func (m *sortedMapModel[K, V]) get(k K) (V, bool) {
+	if i, ok := m.search(k); ok {
+		return m.pairs[i].Snd(), true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *sortedMapModel[K, V]) put(k K, v V) {
+	if i, ok := m.search(k); ok {
+		m.pairs[i] = pair.New(m.pairs[i].Fst(), v)
+	} else {
+		m.pairs = slices.Insert(m.pairs, i, pair.New(k, v))
+	}
+}
+
+func (m *sortedMapModel[K, V]) remove(k K) {
+	if i, ok := m.search(k); ok {
+		m.pairs = slices.Delete(m.pairs, i, i+1)
+	}
+}
+
+func (m *sortedMapModel[K, V]) clear()                     { m.pairs = nil }
+func (m *sortedMapModel[K, V]) size() int                  { return len(m.pairs) }
+func (m *sortedMapModel[K, V]) entries() []pair.Pair[K, V] { return m.pairs }
+
+// MutableMap checks the MutableMap contract: Get and ContainsKey reflect the
+// latest Put for each key, and All, Keys and Values return every entry exactly
+// once.
+func MutableMap[K comparable, V comparable](t testing.TB, newMap func() collections.MutableMap[K, V], keys Gen[K], values Gen[V], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableMap[K, V], mapModel[K, V]]{
+		name:  "MutableMap",
+		newC:  newMap,
+		newM:  func() mapModel[K, V] { return hashMapModel[K, V]{} },
+		steps: mapSteps[K, V, collections.MutableMap[K, V]](keys, values),
+		check: checkMap[K, V, collections.MutableMap[K, V]],
+	}, opts)
+}
+
+// MutableSequencedMap checks the MutableSequencedMap contract. Since the
+// encounter order is up to the implementation, the suite checks that Keys,
+// Values, First, Last, the backward iterators, PollFirst and PollLast agree with
+// the order of All, and that PutFirst and PutLast put the entry at the
+// corresponding end.
+func MutableSequencedMap[K comparable, V comparable](t testing.TB, newMap func() collections.MutableSequencedMap[K, V], keys Gen[K], values Gen[V], opts ...Option) {
+	t.Helper()
+	type C = collections.MutableSequencedMap[K, V]
+	steps := append(mapSteps[K, V, C](keys, values),
+		mapEndStep("First()", C.First, nil, first, equal[K], false),
+		mapEndStep("Last()", C.Last, nil, last, equal[K], false),
+		mapEndStep("PollFirst()", C.PollFirst, nil, first, equal[K], true),
+		mapEndStep("PollLast()", C.PollLast, nil, last, equal[K], true),
+		putEndStep("PutFirst", C.PutFirst, C.First, keys, values),
+		putEndStep("PutLast", C.PutLast, C.Last, keys, values),
+	)
+	run(t, suite[C, mapModel[K, V]]{
+		name:  "MutableSequencedMap",
+		newC:  newMap,
+		newM:  func() mapModel[K, V] { return hashMapModel[K, V]{} },
+		steps: steps,
+		check: func(c C, m mapModel[K, V]) error {
+			if err := checkMap[K, V](c, m); err != nil {
+				return err
+			}
+			return checkSequencedMap[K, V](c, equal[K])
+		},
+	}, opts)
+}
+
+// MutableSortedMap checks the MutableSortedMap contract for maps ordered by
+// comp. PutFirst and PutLast are not exercised, since they cannot be honored by
+// a sorted map.
+func MutableSortedMap[K any, V comparable](t testing.TB, newMap func() collections.MutableSortedMap[K, V], comp comparator.Comparator[K], keys Gen[K], values Gen[V], opts ...Option) {
+	t.Helper()
+	run(t, sortedMapSuite("MutableSortedMap", newMap, comp, sortedMapSteps[K, V, collections.MutableSortedMap[K, V]](comp, keys, values)), opts)
+}
+
+// MutableNavigableMap checks the MutableNavigableMap contract for maps ordered
+// by comp, including Lower, Floor, Ceiling and Higher.
+func MutableNavigableMap[K any, V comparable](t testing.TB, newMap func() collections.MutableNavigableMap[K, V], comp comparator.Comparator[K], keys Gen[K], values Gen[V], opts ...Option) {
+	t.Helper()
+	type C = collections.MutableNavigableMap[K, V]
+	steps := append(sortedMapSteps[K, V, C](comp, keys, values),
+		navigateMapStep("Lower", C.Lower, comp, keys, func(i int, found bool) int { return i - 1 }),
+		navigateMapStep("Floor", C.Floor, comp, keys, func(i int, found bool) int {
+			if found {
+				return i
+			}
+			return i - 1
+		}),
+		navigateMapStep("Ceiling", C.Ceiling, comp, keys, func(i int, found bool) int { return i }),
+		navigateMapStep("Higher", C.Higher, comp, keys, func(i int, found bool) int {
+			if found {
+				return i + 1
+			}
+			return i
+		}),
+	)
+	run(t, sortedMapSuite("MutableNavigableMap", newMap, comp, steps), opts)
+}
+
+func sortedMapSuite[K any, V comparable, C collections.MutableSortedMap[K, V]](name string, newMap func() C, comp comparator.Comparator[K], steps []stepGen[C, mapModel[K, V]]) suite[C, mapModel[K, V]] {
+	eq := func(a, b K) bool { return comp(a, b) == 0 }
+	return suite[C, mapModel[K, V]]{
+		name:  name,
+		newC:  newMap,
+		newM:  func() mapModel[K, V] { return &sortedMapModel[K, V]{comp: comp} },
+		steps: steps,
+		check: func(c C, m mapModel[K, V]) error {
+			if err := checkMapSize(c, m.size()); err != nil {
+				return err
+			}
+			if err := equalEntries(c.All(), m.entries(), eq); err != nil {
+				return fmt.Errorf("All(): %w", err)
+			}
+			return checkSequencedMap[K, V](c, eq)
+		},
+	}
+}
+
+func mapSteps[K any, V comparable, C collections.MutableMap[K, V]](keys Gen[K], values Gen[V]) []stepGen[C, mapModel[K, V]] {
+	put := func(r *rand.Rand) step[C, mapModel[K, V]] {
+		k, v := keys(r), values(r)
+		return step[C, mapModel[K, V]]{
+			desc: fmt.Sprintf("Put(%v, %v)", k, v),
+			apply: func(c C, m mapModel[K, V]) error {
+				c.Put(k, v)
+				m.put(k, v)
+				return nil
+			},
+		}
+	}
+	return []stepGen[C, mapModel[K, V]]{
+		put,
+		put,
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			k := keys(r)
+			return step[C, mapModel[K, V]]{
+				desc: fmt.Sprintf("Remove(%v)", k),
+				apply: func(c C, m mapModel[K, V]) error {
+					c.Remove(k)
+					m.remove(k)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			k := keys(r)
+			return step[C, mapModel[K, V]]{
+				desc: fmt.Sprintf("Get(%v)", k),
+				apply: func(c C, m mapModel[K, V]) error {
+					got, gotOK := c.Get(k)
+					want, wantOK := m.get(k)
+					if gotOK != wantOK || (wantOK && got != want) {
+						return fmt.Errorf("returned %v, %t, want %v, %t", got, gotOK, want, wantOK)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			k := keys(r)
+			return step[C, mapModel[K, V]]{
+				desc: fmt.Sprintf("ContainsKey(%v)", k),
+				apply: func(c C, m mapModel[K, V]) error {
+					_, want := m.get(k)
+					if got := c.ContainsKey(k); got != want {
+						return fmt.Errorf("returned %t, want %t", got, want)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			return step[C, mapModel[K, V]]{
+				desc: "Clear()",
+				apply: func(c C, m mapModel[K, V]) error {
+					c.Clear()
+					m.clear()
+					return nil
+				},
+			}
+		},
+	}
+}
+
+func sortedMapSteps[K any, V comparable, C collections.MutableSortedMap[K, V]](comp comparator.Comparator[K], keys Gen[K], values Gen[V]) []stepGen[C, mapModel[K, V]] {
+	eq := func(a, b K) bool { return comp(a, b) == 0 }
+	model := func(m mapModel[K, V]) []pair.Pair[K, V] { return m.entries() }
+	rangeStep := func(desc string, seq func(C) iter.Seq2[K, V], in func(K) bool) step[C, mapModel[K, V]] {
+		return step[C, mapModel[K, V]]{
+			desc: desc,
+			apply: func(c C, m mapModel[K, V]) error {
+				want := slices.DeleteFunc(slices.Clone(m.entries()), func(e pair.Pair[K, V]) bool {
+					return !in(e.Fst())
+				})
+				return equalEntries(seq(c), want, eq)
+			},
+		}
+	}
+	return append(mapSteps[K, V, C](keys, values),
+		mapEndStep("First()", C.First, model, first, eq, false),
+		mapEndStep("Last()", C.Last, model, last, eq, false),
+		mapEndStep("PollFirst()", C.PollFirst, model, first, eq, true),
+		mapEndStep("PollLast()", C.PollLast, model, last, eq, true),
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			from := keys(r)
+			return rangeStep(fmt.Sprintf("From(%v)", from),
+				func(c C) iter.Seq2[K, V] { return c.From(from) },
+				func(k K) bool { return comp(k, from) >= 0 })
+		},
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			to := keys(r)
+			return rangeStep(fmt.Sprintf("To(%v)", to),
+				func(c C) iter.Seq2[K, V] { return c.To(to) },
+				func(k K) bool { return comp(k, to) < 0 })
+		},
+		func(r *rand.Rand) step[C, mapModel[K, V]] {
+			from, to := keys(r), keys(r)
+			return rangeStep(fmt.Sprintf("Between(%v, %v)", from, to),
+				func(c C) iter.Seq2[K, V] { return c.Between(from, to) },
+				func(k K) bool { return comp(k, from) >= 0 && comp(k, to) < 0 })
+		},
+	)
+}
+
+// navigateMapStep is the map counterpart of navigateStep.
+func navigateMapStep[K any, V comparable, C any](name string, navigate func(C, K) (K, V, bool), comp comparator.Comparator[K], keys Gen[K], pick func(i int, found bool) int) stepGen[C, mapModel[K, V]] {
+	return func(r *rand.Rand) step[C, mapModel[K, V]] {
+		k := keys(r)
+		return step[C, mapModel[K, V]]{
+			desc: fmt.Sprintf("%s(%v)", name, k),
+			apply: func(c C, m mapModel[K, V]) error {
+				entries := m.entries()
+				i, found := slices.BinarySearchFunc(entries, k, func(e pair.Pair[K, V], k K) int {
+					return comp(e.Fst(), k)
+				})
+				i = pick(i, found)
+				gotK, gotV, ok := navigate(c, k)
+				switch {
+				case i < 0 || i >= len(entries):
+					if ok {
+						return fmt.Errorf("returned %v, %v, true, want no entry", gotK, gotV)
+					}
+				case !ok || comp(gotK, entries[i].Fst()) != 0 || gotV != entries[i].Snd():
+					return fmt.Errorf("returned %v, %v, %t, want %v, %v, true", gotK, gotV, ok, entries[i].Fst(), entries[i].Snd())
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// mapEndStep generates a step that checks a method returning the first or last
+// entry, and removes it from the model if poll is set. The expected entry is
+// taken from order, or from All when order is nil.
+func mapEndStep[K any, V comparable, C collections.MutableSequencedMap[K, V]](desc string, take func(C) (K, V), order func(mapModel[K, V]) []pair.Pair[K, V], end func([]pair.Pair[K, V]) pair.Pair[K, V], eq func(a, b K) bool, poll bool) stepGen[C, mapModel[K, V]] {
+	return func(r *rand.Rand) step[C, mapModel[K, V]] {
+		return step[C, mapModel[K, V]]{
+			desc: desc,
+			apply: func(c C, m mapModel[K, V]) error {
+				if m.size() == 0 {
+					return mustPanic(func() { take(c) })
+				}
+				var entries []pair.Pair[K, V]
+				if order != nil {
+					entries = order(m)
+				} else {
+					entries = collectEntries(c.All())
+				}
+				want := end(entries)
+				if k, v := take(c); !eq(k, want.Fst()) || v != want.Snd() {
+					return fmt.Errorf("returned %v, %v, want %v", k, v, want)
+				}
+				if poll {
+					m.remove(want.Fst())
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// putEndStep generates a step that puts an entry at one end of a sequenced map
+// and checks that end afterwards.
+func putEndStep[K comparable, V comparable, C collections.MutableSequencedMap[K, V]](name string, put func(C, K, V), end func(C) (K, V), keys Gen[K], values Gen[V]) stepGen[C, mapModel[K, V]] {
+	return func(r *rand.Rand) step[C, mapModel[K, V]] {
+		k, v := keys(r), values(r)
+		return step[C, mapModel[K, V]]{
+			desc: fmt.Sprintf("%s(%v, %v)", name, k, v),
+			apply: func(c C, m mapModel[K, V]) error {
+				put(c, k, v)
+				m.put(k, v)
+				if gotK, gotV := end(c); gotK != k || gotV != v {
+					return fmt.Errorf("end of map is %v, %v, want %v, %v", gotK, gotV, k, v)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+func checkMapSize[K any, V any](c collections.Map[K, V], want int) error {
+	if got := c.Size(); got != want {
+		return fmt.Errorf("Size() = %d, want %d", got, want)
+	}
+	if got := c.Empty(); got != (want == 0) {
+		return fmt.Errorf("Empty() = %t with %d entries", got, want)
+	}
+	return nil
+}
+
+// checkMap checks that c holds exactly the entries of m, and that Keys and
+// Values agree with All.
+func checkMap[K comparable, V comparable, C collections.MutableMap[K, V]](c C, m mapModel[K, V]) error {
+	if err := checkMapSize(c, m.size()); err != nil {
+		return err
+	}
+	seen := make(map[K]bool, m.size())
+	for k, v := range c.All() {
+		if want, ok := m.get(k); !ok || want != v {
+			return fmt.Errorf("All() returned %v, %v, which is not in the map", k, v)
+		}
+		if seen[k] {
+			return fmt.Errorf("All() returned key %v more than once", k)
+		}
+		seen[k] = true
+	}
+	if len(seen) != m.size() {
+		return fmt.Errorf("All() returned %d entries, want %d", len(seen), m.size())
+	}
+	counts := make(map[V]int, m.size())
+	for _, e := range m.entries() {
+		counts[e.Snd()]++
+	}
+	for k := range c.Keys() {
+		if !seen[k] {
+			return fmt.Errorf("Keys() returned %v, which is not in the map", k)
+		}
+		delete(seen, k)
+	}
+	if len(seen) != 0 {
+		return fmt.Errorf("Keys() is missing %d keys", len(seen))
+	}
+	for v := range c.Values() {
+		if counts[v]--; counts[v] < 0 {
+			return fmt.Errorf("Values() returned %v too many times", v)
+		}
+	}
+	for v, n := range counts {
+		if n > 0 {
+			return fmt.Errorf("Values() is missing %v", v)
+		}
+	}
+	return nil
+}
+
+// checkSequencedMap checks that Keys, Values, First, Last and the backward
+// iterators agree with All.
+func checkSequencedMap[K any, V comparable](c collections.SequencedMap[K, V], eq func(a, b K) bool) error {
+	entries := collectEntries(c.All())
+	keys := make([]K, len(entries))
+	vals := make([]V, len(entries))
+	for i, e := range entries {
+		keys[i], vals[i] = e.Unwrap()
+	}
+	if got := slices.Collect(c.Keys()); !slices.EqualFunc(got, keys, eq) {
+		return fmt.Errorf("Keys() = %v, want %v", got, keys)
+	}
+	if got := slices.Collect(c.Values()); !slices.Equal(got, vals) {
+		return fmt.Errorf("Values() = %v, want %v", got, vals)
+	}
+	if len(entries) > 0 {
+		if k, v := c.First(); !eq(k, first(keys)) || v != first(vals) {
+			return fmt.Errorf("First() = %v, %v, want %v", k, v, first(entries))
+		}
+		if k, v := c.Last(); !eq(k, last(keys)) || v != last(vals) {
+			return fmt.Errorf("Last() = %v, %v, want %v", k, v, last(entries))
+		}
+	}
+	slices.Reverse(entries)
+	slices.Reverse(keys)
+	slices.Reverse(vals)
+	if err := equalEntries(c.Backward(), entries, eq); err != nil {
+		return fmt.Errorf("Backward(): %w", err)
+	}
+	if got := slices.Collect(c.BackwardKeys()); !slices.EqualFunc(got, keys, eq) {
+		return fmt.Errorf("BackwardKeys() = %v, want %v", got, keys)
+	}
+	if got := slices.Collect(c.BackwardValues()); !slices.Equal(got, vals) {
+		return fmt.Errorf("BackwardValues() = %v, want %v", got, vals)
+	}
+	return nil
+}
+
+func collectEntries[K any, V any](seq iter.Seq2[K, V]) []pair.Pair[K, V] {
+	var entries []pair.Pair[K, V]
+	for k, v := range seq {
+		entries = append(entries, pair.New(k, v))
+	}
+	return entries
+}
+
+func equalEntries[K any, V comparable](seq iter.Seq2[K, V], want []pair.Pair[K, V], eq func(a, b K) bool) error {
+	got := collectEntries(seq)
+	if !slices.EqualFunc(got, want, func(a, b pair.Pair[K, V]) bool {
+		return eq(a.Fst(), b.Fst()) && a.Snd() == b.Snd()
+	}) {
+		return fmt.Errorf("iterated %v, want %v", got, want)
+	}
+	return nil
+}
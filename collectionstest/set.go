@@ -0,0 +1,523 @@
+package collectionstest
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/comparator"
+)
+
+// setModel is the reference model for sets.
+type setModel[T any] interface {
+	contains(t T) bool
+	add(t T)
+	remove(t T)
+	clear()
+	size() int
+	// elements returns the elements of the set, in order if the model is sorted.
+	elements() []T
+	// empty returns a new, empty model of the same kind.
+	empty() setModel[T]
+}
+
+type hashModel[T comparable] map[T]struct{}
+
+func (m hashModel[T]) contains(t T) bool {
+	_, ok := m[t]
+	return ok
+}
+
+func (m hashModel[T]) add(t T)    { m[t] = struct{}{} }
+func (m hashModel[T]) remove(t T) { delete(m, t) }
+func (m hashModel[T]) clear()     { clear(m) }
+func (m hashModel[T]) size() int  { return len(m) }
+
+func (m hashModel[T]) elements() []T {
+	elems := make([]T, 0, len(m))
+	for t := range m {
+		elems = append(elems, t)
+	}
+	return elems
+}
+
+func (m hashModel[T]) empty() setModel[T] {
+	return hashModel[T]{}
+}
+
+type sortedModel[T any] struct {
+	comp  comparator.Comparator[T]
+	elems []T
+}
+
+func (m *sortedModel[T]) search(t T) (int, bool) {
+	return slices.BinarySearchFunc(m.elems, t, m.comp)
+}
+
+func (m *sortedModel[T]) contains(t T) bool {
+	_, ok := m.search(t)
+	return ok
+}
+
+func (m *sortedModel[T]) add(t T) {
+	if i, ok := m.search(t); !ok {
+		m.elems = slices.Insert(m.elems, i, t)
+	}
+}
+
+func (m *sortedModel[T]) remove(t T) {
+	if i, ok := m.search(t); ok {
+		m.elems = slices.Delete(m.elems, i, i+1)
+	}
+}
+
+func (m *sortedModel[T]) clear()        { m.elems = nil }
+func (m *sortedModel[T]) size() int     { return len(m.elems) }
+func (m *sortedModel[T]) elements() []T { return m.elems }
+
+func (m *sortedModel[T]) empty() setModel[T] {
+	return &sortedModel[T]{comp: m.comp}
+}
+
+// MutableSet checks the MutableSet contract: the set never holds duplicates,
+// Remove takes any element, and the bulk operations behave as set difference,
+// intersection and inclusion, including when passed the set itself.
+func MutableSet[T comparable](t testing.TB, newSet func() collections.MutableSet[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, suite[collections.MutableSet[T], setModel[T]]{
+		name:  "MutableSet",
+		newC:  newSet,
+		newM:  func() setModel[T] { return hashModel[T]{} },
+		steps: setSteps[T, collections.MutableSet[T]](gen),
+		check: checkSet[T, collections.MutableSet[T]],
+	}, opts)
+}
+
+// MutableSequencedSet checks the MutableSequencedSet contract. Since the
+// encounter order is up to the implementation, the suite checks that First,
+// Last, Backward, PollFirst and PollLast agree with the order of All, and that
+// AddFirst and AddLast put the element at the corresponding end.
+func MutableSequencedSet[T comparable](t testing.TB, newSet func() collections.MutableSequencedSet[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	type C = collections.MutableSequencedSet[T]
+	steps := append(setSteps[T, C](gen),
+		endStep("First()", C.First, first[T], false),
+		endStep("Last()", C.Last, last[T], false),
+		endStep("PollFirst()", C.PollFirst, first[T], true),
+		endStep("PollLast()", C.PollLast, last[T], true),
+		addEndStep("AddFirst", C.AddFirst, C.First, gen),
+		addEndStep("AddLast", C.AddLast, C.Last, gen),
+	)
+	run(t, suite[C, setModel[T]]{
+		name:  "MutableSequencedSet",
+		newC:  newSet,
+		newM:  func() setModel[T] { return hashModel[T]{} },
+		steps: steps,
+		check: func(c C, m setModel[T]) error {
+			if err := checkSet[T](c, m); err != nil {
+				return err
+			}
+			return checkSequenced[T](c, equal[T])
+		},
+	}, opts)
+}
+
+// MutableSortedSet checks the MutableSortedSet contract for sets ordered by
+// comp. AddFirst and AddLast are not exercised, since they cannot be honored by
+// a sorted set.
+func MutableSortedSet[T any](t testing.TB, newSet func() collections.MutableSortedSet[T], comp comparator.Comparator[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	run(t, sortedSetSuite("MutableSortedSet", newSet, comp, sortedSetSteps[T, collections.MutableSortedSet[T]](comp, gen)), opts)
+}
+
+// MutableNavigableSet checks the MutableNavigableSet contract for sets ordered
+// by comp, including Lower, Floor, Ceiling and Higher.
+func MutableNavigableSet[T any](t testing.TB, newSet func() collections.MutableNavigableSet[T], comp comparator.Comparator[T], gen Gen[T], opts ...Option) {
+	t.Helper()
+	type C = collections.MutableNavigableSet[T]
+	steps := append(sortedSetSteps[T, C](comp, gen),
+		navigateStep("Lower", C.Lower, comp, gen, func(i int, found bool) int { return i - 1 }),
+		navigateStep("Floor", C.Floor, comp, gen, func(i int, found bool) int {
+			if found {
+				return i
+			}
+			return i - 1
+		}),
+		navigateStep("Ceiling", C.Ceiling, comp, gen, func(i int, found bool) int { return i }),
+		navigateStep("Higher", C.Higher, comp, gen, func(i int, found bool) int {
+			if found {
+				return i + 1
+			}
+			return i
+		}),
+	)
+	run(t, sortedSetSuite("MutableNavigableSet", newSet, comp, steps), opts)
+}
+
+func sortedSetSuite[T any, C collections.MutableSortedSet[T]](name string, newSet func() C, comp comparator.Comparator[T], steps []stepGen[C, setModel[T]]) suite[C, setModel[T]] {
+	eq := func(a, b T) bool { return comp(a, b) == 0 }
+	return suite[C, setModel[T]]{
+		name:  name,
+		newC:  newSet,
+		newM:  func() setModel[T] { return &sortedModel[T]{comp: comp} },
+		steps: steps,
+		check: func(c C, m setModel[T]) error {
+			if err := checkSize(c, m.size()); err != nil {
+				return err
+			}
+			if err := equalElements(slices.Collect(c.All()), m.elements(), eq); err != nil {
+				return fmt.Errorf("All(): %w", err)
+			}
+			return checkSequenced[T](c, eq)
+		},
+	}
+}
+
+func setSteps[T any, C collections.MutableSet[T]](gen Gen[T]) []stepGen[C, setModel[T]] {
+	add := func(r *rand.Rand) step[C, setModel[T]] {
+		t := gen(r)
+		return step[C, setModel[T]]{
+			desc: fmt.Sprintf("Add(%v)", t),
+			apply: func(c C, m setModel[T]) error {
+				c.Add(t)
+				m.add(t)
+				return nil
+			},
+		}
+	}
+	return []stepGen[C, setModel[T]]{
+		add,
+		add,
+		func(r *rand.Rand) step[C, setModel[T]] {
+			ts := values(r, gen)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("AddAll(%v)", ts),
+				apply: func(c C, m setModel[T]) error {
+					c.AddAll(slices.Values(ts))
+					for _, t := range ts {
+						m.add(t)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			t := gen(r)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("RemoveElement(%v)", t),
+				apply: func(c C, m setModel[T]) error {
+					c.RemoveElement(t)
+					m.remove(t)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			return step[C, setModel[T]]{
+				desc: "Remove()",
+				apply: func(c C, m setModel[T]) error {
+					if m.size() == 0 {
+						return mustPanic(func() { c.Remove() })
+					}
+					t := c.Remove()
+					if !m.contains(t) {
+						return fmt.Errorf("returned %v, which is not in the set", t)
+					}
+					m.remove(t)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			t := gen(r)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("Contains(%v)", t),
+				apply: func(c C, m setModel[T]) error {
+					if got, want := c.Contains(t), m.contains(t); got != want {
+						return fmt.Errorf("returned %t, want %t", got, want)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			ts := values(r, gen)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("ContainsAll(%v)", ts),
+				apply: func(c C, m setModel[T]) error {
+					want := !slices.ContainsFunc(ts, func(t T) bool { return !m.contains(t) })
+					if got := c.ContainsAll(arraylist.Wrap(ts)); got != want {
+						return fmt.Errorf("returned %t, want %t", got, want)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			ts := values(r, gen)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("RemoveAll(%v)", ts),
+				apply: func(c C, m setModel[T]) error {
+					c.RemoveAll(arraylist.Wrap(ts))
+					for _, t := range ts {
+						m.remove(t)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			ts := values(r, gen)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("RetainAll(%v)", ts),
+				apply: func(c C, m setModel[T]) error {
+					c.RetainAll(arraylist.Wrap(ts))
+					retain := m.empty()
+					for _, t := range ts {
+						if m.contains(t) {
+							retain.add(t)
+						}
+					}
+					m.clear()
+					for _, t := range retain.elements() {
+						m.add(t)
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			return step[C, setModel[T]]{
+				desc: "RemoveAll(self)",
+				apply: func(c C, m setModel[T]) error {
+					c.RemoveAll(c)
+					m.clear()
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			return step[C, setModel[T]]{
+				desc: "RetainAll(self)",
+				apply: func(c C, m setModel[T]) error {
+					c.RetainAll(c)
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			return step[C, setModel[T]]{
+				desc: "ContainsAll(self)",
+				apply: func(c C, m setModel[T]) error {
+					if !c.ContainsAll(c) {
+						return fmt.Errorf("returned false")
+					}
+					return nil
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			return step[C, setModel[T]]{
+				desc: "Clear()",
+				apply: func(c C, m setModel[T]) error {
+					c.Clear()
+					m.clear()
+					return nil
+				},
+			}
+		},
+	}
+}
+
+func sortedSetSteps[T any, C collections.MutableSortedSet[T]](comp comparator.Comparator[T], gen Gen[T]) []stepGen[C, setModel[T]] {
+	eq := func(a, b T) bool { return comp(a, b) == 0 }
+	model := func(m setModel[T]) []T { return m.elements() }
+	between := func(m setModel[T], from, to T) []T {
+		var want []T
+		for _, t := range m.elements() {
+			if comp(t, from) >= 0 && comp(t, to) < 0 {
+				want = append(want, t)
+			}
+		}
+		return want
+	}
+	return append(setSteps[T, C](gen),
+		sortedEndStep("First()", C.First, model, first[T], eq, false),
+		sortedEndStep("Last()", C.Last, model, last[T], eq, false),
+		sortedEndStep("PollFirst()", C.PollFirst, model, first[T], eq, true),
+		sortedEndStep("PollLast()", C.PollLast, model, last[T], eq, true),
+		func(r *rand.Rand) step[C, setModel[T]] {
+			from := gen(r)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("From(%v)", from),
+				apply: func(c C, m setModel[T]) error {
+					want := slices.DeleteFunc(slices.Clone(m.elements()), func(t T) bool { return comp(t, from) < 0 })
+					return equalElements(slices.Collect(c.From(from)), want, eq)
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			to := gen(r)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("To(%v)", to),
+				apply: func(c C, m setModel[T]) error {
+					want := slices.DeleteFunc(slices.Clone(m.elements()), func(t T) bool { return comp(t, to) >= 0 })
+					return equalElements(slices.Collect(c.To(to)), want, eq)
+				},
+			}
+		},
+		func(r *rand.Rand) step[C, setModel[T]] {
+			from, to := gen(r), gen(r)
+			return step[C, setModel[T]]{
+				desc: fmt.Sprintf("Between(%v, %v)", from, to),
+				apply: func(c C, m setModel[T]) error {
+					return equalElements(slices.Collect(c.Between(from, to)), between(m, from, to), eq)
+				},
+			}
+		},
+	)
+}
+
+// navigateStep generates a step that checks a navigation method such as Floor.
+// pick maps the result of a binary search for the argument in the model to the
+// index of the expected element.
+func navigateStep[T any, C any](name string, navigate func(C, T) (T, bool), comp comparator.Comparator[T], gen Gen[T], pick func(i int, found bool) int) stepGen[C, setModel[T]] {
+	return func(r *rand.Rand) step[C, setModel[T]] {
+		t := gen(r)
+		return step[C, setModel[T]]{
+			desc: fmt.Sprintf("%s(%v)", name, t),
+			apply: func(c C, m setModel[T]) error {
+				elems := m.elements()
+				i, found := slices.BinarySearchFunc(elems, t, comp)
+				i = pick(i, found)
+				got, ok := navigate(c, t)
+				switch {
+				case i < 0 || i >= len(elems):
+					if ok {
+						return fmt.Errorf("returned %v, true, want no element", got)
+					}
+				case !ok || comp(got, elems[i]) != 0:
+					return fmt.Errorf("returned %v, %t, want %v, true", got, ok, elems[i])
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// endStep generates a step that checks a method returning the first or last
+// element against the order of All, and removes it from the model if poll is
+// set.
+func endStep[T comparable, C collections.MutableSequencedSet[T]](desc string, take func(C) T, end func([]T) T, poll bool) stepGen[C, setModel[T]] {
+	return sortedEndStep(desc, take, func(m setModel[T]) []T { return nil }, end, equal[T], poll)
+}
+
+// sortedEndStep is like endStep, but when order returns a non-nil slice the
+// expected element is taken from it instead of from All.
+func sortedEndStep[T any, C collections.MutableSequencedSet[T]](desc string, take func(C) T, order func(setModel[T]) []T, end func([]T) T, eq func(a, b T) bool, poll bool) stepGen[C, setModel[T]] {
+	return func(r *rand.Rand) step[C, setModel[T]] {
+		return step[C, setModel[T]]{
+			desc: desc,
+			apply: func(c C, m setModel[T]) error {
+				if m.size() == 0 {
+					return mustPanic(func() { take(c) })
+				}
+				elems := order(m)
+				if elems == nil {
+					elems = slices.Collect(c.All())
+				}
+				want := end(elems)
+				if got := take(c); !eq(got, want) {
+					return fmt.Errorf("returned %v, want %v", got, want)
+				}
+				if poll {
+					m.remove(want)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// addEndStep generates a step that adds an element at one end of a sequenced
+// set and checks that end afterwards.
+func addEndStep[T comparable, C collections.MutableSequencedSet[T]](name string, add func(C, T), end func(C) T, gen Gen[T]) stepGen[C, setModel[T]] {
+	return func(r *rand.Rand) step[C, setModel[T]] {
+		t := gen(r)
+		return step[C, setModel[T]]{
+			desc: fmt.Sprintf("%s(%v)", name, t),
+			apply: func(c C, m setModel[T]) error {
+				add(c, t)
+				m.add(t)
+				if got := end(c); got != t {
+					return fmt.Errorf("end of set is %v, want %v", got, t)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+// checkSet checks that c holds exactly the elements of m.
+func checkSet[T any, C collections.MutableSet[T]](c C, m setModel[T]) error {
+	if err := checkSize(c, m.size()); err != nil {
+		return err
+	}
+	seen := m.empty()
+	for t := range c.All() {
+		if !m.contains(t) {
+			return fmt.Errorf("All() returned %v, which is not in the set", t)
+		}
+		if seen.contains(t) {
+			return fmt.Errorf("All() returned %v more than once", t)
+		}
+		seen.add(t)
+	}
+	if seen.size() != m.size() {
+		return fmt.Errorf("All() returned %d elements, want %d", seen.size(), m.size())
+	}
+	return nil
+}
+
+// checkSequenced checks that First, Last and Backward agree with All.
+func checkSequenced[T any](c collections.SequencedSet[T], eq func(a, b T) bool) error {
+	elems := slices.Collect(c.All())
+	backward := slices.Collect(c.Backward())
+	slices.Reverse(backward)
+	if !slices.EqualFunc(backward, elems, eq) {
+		return fmt.Errorf("Backward() = %v, want the reverse of %v", backward, elems)
+	}
+	if len(elems) == 0 {
+		return nil
+	}
+	if got, want := c.First(), first(elems); !eq(got, want) {
+		return fmt.Errorf("First() = %v, want %v", got, want)
+	}
+	if got, want := c.Last(), last(elems); !eq(got, want) {
+		return fmt.Errorf("Last() = %v, want %v", got, want)
+	}
+	return nil
+}
+
+func values[T any](r *rand.Rand, gen Gen[T]) []T {
+	ts := make([]T, r.Intn(4))
+	for i := range ts {
+		ts[i] = gen(r)
+	}
+	return ts
+}
+
+func first[T any](ts []T) T {
+	return ts[0]
+}
+
+func last[T any](ts []T) T {
+	return ts[len(ts)-1]
+}
+
+func equal[T comparable](a, b T) bool {
+	return a == b
+}
@@ -0,0 +1,400 @@
+package collections_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/internal/failfast"
+	"github.com/lock14/collections/linkedhashmap"
+	"github.com/lock14/collections/linkedhashset"
+	linked_list "github.com/lock14/collections/linkedlist"
+	"github.com/lock14/collections/treemap"
+	"github.com/lock14/collections/treeset"
+)
+
+// cursorList is a list container that supports cursors.
+type cursorList interface {
+	collections.MutableCollection[int]
+	Cursor() collections.ListCursor[int]
+}
+
+// cursorMap is a map container that supports cursors.
+type cursorMap interface {
+	collections.MutableMap[int, int]
+	Cursor() collections.MapCursor[int, int]
+}
+
+// cursorSet is a set container that supports cursors.
+type cursorSet interface {
+	collections.MutableSet[int]
+	Cursor() collections.Cursor[int]
+}
+
+var cursorLists = []struct {
+	name string
+	new  func(elems ...int) cursorList
+}{
+	{name: "arraylist", new: func(elems ...int) cursorList { return arraylist.FromSeq(slices.Values(elems)) }},
+	{name: "arraydeque", new: func(elems ...int) cursorList {
+		return arraydeque.FromSeq(slices.Values(elems), arraydeque.WithCapacity(4))
+	}},
+	{name: "linkedlist", new: func(elems ...int) cursorList { return linked_list.FromSeq(slices.Values(elems)) }},
+}
+
+var cursorMaps = []struct {
+	name string
+	new  func() cursorMap
+}{
+	{name: "linkedhashmap", new: func() cursorMap { return linkedhashmap.New[int, int]() }},
+	{name: "linkedhashmap access order", new: func() cursorMap {
+		return linkedhashmap.New[int, int](linkedhashmap.WithAccessOrder())
+	}},
+	{name: "treemap", new: func() cursorMap { return treemap.NewOrdered[int, int](treemap.WithDegree[int](2)) }},
+}
+
+var cursorSets = []struct {
+	name string
+	new  func(elems ...int) cursorSet
+}{
+	{name: "linkedhashset", new: func(elems ...int) cursorSet { return linkedhashset.FromSeq(slices.Values(elems)) }},
+	{name: "treeset", new: func(elems ...int) cursorSet { return treeset.FromSeqOrdered(slices.Values(elems)) }},
+}
+
+func TestListCursor(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		elems  []int
+		walk   func(t *testing.T, c collections.ListCursor[int])
+		result []int
+	}{
+		{
+			name:  "remove while walking forward",
+			elems: []int{1, 2, 3, 4, 5, 6},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				for c.Next() {
+					if c.Value()%2 == 0 {
+						c.Remove()
+					}
+				}
+			},
+			result: []int{1, 3, 5},
+		},
+		{
+			name:  "stop after removing",
+			elems: []int{1, 2, 3, 4, 5},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				for c.Next() {
+					if c.Value() == 2 || c.Value() == 3 {
+						c.Remove()
+					} else if c.Value() == 4 {
+						break
+					}
+				}
+			},
+			result: []int{1, 4, 5},
+		},
+		{
+			name:  "remove while walking backward",
+			elems: []int{1, 2, 3, 4, 5, 6},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				for c.Prev() {
+					if c.Value()%3 != 0 {
+						c.Remove()
+					}
+				}
+			},
+			result: []int{3, 6},
+		},
+		{
+			name:  "set",
+			elems: []int{1, 2, 3},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				for c.Next() {
+					c.Set(c.Value() * 10)
+				}
+			},
+			result: []int{10, 20, 30},
+		},
+		{
+			name:  "insert around element",
+			elems: []int{1, 2, 3, 4},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				for c.Next() && c.Value() != 3 {
+				}
+				c.InsertBefore(30)
+				c.InsertAfter(31)
+				if got := c.Value(); got != 3 {
+					t.Errorf("cursor moved: got %d, want 3", got)
+				}
+				if !c.Next() || c.Value() != 31 {
+					t.Errorf("expected Next to visit the element inserted after")
+				}
+				c.Prev()
+				if !c.Prev() || c.Value() != 30 {
+					t.Errorf("expected Prev to visit the element inserted before")
+				}
+			},
+			result: []int{1, 2, 30, 3, 31, 4},
+		},
+		{
+			name:  "insert outside",
+			elems: []int{1, 2},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				c.InsertBefore(3)
+				c.InsertAfter(0)
+				if !c.Next() || c.Value() != 0 {
+					t.Errorf("expected Next to move to the first element")
+				}
+			},
+			result: []int{0, 1, 2, 3},
+		},
+		{
+			name:  "insert into gap",
+			elems: []int{1, 2, 3, 4},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				c.Next()
+				c.Next()
+				c.Remove()
+				c.InsertAfter(21)
+				c.InsertBefore(20)
+				if !c.Next() || c.Value() != 21 {
+					t.Errorf("expected Next to visit the element inserted after the gap")
+				}
+				c.Prev()
+				if !c.Prev() || c.Value() != 1 {
+					t.Errorf("expected Prev to move to the element before the gap")
+				}
+			},
+			result: []int{1, 20, 21, 3, 4},
+		},
+		{
+			name:  "move from gap",
+			elems: []int{1, 2, 3},
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				c.Next()
+				c.Next()
+				c.Remove()
+				if !c.Prev() || c.Value() != 1 {
+					t.Errorf("expected Prev from gap to visit 1")
+				}
+				c.Remove()
+				if !c.Next() || c.Value() != 3 {
+					t.Errorf("expected Next from gap to visit 3")
+				}
+				if c.Next() {
+					t.Errorf("expected Next past the end to return false")
+				}
+				if !c.Next() || c.Value() != 3 {
+					t.Errorf("expected Next from outside to move to the first element")
+				}
+			},
+			result: []int{3},
+		},
+		{
+			name:  "empty",
+			elems: nil,
+			walk: func(t *testing.T, c collections.ListCursor[int]) {
+				if c.Next() || c.Prev() {
+					t.Errorf("expected no elements")
+				}
+				c.InsertAfter(1)
+				if !c.Prev() || c.Value() != 1 {
+					t.Errorf("expected inserted element")
+				}
+			},
+			result: []int{1},
+		},
+	}
+	for _, impl := range cursorLists {
+		impl := impl
+		for _, tc := range cases {
+			tc := tc
+			t.Run(impl.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+				l := impl.new(tc.elems...)
+				tc.walk(t, l.Cursor())
+				if got := slices.Collect(l.All()); !slices.Equal(got, tc.result) {
+					t.Errorf("wrong contents: got %v, want %v", got, tc.result)
+				}
+				if got := l.Size(); got != len(tc.result) {
+					t.Errorf("wrong size: got %d, want %d", got, len(tc.result))
+				}
+			})
+		}
+	}
+}
+
+func TestListCursorPanics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		panics bool
+		walk   func(l cursorList, c collections.ListCursor[int])
+	}{
+		{name: "value outside", panics: true, walk: func(l cursorList, c collections.ListCursor[int]) { c.Value() }},
+		{name: "set outside", panics: true, walk: func(l cursorList, c collections.ListCursor[int]) { c.Set(0) }},
+		{name: "remove twice", panics: true, walk: func(l cursorList, c collections.ListCursor[int]) {
+			c.Next()
+			c.Remove()
+			c.Remove()
+		}},
+		{name: "value in gap", panics: true, walk: func(l cursorList, c collections.ListCursor[int]) {
+			c.Next()
+			c.Remove()
+			c.Value()
+		}},
+		{name: "external modification", panics: failfast.Enabled, walk: func(l cursorList, c collections.ListCursor[int]) {
+			c.Next()
+			l.Add(4)
+			c.Next()
+		}},
+		{name: "modification through another cursor", panics: failfast.Enabled, walk: func(l cursorList, c collections.ListCursor[int]) {
+			c.Next()
+			other := l.Cursor()
+			other.Next()
+			other.Remove()
+			c.Value()
+		}},
+	}
+	for _, impl := range cursorLists {
+		impl := impl
+		for _, tc := range cases {
+			tc := tc
+			t.Run(impl.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+				l := impl.new(1, 2, 3)
+				defer func() {
+					if r := recover(); (r != nil) != tc.panics {
+						t.Errorf("got panic %v, want panic %t", r, tc.panics)
+					}
+				}()
+				tc.walk(l, l.Cursor())
+			})
+		}
+	}
+}
+
+func TestMapCursor(t *testing.T) {
+	t.Parallel()
+	for _, impl := range cursorMaps {
+		impl := impl
+		t.Run(impl.name, func(t *testing.T) {
+			t.Parallel()
+			m := impl.new()
+			for i := 1; i <= 10; i++ {
+				m.Put(i, i*10)
+			}
+			c := m.Cursor()
+			for c.Next() {
+				if c.Key()%2 == 0 {
+					c.Remove()
+				} else {
+					c.SetValue(c.Value() + 1)
+				}
+			}
+			if got, want := slices.Collect(m.Keys()), []int{1, 3, 5, 7, 9}; !slices.Equal(got, want) {
+				t.Errorf("wrong keys: got %v, want %v", got, want)
+			}
+			if got, want := slices.Collect(m.Values()), []int{11, 31, 51, 71, 91}; !slices.Equal(got, want) {
+				t.Errorf("wrong values: got %v, want %v", got, want)
+			}
+			var backward []int
+			for c.Prev() {
+				backward = append(backward, c.Key())
+			}
+			if want := []int{9, 7, 5, 3, 1}; !slices.Equal(backward, want) {
+				t.Errorf("wrong backward keys: got %v, want %v", backward, want)
+			}
+			c.Next()
+			c.Next()
+			c.Remove()
+			if !c.Prev() || c.Key() != 1 {
+				t.Errorf("expected Prev from gap to visit 1")
+			}
+			c.Remove()
+			if !c.Next() || c.Key() != 5 {
+				t.Errorf("expected Next from gap to visit 5")
+			}
+			if got, want := slices.Collect(m.Keys()), []int{5, 7, 9}; !slices.Equal(got, want) {
+				t.Errorf("wrong keys: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMapCursorPanics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		panics bool
+		walk   func(m cursorMap, c collections.MapCursor[int, int])
+	}{
+		{name: "key outside", panics: true, walk: func(m cursorMap, c collections.MapCursor[int, int]) { c.Key() }},
+		{name: "set value outside", panics: true, walk: func(m cursorMap, c collections.MapCursor[int, int]) { c.SetValue(0) }},
+		{name: "remove twice", panics: true, walk: func(m cursorMap, c collections.MapCursor[int, int]) {
+			c.Next()
+			c.Remove()
+			c.Remove()
+		}},
+		{name: "external put", panics: failfast.Enabled, walk: func(m cursorMap, c collections.MapCursor[int, int]) {
+			c.Next()
+			m.Put(4, 4)
+			c.Next()
+		}},
+		{name: "external value update", panics: false, walk: func(m cursorMap, c collections.MapCursor[int, int]) {
+			c.Next()
+			m.Put(1, 100)
+			c.Next()
+		}},
+	}
+	for _, impl := range cursorMaps {
+		impl := impl
+		for _, tc := range cases {
+			tc := tc
+			if tc.name == "external value update" && impl.name == "linkedhashmap access order" {
+				// Put on an existing key reorders an access-ordered map.
+				continue
+			}
+			t.Run(impl.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+				m := impl.new()
+				for i := 1; i <= 3; i++ {
+					m.Put(i, i)
+				}
+				defer func() {
+					if r := recover(); (r != nil) != tc.panics {
+						t.Errorf("got panic %v, want panic %t", r, tc.panics)
+					}
+				}()
+				tc.walk(m, m.Cursor())
+			})
+		}
+	}
+}
+
+func TestSetCursor(t *testing.T) {
+	t.Parallel()
+	for _, impl := range cursorSets {
+		impl := impl
+		t.Run(impl.name, func(t *testing.T) {
+			t.Parallel()
+			s := impl.new(1, 2, 3, 4, 5, 6)
+			c := s.Cursor()
+			for c.Next() {
+				if c.Value()%2 == 0 {
+					c.Remove()
+				}
+			}
+			if got, want := slices.Collect(s.All()), []int{1, 3, 5}; !slices.Equal(got, want) {
+				t.Errorf("wrong elements: got %v, want %v", got, want)
+			}
+			if !c.Prev() || c.Value() != 5 {
+				t.Errorf("expected Prev from outside to visit the last element")
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package graph
 
 import (
 	"fmt"
+	"github.com/lock14/collections/internal/codec"
 	"github.com/lock14/collections/labeledgraph"
 	"iter"
+	"slices"
 	"strings"
 )
 
@@ -33,6 +35,9 @@ func WithCapacity(n int) Opt {
 type void struct{}
 
 // Graph is a graph with vertices of type V.
+//
+// Its iterators panic with collections.ErrConcurrentModification if a vertex
+// or edge is added or removed during iteration.
 type Graph[V comparable] struct {
 	delegate *labeledgraph.LabeledGraph[V, void]
 }
@@ -165,6 +170,71 @@ func (g *Graph[V]) Equal(other *Graph[V]) bool {
 	return g.delegate.Equal(other.delegate, func(void, void) bool { return true })
 }
 
+var binaryFormat = codec.Format{Name: "graph", Magic: "GRPH", Version: 1}
+
+// MarshalBinary encodes the graph in a versioned binary format built on
+// encoding/gob, so V must be encodable by gob. The encoding records whether the
+// graph is directed, and holds each edge of an undirected graph once.
+func (g *Graph[V]) MarshalBinary() ([]byte, error) {
+	type edgePair struct{ u, v V }
+	seen := make(map[edgePair]bool)
+
+	var us, vs []V
+	for u, v := range g.Edges() {
+		if !g.Directed() {
+			if seen[edgePair{v, u}] {
+				continue
+			}
+			seen[edgePair{u, v}] = true
+		}
+		us, vs = append(us, u), append(vs, v)
+	}
+
+	e := binaryFormat.NewEncoder()
+	e.Encode(g.Directed())
+	e.Encode(slices.Collect(g.Vertices()))
+	e.Encode(us)
+	e.Encode(vs)
+	return e.Bytes()
+}
+
+// UnmarshalBinary replaces the graph with one decoded from data produced by
+// MarshalBinary, including whether it is directed.
+func (g *Graph[V]) UnmarshalBinary(data []byte) error {
+	d, err := binaryFormat.NewDecoder(data)
+	if err != nil {
+		return err
+	}
+	var directed bool
+	var vertices, us, vs []V
+	for _, v := range []any{&directed, &vertices, &us, &vs} {
+		if err := d.Decode(v); err != nil {
+			return err
+		}
+	}
+	if len(us) != len(vs) {
+		return fmt.Errorf("graph: corrupt binary encoding")
+	}
+
+	opts := []Opt{WithCapacity(len(vertices))}
+	if directed {
+		opts = append(opts, WithDirected())
+	}
+	decoded := New[V](opts...)
+	for _, v := range vertices {
+		decoded.AddVertex(v)
+	}
+	for i := range us {
+		decoded.AddEdge(us[i], vs[i])
+	}
+	if g.delegate != nil {
+		// invalidate iterators over the graph being replaced
+		g.delegate.Clear()
+	}
+	*g = *decoded
+	return nil
+}
+
 // String returns a string representation of the graph.
 func (g *Graph[V]) String() string {
 	var sb strings.Builder
@@ -4,6 +4,9 @@ import (
 	"slices"
 	"strings"
 	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
 )
 
 func TestNew(t *testing.T) {
@@ -247,3 +250,92 @@ func TestGraph_Coverage(t *testing.T) {
 	g.AddEdge(1, 2)
 	_ = g.String()
 }
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		opts []Opt
+	}{
+		{name: "undirected"},
+		{name: "directed", opts: []Opt{WithDirected()}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := New[string](tc.opts...)
+			g.AddEdge("a", "b")
+			g.AddEdge("b", "c")
+			g.AddEdge("c", "a")
+			g.AddVertex("d")
+			data, err := g.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			var got Graph[string]
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			if !got.Equal(g) {
+				t.Errorf("expected %v, got %v", g, &got)
+			}
+			if err := got.UnmarshalBinary([]byte("LGPH\x01")); err == nil {
+				t.Errorf("expected an error decoding a labeledgraph encoding")
+			}
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(g *Graph[int])
+		panics bool
+	}{
+		{name: "add_edge", modify: func(g *Graph[int]) { g.AddEdge(3, 1) }, panics: true},
+		{name: "remove_vertex", modify: func(g *Graph[int]) { g.RemoveVertex(3) }, panics: true},
+		{name: "unmarshal_binary", modify: func(g *Graph[int]) {
+			data, err := New[int]().MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			if err := g.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+		}, panics: true},
+		{name: "add_existing_edge", modify: func(g *Graph[int]) { g.AddEdge(1, 2) }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := New[int]()
+			g.AddEdge(1, 2)
+			g.AddEdge(2, 3)
+			assertConcurrentModification(t, tc.panics, func() {
+				for range g.Edges() {
+					tc.modify(g)
+				}
+			})
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
@@ -3,6 +3,7 @@ package hashmap
 
 import (
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
 	"iter"
 	"maps"
 )
@@ -40,6 +41,17 @@ func New[K comparable, V any](opts ...Option) *HashMap[K, V] {
 	}
 }
 
+// FromSeq creates a HashMap containing the key-value pairs of the given sequence.
+// Later pairs overwrite earlier pairs with the same key. Use WithCapacity to
+// pre-size the map when the length of the sequence is known.
+func FromSeq[K comparable, V any](sequence iter.Seq2[K, V], opts ...Option) *HashMap[K, V] {
+	hm := New[K, V](opts...)
+	for k, v := range sequence {
+		hm.m[k] = v
+	}
+	return hm
+}
+
 // Wrap wraps an existing built-in map.
 func Wrap[K comparable, V any](m map[K]V) *HashMap[K, V] {
 	return &HashMap[K, V]{
@@ -81,6 +93,32 @@ func (hm *HashMap[K, V]) All() iter.Seq2[K, V] {
 	return maps.All(hm.m)
 }
 
+// MarshalJSON encodes the map as a JSON object, or as a JSON array of
+// [key, value] pairs if K cannot be an object key.
+func (hm *HashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(hm.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object or array of [key, value] pairs.
+func (hm *HashMap[K, V]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalMap(data, hm.Clear, hm.Put)
+}
+
+var binaryFormat = codec.Format{Name: "hashmap", Magic: "HMAP", Version: 1}
+
+// MarshalBinary encodes the map in a versioned binary format built on
+// encoding/gob, so K and V must be encodable by gob.
+func (hm *HashMap[K, V]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinaryMap(binaryFormat, hm.All())
+}
+
+// UnmarshalBinary replaces the contents of the map with data produced by
+// MarshalBinary.
+func (hm *HashMap[K, V]) UnmarshalBinary(data []byte) error {
+	return codec.UnmarshalBinaryMap(binaryFormat, data, hm.Clear, hm.Put)
+}
+
 func (hm *HashMap[K, V]) Keys() iter.Seq[K] {
 	return maps.Keys(hm.m)
 }
@@ -0,0 +1,16 @@
+package hashmap_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/hashmap"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableMap(t, func() collections.MutableMap[int, int] {
+		return hashmap.New[int, int]()
+	}, collectionstest.Ints(20), collectionstest.Ints(10))
+}
@@ -1,6 +1,7 @@
 package hashmap
 
 import (
+	"encoding/json"
 	"slices"
 	"sort"
 	"testing"
@@ -155,3 +156,111 @@ func TestHashMap_Operations(t *testing.T) {
 		})
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		keys     []int
+		expected map[int]int
+	}{
+		{name: "empty", keys: nil, expected: map[int]int{}},
+		{name: "distinct", keys: []int{1, 2, 3}, expected: map[int]int{1: 0, 2: 1, 3: 2}},
+		{name: "last_wins", keys: []int{1, 2, 1}, expected: map[int]int{1: 2, 2: 1}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// Each key is mapped to its position in the input.
+			hm := FromSeq(func(yield func(int, int) bool) {
+				for i, k := range tc.keys {
+					if !yield(k, i) {
+						return
+					}
+				}
+			}, WithCapacity(len(tc.keys)))
+			if hm.Size() != len(tc.expected) {
+				t.Fatalf("expected size %d, got %d", len(tc.expected), hm.Size())
+			}
+			for k, v := range tc.expected {
+				if actual, ok := hm.Get(k); !ok || actual != v {
+					t.Errorf("expected %d -> %d, got %d", k, v, actual)
+				}
+			}
+		})
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+		hm := New[int, string]()
+		hm.Put(1, "a")
+		data, err := json.Marshal(hm)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `{"1":"a"}`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		var got HashMap[int, string]
+		if err := json.Unmarshal([]byte(`{"1":"a","2":"b"}`), &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if v, ok := got.Get(2); got.Size() != 2 || !ok || v != "b" {
+			t.Errorf("expected {1:a 2:b}, got %v", got.m)
+		}
+	})
+	t.Run("pairs", func(t *testing.T) {
+		t.Parallel()
+		hm := New[point, string]()
+		hm.Put(point{1, 2}, "a")
+		data, err := json.Marshal(hm)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `[[{"X":1,"Y":2},"a"]]`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		var got HashMap[point, string]
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if v, ok := got.Get(point{1, 2}); got.Size() != 1 || !ok || v != "a" {
+			t.Errorf("expected {{1 2}:a}, got %v", got.m)
+		}
+	})
+}
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	hm := New[point, []string]()
+	hm.Put(point{1, 2}, []string{"a"})
+	hm.Put(point{0, 0}, nil)
+	data, err := hm.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var got HashMap[point, []string]
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if got.Size() != 2 || !got.ContainsKey(point{0, 0}) {
+		t.Errorf("expected {{0 0}:[] {1 2}:[a]}, got %v", got.m)
+	}
+	if v, _ := got.Get(point{1, 2}); !slices.Equal(v, []string{"a"}) {
+		t.Errorf("expected [a], got %v", v)
+	}
+	if err := got.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("expected an error for truncated data")
+	}
+	if got.Size() != 2 {
+		t.Errorf("expected truncated data to leave the map unchanged, got %v", got.m)
+	}
+}
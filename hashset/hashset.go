@@ -4,6 +4,7 @@ package hashset
 import (
 	"fmt"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
 	"iter"
 	"maps"
 	"strings"
@@ -42,6 +43,14 @@ func New[T comparable](opts ...Option) *HashSet[T] {
 	}
 }
 
+// FromSeq creates a HashSet containing the elements of the given sequence.
+// Use WithCapacity to pre-size the set when the length of the sequence is known.
+func FromSeq[T comparable](sequence iter.Seq[T], opts ...Option) *HashSet[T] {
+	s := New[T](opts...)
+	s.AddAll(sequence)
+	return s
+}
+
 func (s *HashSet[T]) Add(item T) {
 	s.m[item] = struct{}{}
 }
@@ -118,6 +127,17 @@ func (s *HashSet[T]) All() iter.Seq[T] {
 	return maps.Keys(s.m)
 }
 
+// MarshalJSON encodes the set as a JSON array of its elements.
+func (s *HashSet[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(s.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array.
+func (s *HashSet[T]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalSeq(data, s.Clear, s.Add)
+}
+
 func defaultConfig() *config {
 	return &config{}
 }
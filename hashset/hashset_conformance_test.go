@@ -0,0 +1,16 @@
+package hashset_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/hashset"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableSet(t, func() collections.MutableSet[int] {
+		return hashset.New[int]()
+	}, collectionstest.Ints(20))
+}
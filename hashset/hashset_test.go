@@ -1,6 +1,7 @@
 package hashset
 
 import (
+	"encoding/json"
 	"slices"
 	"sort"
 	"testing"
@@ -220,3 +221,62 @@ func TestHashSet_BulkOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{name: "empty", input: nil, expected: nil},
+		{name: "duplicates", input: []int{3, 1, 3, 2, 1}, expected: []int{1, 2, 3}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := FromSeq(slices.Values(tc.input), WithCapacity(len(tc.input)))
+			actual := slices.Collect(s.All())
+			sort.Ints(actual)
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		elems []int
+		json  string
+	}{
+		{name: "empty", elems: nil, json: "[]"},
+		{name: "single", elems: []int{7}, json: "[7]"},
+		{name: "elements", elems: []int{3, 1, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeq(slices.Values(tc.elems)))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if tc.json != "" && string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			var s HashSet[int]
+			if err := json.Unmarshal(data, &s); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			got := slices.Sorted(s.All())
+			want := slices.Sorted(slices.Values(tc.elems))
+			if !slices.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
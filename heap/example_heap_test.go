@@ -164,3 +164,181 @@ func ExampleHeap_All() {
 	// 3
 	// 2
 }
+
+func ExampleIndexed() {
+	// Dijkstra's algorithm lowers the tentative distance of a node whenever a
+	// shorter path to it is found.
+	graph := map[string]map[string]int{
+		"a": {"b": 7, "c": 2},
+		"b": {"d": 1},
+		"c": {"b": 3, "d": 8},
+		"d": {},
+	}
+	dist := map[string]int{}
+	frontier := heap.IndexedMin[string, int]()
+	frontier.Update("a", 0)
+	for !frontier.Empty() {
+		e := frontier.Remove()
+		dist[e.Key] = e.Priority
+		for next, w := range graph[e.Key] {
+			if _, done := dist[next]; done {
+				continue
+			}
+			if d, ok := frontier.PriorityOf(next); !ok || e.Priority+w < d {
+				frontier.Update(next, e.Priority+w)
+			}
+		}
+	}
+	for _, node := range []string{"a", "b", "c", "d"} {
+		fmt.Println(node, dist[node])
+	}
+
+	// Output:
+	// a 0
+	// b 5
+	// c 2
+	// d 6
+}
+
+func ExampleMinMax() {
+	// A MinMax heap keeps the five best scores seen so far by evicting the
+	// lowest whenever it grows past five.
+	best := heap.NewOrderedMinMax[int]()
+	for _, score := range []int{42, 7, 99, 63, 18, 85, 30, 71} {
+		best.Add(score)
+		if best.Size() > 5 {
+			best.RemoveMin()
+		}
+	}
+	fmt.Println("lowest kept:", best.PeekMin())
+	fmt.Println("highest:", best.PeekMax())
+
+	// Output:
+	// lowest kept: 42
+	// highest: 99
+}
+
+func ExampleHeap_Fix() {
+	type Job struct {
+		Name     string
+		Deadline int
+	}
+	jobs := heap.New[*Job](heap.WithComparator(func(a, b *Job) int {
+		return cmp.Compare(a.Deadline, b.Deadline)
+	}))
+	report := &Job{Name: "report", Deadline: 5}
+	jobs.Add(report)
+	jobs.Add(&Job{Name: "backup", Deadline: 3})
+	jobs.Add(&Job{Name: "deploy", Deadline: 8})
+
+	// The report's deadline moves up; Fix restores the heap order in O(log n).
+	report.Deadline = 1
+	jobs.Fix(jobs.IndexOf(func(j *Job) bool { return j == report }))
+
+	// The deploy is cancelled.
+	jobs.RemoveFunc(func(j *Job) bool { return j.Name == "deploy" })
+
+	for !jobs.Empty() {
+		j := jobs.Remove()
+		fmt.Println(j.Name, j.Deadline)
+	}
+
+	// Output:
+	// report 1
+	// backup 3
+}
+
+func ExampleHeap_Sorted() {
+	h := heap.FromSeq(slices.Values([]int{5, 1, 4, 2, 3}), heap.WithComparator(cmp.Compare[int]))
+
+	// Sorted leaves the heap intact, while Drain empties it.
+	fmt.Println(slices.Collect(h.Sorted()), h.Size())
+	fmt.Println(slices.Collect(h.Drain()), h.Size())
+
+	// Output:
+	// [1 2 3 4 5] 5
+	// [1 2 3 4 5] 0
+}
+
+func ExampleWithStableOrder() {
+	type Request struct {
+		Client   string
+		Priority int
+	}
+	// Requests of equal priority are served in the order they arrived.
+	queue := heap.New(
+		heap.WithComparator(func(a, b Request) int { return cmp.Compare(b.Priority, a.Priority) }),
+		heap.WithStableOrder[Request](),
+	)
+	queue.Add(Request{"alice", 1})
+	queue.Add(Request{"bob", 2})
+	queue.Add(Request{"carol", 1})
+	queue.Add(Request{"dave", 2})
+
+	urgent := heap.New(
+		heap.WithComparator(func(a, b Request) int { return cmp.Compare(b.Priority, a.Priority) }),
+		heap.WithStableOrder[Request](),
+	)
+	urgent.Add(Request{"erin", 2})
+	queue.Merge(urgent)
+
+	for r := range queue.Drain() {
+		fmt.Println(r.Client)
+	}
+
+	// Output:
+	// bob
+	// dave
+	// erin
+	// alice
+	// carol
+}
+
+func ExampleTopK() {
+	// Keep the three slowest requests seen in a stream of latencies.
+	slowest := heap.NewOrderedTopK[int](3)
+	for _, ms := range []int{120, 45, 980, 300, 15, 610, 75} {
+		if dropped, ok := slowest.Offer(ms); ok {
+			fmt.Println("dropped", dropped)
+		}
+	}
+	fmt.Println(slices.Collect(slowest.Sorted()))
+
+	// Output:
+	// dropped 45
+	// dropped 15
+	// dropped 120
+	// dropped 75
+	// [980 610 300]
+}
+
+func ExampleWithArity() {
+	// A 4-ary heap is shallower than a binary heap, which often makes it
+	// faster for large queues; it removes elements in the same order.
+	h := heap.New[int](heap.WithComparator(cmp.Compare[int]), heap.WithArity[int](4))
+	h.AddAll(slices.Values([]int{5, 3, 8, 1, 9, 2}))
+	fmt.Println(slices.Collect(h.Drain()))
+
+	// Output:
+	// [1 2 3 5 8 9]
+}
+
+func ExamplePairing_Merge() {
+	morning := heap.NewOrderedPairing[int]()
+	morning.AddAll(slices.Values([]int{9, 4, 7}))
+	afternoon := heap.NewOrderedPairing[int]()
+	afternoon.AddAll(slices.Values([]int{6, 2}))
+
+	// Merging pairing heaps takes constant time and empties the other heap.
+	morning.Merge(afternoon)
+	fmt.Println("size:", morning.Size(), "other:", afternoon.Size())
+	var order []int
+	for !morning.Empty() {
+		order = append(order, morning.Remove())
+	}
+	fmt.Println(order)
+
+	// Output:
+	// size: 5 other: 0
+	// [2 4 6 7 9]
+}
@@ -1,16 +1,21 @@
-// Package heap provides a binary heap priority queue.
+// Package heap provides heap priority queues.
 package heap
 
 import (
 	"cmp"
+	"fmt"
 	"github.com/lock14/collections"
 	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"slices"
 )
 
 const (
 	DefaultCapacity = 10
+	// the arity of a Heap unless configured with WithArity
+	defaultArity = 2
 )
 
 var _ collections.MutableQueue[int] = (*Heap[int])(nil)
@@ -22,6 +27,8 @@ type Option[T any] func(config *config[T])
 type config[T any] struct {
 	capacity   int
 	comparator comparator.Comparator[T]
+	stable     bool
+	arity      int
 }
 
 // WithComparator configures the comparator used by the Heap.
@@ -38,10 +45,42 @@ func WithCapacity[T any](capacity int) Option[T] {
 	}
 }
 
-// Heap is a binary heap priority queue.
+// WithArity configures the number of children of each node of a Heap, which
+// defaults to 2. A higher arity makes the heap shallower, so that Add and
+// Remove touch fewer levels, at the cost of comparing more children at each
+// level on Remove; 4 is often faster than 2 for large heaps. It is ignored by
+// the other heaps. Panics if d is less than 2.
+func WithArity[T any](d int) Option[T] {
+	if d < 2 {
+		panic("arity must be at least 2")
+	}
+	return func(config *config[T]) {
+		config.arity = d
+	}
+}
+
+// WithStableOrder configures a Heap to break ties between elements its
+// comparator considers equal by the order in which they were added, so that
+// equal elements are removed first-in-first-out. It is ignored by the other
+// heaps.
+func WithStableOrder[T any]() Option[T] {
+	return func(config *config[T]) {
+		config.stable = true
+	}
+}
+
+// Heap is a binary heap priority queue, or a d-ary heap when configured with
+// WithArity.
 type Heap[T any] struct {
 	elements   []T
 	comparator comparator.Comparator[T]
+	modCount   int
+	// with stable order, seqs holds the insertion sequence number of each
+	// element, and next is the number the next element added receives.
+	stable bool
+	seqs   []uint64
+	next   uint64
+	arity  int
 }
 
 // New creates a new Heap with the given options.
@@ -50,10 +89,34 @@ func New[T any](opts ...Option[T]) *Heap[T] {
 	for _, opt := range opts {
 		opt(config)
 	}
-	return &Heap[T]{
+	h := &Heap[T]{
 		elements:   make([]T, 0, config.capacity),
 		comparator: config.comparator,
+		stable:     config.stable,
+		arity:      config.arity,
+	}
+	if h.stable {
+		h.seqs = make([]uint64, 0, config.capacity)
 	}
+	return h
+}
+
+// FromSeq creates a new Heap containing the elements of the given sequence.
+// The heap is built in linear time rather than by repeated insertion.
+func FromSeq[T any](sequence iter.Seq[T], opts ...Option[T]) *Heap[T] {
+	config := defaultConfig[T]()
+	for _, opt := range opts {
+		opt(config)
+	}
+	h := &Heap[T]{
+		elements:   slices.AppendSeq(make([]T, 0, config.capacity), sequence),
+		comparator: config.comparator,
+		stable:     config.stable,
+		arity:      config.arity,
+	}
+	h.number()
+	h.heapify()
+	return h
 }
 
 // Min creates a new Min-Heap using natural ordering.
@@ -68,7 +131,11 @@ func Max[T cmp.Ordered]() *Heap[T] {
 
 func (h *Heap[T]) Add(t T) {
 	h.elements = append(h.elements, t)
+	if h.stable {
+		h.number()
+	}
 	h.siftUp(len(h.elements) - 1)
+	h.modCount++
 }
 
 func (h *Heap[T]) AddAll(sequence iter.Seq[T]) {
@@ -97,6 +164,136 @@ func (h *Heap[T]) Peek() T {
 	return h.elements[0]
 }
 
+// IndexOf returns the index of the first element, in iteration order, for
+// which pred returns true, or -1 if there is none. The index can be passed to
+// Fix, Update and RemoveAt until the heap is next modified.
+func (h *Heap[T]) IndexOf(pred func(T) bool) int {
+	return slices.IndexFunc(h.elements, pred)
+}
+
+// Fix re-establishes the heap ordering after the element at the given index
+// has changed its priority, such as through a pointer the heap holds. It takes
+// O(log n) time. Panics if the index is out of bounds.
+func (h *Heap[T]) Fix(index int) {
+	h.checkIndex(index)
+	h.modCount++
+	h.fix(index)
+}
+
+// Update replaces the element at the given index with t and moves it to its
+// place in the heap in O(log n) time. With stable order, t keeps the place of
+// the element it replaces among equal elements. Panics if the index is out of
+// bounds.
+func (h *Heap[T]) Update(index int, t T) {
+	h.checkIndex(index)
+	h.elements[index] = t
+	h.modCount++
+	h.fix(index)
+}
+
+// RemoveAt removes and returns the element at the given index in O(log n)
+// time. Panics if the index is out of bounds.
+func (h *Heap[T]) RemoveAt(index int) T {
+	h.checkIndex(index)
+	t := h.elements[index]
+	h.delete(index)
+	return t
+}
+
+// RemoveElement removes one element for which eq(element, t) returns true,
+// and reports whether there was one.
+func (h *Heap[T]) RemoveElement(t T, eq func(a, b T) bool) bool {
+	i := h.IndexOf(func(e T) bool { return eq(e, t) })
+	if i < 0 {
+		return false
+	}
+	h.delete(i)
+	return true
+}
+
+// RemoveFunc removes every element for which pred returns true, and returns
+// how many it removed. The remaining elements are reordered in O(n) time.
+func (h *Heap[T]) RemoveFunc(pred func(T) bool) int {
+	kept := 0
+	for i, t := range h.elements {
+		if pred(t) {
+			continue
+		}
+		h.move(i, kept)
+		kept++
+	}
+	removed := len(h.elements) - kept
+	if removed > 0 {
+		h.truncate(kept)
+		h.heapify()
+		h.modCount++
+	}
+	return removed
+}
+
+// Merge moves every element of other into this heap in O(n + m) time, leaving
+// other empty. The merged elements are ordered by this heap's comparator. With
+// stable order, the elements of other keep their relative order and follow
+// the elements of this heap that are equal to them.
+func (h *Heap[T]) Merge(other *Heap[T]) {
+	if other == h || other.Empty() {
+		return
+	}
+	h.elements = append(h.elements, other.elements...)
+	if h.stable && other.stable {
+		for _, seq := range other.seqs {
+			h.seqs = append(h.seqs, h.next+seq)
+		}
+		h.next += other.next
+	}
+	h.number()
+	h.heapify()
+	h.modCount++
+	other.Clear()
+}
+
+// Drain returns an iterator that removes and yields the elements of the heap
+// in priority order, until the heap is empty or the loop stops early. Elements
+// added to the heap during iteration are yielded in their turn.
+func (h *Heap[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for !h.Empty() {
+			if !yield(h.Remove()) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the elements of the heap in priority order,
+// without removing them. Yielding the first k elements takes O(k log k) time.
+// The iterator panics with collections.ErrConcurrentModification if the heap
+// is modified during iteration.
+func (h *Heap[T]) Sorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if h.Empty() {
+			return
+		}
+		modCount := h.modCount
+		// every element not yet yielded is a descendant of one in the
+		// frontier, so the least index in the frontier is the next to yield
+		frontier := New[int](WithComparator(func(i, j int) int {
+			return h.compare(h.elements[i], h.seqAt(i), j)
+		}))
+		frontier.Add(0)
+		for !frontier.Empty() {
+			i := frontier.Remove()
+			if i >= len(h.elements) || !yield(h.elements[i]) {
+				return
+			}
+			failfast.Check(modCount, h.modCount)
+			for child := h.arity*i + 1; child <= h.arity*i+h.arity && child < len(h.elements); child++ {
+				frontier.Add(child)
+			}
+		}
+	}
+}
+
 func (h *Heap[T]) Size() int {
 	return len(h.elements)
 }
@@ -106,16 +303,53 @@ func (h *Heap[T]) Empty() bool {
 }
 
 func (h *Heap[T]) Clear() {
-	// Zero out elements to allow GC
-	var zero T
-	for i := range h.elements {
-		h.elements[i] = zero
-	}
-	h.elements = h.elements[:0]
+	h.truncate(0)
+	h.next = 0
+	h.modCount++
 }
 
+// All returns an iterator over the elements of the heap in no particular order;
+// use Sorted or Drain to iterate in priority order. The iterator panics with
+// collections.ErrConcurrentModification if the heap is modified during
+// iteration.
 func (h *Heap[T]) All() iter.Seq[T] {
-	return slices.Values(h.elements)
+	return func(yield func(T) bool) {
+		modCount := h.modCount
+		for i := 0; i < len(h.elements); i++ {
+			if !yield(h.elements[i]) {
+				return
+			}
+			failfast.Check(modCount, h.modCount)
+		}
+	}
+}
+
+// MarshalJSON encodes the heap as a JSON array of its elements in iteration
+// order.
+func (h *Heap[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(h.All())
+}
+
+// UnmarshalJSON replaces the contents of the heap with the elements of a JSON
+// array. When called on the zero value, the heap orders elements of ordered
+// types by their natural order.
+func (h *Heap[T]) UnmarshalJSON(data []byte) error {
+	if h.comparator == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("heap: cannot unmarshal into a Heap without a comparator")
+		}
+		h.comparator = c
+	}
+	if h.arity == 0 {
+		h.arity = defaultArity
+	}
+	err := codec.UnmarshalSeq(data, h.Clear, func(t T) {
+		h.elements = append(h.elements, t)
+	})
+	h.number()
+	h.heapify()
+	return err
 }
 
 // Private Functions
@@ -123,34 +357,108 @@ func (h *Heap[T]) All() iter.Seq[T] {
 func defaultConfig[T any]() *config[T] {
 	return &config[T]{
 		capacity: DefaultCapacity,
+		arity:    defaultArity,
+	}
+}
+
+func (h *Heap[T]) checkIndex(index int) {
+	if index < 0 || index >= len(h.elements) {
+		panic("index out of bounds")
 	}
 }
 
 func (h *Heap[T]) delete(index int) {
+	h.modCount++
 	last := len(h.elements) - 1
 	var zero T
+	h.elements[index] = h.elements[last]
+	h.elements[last] = zero
+	h.elements = h.elements[:last]
+	if h.stable {
+		h.seqs[index] = h.seqs[last]
+		h.seqs = h.seqs[:last]
+	}
 	if index != last {
-		h.elements[index] = h.elements[last]
-		h.elements[last] = zero
-		h.elements = h.elements[:last]
+		h.fix(index)
+	}
+}
 
-		p := (index - 1) >> 1
-		if index > 0 && h.comparator(h.elements[index], h.elements[p]) <= 0 {
-			h.siftUp(index)
-		} else {
-			h.siftDown(index)
-		}
+// number gives a sequence number to each element appended since the last
+// call, if the heap has stable order.
+func (h *Heap[T]) number() {
+	if !h.stable {
+		return
+	}
+	for len(h.seqs) < len(h.elements) {
+		h.seqs = append(h.seqs, h.next)
+		h.next++
+	}
+}
+
+// seqAt returns the sequence number of the element at index i, or 0 if the
+// heap does not have stable order.
+func (h *Heap[T]) seqAt(i int) uint64 {
+	if !h.stable {
+		return 0
+	}
+	return h.seqs[i]
+}
+
+// compare compares item, whose sequence number is seq, to the element at
+// index j.
+func (h *Heap[T]) compare(item T, seq uint64, j int) int {
+	c := h.comparator(item, h.elements[j])
+	if c == 0 && h.stable {
+		return cmp.Compare(seq, h.seqs[j])
+	}
+	return c
+}
+
+// move copies the element at index from, and its sequence number, to index to.
+func (h *Heap[T]) move(from, to int) {
+	h.elements[to] = h.elements[from]
+	if h.stable {
+		h.seqs[to] = h.seqs[from]
+	}
+}
+
+// truncate shrinks the heap to n elements, zeroing the rest to allow GC.
+func (h *Heap[T]) truncate(n int) {
+	clear(h.elements[n:])
+	h.elements = h.elements[:n]
+	if h.stable {
+		h.seqs = h.seqs[:n]
+	}
+}
+
+// fix moves the element at index up or down to its place in the heap.
+func (h *Heap[T]) fix(index int) {
+	p := (index - 1) / h.arity
+	if index > 0 && h.compare(h.elements[index], h.seqAt(index), p) <= 0 {
+		h.siftUp(index)
 	} else {
-		h.elements[last] = zero
-		h.elements = h.elements[:last]
+		h.siftDown(index)
+	}
+}
+
+func (h *Heap[T]) heapify() {
+	if len(h.elements) < 2 {
+		return
+	}
+	for i := (len(h.elements) - 2) / h.arity; i >= 0; i-- {
+		h.siftDown(i)
 	}
 }
 
 func (h *Heap[T]) siftUp(cur int) {
-	elements := h.elements
+	if h.stable {
+		h.siftUpStable(cur)
+		return
+	}
+	elements, d := h.elements, h.arity
 	item := elements[cur]
 	for cur > 0 {
-		p := (cur - 1) >> 1
+		p := (cur - 1) / d
 		if h.comparator(item, elements[p]) <= 0 {
 			elements[cur] = elements[p]
 			cur = p
@@ -162,6 +470,14 @@ func (h *Heap[T]) siftUp(cur int) {
 }
 
 func (h *Heap[T]) siftDown(cur int) {
+	if h.stable {
+		h.siftDownStable(cur)
+		return
+	}
+	if h.arity != defaultArity {
+		h.siftDownArity(cur)
+		return
+	}
 	elements := h.elements
 	n := len(elements)
 	item := elements[cur]
@@ -180,3 +496,69 @@ func (h *Heap[T]) siftDown(cur int) {
 	}
 	elements[cur] = item
 }
+
+// siftDownArity is siftDown for heaps whose nodes have more than two children,
+// which picks the least of up to arity children at each level.
+func (h *Heap[T]) siftDownArity(cur int) {
+	elements, d := h.elements, h.arity
+	n := len(elements)
+	item := elements[cur]
+	for {
+		child := d*cur + 1
+		if child >= n {
+			break
+		}
+		for c, last := child+1, min(child+d, n); c < last; c++ {
+			if h.comparator(elements[c], elements[child]) <= 0 {
+				child = c
+			}
+		}
+		if h.comparator(item, elements[child]) <= 0 {
+			break
+		}
+		elements[cur] = elements[child]
+		cur = child
+	}
+	elements[cur] = item
+}
+
+// siftUpStable is siftUp for a heap with stable order, which moves each
+// element's sequence number along with it.
+func (h *Heap[T]) siftUpStable(cur int) {
+	elements, seqs, d := h.elements, h.seqs, h.arity
+	item, seq := elements[cur], seqs[cur]
+	for cur > 0 {
+		p := (cur - 1) / d
+		if h.compare(item, seq, p) > 0 {
+			break
+		}
+		elements[cur], seqs[cur] = elements[p], seqs[p]
+		cur = p
+	}
+	elements[cur], seqs[cur] = item, seq
+}
+
+// siftDownStable is siftDown for a heap with stable order, which moves each
+// element's sequence number along with it.
+func (h *Heap[T]) siftDownStable(cur int) {
+	elements, seqs, d := h.elements, h.seqs, h.arity
+	n := len(elements)
+	item, seq := elements[cur], seqs[cur]
+	for {
+		child := d*cur + 1
+		if child >= n {
+			break
+		}
+		for c, last := child+1, min(child+d, n); c < last; c++ {
+			if h.compare(elements[c], seqs[c], child) <= 0 {
+				child = c
+			}
+		}
+		if h.compare(item, seq, child) <= 0 {
+			break
+		}
+		elements[cur], seqs[cur] = elements[child], seqs[child]
+		cur = child
+	}
+	elements[cur], seqs[cur] = item, seq
+}
@@ -1,8 +1,10 @@
 package heap_test
 
 import (
+	"fmt"
 	"github.com/lock14/collections/comparator"
 	"github.com/lock14/collections/heap"
+	"slices"
 	"testing"
 )
 
@@ -84,3 +86,212 @@ func BenchmarkHeap_StructAdd(b *testing.B) {
 		h.Add(item)
 	}
 }
+
+func BenchmarkHeap_FromSeq(b *testing.B) {
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = len(s) - i
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.FromSeq(slices.Values(s), heap.WithComparator(comparator.NaturalOrder[int]()), heap.WithCapacity[int](len(s)))
+	}
+}
+
+func BenchmarkHeap_AddAll(b *testing.B) {
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = len(s) - i
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := heap.New[int](heap.WithComparator(comparator.NaturalOrder[int]()), heap.WithCapacity[int](len(s)))
+		h.AddAll(slices.Values(s))
+	}
+}
+
+func BenchmarkIndexed_Update(b *testing.B) {
+	const n = 1 << 12
+	h := heap.IndexedMin[int, int]()
+	for i := 0; i < n; i++ {
+		h.Update(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Update(i%n, n-i%n)
+	}
+}
+
+func BenchmarkIndexed_AddRemove(b *testing.B) {
+	h := heap.IndexedMin[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Update(i, i)
+		if h.Size() > 100 {
+			h.Remove()
+		}
+	}
+}
+
+func BenchmarkMinMax_AddRemove(b *testing.B) {
+	h := heap.NewOrderedMinMax[int]()
+	for i := 0; i < 1000; i++ {
+		h.Add(i * 7919 % 1000)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Add(i * 7919 % 1000)
+		if i%2 == 0 {
+			h.RemoveMin()
+		} else {
+			h.RemoveMax()
+		}
+	}
+}
+
+func BenchmarkMinMax_FromSeq(b *testing.B) {
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = i * 7919 % len(s)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.MinMaxFromSeq(slices.Values(s), heap.WithComparator(comparator.NaturalOrder[int]()))
+	}
+}
+
+func BenchmarkHeap_SortedFirst10(b *testing.B) {
+	h := heap.Min[int]()
+	for i := 0; i < 10000; i++ {
+		h.Add(i * 7919 % 10000)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for range h.Sorted() {
+			if n++; n == 10 {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkHeap_Merge(b *testing.B) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i * 7919 % len(s)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := heap.FromSeq(slices.Values(s), heap.WithComparator(comparator.NaturalOrder[int]()))
+		h.Merge(heap.FromSeq(slices.Values(s), heap.WithComparator(comparator.NaturalOrder[int]())))
+	}
+}
+
+func BenchmarkHeap_AddRemove_Stable(b *testing.B) {
+	h := heap.New[int](heap.WithComparator(comparator.NaturalOrder[int]()), heap.WithStableOrder[int]())
+	for i := 0; i < 1000; i++ {
+		h.Add(i % 10)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Add(i % 10)
+		h.Remove()
+	}
+}
+
+func topKInput() []int {
+	s := make([]int, 100000)
+	for i := range s {
+		s[i] = i * 7919 % len(s)
+	}
+	return s
+}
+
+func BenchmarkTopK(b *testing.B) {
+	input := topKInput()
+	for _, k := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("k=%d/TopK", k), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tk := heap.NewOrderedTopK[int](k)
+				tk.AddAll(slices.Values(input))
+				for range tk.Sorted() {
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("k=%d/SortTruncate", k), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s := slices.Clone(input)
+				slices.SortFunc(s, func(x, y int) int { return y - x })
+				s = s[:k]
+				for range s {
+				}
+			}
+		})
+	}
+}
+
+// benchQueue is the part of collections.MutableQueue the queue benchmarks use.
+type benchQueue interface {
+	Add(int)
+	Remove() int
+}
+
+func queueKinds() []struct {
+	name string
+	new  func() benchQueue
+} {
+	natural := heap.WithComparator(comparator.NaturalOrder[int]())
+	return []struct {
+		name string
+		new  func() benchQueue
+	}{
+		{name: "Binary", new: func() benchQueue { return heap.New[int](natural) }},
+		{name: "Arity4", new: func() benchQueue { return heap.New[int](natural, heap.WithArity[int](4)) }},
+		{name: "Arity8", new: func() benchQueue { return heap.New[int](natural, heap.WithArity[int](8)) }},
+		{name: "Pairing", new: func() benchQueue { return heap.NewPairing[int](natural) }},
+	}
+}
+
+// BenchmarkQueues_AddRemove compares the heaps on a steady state of 100000
+// elements, adding one and removing the top each iteration.
+func BenchmarkQueues_AddRemove(b *testing.B) {
+	input := topKInput()
+	for _, kind := range queueKinds() {
+		b.Run(kind.name, func(b *testing.B) {
+			h := kind.new()
+			for _, v := range input {
+				h.Add(v)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.Add(input[i%len(input)])
+				h.Remove()
+			}
+		})
+	}
+}
+
+// BenchmarkQueues_FillDrain compares the heaps on adding 100000 elements and
+// then removing them all.
+func BenchmarkQueues_FillDrain(b *testing.B) {
+	input := topKInput()
+	for _, kind := range queueKinds() {
+		b.Run(kind.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				h := kind.new()
+				for _, v := range input {
+					h.Add(v)
+				}
+				for range input {
+					h.Remove()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package heap_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/heap"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.PriorityQueue(t, func() collections.MutableQueue[int] {
+		return heap.Min[int]()
+	}, cmp.Compare[int], collectionstest.Ints(10))
+	collectionstest.PriorityQueue(t, func() collections.MutableQueue[int] {
+		return heap.Max[int]()
+	}, comparator.Reverse(comparator.NaturalOrder[int]()), collectionstest.Ints(10))
+	collectionstest.PriorityQueue(t, func() collections.MutableQueue[int] {
+		return heap.NewOrderedMinMax[int]()
+	}, cmp.Compare[int], collectionstest.Ints(10))
+	collectionstest.PriorityQueue(t, func() collections.MutableQueue[int] {
+		return heap.New[int](heap.WithComparator(comparator.NaturalOrder[int]()), heap.WithArity[int](4))
+	}, cmp.Compare[int], collectionstest.Ints(10))
+	collectionstest.PriorityQueue(t, func() collections.MutableQueue[int] {
+		return heap.NewOrderedPairing[int]()
+	}, cmp.Compare[int], collectionstest.Ints(10))
+}
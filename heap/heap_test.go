@@ -1,9 +1,14 @@
 package heap
 
 import (
+	"encoding/json"
 	"github.com/lock14/collections"
 	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
+	"math/rand/v2"
 	"slices"
+	"strconv"
 	"testing"
 )
 
@@ -248,3 +253,1142 @@ func TestHeap_Coverage(t *testing.T) {
 	assertPanics(func() { h.Remove() })
 	_ = comparator.NaturalOrder[int]()(1, 2)
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		input    []int
+		opts     []Option[int]
+		expected []int
+	}{
+		{
+			name:     "empty",
+			input:    nil,
+			opts:     []Option[int]{WithComparator(comparator.NaturalOrder[int]())},
+			expected: nil,
+		},
+		{
+			name:     "min",
+			input:    []int{5, 3, 8, 1, 9, 2, 7},
+			opts:     []Option[int]{WithComparator(comparator.NaturalOrder[int]())},
+			expected: []int{1, 2, 3, 5, 7, 8, 9},
+		},
+		{
+			name:     "max_with_duplicates",
+			input:    []int{2, 9, 2, 4, 9},
+			opts:     []Option[int]{WithComparator(comparator.Reverse(comparator.NaturalOrder[int]()))},
+			expected: []int{9, 9, 4, 2, 2},
+		},
+		{
+			name:     "large_capacity_hint",
+			input:    []int{3, 1, 2},
+			opts:     []Option[int]{WithComparator(comparator.NaturalOrder[int]()), WithCapacity[int](64)},
+			expected: []int{1, 2, 3},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := FromSeq(slices.Values(tc.input), tc.opts...)
+			if h.Size() != len(tc.expected) {
+				t.Fatalf("expected size %d, got %d", len(tc.expected), h.Size())
+			}
+			var actual []int
+			for !h.Empty() {
+				actual = append(actual, h.Remove())
+			}
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(h *Heap[int])
+		panics bool
+	}{
+		{name: "add", modify: func(h *Heap[int]) { h.Add(0) }, panics: true},
+		{name: "remove", modify: func(h *Heap[int]) { h.Remove() }, panics: true},
+		{name: "clear", modify: func(h *Heap[int]) { h.Clear() }, panics: true},
+		{name: "peek", modify: func(h *Heap[int]) { h.Peek() }, panics: false},
+		{name: "update", modify: func(h *Heap[int]) { h.Update(0, 5) }, panics: true},
+		{name: "remove_func", modify: func(h *Heap[int]) { h.RemoveFunc(func(v int) bool { return v == 2 }) }, panics: true},
+		{name: "remove_func_none", modify: func(h *Heap[int]) { h.RemoveFunc(func(v int) bool { return v > 10 }) }, panics: false},
+		{name: "index_of", modify: func(h *Heap[int]) { h.IndexOf(func(v int) bool { return v == 2 }) }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := FromSeq(slices.Values([]int{3, 1, 2}), WithComparator(comparator.NaturalOrder[int]()))
+			assertConcurrentModification(t, tc.panics, func() {
+				for range h.All() {
+					tc.modify(h)
+				}
+			})
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		heap func() *Heap[int]
+		want []int
+	}{
+		{name: "zero_value_uses_natural_order", heap: func() *Heap[int] { return &Heap[int]{} }, want: []int{1, 2, 3, 4}},
+		{name: "min", heap: Min[int], want: []int{1, 2, 3, 4}},
+		{name: "max_keeps_comparator", heap: Max[int], want: []int{4, 3, 2, 1}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeq(slices.Values([]int{3, 1, 4, 2}), WithComparator(comparator.NaturalOrder[int]())))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			h := tc.heap()
+			if err := json.Unmarshal(data, h); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			var got []int
+			for !h.Empty() {
+				got = append(got, h.Remove())
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONWithoutComparator(t *testing.T) {
+	t.Parallel()
+	var h Heap[struct{ X int }]
+	if err := json.Unmarshal([]byte(`[{"X":1}]`), &h); err == nil {
+		t.Errorf("expected an error for an element type without a natural order")
+	}
+}
+
+func TestIndexed(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		ops  func(h *Indexed[string, int])
+		want []Entry[string, int]
+	}{
+		{
+			name: "decrease_key",
+			ops: func(h *Indexed[string, int]) {
+				h.Update("a", 5)
+				h.Update("b", 3)
+				h.Update("c", 4)
+				h.Update("a", 1)
+			},
+			want: []Entry[string, int]{{"a", 1}, {"b", 3}, {"c", 4}},
+		},
+		{
+			name: "increase_key",
+			ops: func(h *Indexed[string, int]) {
+				h.AddAll(slices.Values([]Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}))
+				h.Update("a", 10)
+			},
+			want: []Entry[string, int]{{"b", 2}, {"c", 3}, {"a", 10}},
+		},
+		{
+			name: "remove_key",
+			ops: func(h *Indexed[string, int]) {
+				h.AddAll(slices.Values([]Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}}))
+				if p, ok := h.RemoveKey("b"); !ok || p != 2 {
+					t.Errorf("RemoveKey(b) = %d, %t, want 2, true", p, ok)
+				}
+				if _, ok := h.RemoveKey("b"); ok {
+					t.Error("RemoveKey(b) succeeded twice")
+				}
+			},
+			want: []Entry[string, int]{{"a", 1}, {"c", 3}, {"d", 4}},
+		},
+		{
+			name: "clear",
+			ops: func(h *Indexed[string, int]) {
+				h.Update("a", 1)
+				h.Clear()
+				h.Update("b", 2)
+			},
+			want: []Entry[string, int]{{"b", 2}},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := IndexedMin[string, int]()
+			tc.ops(h)
+			for _, e := range tc.want {
+				if p, ok := h.PriorityOf(e.Key); !ok || p != e.Priority {
+					t.Errorf("PriorityOf(%s) = %d, %t, want %d, true", e.Key, p, ok, e.Priority)
+				}
+			}
+			var got []Entry[string, int]
+			for !h.Empty() {
+				got = append(got, h.Remove())
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIndexed_Random(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(1, 2))
+	h := IndexedMax[int, int]()
+	model := map[int]int{}
+	for step := 0; step < 5000; step++ {
+		key := r.IntN(50)
+		switch r.IntN(4) {
+		case 0, 1:
+			p := r.IntN(100)
+			h.Update(key, p)
+			model[key] = p
+		case 2:
+			p, ok := h.RemoveKey(key)
+			want, wantOK := model[key]
+			if ok != wantOK || p != want {
+				t.Fatalf("step %d: RemoveKey(%d) = %d, %t, want %d, %t", step, key, p, ok, want, wantOK)
+			}
+			delete(model, key)
+		case 3:
+			if h.Empty() {
+				continue
+			}
+			e := h.Remove()
+			for k, p := range model {
+				if p > e.Priority {
+					t.Fatalf("step %d: Remove returned %v but %d has priority %d", step, e, k, p)
+				}
+			}
+			if model[e.Key] != e.Priority {
+				t.Fatalf("step %d: Remove returned %v, want priority %d", step, e, model[e.Key])
+			}
+			delete(model, e.Key)
+		}
+		if h.Size() != len(model) {
+			t.Fatalf("step %d: size %d, want %d", step, h.Size(), len(model))
+		}
+		for i, e := range h.entries {
+			if h.index[e.Key] != i {
+				t.Fatalf("step %d: index of %d is %d, want %d", step, e.Key, h.index[e.Key], i)
+			}
+			if i > 0 && e.Priority > h.entries[(i-1)/2].Priority {
+				t.Fatalf("step %d: heap order violated at %d", step, i)
+			}
+		}
+		if _, in := model[key]; h.Contains(key) != in {
+			t.Fatalf("step %d: Contains(%d) disagrees with model", step, key)
+		}
+	}
+}
+
+func TestIndexed_Panics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{name: "remove_empty", f: func() { IndexedMin[int, int]().Remove() }},
+		{name: "peek_empty", f: func() { IndexedMin[int, int]().Peek() }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestIndexed_ConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(h *Indexed[int, int])
+		panics bool
+	}{
+		{name: "update", modify: func(h *Indexed[int, int]) { h.Update(1, 0) }, panics: true},
+		{name: "remove_key", modify: func(h *Indexed[int, int]) { h.RemoveKey(2) }, panics: true},
+		{name: "priority_of", modify: func(h *Indexed[int, int]) { h.PriorityOf(1) }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := IndexedMin[int, int]()
+			h.AddAll(slices.Values([]Entry[int, int]{{1, 1}, {2, 2}, {3, 3}}))
+			assertConcurrentModification(t, tc.panics, func() {
+				for range h.All() {
+					tc.modify(h)
+				}
+			})
+		})
+	}
+}
+
+func TestIndexed_JSON(t *testing.T) {
+	t.Parallel()
+	h := IndexedMin[string, int]()
+	h.AddAll(slices.Values([]Entry[string, int]{{"b", 2}, {"a", 1}}))
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `[{"key":"a","priority":1},{"key":"b","priority":2}]`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	var got Indexed[string, int]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p, ok := got.PriorityOf("b"); !ok || p != 2 {
+		t.Errorf("PriorityOf(b) = %d, %t, want 2, true", p, ok)
+	}
+	if e := got.Remove(); e != (Entry[string, int]{"a", 1}) {
+		t.Errorf("Remove = %v, want {a 1}", e)
+	}
+}
+
+// checkMinMax verifies that every element of h is no greater than its
+// descendants on min levels and no less than them on max levels.
+func checkMinMax[T any](t *testing.T, h *MinMax[T]) {
+	t.Helper()
+	for i := 1; i < len(h.elements); i++ {
+		for a := (i - 1) >> 1; ; a = (a - 1) >> 1 {
+			c := h.comparator(h.elements[a], h.elements[i])
+			if onMinLevel(a) && c > 0 || !onMinLevel(a) && c < 0 {
+				t.Fatalf("element %d violates the order of its ancestor %d: %v", i, a, h.elements)
+			}
+			if a == 0 {
+				break
+			}
+		}
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		input []int
+	}{
+		{name: "empty", input: nil},
+		{name: "one", input: []int{1}},
+		{name: "two", input: []int{2, 1}},
+		{name: "sorted", input: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{name: "reversed", input: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{name: "duplicates", input: []int{3, 1, 3, 2, 1, 2, 3, 1}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sorted := slices.Sorted(slices.Values(tc.input))
+			for _, build := range []func() *MinMax[int]{
+				func() *MinMax[int] {
+					return MinMaxFromSeq(slices.Values(tc.input), WithComparator(comparator.NaturalOrder[int]()))
+				},
+				func() *MinMax[int] {
+					h := NewOrderedMinMax[int]()
+					h.AddAll(slices.Values(tc.input))
+					return h
+				},
+			} {
+				h := build()
+				checkMinMax(t, h)
+				// alternate between the ends, which must meet in the middle
+				var lows, highs []int
+				for i := 0; !h.Empty(); i++ {
+					if i%2 == 0 {
+						if h.PeekMin() != sorted[len(lows)] {
+							t.Errorf("PeekMin = %d, want %d", h.PeekMin(), sorted[len(lows)])
+						}
+						lows = append(lows, h.RemoveMin())
+					} else {
+						if h.PeekMax() != sorted[len(sorted)-1-len(highs)] {
+							t.Errorf("PeekMax = %d, want %d", h.PeekMax(), sorted[len(sorted)-1-len(highs)])
+						}
+						highs = append(highs, h.RemoveMax())
+					}
+					checkMinMax(t, h)
+				}
+				slices.Reverse(highs)
+				if got := append(lows, highs...); !slices.Equal(got, sorted) {
+					t.Errorf("expected %v, got %v", sorted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMinMax_Random(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(3, 4))
+	h := NewOrderedMinMax[int]()
+	var model []int
+	for step := 0; step < 5000; step++ {
+		switch op := r.IntN(4); {
+		case op < 2 || len(model) == 0:
+			v := r.IntN(100)
+			h.Add(v)
+			model = append(model, v)
+			slices.Sort(model)
+		case op == 2:
+			if got := h.RemoveMin(); got != model[0] {
+				t.Fatalf("step %d: RemoveMin = %d, want %d", step, got, model[0])
+			}
+			model = model[1:]
+		default:
+			if got := h.RemoveMax(); got != model[len(model)-1] {
+				t.Fatalf("step %d: RemoveMax = %d, want %d", step, got, model[len(model)-1])
+			}
+			model = model[:len(model)-1]
+		}
+		checkMinMax(t, h)
+	}
+}
+
+func TestMinMax_Panics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{name: "remove_min", f: func() { NewOrderedMinMax[int]().RemoveMin() }},
+		{name: "remove_max", f: func() { NewOrderedMinMax[int]().RemoveMax() }},
+		{name: "peek_min", f: func() { NewOrderedMinMax[int]().PeekMin() }},
+		{name: "peek_max", f: func() { NewOrderedMinMax[int]().PeekMax() }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestMinMax_ConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(h *MinMax[int])
+		panics bool
+	}{
+		{name: "add", modify: func(h *MinMax[int]) { h.Add(0) }, panics: true},
+		{name: "remove_max", modify: func(h *MinMax[int]) { h.RemoveMax() }, panics: true},
+		{name: "peek_max", modify: func(h *MinMax[int]) { h.PeekMax() }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := MinMaxFromSeq(slices.Values([]int{3, 1, 2}), WithComparator(comparator.NaturalOrder[int]()))
+			assertConcurrentModification(t, tc.panics, func() {
+				for range h.All() {
+					tc.modify(h)
+				}
+			})
+		})
+	}
+}
+
+func TestMinMax_JSON(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(MinMaxFromSeq(slices.Values([]int{3, 1, 4, 2}), WithComparator(comparator.NaturalOrder[int]())))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var h MinMax[int]
+	if err := json.Unmarshal(data, &h); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	checkMinMax(t, &h)
+	if h.PeekMin() != 1 || h.PeekMax() != 4 {
+		t.Errorf("expected min 1 and max 4, got %d and %d", h.PeekMin(), h.PeekMax())
+	}
+}
+
+func TestHeap_IndexedOperations(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		op      func(t *testing.T, h *Heap[int])
+		want    []int
+		removed int
+	}{
+		{
+			name: "update_decrease",
+			op: func(t *testing.T, h *Heap[int]) {
+				h.Update(h.IndexOf(func(v int) bool { return v == 50 }), 0)
+			},
+			want: []int{0, 10, 20, 30, 40, 60},
+		},
+		{
+			name: "update_increase",
+			op: func(t *testing.T, h *Heap[int]) {
+				h.Update(h.IndexOf(func(v int) bool { return v == 10 }), 70)
+			},
+			want: []int{20, 30, 40, 50, 60, 70},
+		},
+		{
+			name: "remove_at",
+			op: func(t *testing.T, h *Heap[int]) {
+				if v := h.RemoveAt(h.IndexOf(func(v int) bool { return v == 30 })); v != 30 {
+					t.Errorf("RemoveAt returned %d, want 30", v)
+				}
+			},
+			want: []int{10, 20, 40, 50, 60},
+		},
+		{
+			name: "remove_element",
+			op: func(t *testing.T, h *Heap[int]) {
+				eq := func(a, b int) bool { return a == b }
+				if !h.RemoveElement(40, eq) {
+					t.Error("RemoveElement(40) returned false")
+				}
+				if h.RemoveElement(45, eq) {
+					t.Error("RemoveElement(45) returned true")
+				}
+			},
+			want: []int{10, 20, 30, 50, 60},
+		},
+		{
+			name: "remove_func",
+			op: func(t *testing.T, h *Heap[int]) {
+				if n := h.RemoveFunc(func(v int) bool { return v%20 == 0 }); n != 3 {
+					t.Errorf("RemoveFunc removed %d, want 3", n)
+				}
+			},
+			want: []int{10, 30, 50},
+		},
+		{
+			name: "index_of_missing",
+			op: func(t *testing.T, h *Heap[int]) {
+				if i := h.IndexOf(func(v int) bool { return v == 35 }); i != -1 {
+					t.Errorf("IndexOf(35) = %d, want -1", i)
+				}
+			},
+			want: []int{10, 20, 30, 40, 50, 60},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := FromSeq(slices.Values([]int{60, 30, 50, 10, 40, 20}), WithComparator(comparator.NaturalOrder[int]()))
+			tc.op(t, h)
+			var got []int
+			for !h.Empty() {
+				got = append(got, h.Remove())
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHeap_Fix(t *testing.T) {
+	t.Parallel()
+	type job struct {
+		name     string
+		deadline int
+	}
+	jobs := []*job{{"a", 5}, {"b", 1}, {"c", 3}, {"d", 4}, {"e", 2}}
+	h := FromSeq(slices.Values(jobs), WithComparator(func(x, y *job) int { return x.deadline - y.deadline }))
+	r := rand.New(rand.NewPCG(5, 6))
+	for step := 0; step < 200; step++ {
+		j := jobs[r.IntN(len(jobs))]
+		j.deadline = r.IntN(100)
+		h.Fix(h.IndexOf(func(x *job) bool { return x == j }))
+		for i := 1; i < h.Size(); i++ {
+			if h.elements[i].deadline < h.elements[(i-1)/2].deadline {
+				t.Fatalf("step %d: heap order violated at %d", step, i)
+			}
+		}
+	}
+}
+
+func TestHeap_IndexPanics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func(h *Heap[int])
+	}{
+		{name: "fix_negative", f: func(h *Heap[int]) { h.Fix(-1) }},
+		{name: "update_past_end", f: func(h *Heap[int]) { h.Update(3, 0) }},
+		{name: "remove_at_missing", f: func(h *Heap[int]) { h.RemoveAt(h.IndexOf(func(int) bool { return false })) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := FromSeq(slices.Values([]int{1, 2, 3}), WithComparator(comparator.NaturalOrder[int]()))
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f(h)
+		})
+	}
+}
+
+func TestHeap_DrainSorted(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		input []int
+	}{
+		{name: "empty", input: nil},
+		{name: "one", input: []int{1}},
+		{name: "many", input: []int{5, 3, 9, 1, 7, 3, 8, 2, 6, 4, 0}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			want := slices.Sorted(slices.Values(tc.input))
+			h := FromSeq(slices.Values(tc.input), WithComparator(comparator.NaturalOrder[int]()))
+			if got := slices.Collect(h.Sorted()); !slices.Equal(got, want) {
+				t.Errorf("Sorted: expected %v, got %v", want, got)
+			}
+			if h.Size() != len(tc.input) {
+				t.Errorf("Sorted removed elements: size %d, want %d", h.Size(), len(tc.input))
+			}
+			if got := slices.Collect(h.Drain()); !slices.Equal(got, want) {
+				t.Errorf("Drain: expected %v, got %v", want, got)
+			}
+			if !h.Empty() {
+				t.Errorf("Drain left %d elements", h.Size())
+			}
+		})
+	}
+}
+
+func TestHeap_DrainBreak(t *testing.T) {
+	t.Parallel()
+	h := FromSeq(slices.Values([]int{4, 2, 3, 1}), WithComparator(comparator.NaturalOrder[int]()))
+	var got []int
+	for v := range h.Drain() {
+		got = append(got, v)
+		if v == 1 {
+			h.Add(0)
+		}
+		if v == 2 {
+			break
+		}
+	}
+	if want := []int{1, 0, 2}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if h.Size() != 2 {
+		t.Errorf("expected 2 elements left, got %d", h.Size())
+	}
+}
+
+func TestHeap_SortedConcurrentModification(t *testing.T) {
+	t.Parallel()
+	h := FromSeq(slices.Values([]int{3, 1, 2}), WithComparator(comparator.NaturalOrder[int]()))
+	assertConcurrentModification(t, true, func() {
+		for range h.Sorted() {
+			h.Add(0)
+		}
+	})
+}
+
+func TestHeap_Merge(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		left  []int
+		right []int
+	}{
+		{name: "both_empty"},
+		{name: "left_empty", right: []int{3, 1, 2}},
+		{name: "right_empty", left: []int{3, 1, 2}},
+		{name: "interleaved", left: []int{9, 1, 5, 7}, right: []int{8, 2, 6, 4, 0}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			left := FromSeq(slices.Values(tc.left), WithComparator(comparator.NaturalOrder[int]()))
+			right := FromSeq(slices.Values(tc.right), WithComparator(comparator.NaturalOrder[int]()))
+			left.Merge(right)
+			if !right.Empty() {
+				t.Errorf("Merge left %d elements in other", right.Size())
+			}
+			want := slices.Sorted(slices.Values(append(slices.Clone(tc.left), tc.right...)))
+			if got := slices.Collect(left.Drain()); !slices.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestHeap_MergeSelf(t *testing.T) {
+	t.Parallel()
+	h := FromSeq(slices.Values([]int{2, 1}), WithComparator(comparator.NaturalOrder[int]()))
+	h.Merge(h)
+	if h.Size() != 2 {
+		t.Errorf("expected merging a heap into itself to do nothing, got size %d", h.Size())
+	}
+}
+
+type task struct {
+	name     string
+	priority int
+}
+
+func byPriority(a, b task) int {
+	return a.priority - b.priority
+}
+
+func names(seq iter.Seq[task]) []string {
+	var s []string
+	for t := range seq {
+		s = append(s, t.name)
+	}
+	return s
+}
+
+func TestHeap_StableOrder(t *testing.T) {
+	t.Parallel()
+	tasks := []task{{"a", 2}, {"b", 1}, {"c", 2}, {"d", 1}, {"e", 2}, {"f", 1}, {"g", 2}}
+	want := []string{"b", "d", "f", "a", "c", "e", "g"}
+	cases := []struct {
+		name string
+		heap func() *Heap[task]
+	}{
+		{
+			name: "add",
+			heap: func() *Heap[task] {
+				h := New(WithComparator(byPriority), WithStableOrder[task]())
+				h.AddAll(slices.Values(tasks))
+				return h
+			},
+		},
+		{
+			name: "from_seq",
+			heap: func() *Heap[task] {
+				return FromSeq(slices.Values(tasks), WithComparator(byPriority), WithStableOrder[task]())
+			},
+		},
+		{
+			name: "merge",
+			heap: func() *Heap[task] {
+				h := FromSeq(slices.Values(tasks[:3]), WithComparator(byPriority), WithStableOrder[task]())
+				other := New(WithComparator(byPriority), WithStableOrder[task]())
+				other.AddAll(slices.Values(tasks[3:]))
+				h.Merge(other)
+				return h
+			},
+		},
+		{
+			name: "remove_and_fix",
+			heap: func() *Heap[task] {
+				h := FromSeq(slices.Values(append([]task{{"x", 0}}, tasks...)), WithComparator(byPriority), WithStableOrder[task]())
+				h.RemoveFunc(func(t task) bool { return t.name == "x" })
+				i := h.IndexOf(func(t task) bool { return t.name == "a" })
+				h.Update(i, task{"a", 2})
+				return h
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := tc.heap()
+			if got := names(h.Sorted()); !slices.Equal(got, want) {
+				t.Errorf("Sorted: expected %v, got %v", want, got)
+			}
+			if got := names(h.Drain()); !slices.Equal(got, want) {
+				t.Errorf("Drain: expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestHeap_StableOrderRandom(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(7, 8))
+	h := New(WithComparator(byPriority), WithStableOrder[task]())
+	var model []task
+	for step := 0; step < 3000; step++ {
+		if r.IntN(3) > 0 || len(model) == 0 {
+			tk := task{name: strconv.Itoa(step), priority: r.IntN(5)}
+			h.Add(tk)
+			model = append(model, tk)
+			continue
+		}
+		// the model is the first added among those with the least priority
+		best := 0
+		for i, tk := range model {
+			if tk.priority < model[best].priority {
+				best = i
+			}
+		}
+		if got := h.Remove(); got != model[best] {
+			t.Fatalf("step %d: Remove = %v, want %v", step, got, model[best])
+		}
+		model = slices.Delete(model, best, best+1)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		k     int
+		input []int
+		want  []int
+	}{
+		{name: "empty", k: 3, input: nil, want: nil},
+		{name: "fewer_than_k", k: 3, input: []int{2, 1}, want: []int{2, 1}},
+		{name: "exactly_k", k: 3, input: []int{2, 3, 1}, want: []int{3, 2, 1}},
+		{name: "more_than_k", k: 3, input: []int{5, 1, 9, 3, 7, 2, 8}, want: []int{9, 8, 7}},
+		{name: "duplicates", k: 2, input: []int{4, 4, 1, 4}, want: []int{4, 4}},
+		{name: "one", k: 1, input: []int{3, 9, 2}, want: []int{9}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tk := NewOrderedTopK[int](tc.k)
+			tk.AddAll(slices.Values(tc.input))
+			if got := slices.Collect(tk.Sorted()); !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+			if tk.Size() != len(tc.want) {
+				t.Errorf("expected size %d, got %d", len(tc.want), tk.Size())
+			}
+			if tk.Full() != (len(tc.want) == tc.k) {
+				t.Errorf("Full = %t with %d of %d elements", tk.Full(), tk.Size(), tc.k)
+			}
+		})
+	}
+}
+
+func TestTopK_Offer(t *testing.T) {
+	t.Parallel()
+	tk := NewTopK(2, comparator.Reverse(comparator.NaturalOrder[int]()))
+	steps := []struct {
+		offer   int
+		evicted int
+		ok      bool
+	}{
+		{offer: 5, ok: false},
+		{offer: 3, ok: false},
+		{offer: 4, evicted: 5, ok: true},
+		{offer: 9, evicted: 9, ok: true},
+		{offer: 4, evicted: 4, ok: true},
+		{offer: 1, evicted: 4, ok: true},
+	}
+	for _, step := range steps {
+		evicted, ok := tk.Offer(step.offer)
+		if evicted != step.evicted || ok != step.ok {
+			t.Errorf("Offer(%d) = %d, %t, want %d, %t", step.offer, evicted, ok, step.evicted, step.ok)
+		}
+	}
+	if got := slices.Collect(tk.Sorted()); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("expected the two least elements [1 3], got %v", got)
+	}
+	if tk.Peek() != 3 {
+		t.Errorf("Peek = %d, want 3", tk.Peek())
+	}
+	tk.Clear()
+	if !tk.Empty() || tk.K() != 2 {
+		t.Errorf("after Clear: Empty = %t, K = %d", tk.Empty(), tk.K())
+	}
+}
+
+func TestTopK_Random(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(9, 10))
+	for _, k := range []int{1, 5, 50} {
+		tk := NewOrderedTopK[int](k)
+		var all []int
+		for i := 0; i < 1000; i++ {
+			v := r.IntN(200)
+			tk.Add(v)
+			all = append(all, v)
+		}
+		slices.Sort(all)
+		slices.Reverse(all)
+		if got := slices.Collect(tk.Sorted()); !slices.Equal(got, all[:k]) {
+			t.Errorf("k=%d: expected %v, got %v", k, all[:k], got)
+		}
+	}
+}
+
+func TestTopK_Panics(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	NewOrderedTopK[int](0)
+}
+
+func checkArity[T any](t *testing.T, h *Heap[T]) {
+	t.Helper()
+	for i := 1; i < len(h.elements); i++ {
+		if p := (i - 1) / h.arity; h.comparator(h.elements[p], h.elements[i]) > 0 {
+			t.Fatalf("element %d is less than its parent %d", i, p)
+		}
+	}
+}
+
+func TestHeap_Arity(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		opts []Option[int]
+	}{
+		{name: "binary", opts: []Option[int]{WithArity[int](2)}},
+		{name: "ternary", opts: []Option[int]{WithArity[int](3)}},
+		{name: "quaternary", opts: []Option[int]{WithArity[int](4)}},
+		{name: "octonary", opts: []Option[int]{WithArity[int](8)}},
+		{name: "quaternary_stable", opts: []Option[int]{WithArity[int](4), WithStableOrder[int]()}},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := rand.New(rand.NewPCG(5, uint64(i)))
+			opts := append([]Option[int]{WithComparator(comparator.NaturalOrder[int]())}, tc.opts...)
+			initial := make([]int, 100)
+			for j := range initial {
+				initial[j] = r.IntN(1000)
+			}
+			h := FromSeq(slices.Values(initial), opts...)
+			checkArity(t, h)
+			model := slices.Sorted(slices.Values(initial))
+			for step := 0; step < 5000; step++ {
+				switch op := r.IntN(4); {
+				case op < 2 || len(model) == 0:
+					v := r.IntN(1000)
+					h.Add(v)
+					model = append(model, v)
+					slices.Sort(model)
+				case op == 2:
+					if got := h.Remove(); got != model[0] {
+						t.Fatalf("step %d: Remove = %d, want %d", step, got, model[0])
+					}
+					model = model[1:]
+				default:
+					j := r.IntN(h.Size())
+					removed := h.RemoveAt(j)
+					model = slices.Delete(model, slices.Index(model, removed), slices.Index(model, removed)+1)
+				}
+				checkArity(t, h)
+			}
+			if got := slices.Collect(h.Sorted()); !slices.Equal(got, model) {
+				t.Errorf("Sorted = %v, want %v", got, model)
+			}
+		})
+	}
+}
+
+func TestWithArity_Panics(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	WithArity[int](1)
+}
+
+func TestPairing_Random(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(6, 7))
+	h := NewOrderedPairing[int]()
+	var model []int
+	for step := 0; step < 5000; step++ {
+		if r.IntN(3) < 2 || len(model) == 0 {
+			v := r.IntN(100)
+			h.Add(v)
+			model = append(model, v)
+			slices.Sort(model)
+		} else {
+			if got := h.Remove(); got != model[0] {
+				t.Fatalf("step %d: Remove = %d, want %d", step, got, model[0])
+			}
+			model = model[1:]
+		}
+		if h.Size() != len(model) {
+			t.Fatalf("step %d: Size = %d, want %d", step, h.Size(), len(model))
+		}
+		if len(model) > 0 && h.Peek() != model[0] {
+			t.Fatalf("step %d: Peek = %d, want %d", step, h.Peek(), model[0])
+		}
+	}
+	if got := slices.Sorted(h.All()); !slices.Equal(got, model) {
+		t.Errorf("All = %v, want %v", got, model)
+	}
+}
+
+func TestPairing_Merge(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		left  []int
+		right []int
+	}{
+		{name: "both_empty"},
+		{name: "left_empty", right: []int{3, 1, 2}},
+		{name: "right_empty", left: []int{3, 1, 2}},
+		{name: "interleaved", left: []int{9, 1, 5, 7}, right: []int{8, 2, 6, 4, 0}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			left, right := NewOrderedPairing[int](), NewOrderedPairing[int]()
+			left.AddAll(slices.Values(tc.left))
+			right.AddAll(slices.Values(tc.right))
+			left.Merge(right)
+			if !right.Empty() {
+				t.Errorf("Merge left %d elements in other", right.Size())
+			}
+			want := slices.Sorted(slices.Values(append(slices.Clone(tc.left), tc.right...)))
+			var got []int
+			for !left.Empty() {
+				got = append(got, left.Remove())
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestPairing_MergeSelf(t *testing.T) {
+	t.Parallel()
+	h := NewOrderedPairing[int]()
+	h.AddAll(slices.Values([]int{2, 1}))
+	h.Merge(h)
+	if h.Size() != 2 {
+		t.Errorf("expected merging a heap into itself to do nothing, got size %d", h.Size())
+	}
+}
+
+func TestPairing_Panics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{name: "remove", f: func() { NewOrderedPairing[int]().Remove() }},
+		{name: "peek", f: func() { NewOrderedPairing[int]().Peek() }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestPairing_ConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(h *Pairing[int])
+		panics bool
+	}{
+		{name: "add", modify: func(h *Pairing[int]) { h.Add(0) }, panics: true},
+		{name: "remove", modify: func(h *Pairing[int]) { h.Remove() }, panics: true},
+		{name: "merge", modify: func(h *Pairing[int]) { h.Merge(NewOrderedPairing[int]()) }, panics: false},
+		{name: "peek", modify: func(h *Pairing[int]) { h.Peek() }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := NewOrderedPairing[int]()
+			h.AddAll(slices.Values([]int{3, 1, 2}))
+			assertConcurrentModification(t, tc.panics, func() {
+				for range h.All() {
+					tc.modify(h)
+				}
+			})
+		})
+	}
+}
+
+func TestPairing_JSON(t *testing.T) {
+	t.Parallel()
+	src := NewOrderedPairing[int]()
+	src.AddAll(slices.Values([]int{3, 1, 4, 2}))
+	data, err := json.Marshal(src)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var h Pairing[int]
+	if err := json.Unmarshal(data, &h); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if h.Size() != 4 || h.Peek() != 1 {
+		t.Errorf("expected 4 elements with 1 on top, got %d with %d on top", h.Size(), h.Peek())
+	}
+	var s Pairing[struct{}]
+	if err := json.Unmarshal([]byte("[{}]"), &s); err == nil {
+		t.Error("expected error unmarshaling without a comparator")
+	}
+}
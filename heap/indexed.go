@@ -0,0 +1,250 @@
+package heap
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
+)
+
+var _ collections.MutableQueue[Entry[int, int]] = (*Indexed[int, int])(nil)
+
+// Entry is a key and its priority in an Indexed heap.
+type Entry[K comparable, P any] struct {
+	Key      K `json:"key"`
+	Priority P `json:"priority"`
+}
+
+// Indexed is a binary heap of keys ordered by their priorities, which tracks
+// the position of every key so that a key's priority can be changed, or the key
+// removed, in O(log n) time. Each key appears in the heap at most once.
+type Indexed[K comparable, P any] struct {
+	entries    []Entry[K, P]
+	index      map[K]int
+	comparator comparator.Comparator[P]
+	modCount   int
+}
+
+// NewIndexed creates a new Indexed heap with the given options, which
+// configure the comparator used to order priorities and the initial capacity.
+func NewIndexed[K comparable, P any](opts ...Option[P]) *Indexed[K, P] {
+	config := defaultConfig[P]()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Indexed[K, P]{
+		entries:    make([]Entry[K, P], 0, config.capacity),
+		index:      make(map[K]int, config.capacity),
+		comparator: config.comparator,
+	}
+}
+
+// IndexedMin creates a new Indexed heap that removes the key with the least
+// priority first, using natural ordering.
+func IndexedMin[K comparable, P cmp.Ordered]() *Indexed[K, P] {
+	return NewIndexed[K, P](WithComparator(comparator.NaturalOrder[P]()))
+}
+
+// IndexedMax creates a new Indexed heap that removes the key with the greatest
+// priority first, using natural ordering.
+func IndexedMax[K comparable, P cmp.Ordered]() *Indexed[K, P] {
+	return NewIndexed[K, P](WithComparator(comparator.Reverse(comparator.NaturalOrder[P]())))
+}
+
+// Add is an alias for Update(e.Key, e.Priority).
+func (h *Indexed[K, P]) Add(e Entry[K, P]) {
+	h.Update(e.Key, e.Priority)
+}
+
+// AddAll adds or updates every entry of the given sequence.
+func (h *Indexed[K, P]) AddAll(sequence iter.Seq[Entry[K, P]]) {
+	for e := range sequence {
+		h.Add(e)
+	}
+}
+
+// Update sets the priority of the key, adding the key to the heap if it is
+// not already present.
+func (h *Indexed[K, P]) Update(key K, priority P) {
+	h.modCount++
+	i, ok := h.index[key]
+	if !ok {
+		if h.index == nil {
+			h.index = make(map[K]int)
+		}
+		h.entries = append(h.entries, Entry[K, P]{Key: key, Priority: priority})
+		h.siftUp(len(h.entries) - 1)
+		return
+	}
+	old := h.entries[i].Priority
+	h.entries[i].Priority = priority
+	if h.comparator(priority, old) < 0 {
+		h.siftUp(i)
+	} else {
+		h.siftDown(i)
+	}
+}
+
+// Remove removes and returns the entry at the top of the heap.
+// Panics if the heap is empty.
+func (h *Indexed[K, P]) Remove() Entry[K, P] {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	e := h.entries[0]
+	h.delete(0)
+	return e
+}
+
+// RemoveKey removes the key from the heap, and returns its priority and
+// whether it was present.
+func (h *Indexed[K, P]) RemoveKey(key K) (P, bool) {
+	i, ok := h.index[key]
+	if !ok {
+		var zero P
+		return zero, false
+	}
+	p := h.entries[i].Priority
+	h.delete(i)
+	return p, true
+}
+
+// Peek returns the entry at the top of the heap without removing it.
+// Panics if the heap is empty.
+func (h *Indexed[K, P]) Peek() Entry[K, P] {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	return h.entries[0]
+}
+
+// Contains returns true if the key is in the heap.
+func (h *Indexed[K, P]) Contains(key K) bool {
+	_, ok := h.index[key]
+	return ok
+}
+
+// PriorityOf returns the priority of the key, and whether it is in the heap.
+func (h *Indexed[K, P]) PriorityOf(key K) (P, bool) {
+	i, ok := h.index[key]
+	if !ok {
+		var zero P
+		return zero, false
+	}
+	return h.entries[i].Priority, true
+}
+
+func (h *Indexed[K, P]) Size() int {
+	return len(h.entries)
+}
+
+func (h *Indexed[K, P]) Empty() bool {
+	return len(h.entries) == 0
+}
+
+func (h *Indexed[K, P]) Clear() {
+	clear(h.entries)
+	h.entries = h.entries[:0]
+	clear(h.index)
+	h.modCount++
+}
+
+// All returns an iterator over the entries of the heap in no particular order.
+// The iterator panics with collections.ErrConcurrentModification if the heap is
+// modified during iteration.
+func (h *Indexed[K, P]) All() iter.Seq[Entry[K, P]] {
+	return func(yield func(Entry[K, P]) bool) {
+		modCount := h.modCount
+		for i := 0; i < len(h.entries); i++ {
+			if !yield(h.entries[i]) {
+				return
+			}
+			failfast.Check(modCount, h.modCount)
+		}
+	}
+}
+
+// MarshalJSON encodes the heap as a JSON array of its entries in iteration
+// order, each an object with a key and a priority.
+func (h *Indexed[K, P]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(h.All())
+}
+
+// UnmarshalJSON replaces the contents of the heap with the entries of a JSON
+// array. A later entry for a key already decoded updates its priority. When
+// called on the zero value, the heap orders priorities of ordered types by
+// their natural order.
+func (h *Indexed[K, P]) UnmarshalJSON(data []byte) error {
+	if h.comparator == nil {
+		c, ok := codec.NaturalOrder[P]()
+		if !ok {
+			return fmt.Errorf("heap: cannot unmarshal into an Indexed heap without a comparator")
+		}
+		h.comparator = c
+	}
+	return codec.UnmarshalSeq(data, h.Clear, h.Add)
+}
+
+func (h *Indexed[K, P]) delete(index int) {
+	h.modCount++
+	last := len(h.entries) - 1
+	delete(h.index, h.entries[index].Key)
+	if index != last {
+		h.entries[index] = h.entries[last]
+	}
+	h.entries[last] = Entry[K, P]{}
+	h.entries = h.entries[:last]
+	if index != last {
+		p := (index - 1) >> 1
+		if index > 0 && h.comparator(h.entries[index].Priority, h.entries[p].Priority) <= 0 {
+			h.siftUp(index)
+		} else {
+			h.siftDown(index)
+		}
+	}
+}
+
+// siftUp moves the entry at cur towards the root until its parent's priority
+// comes before it, recording the final position of every entry it moves.
+func (h *Indexed[K, P]) siftUp(cur int) {
+	entries := h.entries
+	e := entries[cur]
+	for cur > 0 {
+		p := (cur - 1) >> 1
+		if h.comparator(e.Priority, entries[p].Priority) > 0 {
+			break
+		}
+		entries[cur] = entries[p]
+		h.index[entries[cur].Key] = cur
+		cur = p
+	}
+	entries[cur] = e
+	h.index[e.Key] = cur
+}
+
+// siftDown moves the entry at cur towards the leaves until it comes before
+// both its children, recording the final position of every entry it moves.
+func (h *Indexed[K, P]) siftDown(cur int) {
+	entries := h.entries
+	n := len(entries)
+	e := entries[cur]
+	half := n >> 1
+	for cur < half {
+		child := 2*cur + 1
+		right := child + 1
+		if right < n && h.comparator(entries[right].Priority, entries[child].Priority) <= 0 {
+			child = right
+		}
+		if h.comparator(e.Priority, entries[child].Priority) <= 0 {
+			break
+		}
+		entries[cur] = entries[child]
+		h.index[entries[cur].Key] = cur
+		cur = child
+	}
+	entries[cur] = e
+	h.index[e.Key] = cur
+}
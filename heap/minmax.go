@@ -0,0 +1,284 @@
+package heap
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+var _ collections.MutableQueue[int] = (*MinMax[int])(nil)
+
+// MinMax is a min-max heap, a double-ended priority queue that finds both its
+// least and its greatest element in O(1) time and removes either in O(log n)
+// time. As a collections.MutableQueue, it removes the least element first.
+//
+// Elements on even levels of the tree are no greater than their descendants,
+// and elements on odd levels are no less than theirs, so the least element is
+// at the root and the greatest is one of the root's children.
+type MinMax[T any] struct {
+	elements   []T
+	comparator comparator.Comparator[T]
+	modCount   int
+}
+
+// NewMinMax creates a new MinMax heap with the given options.
+func NewMinMax[T any](opts ...Option[T]) *MinMax[T] {
+	config := defaultConfig[T]()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &MinMax[T]{
+		elements:   make([]T, 0, config.capacity),
+		comparator: config.comparator,
+	}
+}
+
+// MinMaxFromSeq creates a new MinMax heap containing the elements of the given
+// sequence. The heap is built in linear time rather than by repeated insertion.
+func MinMaxFromSeq[T any](sequence iter.Seq[T], opts ...Option[T]) *MinMax[T] {
+	config := defaultConfig[T]()
+	for _, opt := range opts {
+		opt(config)
+	}
+	h := &MinMax[T]{
+		elements:   slices.AppendSeq(make([]T, 0, config.capacity), sequence),
+		comparator: config.comparator,
+	}
+	h.heapify()
+	return h
+}
+
+// NewOrderedMinMax creates a new MinMax heap using natural ordering.
+func NewOrderedMinMax[T cmp.Ordered]() *MinMax[T] {
+	return NewMinMax[T](WithComparator(comparator.NaturalOrder[T]()))
+}
+
+func (h *MinMax[T]) Add(t T) {
+	h.elements = append(h.elements, t)
+	h.pushUp(len(h.elements) - 1)
+	h.modCount++
+}
+
+func (h *MinMax[T]) AddAll(sequence iter.Seq[T]) {
+	for t := range sequence {
+		h.Add(t)
+	}
+}
+
+// Remove is an alias for RemoveMin.
+func (h *MinMax[T]) Remove() T {
+	return h.RemoveMin()
+}
+
+// Peek is an alias for PeekMin.
+func (h *MinMax[T]) Peek() T {
+	return h.PeekMin()
+}
+
+// PeekMin returns the least element of the heap without removing it.
+// Panics if the heap is empty.
+func (h *MinMax[T]) PeekMin() T {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	return h.elements[0]
+}
+
+// PeekMax returns the greatest element of the heap without removing it.
+// Panics if the heap is empty.
+func (h *MinMax[T]) PeekMax() T {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	return h.elements[h.maxIndex()]
+}
+
+// RemoveMin removes and returns the least element of the heap.
+// Panics if the heap is empty.
+func (h *MinMax[T]) RemoveMin() T {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	t := h.elements[0]
+	h.delete(0)
+	return t
+}
+
+// RemoveMax removes and returns the greatest element of the heap.
+// Panics if the heap is empty.
+func (h *MinMax[T]) RemoveMax() T {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	i := h.maxIndex()
+	t := h.elements[i]
+	h.delete(i)
+	return t
+}
+
+func (h *MinMax[T]) Size() int {
+	return len(h.elements)
+}
+
+func (h *MinMax[T]) Empty() bool {
+	return len(h.elements) == 0
+}
+
+func (h *MinMax[T]) Clear() {
+	clear(h.elements)
+	h.elements = h.elements[:0]
+	h.modCount++
+}
+
+// All returns an iterator over the elements of the heap in no particular order.
+// The iterator panics with collections.ErrConcurrentModification if the heap is
+// modified during iteration.
+func (h *MinMax[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		modCount := h.modCount
+		for i := 0; i < len(h.elements); i++ {
+			if !yield(h.elements[i]) {
+				return
+			}
+			failfast.Check(modCount, h.modCount)
+		}
+	}
+}
+
+// MarshalJSON encodes the heap as a JSON array of its elements in iteration
+// order.
+func (h *MinMax[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(h.All())
+}
+
+// UnmarshalJSON replaces the contents of the heap with the elements of a JSON
+// array. When called on the zero value, the heap orders elements of ordered
+// types by their natural order.
+func (h *MinMax[T]) UnmarshalJSON(data []byte) error {
+	if h.comparator == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("heap: cannot unmarshal into a MinMax heap without a comparator")
+		}
+		h.comparator = c
+	}
+	err := codec.UnmarshalSeq(data, h.Clear, func(t T) {
+		h.elements = append(h.elements, t)
+	})
+	h.heapify()
+	return err
+}
+
+// maxIndex returns the index of the greatest element of a non-empty heap.
+func (h *MinMax[T]) maxIndex() int {
+	switch len(h.elements) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.comparator(h.elements[2], h.elements[1]) > 0 {
+		return 2
+	}
+	return 1
+}
+
+func (h *MinMax[T]) delete(index int) {
+	h.modCount++
+	last := len(h.elements) - 1
+	var zero T
+	h.elements[index] = h.elements[last]
+	h.elements[last] = zero
+	h.elements = h.elements[:last]
+	if index < last {
+		h.pushDown(index)
+	}
+}
+
+func (h *MinMax[T]) heapify() {
+	for i := len(h.elements)>>1 - 1; i >= 0; i-- {
+		h.pushDown(i)
+	}
+}
+
+// onMinLevel reports whether index i is on an even level of the tree.
+func onMinLevel(i int) bool {
+	return bits.Len(uint(i+1))&1 == 1
+}
+
+// before reports whether a belongs above b on a min level, or on a max level
+// if min is false.
+func (h *MinMax[T]) before(a, b T, min bool) bool {
+	if min {
+		return h.comparator(a, b) < 0
+	}
+	return h.comparator(a, b) > 0
+}
+
+// pushUp moves the element at i up the tree to its place among its ancestors.
+func (h *MinMax[T]) pushUp(i int) {
+	if i == 0 {
+		return
+	}
+	min := onMinLevel(i)
+	p := (i - 1) >> 1
+	if h.before(h.elements[p], h.elements[i], min) {
+		h.elements[i], h.elements[p] = h.elements[p], h.elements[i]
+		h.pushUpLevels(p, !min)
+	} else {
+		h.pushUpLevels(i, min)
+	}
+}
+
+// pushUpLevels moves the element at i up through its grandparents, which are
+// on the same kind of level as i.
+func (h *MinMax[T]) pushUpLevels(i int, min bool) {
+	item := h.elements[i]
+	for i > 2 {
+		g := ((i-1)>>1 - 1) >> 1
+		if !h.before(item, h.elements[g], min) {
+			break
+		}
+		h.elements[i] = h.elements[g]
+		i = g
+	}
+	h.elements[i] = item
+}
+
+// pushDown moves the element at i down the tree to its place among its
+// descendants.
+func (h *MinMax[T]) pushDown(i int) {
+	elements := h.elements
+	n := len(elements)
+	min := onMinLevel(i)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		// find the child or grandchild that belongs highest
+		m := child
+		for _, j := range [...]int{child + 1, 2*child + 1, 2*child + 2, 2*child + 3, 2*child + 4} {
+			if j < n && h.before(elements[j], elements[m], min) {
+				m = j
+			}
+		}
+		if !h.before(elements[m], elements[i], min) {
+			return
+		}
+		elements[i], elements[m] = elements[m], elements[i]
+		if m <= child+1 {
+			return
+		}
+		if p := (m - 1) >> 1; h.before(elements[p], elements[m], min) {
+			elements[m], elements[p] = elements[p], elements[m]
+		}
+		i = m
+	}
+}
@@ -0,0 +1,207 @@
+package heap
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
+)
+
+var _ collections.MutableQueue[int] = (*Pairing[int])(nil)
+
+// Pairing is a pairing heap, a priority queue of linked nodes that adds an
+// element, or merges another pairing heap into it, in O(1) time and removes
+// its top element in O(log n) amortized time.
+//
+// Each node holds its first child and its next sibling. Removing the root
+// melds its children in two passes, first in pairs from left to right and
+// then the pairs from right to left, which keeps the tree shallow over a run
+// of operations.
+type Pairing[T any] struct {
+	root       *pairingNode[T]
+	size       int
+	comparator comparator.Comparator[T]
+	modCount   int
+}
+
+type pairingNode[T any] struct {
+	value   T
+	child   *pairingNode[T]
+	sibling *pairingNode[T]
+}
+
+// NewPairing creates a new Pairing heap with the given options. The capacity
+// option is ignored, as the heap allocates a node per element.
+func NewPairing[T any](opts ...Option[T]) *Pairing[T] {
+	config := defaultConfig[T]()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return &Pairing[T]{
+		comparator: config.comparator,
+	}
+}
+
+// NewOrderedPairing creates a new Pairing heap using natural ordering.
+func NewOrderedPairing[T cmp.Ordered]() *Pairing[T] {
+	return NewPairing[T](WithComparator(comparator.NaturalOrder[T]()))
+}
+
+func (h *Pairing[T]) Add(t T) {
+	h.root = h.meld(h.root, &pairingNode[T]{value: t})
+	h.size++
+	h.modCount++
+}
+
+func (h *Pairing[T]) AddAll(sequence iter.Seq[T]) {
+	for t := range sequence {
+		h.Add(t)
+	}
+}
+
+// Remove removes and returns the element at the top of the heap.
+// Panics if the heap is empty.
+func (h *Pairing[T]) Remove() T {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	root := h.root
+	h.root = h.mergePairs(root.child)
+	h.size--
+	h.modCount++
+	return root.value
+}
+
+// Peek returns the element at the top of the heap without removing it.
+// Panics if the heap is empty.
+func (h *Pairing[T]) Peek() T {
+	if h.Empty() {
+		panic("heap is empty")
+	}
+	return h.root.value
+}
+
+// Merge moves every element of other into the heap in O(1) time, leaving other
+// empty. Both heaps must order their elements with the same comparator. Merging
+// a heap into itself has no effect.
+func (h *Pairing[T]) Merge(other *Pairing[T]) {
+	if other == h || other.Empty() {
+		return
+	}
+	h.root = h.meld(h.root, other.root)
+	h.size += other.size
+	h.modCount++
+	other.root = nil
+	other.size = 0
+	other.modCount++
+}
+
+func (h *Pairing[T]) Size() int {
+	return h.size
+}
+
+func (h *Pairing[T]) Empty() bool {
+	return h.size == 0
+}
+
+func (h *Pairing[T]) Clear() {
+	h.root = nil
+	h.size = 0
+	h.modCount++
+}
+
+// All returns an iterator over the elements of the heap in no particular order.
+// The iterator panics with collections.ErrConcurrentModification if the heap is
+// modified during iteration.
+func (h *Pairing[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		modCount := h.modCount
+		var stack []*pairingNode[T]
+		if h.root != nil {
+			stack = append(stack, h.root)
+		}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.value) {
+				return
+			}
+			failfast.Check(modCount, h.modCount)
+			if n.sibling != nil {
+				stack = append(stack, n.sibling)
+			}
+			if n.child != nil {
+				stack = append(stack, n.child)
+			}
+		}
+	}
+}
+
+// MarshalJSON encodes the heap as a JSON array of its elements in iteration
+// order.
+func (h *Pairing[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(h.All())
+}
+
+// UnmarshalJSON replaces the contents of the heap with the elements of a JSON
+// array. When called on the zero value, the heap orders elements of ordered
+// types by their natural order.
+func (h *Pairing[T]) UnmarshalJSON(data []byte) error {
+	if h.comparator == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("heap: cannot unmarshal into a Pairing heap without a comparator")
+		}
+		h.comparator = c
+	}
+	return codec.UnmarshalSeq(data, h.Clear, h.Add)
+}
+
+// meld links the roots a and b, neither of which has a sibling, making the one
+// that belongs below the first child of the other, and returns the new root.
+func (h *Pairing[T]) meld(a, b *pairingNode[T]) *pairingNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.comparator(b.value, a.value) < 0 {
+		a, b = b, a
+	}
+	b.sibling = a.child
+	a.child = b
+	return a
+}
+
+// mergePairs melds the list of siblings starting at first into a single tree
+// and returns its root.
+func (h *Pairing[T]) mergePairs(first *pairingNode[T]) *pairingNode[T] {
+	// meld pairs from left to right, linking the results in reverse order
+	var pairs *pairingNode[T]
+	for first != nil {
+		a, b := first, first.sibling
+		if b == nil {
+			a.sibling = pairs
+			pairs = a
+			break
+		}
+		first = b.sibling
+		a.sibling, b.sibling = nil, nil
+		m := h.meld(a, b)
+		m.sibling = pairs
+		pairs = m
+	}
+	// then meld the pairs from right to left
+	var root *pairingNode[T]
+	for pairs != nil {
+		next := pairs.sibling
+		pairs.sibling = nil
+		root = h.meld(root, pairs)
+		pairs = next
+	}
+	return root
+}
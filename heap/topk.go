@@ -0,0 +1,123 @@
+package heap
+
+import (
+	"cmp"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"iter"
+	"slices"
+)
+
+var _ collections.Collection[int] = (*TopK[int])(nil)
+
+// TopK keeps the k greatest elements, according to its comparator, of those
+// added to it. It is backed by a heap of at most k elements whose root is the
+// least element kept, so each element is added or rejected in O(log k) time.
+type TopK[T any] struct {
+	h *Heap[T]
+	k int
+}
+
+// NewTopK creates a TopK that keeps the k greatest elements according to the
+// given comparator. To keep the k least elements, reverse the comparator.
+// Panics if k is less than 1.
+func NewTopK[T any](k int, comp comparator.Comparator[T]) *TopK[T] {
+	if k < 1 {
+		panic("k must be at least 1")
+	}
+	return &TopK[T]{
+		h: New[T](WithComparator(comp), WithCapacity[T](k)),
+		k: k,
+	}
+}
+
+// NewOrderedTopK creates a TopK that keeps the k greatest elements using
+// natural ordering. Panics if k is less than 1.
+func NewOrderedTopK[T cmp.Ordered](k int) *TopK[T] {
+	return NewTopK(k, comparator.NaturalOrder[T]())
+}
+
+// Add adds the element, evicting the least element kept if the TopK is full
+// and t is greater than it.
+func (tk *TopK[T]) Add(t T) {
+	tk.Offer(t)
+}
+
+// AddAll adds every element of the given sequence.
+func (tk *TopK[T]) AddAll(sequence iter.Seq[T]) {
+	for t := range sequence {
+		tk.Offer(t)
+	}
+}
+
+// Offer adds the element and returns the element that is no longer kept as a
+// result, with true, or false if the TopK was not full. Once the TopK is full,
+// the element returned is either the least element kept before, evicted in
+// favor of t, or t itself if it is no greater than every element kept.
+func (tk *TopK[T]) Offer(t T) (T, bool) {
+	h := tk.h
+	if h.Size() < tk.k {
+		h.Add(t)
+		var zero T
+		return zero, false
+	}
+	least := h.elements[0]
+	if h.comparator(t, least) <= 0 {
+		return t, true
+	}
+	h.Update(0, t)
+	return least, true
+}
+
+// Peek returns the least element kept, which any element added must exceed to
+// be kept once the TopK is full. Panics if the TopK is empty.
+func (tk *TopK[T]) Peek() T {
+	return tk.h.Peek()
+}
+
+// K returns the number of elements the TopK keeps.
+func (tk *TopK[T]) K() int {
+	return tk.k
+}
+
+// Size returns the number of elements kept, which is at most K.
+func (tk *TopK[T]) Size() int {
+	return tk.h.Size()
+}
+
+// Empty returns true if no elements have been kept.
+func (tk *TopK[T]) Empty() bool {
+	return tk.h.Empty()
+}
+
+// Full returns true if the TopK keeps K elements.
+func (tk *TopK[T]) Full() bool {
+	return tk.h.Size() == tk.k
+}
+
+// Clear removes all the elements kept.
+func (tk *TopK[T]) Clear() {
+	tk.h.Clear()
+}
+
+// All returns an iterator over the elements kept in no particular order. The
+// iterator panics with collections.ErrConcurrentModification if the TopK is
+// modified during iteration.
+func (tk *TopK[T]) All() iter.Seq[T] {
+	return tk.h.All()
+}
+
+// Sorted returns an iterator over the elements kept from greatest to least.
+// It iterates over a sorted copy, taken when iteration begins, so the TopK may
+// be modified during iteration.
+func (tk *TopK[T]) Sorted() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s := slices.Clone(tk.h.elements)
+		slices.SortFunc(s, func(a, b T) int { return tk.h.comparator(b, a) })
+		for _, t := range s {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package anymap implements collections.MutableMap for key types that are only
+// known to be comparable at run time. Containers whose key type parameters are
+// constrained by any use it to give their zero values the hash-backed maps
+// their hash constructors would, so that the zero values can be decoded into.
+package anymap
+
+import (
+	"github.com/lock14/collections"
+	"iter"
+	"maps"
+	"reflect"
+)
+
+var _ collections.MutableMap[int, int] = (*Map[int, int])(nil)
+
+// Map is a built-in map from keys boxed as any to values.
+type Map[K any, V any] struct {
+	m map[any]V
+}
+
+// New creates an empty Map, and reports false instead if K is not comparable.
+// As with built-in maps keyed by an interface type, operations panic if a key's
+// dynamic type is not comparable.
+func New[K any, V any]() (*Map[K, V], bool) {
+	if !reflect.TypeFor[K]().Comparable() {
+		return nil, false
+	}
+	return &Map[K, V]{m: make(map[any]V)}, true
+}
+
+// Equal reports whether a and b are equal according to ==, which panics if
+// their type is not comparable.
+func Equal[K any](a, b K) bool {
+	return any(a) == any(b)
+}
+
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	v, ok := m.m[k]
+	return v, ok
+}
+
+func (m *Map[K, V]) Size() int {
+	return len(m.m)
+}
+
+func (m *Map[K, V]) Empty() bool {
+	return len(m.m) == 0
+}
+
+func (m *Map[K, V]) ContainsKey(k K) bool {
+	_, ok := m.m[k]
+	return ok
+}
+
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.m {
+			if !yield(unbox[K](k), v) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.m {
+			if !yield(unbox[K](k)) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return maps.Values(m.m)
+}
+
+func (m *Map[K, V]) Put(k K, v V) {
+	m.m[k] = v
+}
+
+func (m *Map[K, V]) Remove(k K) {
+	delete(m.m, k)
+}
+
+func (m *Map[K, V]) Clear() {
+	clear(m.m)
+}
+
+// unbox returns k as a K. A nil key of an interface type K boxes to a nil any,
+// which unboxes to the zero K.
+func unbox[K any](k any) K {
+	key, _ := k.(K)
+	return key
+}
@@ -0,0 +1,64 @@
+package anymap
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+	type point struct{ x, y int }
+	m, ok := New[point, string]()
+	if !ok {
+		t.Fatal("expected a struct of ints to be comparable")
+	}
+	m.Put(point{1, 2}, "a")
+	m.Put(point{3, 4}, "b")
+	m.Put(point{1, 2}, "c")
+	if v, ok := m.Get(point{1, 2}); !ok || v != "c" {
+		t.Errorf("Get = %q, %t, want c, true", v, ok)
+	}
+	if m.Size() != 2 || m.Empty() || !m.ContainsKey(point{3, 4}) {
+		t.Errorf("expected two entries including {3 4}, got %v", maps.Collect(m.All()))
+	}
+	if got := slices.Sorted(m.Values()); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("Values = %v, want [b c]", got)
+	}
+	m.Remove(point{3, 4})
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []point{{1, 2}}) {
+		t.Errorf("Keys = %v, want [{1 2}]", got)
+	}
+	m.Clear()
+	if !m.Empty() {
+		t.Error("expected Clear to empty the map")
+	}
+}
+
+func TestMapNilInterfaceKey(t *testing.T) {
+	t.Parallel()
+	m, _ := New[error, int]()
+	m.Put(nil, 1)
+	for k, v := range m.All() {
+		if k != nil || v != 1 {
+			t.Errorf("expected nil key mapped to 1, got %v: %d", k, v)
+		}
+	}
+}
+
+func TestNewNotComparable(t *testing.T) {
+	t.Parallel()
+	if _, ok := New[[]int, int](); ok {
+		t.Error("expected slices to be reported as not comparable")
+	}
+	if _, ok := New[func(), int](); ok {
+		t.Error("expected funcs to be reported as not comparable")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+	if !Equal("a", "a") || Equal("a", "b") {
+		t.Error("Equal disagrees with ==")
+	}
+}
@@ -0,0 +1,159 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"iter"
+)
+
+// A Format describes a binary encoding. Every encoding begins with a header of
+// the four-byte Magic followed by a version byte, and continues with a gob
+// stream whose layout is determined by the version. Marshaling writes Version;
+// unmarshaling accepts any version from 1 to Version, so that data written by
+// earlier releases can still be read after the format evolves.
+//
+// Since encoding/gob falls back to encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler, types with these encodings also support gob.
+type Format struct {
+	// Name identifies the encoded type in error messages.
+	Name string
+	// Magic is the four-byte signature at the start of every encoding.
+	Magic string
+	// Version is the latest version of the format.
+	Version byte
+}
+
+const headerLen = 5
+
+// Encoder writes an encoding in the latest version of a Format.
+type Encoder struct {
+	buf bytes.Buffer
+	enc *gob.Encoder
+	err error
+}
+
+// NewEncoder returns an Encoder that has written the header of f.
+func (f Format) NewEncoder() *Encoder {
+	e := &Encoder{}
+	e.buf.WriteString(f.Magic)
+	e.buf.WriteByte(f.Version)
+	e.enc = gob.NewEncoder(&e.buf)
+	return e
+}
+
+// Encode appends v to the gob stream. After the first error, Encode does
+// nothing; the error is reported by Bytes.
+func (e *Encoder) Encode(v any) {
+	if e.err == nil {
+		e.err = e.enc.Encode(v)
+	}
+}
+
+// Bytes returns the encoding, or the first error encountered by Encode.
+func (e *Encoder) Bytes() ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// Decoder reads an encoding in any supported version of a Format.
+type Decoder struct {
+	dec     *gob.Decoder
+	version byte
+}
+
+// NewDecoder validates the header of data and returns a Decoder positioned at
+// the start of its gob stream.
+func (f Format) NewDecoder(data []byte) (*Decoder, error) {
+	if len(data) < headerLen || string(data[:len(f.Magic)]) != f.Magic {
+		return nil, fmt.Errorf("%s: invalid binary encoding", f.Name)
+	}
+	version := data[len(f.Magic)]
+	if version == 0 || version > f.Version {
+		return nil, fmt.Errorf("%s: unsupported binary format version %d", f.Name, version)
+	}
+	return &Decoder{
+		dec:     gob.NewDecoder(bytes.NewReader(data[headerLen:])),
+		version: version,
+	}, nil
+}
+
+// Version returns the version the data was written with.
+func (d *Decoder) Version() byte {
+	return d.version
+}
+
+// Decode reads the next value of the gob stream into the value pointed to by
+// v.
+func (d *Decoder) Decode(v any) error {
+	return d.dec.Decode(v)
+}
+
+// MarshalBinarySeq encodes the elements of seq in f.
+func MarshalBinarySeq[T any](f Format, seq iter.Seq[T]) ([]byte, error) {
+	var ts []T
+	for t := range seq {
+		ts = append(ts, t)
+	}
+	e := f.NewEncoder()
+	e.Encode(ts)
+	return e.Bytes()
+}
+
+// UnmarshalBinarySeq decodes an encoding produced by MarshalBinarySeq, then
+// calls reset and adds the elements in order.
+func UnmarshalBinarySeq[T any](f Format, data []byte, reset func(), add func(T)) error {
+	d, err := f.NewDecoder(data)
+	if err != nil {
+		return err
+	}
+	var ts []T
+	if err := d.Decode(&ts); err != nil {
+		return err
+	}
+	reset()
+	for _, t := range ts {
+		add(t)
+	}
+	return nil
+}
+
+// MarshalBinaryMap encodes the entries of seq in f.
+func MarshalBinaryMap[K any, V any](f Format, seq iter.Seq2[K, V]) ([]byte, error) {
+	var keys []K
+	var values []V
+	for k, v := range seq {
+		keys, values = append(keys, k), append(values, v)
+	}
+	e := f.NewEncoder()
+	e.Encode(keys)
+	e.Encode(values)
+	return e.Bytes()
+}
+
+// UnmarshalBinaryMap decodes an encoding produced by MarshalBinaryMap, then
+// calls reset and puts the entries in order.
+func UnmarshalBinaryMap[K any, V any](f Format, data []byte, reset func(), put func(K, V)) error {
+	d, err := f.NewDecoder(data)
+	if err != nil {
+		return err
+	}
+	var keys []K
+	var values []V
+	if err := d.Decode(&keys); err != nil {
+		return err
+	}
+	if err := d.Decode(&values); err != nil {
+		return err
+	}
+	if len(keys) != len(values) {
+		return fmt.Errorf("%s: corrupt binary encoding: %d keys and %d values", f.Name, len(keys), len(values))
+	}
+	reset()
+	for i := range keys {
+		put(keys[i], values[i])
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFormatHeader(t *testing.T) {
+	t.Parallel()
+	v1 := Format{Name: "test", Magic: "TEST", Version: 1}
+	v2 := Format{Name: "test", Magic: "TEST", Version: 2}
+	data, err := MarshalBinarySeq(v1, slices.Values([]int{1, 2}))
+	if err != nil {
+		t.Fatalf("MarshalBinarySeq: %v", err)
+	}
+	if got := string(data[:headerLen]); got != "TEST\x01" {
+		t.Errorf("expected header %q, got %q", "TEST\x01", got)
+	}
+	d, err := v2.NewDecoder(data)
+	if err != nil {
+		t.Fatalf("expected a later format to accept version 1, got %v", err)
+	}
+	if d.Version() != 1 {
+		t.Errorf("expected version 1, got %d", d.Version())
+	}
+
+	v3, err := Format{Name: "test", Magic: "TEST", Version: 3}.NewEncoder().Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "short", data: []byte("TES")},
+		{name: "magic", data: append([]byte("BEST"), data[len("TEST"):]...)},
+		{name: "version_zero", data: []byte("TEST\x00")},
+		{name: "newer_version", data: v3},
+		{name: "truncated", data: data[:len(data)-1]},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := []int{0}
+			err := UnmarshalBinarySeq(v2, tc.data, func() { got = nil }, func(i int) { got = append(got, i) })
+			if err == nil {
+				t.Errorf("expected an error")
+			}
+			if !slices.Equal(got, []int{0}) {
+				t.Errorf("expected a failed decode to leave the contents unchanged, got %v", got)
+			}
+		})
+	}
+}
+
+func TestBinaryMap(t *testing.T) {
+	t.Parallel()
+	f := Format{Name: "test", Magic: "TEST", Version: 1}
+	data, err := MarshalBinaryMap(f, slices.All([]point{{1, 2}, {}}))
+	if err != nil {
+		t.Fatalf("MarshalBinaryMap: %v", err)
+	}
+	var got []point
+	if err := UnmarshalBinaryMap(f, data, func() {}, func(i int, p point) { got = append(got, p) }); err != nil {
+		t.Fatalf("UnmarshalBinaryMap: %v", err)
+	}
+	if want := []point{{1, 2}, {}}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	e := f.NewEncoder()
+	e.Encode([]int{1, 2})
+	e.Encode([]point{{}})
+	data, _ = e.Bytes()
+	if err := UnmarshalBinaryMap(f, data, func() {}, func(int, point) {}); err == nil {
+		t.Errorf("expected an error for mismatched keys and values")
+	}
+
+	e = f.NewEncoder()
+	e.Encode(func() {})
+	if _, err := e.Bytes(); err == nil {
+		t.Errorf("expected an error encoding a func")
+	}
+}
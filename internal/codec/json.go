@@ -0,0 +1,279 @@
+// Package codec implements the encodings shared by the containers in this
+// module.
+//
+// In JSON, sequences encode as arrays in iteration order. Maps encode as
+// objects when their keys can be object keys under the rules of encoding/json
+// (strings, integers and encoding.TextMarshaler implementations), and as arrays
+// of [key, value] pairs otherwise, so that keys of any type round-trip.
+//
+// Following the convention of encoding/json, decoding the JSON literal null is
+// a no-op. Decoding is otherwise all-or-nothing: the input is fully decoded
+// before the container is reset and refilled, so a malformed document leaves
+// the container unchanged.
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// MarshalSeq encodes the elements of seq as a JSON array.
+func MarshalSeq[T any](seq iter.Seq[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	first := true
+	for t := range seq {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalSeq decodes a JSON array, then calls reset and adds the elements in
+// order.
+func UnmarshalSeq[T any](data []byte, reset func(), add func(T)) error {
+	if isNull(data) {
+		return nil
+	}
+	var ts []T
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return err
+	}
+	reset()
+	for _, t := range ts {
+		add(t)
+	}
+	return nil
+}
+
+// MarshalMap encodes the entries of seq as a JSON object if K can be an object
+// key, and as an array of [key, value] pairs otherwise.
+func MarshalMap[K any, V any](seq iter.Seq2[K, V]) ([]byte, error) {
+	if !textKeys[K]() {
+		return marshalPairs(seq)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for k, v := range seq {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		key, err := encodeKey(k)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte(':')
+		if b, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func marshalPairs[K any, V any](seq iter.Seq2[K, V]) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	first := true
+	for k, v := range seq {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		b, err := MarshalPair(k, v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalMap decodes either encoding produced by MarshalMap, then calls reset
+// and puts the entries in the order they appear.
+func UnmarshalMap[K any, V any](data []byte, reset func(), put func(K, V)) error {
+	if isNull(data) {
+		return nil
+	}
+	var keys []K
+	var values []V
+	if firstByte(data) == '[' {
+		var pairs []json.RawMessage
+		if err := json.Unmarshal(data, &pairs); err != nil {
+			return err
+		}
+		keys, values = make([]K, len(pairs)), make([]V, len(pairs))
+		for i, p := range pairs {
+			if err := UnmarshalPair(p, &keys[i], &values[i]); err != nil {
+				return err
+			}
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			k, err := decodeKey[K](tok.(string))
+			if err != nil {
+				return err
+			}
+			var v V
+			if err := dec.Decode(&v); err != nil {
+				return err
+			}
+			keys, values = append(keys, k), append(values, v)
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+	}
+	reset()
+	for i := range keys {
+		put(keys[i], values[i])
+	}
+	return nil
+}
+
+// MarshalPair encodes a pair as a two-element JSON array.
+func MarshalPair[K any, V any](k K, v V) ([]byte, error) {
+	return json.Marshal([2]any{k, v})
+}
+
+// UnmarshalPair decodes a two-element JSON array into k and v.
+func UnmarshalPair[K any, V any](data []byte, k *K, v *V) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || raw == nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("json: cannot unmarshal array of length %d into a pair", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], k); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[1], v)
+}
+
+// textKeys reports whether encoding/json would encode K as an object key.
+func textKeys[K any]() bool {
+	t := reflect.TypeFor[K]()
+	if t.Kind() == reflect.String || t.Implements(textMarshalerType) {
+		return true
+	}
+	return isInt(t.Kind()) || isUint(t.Kind())
+}
+
+// encodeKey encodes k as an object key, following encoding/json.
+func encodeKey[K any](k K) (string, error) {
+	v := reflect.ValueOf(&k).Elem()
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if tm, ok := any(k).(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch {
+	case isInt(v.Kind()):
+		return strconv.FormatInt(v.Int(), 10), nil
+	case isUint(v.Kind()):
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("json: unsupported key type %v", v.Type())
+}
+
+// decodeKey decodes an object key into a K, following encoding/json.
+func decodeKey[K any](s string) (K, error) {
+	var k K
+	t := reflect.TypeFor[K]()
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		err := any(&k).(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		return k, err
+	}
+	v := reflect.ValueOf(&k).Elem()
+	switch {
+	case t.Kind() == reflect.String:
+		v.SetString(s)
+	case isInt(t.Kind()):
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return k, fmt.Errorf("json: cannot unmarshal number %s into key of type %v", s, t)
+		}
+		v.SetInt(n)
+	case isUint(t.Kind()):
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return k, fmt.Errorf("json: cannot unmarshal number %s into key of type %v", s, t)
+		}
+		v.SetUint(n)
+	default:
+		return k, fmt.Errorf("json: cannot unmarshal object into map with key type %v", t)
+	}
+	return k, nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("json: expected %v, found %v", want, tok)
+	}
+	return nil
+}
+
+func firstByte(data []byte) byte {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	if len(data) == 0 {
+		return 0
+	}
+	return data[0]
+}
+
+func isNull(data []byte) bool {
+	return string(bytes.TrimSpace(data)) == "null"
+}
@@ -0,0 +1,210 @@
+package codec
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// upper is a key type that encodes as text but is not itself a string, so
+// encoding/json consults its TextMarshaler.
+type upper struct {
+	s string
+}
+
+func (u upper) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(u.s)), nil
+}
+
+func (u *upper) UnmarshalText(text []byte) error {
+	u.s = strings.ToLower(string(text))
+	return nil
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestMarshalMap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		seq  func() ([]byte, error)
+		want string
+	}{
+		{name: "string", seq: func() ([]byte, error) { return MarshalMap(slices.All([]string{"a", "b"})) }, want: `{"0":"a","1":"b"}`},
+		{name: "uint8", seq: func() ([]byte, error) { return MarshalMap(maps.All(map[uint8]int{255: 1})) }, want: `{"255":1}`},
+		{name: "text_marshaler", seq: func() ([]byte, error) { return MarshalMap(maps.All(map[upper]int{{"a"}: 1})) }, want: `{"A":1}`},
+		{name: "struct", seq: func() ([]byte, error) { return MarshalMap(maps.All(map[point]int{{1, 2}: 3})) }, want: `[[{"X":1,"Y":2},3]]`},
+		{name: "float", seq: func() ([]byte, error) { return MarshalMap(maps.All(map[float64]int{1.5: 3})) }, want: `[[1.5,3]]`},
+		{name: "empty", seq: func() ([]byte, error) { return MarshalMap(maps.All(map[string]int{})) }, want: `{}`},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := tc.seq()
+			if err != nil {
+				t.Fatalf("MarshalMap: %v", err)
+			}
+			if string(data) != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, data)
+			}
+		})
+	}
+}
+
+func TestUnmarshalMap(t *testing.T) {
+	t.Parallel()
+	type entry struct {
+		k upper
+		v int
+	}
+	tests := []struct {
+		name    string
+		data    string
+		want    []entry
+		wantErr bool
+	}{
+		{name: "object", data: ` {"B":2,"A":1} `, want: []entry{{upper{"b"}, 2}, {upper{"a"}, 1}}},
+		{name: "pairs", data: `[["B",2],["A",1]]`, want: []entry{{upper{"b"}, 2}, {upper{"a"}, 1}}},
+		{name: "null", data: `null`, want: []entry{{upper{"stale"}, 0}}},
+		{name: "truncated", data: `{"A":1`, want: []entry{{upper{"stale"}, 0}}, wantErr: true},
+		{name: "bad_value", data: `{"A":1,"B":"x"}`, want: []entry{{upper{"stale"}, 0}}, wantErr: true},
+		{name: "short_pair", data: `[["a"]]`, want: []entry{{upper{"stale"}, 0}}, wantErr: true},
+		{name: "scalar", data: `1`, want: []entry{{upper{"stale"}, 0}}, wantErr: true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := []entry{{upper{"stale"}, 0}}
+			err := UnmarshalMap([]byte(tc.data), func() { got = nil }, func(k upper, v int) {
+				got = append(got, entry{k, v})
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %t, got %v", tc.wantErr, err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalMapKeys(t *testing.T) {
+	t.Parallel()
+	var ints []int8
+	if err := UnmarshalMap([]byte(`{"-3":0,"7":0}`), func() {}, func(k int8, _ int) { ints = append(ints, k) }); err != nil {
+		t.Fatalf("UnmarshalMap: %v", err)
+	}
+	if want := []int8{-3, 7}; !slices.Equal(ints, want) {
+		t.Errorf("expected %v, got %v", want, ints)
+	}
+	tests := []struct {
+		name      string
+		unmarshal func() error
+	}{
+		{name: "overflow", unmarshal: func() error {
+			return UnmarshalMap([]byte(`{"300":0}`), func() {}, func(int8, int) {})
+		}},
+		{name: "negative_uint", unmarshal: func() error {
+			return UnmarshalMap([]byte(`{"-1":0}`), func() {}, func(uint, int) {})
+		}},
+		{name: "struct_key", unmarshal: func() error {
+			return UnmarshalMap([]byte(`{"a":0}`), func() {}, func(point, int) {})
+		}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tc.unmarshal(); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestUnmarshalSeq(t *testing.T) {
+	t.Parallel()
+	got := []int{0}
+	reset := func() { got = nil }
+	add := func(i int) { got = append(got, i) }
+	if err := UnmarshalSeq([]byte(`[1,"2"]`), reset, add); err == nil {
+		t.Errorf("expected an error for a mistyped element")
+	}
+	if err := UnmarshalSeq([]byte(`null`), reset, add); err != nil {
+		t.Errorf("UnmarshalSeq(null): %v", err)
+	}
+	if want := []int{0}; !slices.Equal(got, want) {
+		t.Errorf("expected %v to be left unchanged, got %v", want, got)
+	}
+	if err := UnmarshalSeq([]byte(`[1,2]`), reset, add); err != nil {
+		t.Fatalf("UnmarshalSeq: %v", err)
+	}
+	if want := []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNaturalOrder(t *testing.T) {
+	t.Parallel()
+	type name string
+	type small int8
+	type size uint
+	type weight float32
+	tests := []struct {
+		name    string
+		compare func() (int, bool)
+		want    int
+	}{
+		{name: "int", compare: compareWith[int](1, 2), want: -1},
+		{name: "string", compare: compareWith[string]("b", "a"), want: 1},
+		{name: "named_string", compare: compareWith[name]("a", "a"), want: 0},
+		{name: "named_int", compare: compareWith[small](-1, 1), want: -1},
+		{name: "named_uint", compare: compareWith[size](3, 2), want: 1},
+		{name: "named_float", compare: compareWith[weight](0.5, 0.25), want: 1},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := tc.compare()
+			if !ok {
+				t.Fatalf("expected a natural order")
+			}
+			if got != tc.want {
+				t.Errorf("expected %d, got %d", tc.want, got)
+			}
+		})
+	}
+	for _, ok := range []bool{natural[point](), natural[[]int](), natural[*int]()} {
+		if ok {
+			t.Errorf("expected no natural order for an unordered type")
+		}
+	}
+}
+
+func compareWith[T any](a, b T) func() (int, bool) {
+	return func() (int, bool) {
+		c, ok := NaturalOrder[T]()
+		if !ok {
+			return 0, false
+		}
+		return c(a, b), true
+	}
+}
+
+func natural[T any]() bool {
+	_, ok := NaturalOrder[T]()
+	return ok
+}
+
+func ExampleMarshalMap() {
+	data, _ := MarshalMap(maps.All(map[point]string{{1, 2}: "a"}))
+	fmt.Println(string(data))
+	// Output: [[{"X":1,"Y":2},"a"]]
+}
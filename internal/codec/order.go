@@ -0,0 +1,69 @@
+package codec
+
+import (
+	"cmp"
+	"reflect"
+
+	"github.com/lock14/collections/comparator"
+)
+
+// NaturalOrder returns a comparator for T if its underlying type is ordered,
+// so that zero-value sorted containers can be decoded without being given a
+// comparator. Types whose underlying type is predeclared use cmp.Compare
+// directly; other ordered types are compared through reflection.
+func NaturalOrder[T any]() (comparator.Comparator[T], bool) {
+	var c any
+	switch any(*new(T)).(type) {
+	case int:
+		c = comparator.NaturalOrder[int]()
+	case int8:
+		c = comparator.NaturalOrder[int8]()
+	case int16:
+		c = comparator.NaturalOrder[int16]()
+	case int32:
+		c = comparator.NaturalOrder[int32]()
+	case int64:
+		c = comparator.NaturalOrder[int64]()
+	case uint:
+		c = comparator.NaturalOrder[uint]()
+	case uint8:
+		c = comparator.NaturalOrder[uint8]()
+	case uint16:
+		c = comparator.NaturalOrder[uint16]()
+	case uint32:
+		c = comparator.NaturalOrder[uint32]()
+	case uint64:
+		c = comparator.NaturalOrder[uint64]()
+	case uintptr:
+		c = comparator.NaturalOrder[uintptr]()
+	case float32:
+		c = comparator.NaturalOrder[float32]()
+	case float64:
+		c = comparator.NaturalOrder[float64]()
+	case string:
+		c = comparator.NaturalOrder[string]()
+	}
+	if c != nil {
+		return c.(comparator.Comparator[T]), true
+	}
+	kind := reflect.TypeFor[T]().Kind()
+	switch {
+	case isInt(kind):
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}, true
+	case isUint(kind):
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}, true
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}, true
+	case kind == reflect.String:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,155 @@
+// Package cursor implements collections.ListCursor over index-addressable
+// storage such as ring buffers.
+package cursor
+
+import (
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+)
+
+var _ collections.ListCursor[int] = (*Index[int])(nil)
+
+// Storage is the index-addressable storage an Index cursor walks over.
+type Storage[T any] interface {
+	// Len returns the number of elements.
+	Len() int
+	// At returns the element at index i.
+	At(i int) T
+	// SetAt replaces the element at index i.
+	SetAt(i int, t T)
+	// InsertAt inserts t at index i, shifting later elements up.
+	InsertAt(i int, t T)
+	// DeleteAt removes the element at index i, shifting later elements down.
+	DeleteAt(i int)
+	// ModCount returns the structural modification count of the storage.
+	ModCount() int
+}
+
+type state int
+
+const (
+	outside state = iota
+	element
+	gap
+)
+
+// Index is a collections.ListCursor over a Storage. When the cursor is on an
+// element, pos is its index; in a gap, pos is the index of the element that
+// follows the gap.
+type Index[T any] struct {
+	s        Storage[T]
+	state    state
+	pos      int
+	modCount int
+}
+
+// New returns a cursor positioned outside the given storage.
+func New[T any](s Storage[T]) *Index[T] {
+	return &Index[T]{
+		s:        s,
+		modCount: s.ModCount(),
+	}
+}
+
+// Next moves the cursor to the next element and reports whether there is one.
+func (c *Index[T]) Next() bool {
+	c.check()
+	next := 0
+	switch c.state {
+	case element:
+		next = c.pos + 1
+	case gap:
+		next = c.pos
+	}
+	return c.moveTo(next)
+}
+
+// Prev moves the cursor to the previous element and reports whether there is one.
+func (c *Index[T]) Prev() bool {
+	c.check()
+	prev := c.s.Len() - 1
+	if c.state != outside {
+		prev = c.pos - 1
+	}
+	return c.moveTo(prev)
+}
+
+// Value returns the element at the cursor.
+func (c *Index[T]) Value() T {
+	c.checkElement()
+	return c.s.At(c.pos)
+}
+
+// Set replaces the element at the cursor.
+func (c *Index[T]) Set(t T) {
+	c.checkElement()
+	c.s.SetAt(c.pos, t)
+}
+
+// Remove removes the element at the cursor, leaving the cursor in its place.
+func (c *Index[T]) Remove() {
+	c.checkElement()
+	c.s.DeleteAt(c.pos)
+	c.state = gap
+	c.modCount = c.s.ModCount()
+}
+
+// InsertBefore inserts t immediately before the cursor.
+func (c *Index[T]) InsertBefore(t T) {
+	c.check()
+	if c.state == outside {
+		c.s.InsertAt(c.s.Len(), t)
+	} else {
+		c.s.InsertAt(c.pos, t)
+		c.pos++
+	}
+	c.modCount = c.s.ModCount()
+}
+
+// InsertAfter inserts t immediately after the cursor.
+func (c *Index[T]) InsertAfter(t T) {
+	c.check()
+	switch c.state {
+	case outside:
+		c.s.InsertAt(0, t)
+	case element:
+		c.s.InsertAt(c.pos+1, t)
+	case gap:
+		c.s.InsertAt(c.pos, t)
+	}
+	c.modCount = c.s.ModCount()
+}
+
+func (c *Index[T]) moveTo(i int) bool {
+	if i < 0 || i >= c.s.Len() {
+		c.state = outside
+		return false
+	}
+	c.state = element
+	c.pos = i
+	return true
+}
+
+func (c *Index[T]) check() {
+	failfast.Check(c.modCount, c.s.ModCount())
+}
+
+func (c *Index[T]) checkElement() {
+	c.check()
+	if c.state != element {
+		panic("cursor is not positioned on an element")
+	}
+}
+
+var _ collections.Cursor[int] = Keys[int, struct{}]{}
+
+// Keys is a collections.Cursor over the keys of a collections.MapCursor. Sets
+// backed by maps use it to expose cursors over their elements.
+type Keys[K any, V any] struct {
+	collections.MapCursor[K, V]
+}
+
+// Value returns the key of the entry at the cursor.
+func (c Keys[K, V]) Value() K {
+	return c.Key()
+}
@@ -0,0 +1,6 @@
+//go:build !nofailfast
+
+package failfast
+
+// Enabled reports whether iterators check for concurrent modification.
+const Enabled = true
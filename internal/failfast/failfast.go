@@ -0,0 +1,23 @@
+// Package failfast implements the modification checks that make collection
+// iterators panic with collections.ErrConcurrentModification when the
+// collection is structurally modified during iteration.
+//
+// Collections keep a modification count that is incremented by every
+// structural modification. An iterator records the count when iteration
+// begins and calls Check each time control returns to it from the loop body.
+//
+// Building with the nofailfast tag sets Enabled to false, which turns Check
+// into a no-op that the compiler removes entirely.
+package failfast
+
+import (
+	"github.com/lock14/collections"
+)
+
+// Check panics with collections.ErrConcurrentModification if the modification
+// count observed when iteration began differs from the current count.
+func Check(expected, actual int) {
+	if Enabled && expected != actual {
+		panic(collections.ErrConcurrentModification)
+	}
+}
@@ -0,0 +1,36 @@
+package failfast
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		expected int
+		actual   int
+		panics   bool
+	}{
+		{name: "unchanged", expected: 3, actual: 3, panics: false},
+		{name: "changed", expected: 3, actual: 4, panics: Enabled},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				r := recover()
+				if tc.panics && r != any(collections.ErrConcurrentModification) {
+					t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+				}
+				if !tc.panics && r != nil {
+					t.Errorf("unexpected panic: %v", r)
+				}
+			}()
+			Check(tc.expected, tc.actual)
+		})
+	}
+}
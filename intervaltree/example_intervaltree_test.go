@@ -0,0 +1,32 @@
+package intervaltree_test
+
+import (
+	"fmt"
+
+	"github.com/lock14/collections/intervaltree"
+)
+
+func ExampleIntervalTree() {
+	// Meetings as [start, end) minutes past nine.
+	calendar := intervaltree.NewOrdered[int, string]()
+	calendar.Insert(0, 30, "standup")
+	calendar.Insert(60, 120, "design review")
+	calendar.Insert(90, 150, "1:1")
+
+	// Does a new meeting from 10:00 to 10:30 conflict with anything?
+	fmt.Println("Conflict:", calendar.AnyOverlap(60, 90))
+	for m := range calendar.Overlapping(100, 110) {
+		fmt.Println(m)
+	}
+
+	// What is happening at 9:15?
+	for m := range calendar.Containing(15) {
+		fmt.Println(m.Value)
+	}
+
+	// Output:
+	// Conflict: true
+	// [60, 120): design review
+	// [90, 150): 1:1
+	// standup
+}
@@ -0,0 +1,425 @@
+// Package intervaltree provides an augmented interval tree, which stores
+// half-open intervals [lo, hi) with a value each and finds the intervals that
+// overlap a query interval or contain a point.
+//
+// The tree is an AVL tree ordered by the lower endpoint of each interval, in
+// which every node records the greatest upper endpoint in its subtree. That
+// bound lets queries skip every subtree that ends before the query begins, so
+// AnyOverlap runs in O(log n) time and Overlapping and Containing yield k
+// intervals in O(min(n, (k+1) log n)) time. The same interval may be inserted
+// more than once; intervals with equal endpoints are kept in insertion order.
+package intervaltree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
+)
+
+var _ collections.Collection[Interval[int, string]] = (*IntervalTree[int, string])(nil)
+
+// Interval is a half-open interval [Lo, Hi) and the value stored with it.
+type Interval[K any, V any] struct {
+	Lo    K `json:"lo"`
+	Hi    K `json:"hi"`
+	Value V `json:"value"`
+}
+
+// String returns a string representation of the interval.
+func (iv Interval[K, V]) String() string {
+	return fmt.Sprintf("[%+v, %+v): %+v", iv.Lo, iv.Hi, iv.Value)
+}
+
+// config holds the values for configuring an IntervalTree.
+type config[K any] struct {
+	comparator comparator.Comparator[K]
+}
+
+// Option configures an IntervalTree config.
+type Option[K any] func(*config[K])
+
+// WithComparator configures the comparator used to order the endpoints of the
+// intervals.
+func WithComparator[K any](comp comparator.Comparator[K]) Option[K] {
+	return func(c *config[K]) {
+		c.comparator = comp
+	}
+}
+
+type node[K any, V any] struct {
+	iv          Interval[K, V]
+	seq         uint64
+	max         K
+	height      int
+	left, right *node[K, V]
+}
+
+// IntervalTree is a collection of intervals with values, which may overlap and
+// may repeat. Iterators panic with collections.ErrConcurrentModification if an
+// interval is inserted or deleted while they are running.
+type IntervalTree[K any, V any] struct {
+	root       *node[K, V]
+	size       int
+	comparator comparator.Comparator[K]
+	seq        uint64
+	modCount   int
+}
+
+// New creates an empty IntervalTree with the given options.
+func New[K any, V any](opts ...Option[K]) *IntervalTree[K, V] {
+	config := &config[K]{}
+	for _, option := range opts {
+		option(config)
+	}
+	if config.comparator == nil {
+		panic("comparator must be provided or use NewOrdered")
+	}
+	return &IntervalTree[K, V]{comparator: config.comparator}
+}
+
+// NewOrdered creates an empty IntervalTree for endpoints that satisfy
+// cmp.Ordered using natural ordering.
+func NewOrdered[K cmp.Ordered, V any](opts ...Option[K]) *IntervalTree[K, V] {
+	return New[K, V](append(opts, WithComparator(comparator.NaturalOrder[K]()))...)
+}
+
+// Insert adds the interval [lo, hi) with the given value. Insert panics if lo
+// is not less than hi.
+func (t *IntervalTree[K, V]) Insert(lo, hi K, value V) {
+	if t.comparator(lo, hi) >= 0 {
+		panic(fmt.Sprintf("invalid interval [%+v, %+v)", lo, hi))
+	}
+	t.seq++
+	t.root = t.insert(t.root, &node[K, V]{iv: Interval[K, V]{lo, hi, value}, seq: t.seq, max: hi, height: 1})
+	t.size++
+	t.modCount++
+}
+
+// Delete removes the earliest inserted interval with endpoints lo and hi, and
+// returns its value and true, or false if there is no such interval.
+func (t *IntervalTree[K, V]) Delete(lo, hi K) (V, bool) {
+	return t.DeleteFunc(lo, hi, func(V) bool { return true })
+}
+
+// DeleteFunc removes the earliest inserted interval with endpoints lo and hi
+// whose value satisfies match, and returns its value and true, or false if
+// there is no such interval.
+func (t *IntervalTree[K, V]) DeleteFunc(lo, hi K, match func(V) bool) (V, bool) {
+	var found *node[K, V]
+	t.equal(t.root, lo, hi, func(n *node[K, V]) bool {
+		if match(n.iv.Value) {
+			found = n
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		var zero V
+		return zero, false
+	}
+	t.root = t.delete(t.root, found)
+	t.size--
+	t.modCount++
+	return found.iv.Value, true
+}
+
+// Clear removes all intervals from the tree.
+func (t *IntervalTree[K, V]) Clear() {
+	t.root = nil
+	t.size = 0
+	t.modCount++
+}
+
+// Size returns the number of intervals in the tree.
+func (t *IntervalTree[K, V]) Size() int {
+	return t.size
+}
+
+// Empty returns true if the tree contains no intervals.
+func (t *IntervalTree[K, V]) Empty() bool {
+	return t.size == 0
+}
+
+// All returns an iterator over all intervals in the tree, ordered by lower
+// endpoint, then by upper endpoint, then by insertion.
+func (t *IntervalTree[K, V]) All() iter.Seq[Interval[K, V]] {
+	return func(yield func(Interval[K, V]) bool) {
+		t.inOrder(t.root, t.checked(yield))
+	}
+}
+
+// Overlapping returns an iterator over the intervals that share at least one
+// point with [lo, hi), in the order of All. It yields nothing if lo is not less
+// than hi.
+func (t *IntervalTree[K, V]) Overlapping(lo, hi K) iter.Seq[Interval[K, V]] {
+	return func(yield func(Interval[K, V]) bool) {
+		if t.comparator(lo, hi) < 0 {
+			t.overlapping(t.root, lo, hi, t.checked(yield))
+		}
+	}
+}
+
+// Containing returns an iterator over the intervals that contain point, in the
+// order of All.
+func (t *IntervalTree[K, V]) Containing(point K) iter.Seq[Interval[K, V]] {
+	return func(yield func(Interval[K, V]) bool) {
+		t.containing(t.root, point, t.checked(yield))
+	}
+}
+
+// AnyOverlap returns true if at least one interval shares a point with
+// [lo, hi).
+func (t *IntervalTree[K, V]) AnyOverlap(lo, hi K) bool {
+	if t.comparator(lo, hi) >= 0 {
+		return false
+	}
+	// If the left subtree ends after lo but holds no overlapping interval, the
+	// interval ending latest there starts at or after hi, and so does every
+	// interval to the right; either way the right subtree need not be searched.
+	n := t.root
+	for n != nil && !t.overlaps(n, lo, hi) {
+		if n.left != nil && t.comparator(n.left.max, lo) > 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n != nil
+}
+
+// String returns a string representation of the tree.
+func (t *IntervalTree[K, V]) String() string {
+	ivs := make([]string, 0, t.size)
+	for iv := range t.All() {
+		ivs = append(ivs, iv.String())
+	}
+	return "{" + strings.Join(ivs, ", ") + "}"
+}
+
+// MarshalJSON encodes the tree as a JSON array of intervals in the order of
+// All, each an object with lo, hi and value fields.
+func (t *IntervalTree[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(t.All())
+}
+
+// UnmarshalJSON replaces the contents of the tree with a JSON array of
+// intervals. When called on the zero value, the tree orders endpoints of
+// ordered types by their natural order.
+func (t *IntervalTree[K, V]) UnmarshalJSON(data []byte) error {
+	if t.comparator == nil {
+		c, ok := codec.NaturalOrder[K]()
+		if !ok {
+			return fmt.Errorf("intervaltree: cannot unmarshal into an IntervalTree without a comparator")
+		}
+		*t = *New[K, V](WithComparator(c))
+	}
+	var ivs []Interval[K, V]
+	decoded := false
+	err := codec.UnmarshalSeq(data, func() { decoded = true }, func(iv Interval[K, V]) {
+		ivs = append(ivs, iv)
+	})
+	if err != nil || !decoded {
+		return err
+	}
+	for _, iv := range ivs {
+		if t.comparator(iv.Lo, iv.Hi) >= 0 {
+			return fmt.Errorf("intervaltree: invalid interval [%+v, %+v)", iv.Lo, iv.Hi)
+		}
+	}
+	t.Clear()
+	for _, iv := range ivs {
+		t.Insert(iv.Lo, iv.Hi, iv.Value)
+	}
+	return nil
+}
+
+// checked wraps yield so that the iteration panics if the tree is modified
+// while the loop body runs.
+func (t *IntervalTree[K, V]) checked(yield func(Interval[K, V]) bool) func(Interval[K, V]) bool {
+	if !failfast.Enabled {
+		return yield
+	}
+	modCount := t.modCount
+	return func(iv Interval[K, V]) bool {
+		if !yield(iv) {
+			return false
+		}
+		failfast.Check(modCount, t.modCount)
+		return true
+	}
+}
+
+func (t *IntervalTree[K, V]) inOrder(n *node[K, V], yield func(Interval[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return t.inOrder(n.left, yield) && yield(n.iv) && t.inOrder(n.right, yield)
+}
+
+func (t *IntervalTree[K, V]) overlaps(n *node[K, V], lo, hi K) bool {
+	return t.comparator(n.iv.Lo, hi) < 0 && t.comparator(lo, n.iv.Hi) < 0
+}
+
+func (t *IntervalTree[K, V]) overlapping(n *node[K, V], lo, hi K, yield func(Interval[K, V]) bool) bool {
+	if n == nil || t.comparator(n.max, lo) <= 0 {
+		return true
+	}
+	if !t.overlapping(n.left, lo, hi, yield) {
+		return false
+	}
+	if t.comparator(n.iv.Lo, hi) >= 0 {
+		return true
+	}
+	if t.comparator(lo, n.iv.Hi) < 0 && !yield(n.iv) {
+		return false
+	}
+	return t.overlapping(n.right, lo, hi, yield)
+}
+
+func (t *IntervalTree[K, V]) containing(n *node[K, V], point K, yield func(Interval[K, V]) bool) bool {
+	if n == nil || t.comparator(n.max, point) <= 0 {
+		return true
+	}
+	if !t.containing(n.left, point, yield) {
+		return false
+	}
+	if t.comparator(n.iv.Lo, point) > 0 {
+		return true
+	}
+	if t.comparator(point, n.iv.Hi) < 0 && !yield(n.iv) {
+		return false
+	}
+	return t.containing(n.right, point, yield)
+}
+
+// equal visits the nodes with endpoints lo and hi in insertion order.
+func (t *IntervalTree[K, V]) equal(n *node[K, V], lo, hi K, visit func(*node[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+	c := t.compareEndpoints(lo, hi, n)
+	if c < 0 {
+		return t.equal(n.left, lo, hi, visit)
+	}
+	if c > 0 {
+		return t.equal(n.right, lo, hi, visit)
+	}
+	return t.equal(n.left, lo, hi, visit) && visit(n) && t.equal(n.right, lo, hi, visit)
+}
+
+func (t *IntervalTree[K, V]) compareEndpoints(lo, hi K, n *node[K, V]) int {
+	if c := t.comparator(lo, n.iv.Lo); c != 0 {
+		return c
+	}
+	return t.comparator(hi, n.iv.Hi)
+}
+
+func (t *IntervalTree[K, V]) compare(a, b *node[K, V]) int {
+	if c := t.compareEndpoints(a.iv.Lo, a.iv.Hi, b); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+func (t *IntervalTree[K, V]) insert(n, x *node[K, V]) *node[K, V] {
+	if n == nil {
+		return x
+	}
+	if t.compare(x, n) < 0 {
+		n.left = t.insert(n.left, x)
+	} else {
+		n.right = t.insert(n.right, x)
+	}
+	return t.balance(n)
+}
+
+func (t *IntervalTree[K, V]) delete(n, x *node[K, V]) *node[K, V] {
+	if c := t.compare(x, n); c < 0 {
+		n.left = t.delete(n.left, x)
+		return t.balance(n)
+	} else if c > 0 {
+		n.right = t.delete(n.right, x)
+		return t.balance(n)
+	}
+	if n.left == nil {
+		return n.right
+	}
+	if n.right == nil {
+		return n.left
+	}
+	right, successor := t.deleteMin(n.right)
+	successor.left, successor.right = n.left, right
+	return t.balance(successor)
+}
+
+// deleteMin removes the leftmost node of the subtree rooted at n, and returns
+// the new root of the subtree and the removed node.
+func (t *IntervalTree[K, V]) deleteMin(n *node[K, V]) (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	left, min := t.deleteMin(n.left)
+	n.left = left
+	return t.balance(n), min
+}
+
+func height[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recomputes the height and maximum upper endpoint of n from its
+// children.
+func (t *IntervalTree[K, V]) update(n *node[K, V]) {
+	n.height = 1 + max(height(n.left), height(n.right))
+	n.max = n.iv.Hi
+	if n.left != nil && t.comparator(n.left.max, n.max) > 0 {
+		n.max = n.left.max
+	}
+	if n.right != nil && t.comparator(n.right.max, n.max) > 0 {
+		n.max = n.right.max
+	}
+}
+
+func (t *IntervalTree[K, V]) balance(n *node[K, V]) *node[K, V] {
+	t.update(n)
+	switch bf := height(n.left) - height(n.right); {
+	case bf > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = t.rotateLeft(n.left)
+		}
+		return t.rotateRight(n)
+	case bf < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = t.rotateRight(n.right)
+		}
+		return t.rotateLeft(n)
+	}
+	return n
+}
+
+func (t *IntervalTree[K, V]) rotateLeft(n *node[K, V]) *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	t.update(n)
+	t.update(r)
+	return r
+}
+
+func (t *IntervalTree[K, V]) rotateRight(n *node[K, V]) *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	t.update(n)
+	t.update(l)
+	return l
+}
@@ -0,0 +1,43 @@
+package intervaltree_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections/intervaltree"
+)
+
+func BenchmarkIntervalTree_Insert(b *testing.B) {
+	t := intervaltree.NewOrdered[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lo := (i * 7919) % 100000
+		t.Insert(lo, lo+100, i)
+	}
+}
+
+func BenchmarkIntervalTree_Overlapping(b *testing.B) {
+	t := intervaltree.NewOrdered[int, int]()
+	for i := 0; i < 10000; i++ {
+		lo := (i * 7919) % 100000
+		t.Insert(lo, lo+100, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lo := (i * 104729) % 100000
+		for range t.Overlapping(lo, lo+50) {
+		}
+	}
+}
+
+func BenchmarkIntervalTree_AnyOverlap(b *testing.B) {
+	t := intervaltree.NewOrdered[int, int]()
+	for i := 0; i < 10000; i++ {
+		lo := (i * 7919) % 100000
+		t.Insert(lo, lo+5, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lo := (i * 104729) % 100000
+		t.AnyOverlap(lo, lo+2)
+	}
+}
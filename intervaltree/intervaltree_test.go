@@ -0,0 +1,250 @@
+package intervaltree
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+)
+
+func intervals(t *IntervalTree[int, string]) []Interval[int, string] {
+	return slices.Collect(t.All())
+}
+
+func TestQueries(t *testing.T) {
+	t.Parallel()
+	tree := NewOrdered[int, string]()
+	tree.Insert(1, 5, "a")
+	tree.Insert(3, 8, "b")
+	tree.Insert(10, 12, "c")
+	tree.Insert(1, 5, "d")
+	tree.Insert(0, 20, "e")
+	cases := []struct {
+		name string
+		seq  func() []Interval[int, string]
+		want []string
+	}{
+		{name: "all", seq: func() []Interval[int, string] { return intervals(tree) }, want: []string{"e", "a", "d", "b", "c"}},
+		{name: "overlapping", seq: func() []Interval[int, string] { return slices.Collect(tree.Overlapping(4, 10)) }, want: []string{"e", "a", "d", "b"}},
+		{name: "touching is not overlapping", seq: func() []Interval[int, string] { return slices.Collect(tree.Overlapping(8, 10)) }, want: []string{"e"}},
+		{name: "empty query", seq: func() []Interval[int, string] { return slices.Collect(tree.Overlapping(4, 4)) }},
+		{name: "containing", seq: func() []Interval[int, string] { return slices.Collect(tree.Containing(3)) }, want: []string{"e", "a", "d", "b"}},
+		{name: "containing upper endpoint", seq: func() []Interval[int, string] { return slices.Collect(tree.Containing(12)) }, want: []string{"e"}},
+		{name: "containing nothing", seq: func() []Interval[int, string] { return slices.Collect(tree.Containing(20)) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, iv := range tc.seq() {
+				got = append(got, iv.Value)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAnyOverlap(t *testing.T) {
+	t.Parallel()
+	tree := NewOrdered[int, int]()
+	for i := 0; i < 100; i += 10 {
+		tree.Insert(i, i+5, i)
+	}
+	cases := []struct {
+		lo, hi int
+		want   bool
+	}{
+		{lo: 5, hi: 10, want: false},
+		{lo: 4, hi: 10, want: true},
+		{lo: 95, hi: 200, want: false},
+		{lo: -10, hi: 1, want: true},
+		{lo: 42, hi: 43, want: true},
+		{lo: 42, hi: 42, want: false},
+	}
+	for _, tc := range cases {
+		if got := tree.AnyOverlap(tc.lo, tc.hi); got != tc.want {
+			t.Errorf("expected AnyOverlap(%d, %d) to be %t", tc.lo, tc.hi, tc.want)
+		}
+	}
+	if NewOrdered[int, int]().AnyOverlap(0, 1) {
+		t.Errorf("expected an empty tree to overlap nothing")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	tree := NewOrdered[int, string]()
+	tree.Insert(1, 5, "a")
+	tree.Insert(1, 5, "b")
+	tree.Insert(1, 5, "c")
+	tree.Insert(2, 3, "d")
+	if v, ok := tree.Delete(1, 5); !ok || v != "a" {
+		t.Errorf("expected to delete the earliest inserted interval, got %s, %t", v, ok)
+	}
+	if v, ok := tree.DeleteFunc(1, 5, func(v string) bool { return v == "c" }); !ok || v != "c" {
+		t.Errorf("expected to delete c, got %s, %t", v, ok)
+	}
+	if _, ok := tree.DeleteFunc(1, 5, func(v string) bool { return v == "x" }); ok {
+		t.Errorf("expected no interval to match")
+	}
+	if _, ok := tree.Delete(1, 4); ok {
+		t.Errorf("expected no interval [1, 4)")
+	}
+	if got, want := tree.String(), "{[1, 5): b, [2, 3): d}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	tree.Clear()
+	if !tree.Empty() || tree.Size() != 0 || tree.AnyOverlap(0, 10) {
+		t.Errorf("expected an empty tree, got %v", tree)
+	}
+}
+
+// check verifies the AVL and maximum endpoint invariants of the subtree rooted
+// at n, and returns its height.
+func check[K any, V any](t *testing.T, tree *IntervalTree[K, V], n *node[K, V]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	l, r := check(t, tree, n.left), check(t, tree, n.right)
+	if l-r > 1 || r-l > 1 || n.height != 1+max(l, r) {
+		t.Fatalf("unbalanced node %v: left height %d, right height %d, height %d", n.iv, l, r, n.height)
+	}
+	want := n.iv.Hi
+	for _, c := range []*node[K, V]{n.left, n.right} {
+		if c != nil && tree.comparator(c.max, want) > 0 {
+			want = c.max
+		}
+	}
+	if tree.comparator(n.max, want) != 0 {
+		t.Fatalf("node %v has max %v, want %v", n.iv, n.max, want)
+	}
+	return n.height
+}
+
+func TestRandomized(t *testing.T) {
+	t.Parallel()
+	r := rand.New(rand.NewPCG(1, 2))
+	tree := NewOrdered[int, int]()
+	var model []Interval[int, int]
+	for i := 0; i < 2000; i++ {
+		lo := r.IntN(100)
+		hi := lo + 1 + r.IntN(20)
+		if r.IntN(3) > 0 || len(model) == 0 {
+			tree.Insert(lo, hi, i)
+			model = append(model, Interval[int, int]{lo, hi, i})
+		} else {
+			iv := model[r.IntN(len(model))]
+			v, ok := tree.Delete(iv.Lo, iv.Hi)
+			j := slices.IndexFunc(model, func(m Interval[int, int]) bool { return m.Lo == iv.Lo && m.Hi == iv.Hi })
+			if !ok || v != model[j].Value {
+				t.Fatalf("expected Delete(%d, %d) to return %d, got %d, %t", iv.Lo, iv.Hi, model[j].Value, v, ok)
+			}
+			model = slices.Delete(model, j, j+1)
+		}
+		check(t, tree, tree.root)
+		if tree.Size() != len(model) {
+			t.Fatalf("expected size %d, got %d", len(model), tree.Size())
+		}
+
+		qlo := r.IntN(130) - 10
+		qhi := qlo + r.IntN(15)
+		var want []Interval[int, int]
+		for _, m := range model {
+			if qlo < qhi && m.Lo < qhi && qlo < m.Hi {
+				want = append(want, m)
+			}
+		}
+		got := slices.Collect(tree.Overlapping(qlo, qhi))
+		sortIntervals(want)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Overlapping(%d, %d): expected %v, got %v", qlo, qhi, want, got)
+		}
+		if tree.AnyOverlap(qlo, qhi) != (len(want) > 0) {
+			t.Fatalf("AnyOverlap(%d, %d): expected %t", qlo, qhi, len(want) > 0)
+		}
+		want = want[:0]
+		for _, m := range model {
+			if m.Lo <= qlo && qlo < m.Hi {
+				want = append(want, m)
+			}
+		}
+		sortIntervals(want)
+		if got := slices.Collect(tree.Containing(qlo)); !slices.Equal(got, want) {
+			t.Fatalf("Containing(%d): expected %v, got %v", qlo, want, got)
+		}
+	}
+}
+
+func sortIntervals(ivs []Interval[int, int]) {
+	slices.SortStableFunc(ivs, func(a, b Interval[int, int]) int {
+		return cmp.Or(cmp.Compare(a.Lo, b.Lo), cmp.Compare(a.Hi, b.Hi))
+	})
+}
+
+func TestInvalidInterval(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected an empty interval to panic")
+		}
+	}()
+	NewOrdered[int, int]().Insert(3, 3, 0)
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	if !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	tree := NewOrdered[int, int]()
+	tree.Insert(0, 10, 0)
+	tree.Insert(5, 15, 1)
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, collections.ErrConcurrentModification) {
+			t.Errorf("expected ErrConcurrentModification, got %v", err)
+		}
+	}()
+	for iv := range tree.Containing(7) {
+		tree.Insert(iv.Lo, iv.Hi, iv.Value)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	tree := NewOrdered[int, string]()
+	tree.Insert(5, 9, "b")
+	tree.Insert(1, 3, "a")
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `[{"lo":1,"hi":3,"value":"a"},{"lo":5,"hi":9,"value":"b"}]`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	var got IntervalTree[int, string]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !slices.Equal(intervals(&got), intervals(tree)) {
+		t.Errorf("expected %v, got %v", tree, &got)
+	}
+	if err := json.Unmarshal([]byte(`[{"lo":4,"hi":2,"value":"x"}]`), &got); err == nil {
+		t.Errorf("expected an error for an invalid interval")
+	}
+	if got.Size() != 2 {
+		t.Errorf("expected a failed decode to leave the tree unchanged, got %v", &got)
+	}
+	var noOrder IntervalTree[struct{}, int]
+	if err := json.Unmarshal([]byte(`[]`), &noOrder); err == nil {
+		t.Errorf("expected an error decoding a type without a natural order")
+	}
+}
@@ -4,8 +4,11 @@ package labeledgraph
 import (
 	"fmt"
 	"github.com/lock14/collections/hashset"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"maps"
+	"slices"
 	"strings"
 )
 
@@ -34,10 +37,15 @@ func WithCapacity(n int) Opt {
 
 // LabeledGraph is a graph with vertices of type V
 // and whose edges are labeled with type L.
+//
+// Its iterators panic with collections.ErrConcurrentModification if a vertex
+// or edge is added or removed during iteration. Changing the label of an
+// existing edge is not a structural modification.
 type LabeledGraph[V comparable, L any] struct {
 	graph     map[V]nodeData[V, L]
 	directed  bool
 	edgeCount int
+	modCount  int
 }
 
 // New returns a new LabeledGraph constructed according to the given options.
@@ -63,6 +71,7 @@ func (g *LabeledGraph[V, L]) ContainsVertex(v V) bool {
 func (g *LabeledGraph[V, L]) AddVertex(v V) {
 	if !g.ContainsVertex(v) {
 		g.graph[v] = g.nodeData()
+		g.modCount++
 	}
 }
 
@@ -86,6 +95,7 @@ func (g *LabeledGraph[V, L]) RemoveVertex(v V) {
 			g.graph[u].removePredecessor(v)
 		}
 		delete(g.graph, v)
+		g.modCount++
 	}
 }
 
@@ -102,6 +112,7 @@ func (g *LabeledGraph[V, L]) AddEdge(u, v V, l L) {
 	g.AddVertex(v)
 	if !g.ContainsEdge(u, v) {
 		g.edgeCount++
+		g.modCount++
 	}
 	g.graph[u].addSuccessor(v, l)
 	g.graph[v].addPredecessor(u, l)
@@ -113,6 +124,7 @@ func (g *LabeledGraph[V, L]) RemoveEdge(u, v V) {
 		g.graph[u].removeSuccessor(v)
 		g.graph[v].removePredecessor(u)
 		g.edgeCount--
+		g.modCount++
 	}
 }
 
@@ -196,7 +208,15 @@ func (g *LabeledGraph[V, L]) OutDegree(u V) (int, bool) {
 
 // Vertices returns an iterator over all vertices in the graph.
 func (g *LabeledGraph[V, L]) Vertices() iter.Seq[V] {
-	return maps.Keys(g.graph)
+	return func(yield func(V) bool) {
+		modCount := g.modCount
+		for v := range g.graph {
+			if !yield(v) {
+				return
+			}
+			failfast.Check(modCount, g.modCount)
+		}
+	}
 }
 
 // Neighbors is an alias for Successors.
@@ -211,11 +231,13 @@ func (g *LabeledGraph[V, L]) Neighbors(v V) iter.Seq[V] {
 //   - For undirected graphs, Predecessors, Successors, and Neighbors are all synonymous.
 func (g *LabeledGraph[V, L]) Successors(u V) iter.Seq[V] {
 	return func(yield func(V) bool) {
+		modCount := g.modCount
 		if g.ContainsVertex(u) {
 			for v := range g.graph[u].successors() {
 				if !yield(v) {
 					return
 				}
+				failfast.Check(modCount, g.modCount)
 			}
 		}
 	}
@@ -228,11 +250,13 @@ func (g *LabeledGraph[V, L]) Successors(u V) iter.Seq[V] {
 //   - For undirected graphs, Predecessors, Successors, and Neighbors are all synonymous.
 func (g *LabeledGraph[V, L]) Predecessors(v V) iter.Seq[V] {
 	return func(yield func(V) bool) {
+		modCount := g.modCount
 		if g.ContainsVertex(v) {
 			for u := range g.graph[v].predecessors() {
 				if !yield(u) {
 					return
 				}
+				failfast.Check(modCount, g.modCount)
 			}
 		}
 	}
@@ -241,6 +265,7 @@ func (g *LabeledGraph[V, L]) Predecessors(v V) iter.Seq[V] {
 // Edges returns an iterator over all edges in the graph.
 func (g *LabeledGraph[V, L]) Edges() iter.Seq2[V, V] {
 	return func(yield func(V, V) bool) {
+		modCount := g.modCount
 		for u, node := range g.graph {
 			if g.directed {
 				dNode := node.(*directedNodeData[V, L])
@@ -248,6 +273,7 @@ func (g *LabeledGraph[V, L]) Edges() iter.Seq2[V, V] {
 					if !yield(u, v) {
 						return
 					}
+					failfast.Check(modCount, g.modCount)
 				}
 			} else {
 				uNode := node.(*undirectedNodeData[V, L])
@@ -255,6 +281,7 @@ func (g *LabeledGraph[V, L]) Edges() iter.Seq2[V, V] {
 					if !yield(u, v) {
 						return
 					}
+					failfast.Check(modCount, g.modCount)
 				}
 			}
 		}
@@ -316,6 +343,7 @@ func (g *LabeledGraph[V, L]) OutIncidentEdges(u V) iter.Seq2[V, V] {
 func (g *LabeledGraph[V, L]) Clear() {
 	clear(g.graph)
 	g.edgeCount = 0
+	g.modCount++
 }
 
 // Clone returns a deep copy of the graph.
@@ -381,6 +409,73 @@ func (g *LabeledGraph[V, L]) Equal(other *LabeledGraph[V, L], eq func(L, L) bool
 	return true
 }
 
+var binaryFormat = codec.Format{Name: "labeledgraph", Magic: "LGPH", Version: 1}
+
+// MarshalBinary encodes the graph in a versioned binary format built on
+// encoding/gob, so V and L must be encodable by gob. The encoding records
+// whether the graph is directed, and holds each edge of an undirected graph
+// once.
+func (g *LabeledGraph[V, L]) MarshalBinary() ([]byte, error) {
+	type edgePair struct{ u, v V }
+	seen := make(map[edgePair]bool)
+
+	var us, vs []V
+	var labels []L
+	for u, v := range g.Edges() {
+		if !g.directed {
+			if seen[edgePair{v, u}] {
+				continue
+			}
+			seen[edgePair{u, v}] = true
+		}
+		l, _ := g.Label(u, v)
+		us, vs, labels = append(us, u), append(vs, v), append(labels, l)
+	}
+
+	e := binaryFormat.NewEncoder()
+	e.Encode(g.directed)
+	e.Encode(slices.Collect(g.Vertices()))
+	e.Encode(us)
+	e.Encode(vs)
+	e.Encode(labels)
+	return e.Bytes()
+}
+
+// UnmarshalBinary replaces the graph with one decoded from data produced by
+// MarshalBinary, including whether it is directed.
+func (g *LabeledGraph[V, L]) UnmarshalBinary(data []byte) error {
+	d, err := binaryFormat.NewDecoder(data)
+	if err != nil {
+		return err
+	}
+	var directed bool
+	var vertices, us, vs []V
+	var labels []L
+	for _, v := range []any{&directed, &vertices, &us, &vs, &labels} {
+		if err := d.Decode(v); err != nil {
+			return err
+		}
+	}
+	if len(us) != len(vs) || len(us) != len(labels) {
+		return fmt.Errorf("labeledgraph: corrupt binary encoding")
+	}
+
+	opts := []Opt{WithCapacity(len(vertices))}
+	if directed {
+		opts = append(opts, WithDirected())
+	}
+	decoded := New[V, L](opts...)
+	for _, v := range vertices {
+		decoded.AddVertex(v)
+	}
+	for i := range us {
+		decoded.AddEdge(us[i], vs[i], labels[i])
+	}
+	decoded.modCount = g.modCount + 1
+	*g = *decoded
+	return nil
+}
+
 // String returns a string representation of the graph.
 func (g *LabeledGraph[V, L]) String() string {
 	var sb strings.Builder
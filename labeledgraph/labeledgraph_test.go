@@ -1,9 +1,15 @@
 package labeledgraph
 
 import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
 	"slices"
 	"strings"
 	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
 )
 
 func TestNew(t *testing.T) {
@@ -644,3 +650,141 @@ func TestLabeledGraph_Coverage(t *testing.T) {
 	g5 := New[int, int](WithDirected())
 	_ = g3.Equal(g5, func(a, b int) bool { return a == b })
 }
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		opts []Opt
+	}{
+		{name: "undirected"},
+		{name: "directed", opts: []Opt{WithDirected()}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := New[int, string](tc.opts...)
+			g.AddEdge(1, 2, "a")
+			g.AddEdge(2, 3, "b")
+			g.AddEdge(3, 3, "loop")
+			g.AddVertex(4)
+			data, err := g.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			got := New[int, string]()
+			got.AddEdge(5, 6, "stale")
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			if !got.Equal(g, func(a, b string) bool { return a == b }) {
+				t.Errorf("expected %v, got %v", g, got)
+			}
+		})
+	}
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+	g := New[string, float64](WithDirected())
+	g.AddEdge("a", "b", 1.5)
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(g); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var got LabeledGraph[string, float64]
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if l, ok := got.Label("a", "b"); !got.Directed() || !ok || l != 1.5 {
+		t.Errorf("expected a directed edge a -> b labeled 1.5, got %v", &got)
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	iterators := []struct {
+		name string
+		walk func(g *LabeledGraph[int, string], body func())
+	}{
+		{name: "vertices", walk: func(g *LabeledGraph[int, string], body func()) {
+			for range g.Vertices() {
+				body()
+			}
+		}},
+		{name: "successors", walk: func(g *LabeledGraph[int, string], body func()) {
+			for range g.Successors(1) {
+				body()
+			}
+		}},
+		{name: "predecessors", walk: func(g *LabeledGraph[int, string], body func()) {
+			for range g.Predecessors(2) {
+				body()
+			}
+		}},
+		{name: "edges", walk: func(g *LabeledGraph[int, string], body func()) {
+			for range g.Edges() {
+				body()
+			}
+		}},
+		{name: "incident_edges", walk: func(g *LabeledGraph[int, string], body func()) {
+			for range g.IncidentEdges(2) {
+				body()
+			}
+		}},
+	}
+	cases := []struct {
+		name   string
+		modify func(g *LabeledGraph[int, string])
+		panics bool
+	}{
+		{name: "add_vertex", modify: func(g *LabeledGraph[int, string]) { g.AddVertex(9) }, panics: true},
+		{name: "remove_vertex", modify: func(g *LabeledGraph[int, string]) { g.RemoveVertex(3) }, panics: true},
+		{name: "add_edge", modify: func(g *LabeledGraph[int, string]) { g.AddEdge(3, 1, "c") }, panics: true},
+		{name: "remove_edge", modify: func(g *LabeledGraph[int, string]) { g.RemoveEdge(2, 3) }, panics: true},
+		{name: "clear", modify: func(g *LabeledGraph[int, string]) { g.Clear() }, panics: true},
+		{name: "set_label", modify: func(g *LabeledGraph[int, string]) { g.SetLabel(1, 2, "z") }, panics: false},
+		{name: "relabel_edge", modify: func(g *LabeledGraph[int, string]) { g.AddEdge(1, 2, "y") }, panics: false},
+	}
+	for _, directed := range []bool{false, true} {
+		for _, it := range iterators {
+			it := it
+			for _, tc := range cases {
+				tc := tc
+				name := fmt.Sprintf("directed_%t/%s/%s", directed, it.name, tc.name)
+				directed := directed
+				t.Run(name, func(t *testing.T) {
+					t.Parallel()
+					var opts []Opt
+					if directed {
+						opts = append(opts, WithDirected())
+					}
+					g := New[int, string](opts...)
+					g.AddEdge(1, 2, "a")
+					g.AddEdge(2, 3, "b")
+					assertConcurrentModification(t, tc.panics, func() {
+						it.walk(g, func() { tc.modify(g) })
+					})
+				})
+			}
+		}
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
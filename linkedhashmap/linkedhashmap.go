@@ -3,6 +3,8 @@ package linkedhashmap
 
 import (
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"math"
 )
@@ -58,11 +60,17 @@ func WithCapacity(capacity int) Opt {
 // public functions/receivers
 
 // LinkedHashMap is a hash map that preserves insertion or access order.
+//
+// Iterators panic with collections.ErrConcurrentModification if the map is
+// structurally modified during iteration. Adding or removing a key is a
+// structural modification, as is any operation that moves an entry, which
+// includes Get and Put on an existing key when the map is in access order.
 type LinkedHashMap[K comparable, V any] struct {
 	hashtable   map[K]*node[K, V]
 	list        *node[K, V]
 	accessOrder KeyOrder
 	maxElements int
+	modCount    int
 }
 
 // New creates a new LinkedHashMap with the given options.
@@ -79,6 +87,17 @@ func New[K comparable, V any](opts ...Opt) *LinkedHashMap[K, V] {
 	}
 }
 
+// FromSeq creates a LinkedHashMap containing the key-value pairs of the given sequence
+// in the order they are produced. Use WithCapacity to pre-size the map when the
+// length of the sequence is known.
+func FromSeq[K comparable, V any](sequence iter.Seq2[K, V], opts ...Opt) *LinkedHashMap[K, V] {
+	hm := New[K, V](opts...)
+	for k, v := range sequence {
+		hm.Put(k, v)
+	}
+	return hm
+}
+
 func (hm *LinkedHashMap[K, V]) Put(key K, value V) {
 	n, ok := hm.hashtable[key]
 	if ok {
@@ -87,6 +106,7 @@ func (hm *LinkedHashMap[K, V]) Put(key K, value V) {
 			unlink(n)
 			// make n the tail of the list
 			insertBefore(hm.list, n)
+			hm.modCount++
 		}
 	} else {
 		n = &node[K, V]{
@@ -96,6 +116,7 @@ func (hm *LinkedHashMap[K, V]) Put(key K, value V) {
 		hm.hashtable[key] = n
 		// make n the tail of the list
 		insertBefore(hm.list, n)
+		hm.modCount++
 		if hm.removeEldest() {
 			eldest := hm.list.next
 			unlink(eldest)
@@ -110,6 +131,7 @@ func (hm *LinkedHashMap[K, V]) PutFirst(key K, value V) {
 		n.value = value
 		unlink(n)
 		insertBefore(hm.list.next, n)
+		hm.modCount++
 	} else {
 		n = &node[K, V]{
 			key:   key,
@@ -117,6 +139,7 @@ func (hm *LinkedHashMap[K, V]) PutFirst(key K, value V) {
 		}
 		hm.hashtable[key] = n
 		insertBefore(hm.list.next, n)
+		hm.modCount++
 		if hm.removeEldest() {
 			eldest := hm.list.prev
 			unlink(eldest)
@@ -131,6 +154,7 @@ func (hm *LinkedHashMap[K, V]) PutLast(key K, value V) {
 		n.value = value
 		unlink(n)
 		insertBefore(hm.list, n)
+		hm.modCount++
 	} else {
 		hm.Put(key, value)
 	}
@@ -162,6 +186,7 @@ func (hm *LinkedHashMap[K, V]) PollFirst() (K, V) {
 	e := hm.list.next
 	unlink(e)
 	delete(hm.hashtable, e.key)
+	hm.modCount++
 	return e.key, e.value
 }
 
@@ -173,6 +198,7 @@ func (hm *LinkedHashMap[K, V]) PollLast() (K, V) {
 	e := hm.list.prev
 	unlink(e)
 	delete(hm.hashtable, e.key)
+	hm.modCount++
 	return e.key, e.value
 }
 
@@ -186,6 +212,7 @@ func (hm *LinkedHashMap[K, V]) Get(key K) (V, bool) {
 		unlink(n)
 		// make n the tail of the list
 		insertBefore(hm.list, n)
+		hm.modCount++
 	}
 	return n.value, true
 }
@@ -195,6 +222,7 @@ func (hm *LinkedHashMap[K, V]) Remove(key K) {
 	if ok {
 		unlink(n)
 		delete(hm.hashtable, key)
+		hm.modCount++
 	}
 }
 
@@ -214,64 +242,109 @@ func (hm *LinkedHashMap[K, V]) Empty() bool {
 func (hm *LinkedHashMap[K, V]) Clear() {
 	hm.hashtable = make(map[K]*node[K, V])
 	hm.list = sentinel[K, V]()
+	hm.modCount++
 }
 
 func (hm *LinkedHashMap[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		for cur := hm.list.next; cur != hm.list && yield(cur.key, cur.value); {
-			cur = cur.next
+		modCount := hm.modCount
+		for cur := hm.list.next; cur != hm.list; cur = cur.next {
+			if !yield(cur.key, cur.value) {
+				return
+			}
+			failfast.Check(modCount, hm.modCount)
 		}
 	}
 }
 
+// MarshalJSON encodes the map as a JSON object with its entries in iteration
+// order, or as a JSON array of [key, value] pairs if K cannot be an object key.
+func (hm *LinkedHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(hm.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object or array of [key, value] pairs, inserted in the order they appear. The
+// key order and maximum size of the map are retained; the zero value decodes
+// into a map in insertion order.
+func (hm *LinkedHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	if hm.list == nil {
+		*hm = *New[K, V]()
+	}
+	return codec.UnmarshalMap(data, hm.Clear, hm.Put)
+}
+
 func (hm *LinkedHashMap[K, V]) Keys() iter.Seq[K] {
 	return func(yield func(K) bool) {
+		modCount := hm.modCount
 		for cur := hm.list.next; cur != hm.list; cur = cur.next {
 			if !yield(cur.key) {
 				return
 			}
+			failfast.Check(modCount, hm.modCount)
 		}
 	}
 }
 
 func (hm *LinkedHashMap[K, V]) Values() iter.Seq[V] {
 	return func(yield func(V) bool) {
+		modCount := hm.modCount
 		for cur := hm.list.next; cur != hm.list; cur = cur.next {
 			if !yield(cur.value) {
 				return
 			}
+			failfast.Check(modCount, hm.modCount)
 		}
 	}
 }
 
 func (hm *LinkedHashMap[K, V]) Backward() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		for cur := hm.list.prev; cur != hm.list && yield(cur.key, cur.value); {
-			cur = cur.prev
+		modCount := hm.modCount
+		for cur := hm.list.prev; cur != hm.list; cur = cur.prev {
+			if !yield(cur.key, cur.value) {
+				return
+			}
+			failfast.Check(modCount, hm.modCount)
 		}
 	}
 }
 
 func (hm *LinkedHashMap[K, V]) BackwardKeys() iter.Seq[K] {
 	return func(yield func(K) bool) {
+		modCount := hm.modCount
 		for cur := hm.list.prev; cur != hm.list; cur = cur.prev {
 			if !yield(cur.key) {
 				return
 			}
+			failfast.Check(modCount, hm.modCount)
 		}
 	}
 }
 
 func (hm *LinkedHashMap[K, V]) BackwardValues() iter.Seq[V] {
 	return func(yield func(V) bool) {
+		modCount := hm.modCount
 		for cur := hm.list.prev; cur != hm.list; cur = cur.prev {
 			if !yield(cur.value) {
 				return
 			}
+			failfast.Check(modCount, hm.modCount)
 		}
 	}
 }
 
+// Cursor returns a cursor positioned outside the map. All cursor operations
+// take O(1) time, and reading through the cursor does not reorder entries of
+// an access-ordered map.
+func (hm *LinkedHashMap[K, V]) Cursor() collections.MapCursor[K, V] {
+	return &cursor[K, V]{
+		hm:       hm,
+		cur:      hm.list,
+		modCount: hm.modCount,
+	}
+}
+
 func (hm *LinkedHashMap[K, V]) removeEldest() bool {
 	return hm.Size() > hm.maxElements
 }
@@ -312,3 +385,73 @@ func unlink[K, V any](n *node[K, V]) {
 	n.prev = nil
 	n.next = nil
 }
+
+// cursor stuff
+
+var _ collections.MapCursor[int, int] = (*cursor[int, int])(nil)
+
+// cursor is a collections.MapCursor over a LinkedHashMap. The cursor is outside
+// the map when cur is the sentinel, and in a gap after Remove when cur is nil,
+// in which case gapPrev is the node that precedes the gap.
+type cursor[K comparable, V any] struct {
+	hm       *LinkedHashMap[K, V]
+	cur      *node[K, V]
+	gapPrev  *node[K, V]
+	modCount int
+}
+
+func (c *cursor[K, V]) Next() bool {
+	c.check()
+	if c.cur != nil {
+		c.cur = c.cur.next
+	} else {
+		c.cur = c.gapPrev.next
+	}
+	return c.cur != c.hm.list
+}
+
+func (c *cursor[K, V]) Prev() bool {
+	c.check()
+	if c.cur != nil {
+		c.cur = c.cur.prev
+	} else {
+		c.cur = c.gapPrev
+	}
+	return c.cur != c.hm.list
+}
+
+func (c *cursor[K, V]) Key() K {
+	c.checkEntry()
+	return c.cur.key
+}
+
+func (c *cursor[K, V]) Value() V {
+	c.checkEntry()
+	return c.cur.value
+}
+
+func (c *cursor[K, V]) SetValue(v V) {
+	c.checkEntry()
+	c.cur.value = v
+}
+
+func (c *cursor[K, V]) Remove() {
+	c.checkEntry()
+	c.gapPrev = c.cur.prev
+	unlink(c.cur)
+	delete(c.hm.hashtable, c.cur.key)
+	c.cur = nil
+	c.hm.modCount++
+	c.modCount = c.hm.modCount
+}
+
+func (c *cursor[K, V]) check() {
+	failfast.Check(c.modCount, c.hm.modCount)
+}
+
+func (c *cursor[K, V]) checkEntry() {
+	c.check()
+	if c.cur == nil || c.cur == c.hm.list {
+		panic("cursor is not positioned on an entry")
+	}
+}
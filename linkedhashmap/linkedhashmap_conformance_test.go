@@ -0,0 +1,19 @@
+package linkedhashmap_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/linkedhashmap"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableSequencedMap(t, func() collections.MutableSequencedMap[int, int] {
+		return linkedhashmap.New[int, int]()
+	}, collectionstest.Ints(20), collectionstest.Ints(10))
+	collectionstest.MutableSequencedMap(t, func() collections.MutableSequencedMap[int, int] {
+		return linkedhashmap.New[int, int](linkedhashmap.WithAccessOrder())
+	}, collectionstest.Ints(20), collectionstest.Ints(10))
+}
@@ -1,6 +1,10 @@
 package linkedhashmap
 
 import (
+	"encoding/json"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
 	"slices"
 	"testing"
 )
@@ -505,3 +509,169 @@ func TestLinkedHashMap_Backward(t *testing.T) {
 		break
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name         string
+		keys         []string
+		opts         []Opt
+		expectedKeys []string
+		expectedVals []int
+	}{
+		{
+			name:         "empty",
+			keys:         nil,
+			expectedKeys: nil,
+			expectedVals: nil,
+		},
+		{
+			name:         "insertion_order",
+			keys:         []string{"c", "a", "b"},
+			expectedKeys: []string{"c", "a", "b"},
+			expectedVals: []int{0, 1, 2},
+		},
+		{
+			name:         "duplicate_keeps_position_and_last_value",
+			keys:         []string{"a", "b", "a"},
+			expectedKeys: []string{"a", "b"},
+			expectedVals: []int{2, 1},
+		},
+		{
+			name:         "max_elements_evicts_eldest",
+			keys:         []string{"a", "b", "c", "d"},
+			opts:         []Opt{WithMaxElements(2)},
+			expectedKeys: []string{"c", "d"},
+			expectedVals: []int{2, 3},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			hm := FromSeq(func(yield func(string, int) bool) {
+				for i, k := range tc.keys {
+					if !yield(k, i) {
+						return
+					}
+				}
+			}, tc.opts...)
+			if keys := slices.Collect(hm.Keys()); !slices.Equal(keys, tc.expectedKeys) {
+				t.Errorf("expected keys %v, got %v", tc.expectedKeys, keys)
+			}
+			if vals := slices.Collect(hm.Values()); !slices.Equal(vals, tc.expectedVals) {
+				t.Errorf("expected values %v, got %v", tc.expectedVals, vals)
+			}
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		opts   []Opt
+		modify func(m *LinkedHashMap[int, int])
+		panics bool
+	}{
+		{name: "put new key", modify: func(m *LinkedHashMap[int, int]) { m.Put(4, 4) }, panics: true},
+		{name: "put existing key", modify: func(m *LinkedHashMap[int, int]) { m.Put(1, 10) }, panics: false},
+		{name: "put existing key in access order", opts: []Opt{WithAccessOrder()}, modify: func(m *LinkedHashMap[int, int]) { m.Put(1, 10) }, panics: true},
+		{name: "put first", modify: func(m *LinkedHashMap[int, int]) { m.PutFirst(3, 3) }, panics: true},
+		{name: "put last", modify: func(m *LinkedHashMap[int, int]) { m.PutLast(1, 1) }, panics: true},
+		{name: "poll first", modify: func(m *LinkedHashMap[int, int]) { m.PollFirst() }, panics: true},
+		{name: "poll last", modify: func(m *LinkedHashMap[int, int]) { m.PollLast() }, panics: true},
+		{name: "remove", modify: func(m *LinkedHashMap[int, int]) { m.Remove(2) }, panics: true},
+		{name: "remove absent", modify: func(m *LinkedHashMap[int, int]) { m.Remove(5) }, panics: false},
+		{name: "clear", modify: func(m *LinkedHashMap[int, int]) { m.Clear() }, panics: true},
+		{name: "get", modify: func(m *LinkedHashMap[int, int]) { m.Get(1) }, panics: false},
+		{name: "get in access order", opts: []Opt{WithAccessOrder()}, modify: func(m *LinkedHashMap[int, int]) { m.Get(1) }, panics: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for _, it := range []struct {
+				name string
+				seq  func(m *LinkedHashMap[int, int]) iter.Seq2[int, int]
+			}{
+				{name: "all", seq: (*LinkedHashMap[int, int]).All},
+				{name: "backward", seq: (*LinkedHashMap[int, int]).Backward},
+			} {
+				m := New[int, int](tc.opts...)
+				for i := 1; i <= 3; i++ {
+					m.Put(i, i)
+				}
+				t.Run(it.name, func(t *testing.T) {
+					assertConcurrentModification(t, tc.panics, func() {
+						for range it.seq(m) {
+							tc.modify(m)
+						}
+					})
+				})
+			}
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		opts []Opt
+		json string
+	}{
+		{name: "empty", json: "{}"},
+		{name: "insertion_order", json: `{"b":1,"a":2,"c":3}`},
+		{name: "access_order", opts: []Opt{WithAccessOrder()}, json: `{"b":1,"a":2,"c":3}`},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			hm := New[string, int](tc.opts...)
+			if err := json.Unmarshal([]byte(tc.json), hm); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			data, err := json.Marshal(hm)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			if got, want := hm.accessOrder, New[string, int](tc.opts...).accessOrder; got != want {
+				t.Errorf("expected key order %t to be retained, got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONZeroValue(t *testing.T) {
+	t.Parallel()
+	var hm LinkedHashMap[string, int]
+	if err := json.Unmarshal([]byte(`{"b":1,"a":2}`), &hm); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	hm.Put("c", 3)
+	if got, want := slices.Collect(hm.Keys()), []string{"b", "a", "c"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -4,6 +4,8 @@ package linkedhashset
 import (
 	"fmt"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/cursor"
 	"github.com/lock14/collections/linkedhashmap"
 	"iter"
 	"strings"
@@ -46,6 +48,14 @@ func New[T comparable](opts ...Option) *LinkedHashSet[T] {
 	}
 }
 
+// FromSeq creates a LinkedHashSet containing the elements of the given sequence
+// in the order they are produced.
+func FromSeq[T comparable](sequence iter.Seq[T], opts ...Option) *LinkedHashSet[T] {
+	s := New[T](opts...)
+	s.AddAll(sequence)
+	return s
+}
+
 // Add adds the specified item to the set.
 func (s *LinkedHashSet[T]) Add(item T) {
 	s.m.Put(item, struct{}{})
@@ -123,6 +133,11 @@ func (s *LinkedHashSet[T]) AddAll(sequence iter.Seq[T]) {
 
 // RemoveAll removes all elements in the given collection from the set.
 func (s *LinkedHashSet[T]) RemoveAll(other collections.Collection[T]) {
+	if o, ok := other.(*LinkedHashSet[T]); ok && o == s {
+		// Removing elements while iterating over the same set is a concurrent modification.
+		s.Clear()
+		return
+	}
 	for t := range other.All() {
 		s.RemoveElement(t)
 	}
@@ -181,6 +196,12 @@ func (s *LinkedHashSet[T]) Backward() iter.Seq[T] {
 	return s.m.BackwardKeys()
 }
 
+// Cursor returns a cursor positioned outside the set. All cursor operations
+// take O(1) time.
+func (s *LinkedHashSet[T]) Cursor() collections.Cursor[T] {
+	return cursor.Keys[T, struct{}]{MapCursor: s.m.Cursor()}
+}
+
 // String returns a string representation of the set.
 func (s *LinkedHashSet[T]) String() string {
 	var sb strings.Builder
@@ -196,3 +217,18 @@ func (s *LinkedHashSet[T]) String() string {
 	sb.WriteString("]")
 	return sb.String()
 }
+
+// MarshalJSON encodes the set as a JSON array of its elements in iteration
+// order.
+func (s *LinkedHashSet[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(s.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array, added in the order they appear.
+func (s *LinkedHashSet[T]) UnmarshalJSON(data []byte) error {
+	if s.m == nil {
+		s.m = linkedhashmap.New[T, struct{}]()
+	}
+	return codec.UnmarshalSeq(data, s.Clear, s.Add)
+}
@@ -0,0 +1,19 @@
+package linkedhashset_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/linkedhashset"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableSequencedSet(t, func() collections.MutableSequencedSet[int] {
+		return linkedhashset.New[int]()
+	}, collectionstest.Ints(20))
+	collectionstest.MutableSequencedSet(t, func() collections.MutableSequencedSet[int] {
+		return linkedhashset.New[int](linkedhashset.WithAccessOrder())
+	}, collectionstest.Ints(20))
+}
@@ -1,6 +1,7 @@
 package linkedhashset
 
 import (
+	"encoding/json"
 	"github.com/lock14/collections/arraylist"
 	"slices"
 	"testing"
@@ -432,3 +433,66 @@ func TestLinkedHashSet_Backward(t *testing.T) {
 		break
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{name: "empty", input: nil, expected: nil},
+		{name: "insertion_order_with_duplicates", input: []int{3, 1, 3, 2, 1}, expected: []int{3, 1, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := FromSeq(slices.Values(tc.input), WithCapacity(len(tc.input)))
+			if actual := slices.Collect(s.All()); !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRemoveAllSelf(t *testing.T) {
+	t.Parallel()
+	s := FromSeq(slices.Values([]int{1, 2, 3}))
+	s.RemoveAll(s)
+	if !s.Empty() {
+		t.Errorf("expected empty set, got %v", s)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		elems []int
+		json  string
+	}{
+		{name: "empty", elems: nil, json: "[]"},
+		{name: "insertion_order", elems: []int{3, 1, 2}, json: "[3,1,2]"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeq(slices.Values(tc.elems)))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			var s LinkedHashSet[int]
+			if err := json.Unmarshal(data, &s); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := slices.Collect(s.All()); !slices.Equal(got, tc.elems) {
+				t.Errorf("expected %v, got %v", tc.elems, got)
+			}
+		})
+	}
+}
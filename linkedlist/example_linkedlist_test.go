@@ -219,3 +219,18 @@ func ExampleLinkedList_All() {
 	// 1
 	// 2
 }
+
+func ExampleLinkedList_Cursor() {
+	// A cursor removes elements in place while walking the list, in O(1) per removal.
+	l := linked_list.FromSeq(slices.Values([]int{1, 2, 3, 4, 5, 6}))
+
+	for c := l.Cursor(); c.Next(); {
+		if c.Value()%2 == 0 {
+			c.Remove()
+		}
+	}
+	fmt.Println(l)
+
+	// Output:
+	// [1, 3, 5]
+}
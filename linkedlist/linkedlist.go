@@ -4,6 +4,8 @@ package linked_list
 import (
 	"fmt"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"strings"
 )
@@ -13,8 +15,9 @@ var _ collections.MutableDeque[int] = (*LinkedList[int])(nil)
 
 // LinkedList is a doubly-linked list implementation.
 type LinkedList[T any] struct {
-	list node[T]
-	size int
+	list     node[T]
+	size     int
+	modCount int
 }
 
 type node[T any] struct {
@@ -33,9 +36,17 @@ func New[T any]() *LinkedList[T] {
 	return l
 }
 
+// FromSeq creates a LinkedList containing the elements of the given sequence in order.
+func FromSeq[T any](sequence iter.Seq[T]) *LinkedList[T] {
+	l := New[T]()
+	l.AddAll(sequence)
+	return l
+}
+
 func (l *LinkedList[T]) AddFront(t T) {
 	insertBefore(l.list.next, t)
 	l.size++
+	l.modCount++
 }
 
 func (l *LinkedList[T]) RemoveFront() T {
@@ -45,12 +56,14 @@ func (l *LinkedList[T]) RemoveFront() T {
 	n := l.list.next
 	unlink(n)
 	l.size--
+	l.modCount++
 	return n.data
 }
 
 func (l *LinkedList[T]) AddBack(t T) {
 	insertBefore(&l.list, t)
 	l.size++
+	l.modCount++
 }
 
 func (l *LinkedList[T]) RemoveBack() T {
@@ -60,6 +73,7 @@ func (l *LinkedList[T]) RemoveBack() T {
 	n := l.list.prev
 	unlink(n)
 	l.size--
+	l.modCount++
 	return n.data
 }
 
@@ -130,6 +144,7 @@ func (l *LinkedList[T]) Clear() {
 	l.list.next = &l.list
 	l.list.prev = &l.list
 	l.size = 0
+	l.modCount++
 }
 
 func (l *LinkedList[T]) String() string {
@@ -140,10 +155,33 @@ func (l *LinkedList[T]) String() string {
 	return "[" + strings.Join(str, ", ") + "]"
 }
 
+// MarshalJSON encodes the list as a JSON array of its elements from front to
+// back.
+func (l *LinkedList[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(l.All())
+}
+
+// UnmarshalJSON replaces the contents of the list with the elements of a JSON
+// array. It can be called on the zero value.
+func (l *LinkedList[T]) UnmarshalJSON(data []byte) error {
+	if l.list.next == nil {
+		l.list.next = &l.list
+		l.list.prev = &l.list
+	}
+	return codec.UnmarshalSeq(data, l.Clear, l.AddBack)
+}
+
+// All returns an iterator over all elements from front to back. The iterator
+// panics with collections.ErrConcurrentModification if the list is structurally
+// modified during iteration.
 func (l *LinkedList[T]) All() iter.Seq[T] {
 	return func(yield func(T) bool) {
-		for cur := l.list.next; cur != &l.list && yield(cur.data); {
-			cur = cur.next
+		modCount := l.modCount
+		for cur := l.list.next; cur != &l.list; cur = cur.next {
+			if !yield(cur.data) {
+				return
+			}
+			failfast.Check(modCount, l.modCount)
 		}
 	}
 }
@@ -183,3 +221,101 @@ func unlink[T any](n *node[T]) {
 	n.prev = nil
 	n.next = nil
 }
+
+var _ collections.ListCursor[int] = (*cursor[int])(nil)
+
+// Cursor returns a cursor positioned outside the list. All cursor operations
+// take O(1) time.
+func (l *LinkedList[T]) Cursor() collections.ListCursor[T] {
+	return &cursor[T]{
+		l:        l,
+		cur:      &l.list,
+		modCount: l.modCount,
+	}
+}
+
+// cursor is a collections.ListCursor over a LinkedList. The cursor is outside
+// the list when cur is the sentinel, and in a gap after Remove when cur is nil,
+// in which case gapPrev is the node that precedes the gap.
+type cursor[T any] struct {
+	l        *LinkedList[T]
+	cur      *node[T]
+	gapPrev  *node[T]
+	modCount int
+}
+
+func (c *cursor[T]) Next() bool {
+	c.check()
+	if c.cur != nil {
+		c.cur = c.cur.next
+	} else {
+		c.cur = c.gapPrev.next
+	}
+	return c.cur != &c.l.list
+}
+
+func (c *cursor[T]) Prev() bool {
+	c.check()
+	if c.cur != nil {
+		c.cur = c.cur.prev
+	} else {
+		c.cur = c.gapPrev
+	}
+	return c.cur != &c.l.list
+}
+
+func (c *cursor[T]) Value() T {
+	c.checkElement()
+	return c.cur.data
+}
+
+func (c *cursor[T]) Set(t T) {
+	c.checkElement()
+	c.cur.data = t
+}
+
+func (c *cursor[T]) Remove() {
+	c.checkElement()
+	c.gapPrev = c.cur.prev
+	unlink(c.cur)
+	c.cur = nil
+	c.l.size--
+	c.l.modCount++
+	c.modCount = c.l.modCount
+}
+
+func (c *cursor[T]) InsertBefore(t T) {
+	c.check()
+	if c.cur != nil {
+		insertBefore(c.cur, t)
+	} else {
+		insertBefore(c.gapPrev.next, t)
+		c.gapPrev = c.gapPrev.next
+	}
+	c.l.size++
+	c.l.modCount++
+	c.modCount = c.l.modCount
+}
+
+func (c *cursor[T]) InsertAfter(t T) {
+	c.check()
+	if c.cur != nil {
+		insertBefore(c.cur.next, t)
+	} else {
+		insertBefore(c.gapPrev.next, t)
+	}
+	c.l.size++
+	c.l.modCount++
+	c.modCount = c.l.modCount
+}
+
+func (c *cursor[T]) check() {
+	failfast.Check(c.modCount, c.l.modCount)
+}
+
+func (c *cursor[T]) checkElement() {
+	c.check()
+	if c.cur == nil || c.cur == &c.l.list {
+		panic("cursor is not positioned on an element")
+	}
+}
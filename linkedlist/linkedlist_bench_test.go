@@ -65,3 +65,19 @@ func BenchmarkLinkedList_IterateAll(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkLinkedList_CursorRemoveIf(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		l := linked_list.New[int]()
+		for j := 0; j < 1000; j++ {
+			l.AddBack(j)
+		}
+		b.StartTimer()
+		for c := l.Cursor(); c.Next(); {
+			if c.Value()%2 == 0 {
+				c.Remove()
+			}
+		}
+	}
+}
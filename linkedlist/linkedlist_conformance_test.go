@@ -0,0 +1,19 @@
+package linked_list_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/linkedlist"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableList(t, func() collections.MutableList[int] {
+		return linked_list.New[int]()
+	}, collectionstest.Ints(10))
+	collectionstest.MutableDeque(t, func() collections.MutableDeque[int] {
+		return linked_list.New[int]()
+	}, collectionstest.Ints(10))
+}
@@ -1,6 +1,9 @@
 package linked_list
 
 import (
+	"encoding/json"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
 	"slices"
 	"testing"
 )
@@ -235,3 +238,108 @@ func TestLinkedList_CoveragePanics(t *testing.T) {
 	assertPanics(func() { ll.PeekBack() })
 	assertPanics(func() { ll.Get(10) })
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		input []int
+	}{
+		{name: "empty", input: nil},
+		{name: "elements", input: []int{3, 1, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			l := FromSeq(slices.Values(tc.input))
+			if actual := slices.Collect(l.All()); !slices.Equal(actual, tc.input) {
+				t.Errorf("expected %v, got %v", tc.input, actual)
+			}
+			if l.Size() != len(tc.input) {
+				t.Errorf("expected size %d, got %d", len(tc.input), l.Size())
+			}
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(l *LinkedList[int])
+		panics bool
+	}{
+		{name: "add front", modify: func(l *LinkedList[int]) { l.AddFront(0) }, panics: true},
+		{name: "add back", modify: func(l *LinkedList[int]) { l.AddBack(4) }, panics: true},
+		{name: "remove front", modify: func(l *LinkedList[int]) { l.RemoveFront() }, panics: true},
+		{name: "remove back", modify: func(l *LinkedList[int]) { l.RemoveBack() }, panics: true},
+		{name: "clear", modify: func(l *LinkedList[int]) { l.Clear() }, panics: true},
+		{name: "set", modify: func(l *LinkedList[int]) { l.Set(1, 20) }, panics: false},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			l := FromSeq(slices.Values([]int{1, 2, 3}))
+			assertConcurrentModification(t, tc.panics, func() {
+				for range l.All() {
+					tc.modify(l)
+				}
+			})
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		elems []int
+		json  string
+	}{
+		{name: "empty", elems: nil, json: "[]"},
+		{name: "elements", elems: []int{3, 1, 2}, json: "[3,1,2]"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeq(slices.Values(tc.elems)))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			var l LinkedList[int]
+			if err := json.Unmarshal(data, &l); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := slices.Collect(l.All()); !slices.Equal(got, tc.elems) {
+				t.Errorf("expected %v, got %v", tc.elems, got)
+			}
+			l.AddFront(0)
+			if got, want := l.Size(), len(tc.elems)+1; got != want {
+				t.Errorf("expected size %d after AddFront, got %d", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package multimap_test
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/lock14/collections/multimap"
+)
+
+func ExampleListMultimap() {
+	// A ListMultimap replaces map[K][]V bookkeeping: putting the first value
+	// creates the key, and removing the last value removes it.
+	tags := multimap.NewList[string, string](multimap.WithInsertionOrder[string]())
+	tags.Put("post-1", "go")
+	tags.Put("post-2", "rust")
+	tags.PutAll("post-1", slices.Values([]string{"generics", "go"}))
+
+	fmt.Println(tags.Get("post-1"))
+	fmt.Println("Entries:", tags.Size())
+
+	tags.RemoveValue("post-2", "rust")
+	fmt.Println("Has post-2:", tags.ContainsKey("post-2"))
+
+	// Output:
+	// [go, generics, go]
+	// Entries: 4
+	// Has post-2: false
+}
+
+func ExampleInvert() {
+	authors := multimap.NewSet[string, string]()
+	authors.PutAll("Go in Action", slices.Values([]string{"Kennedy", "Ketelsen"}))
+	authors.Put("The Go Programming Language", "Kernighan")
+
+	// Index books by author, with both authors and titles in sorted order.
+	books := multimap.Invert(multimap.NewSortedSet[string](cmp.Compare[string], multimap.WithKeyComparator(cmp.Compare[string])), authors)
+	for author, book := range books.Entries() {
+		fmt.Printf("%s: %s\n", author, book)
+	}
+
+	// Output:
+	// Kennedy: Go in Action
+	// Kernighan: The Go Programming Language
+	// Ketelsen: Go in Action
+}
@@ -0,0 +1,85 @@
+package multimap
+
+import (
+	"fmt"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraylist"
+)
+
+var _ MutableMultimap[int, int] = (*ListMultimap[int, int])(nil)
+
+// ListMultimap is a multimap that keeps the values of each key in an
+// arraylist, in the order they were put, and allows a key to be associated with
+// the same value more than once.
+type ListMultimap[K comparable, V comparable] struct {
+	base[K, V, *arraylist.SliceWrapper[V]]
+}
+
+// NewList creates an empty ListMultimap.
+func NewList[K comparable, V comparable](opts ...Option[K]) *ListMultimap[K, V] {
+	mm := &ListMultimap[K, V]{base: newBase[K, V, *arraylist.SliceWrapper[V]](opts)}
+	mm.initValues()
+	return mm
+}
+
+// UnmarshalJSON replaces the contents of the multimap with the entries of a
+// JSON object or array of [key, values] pairs. When called on the zero value,
+// the multimap holds its keys in a hashmap.
+func (mm *ListMultimap[K, V]) UnmarshalJSON(data []byte) error {
+	if mm.m == nil {
+		mm.base = newBase[K, V, *arraylist.SliceWrapper[V]](nil)
+		mm.initValues()
+	}
+	return mm.base.UnmarshalJSON(data)
+}
+
+// initValues configures how the multimap creates and searches the list of
+// values of each key.
+func (mm *ListMultimap[K, V]) initValues() {
+	mm.newValues = func() *arraylist.SliceWrapper[V] {
+		return arraylist.New[V]()
+	}
+	mm.contains = func(l *arraylist.SliceWrapper[V], v V) bool {
+		for t := range l.All() {
+			if t == v {
+				return true
+			}
+		}
+		return false
+	}
+	mm.remove = func(l *arraylist.SliceWrapper[V], v V) bool {
+		for c := l.Cursor(); c.Next(); {
+			if c.Value() == v {
+				c.Remove()
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Get returns a read-only view of the values associated with the specified
+// key, in the order they were put. The view is empty if the key is absent, and
+// reflects later changes to the multimap.
+func (mm *ListMultimap[K, V]) Get(key K) collections.ReadOnlyList[V] {
+	return collections.NewReadOnlyList[V](listView[K, V]{valuesView[K, V, *arraylist.SliceWrapper[V]]{&mm.base, key}})
+}
+
+// RemoveValue removes the first association of the specified value with the
+// specified key, and removes the key if it has no values left.
+func (mm *ListMultimap[K, V]) RemoveValue(key K, value V) {
+	mm.base.RemoveValue(key, value)
+}
+
+type listView[K comparable, V comparable] struct {
+	valuesView[K, V, *arraylist.SliceWrapper[V]]
+}
+
+func (v listView[K, V]) Get(idx int) V {
+	values, ok := v.b.values(v.key)
+	if !ok {
+		panic(fmt.Sprintf("index out of range [%d] with length 0", idx))
+	}
+	return values.Get(idx)
+}
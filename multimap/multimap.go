@@ -0,0 +1,317 @@
+// Package multimap provides maps that associate each key with a collection of
+// values.
+//
+// ListMultimap keeps the values of each key in insertion order and allows
+// duplicates, SetMultimap keeps a set of values per key, and SortedSetMultimap
+// keeps each set of values sorted by a comparator. A key is present exactly
+// while it has at least one value: putting a value for an absent key adds it,
+// and removing the last value of a key removes the key.
+//
+// Keys are held in a hashmap by default. Use WithInsertionOrder to iterate keys
+// in the order they were first added, backed by a linkedhashmap, or
+// WithKeyComparator to iterate them in sorted order, backed by a treemap.
+package multimap
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/linkedhashmap"
+	"github.com/lock14/collections/treemap"
+)
+
+// Multimap represents a collection of key-value pairs in which a key may be
+// associated with more than one value.
+type Multimap[K any, V any] interface {
+	// Size returns the number of key-value pairs in the multimap.
+	Size() int
+	// Empty returns true if the multimap contains no key-value pairs.
+	Empty() bool
+	// ContainsKey returns true if the multimap has at least one value for the specified key.
+	ContainsKey(K) bool
+	// ContainsEntry returns true if the multimap associates the specified value with the specified key.
+	ContainsEntry(K, V) bool
+	// Entries returns an iterator over all key-value pairs in the multimap.
+	Entries() iter.Seq2[K, V]
+	// KeySet returns a read-only view of the distinct keys in the multimap.
+	KeySet() collections.ReadOnlySet[K]
+}
+
+// MutableMultimap represents a multimap that can be modified.
+type MutableMultimap[K any, V any] interface {
+	Multimap[K, V]
+	// Put associates the specified value with the specified key.
+	Put(K, V)
+	// PutAll associates all values of the given sequence with the specified key.
+	PutAll(K, iter.Seq[V])
+	// RemoveValue removes a single association of the specified value with the specified key.
+	RemoveValue(K, V)
+	// RemoveAll removes all values associated with the specified key.
+	RemoveAll(K)
+	// Clear removes all key-value pairs from the multimap.
+	Clear()
+}
+
+// Invert puts every entry of src into dst with its key and value swapped, and
+// returns dst.
+func Invert[K any, V any, M MutableMultimap[V, K]](dst M, src Multimap[K, V]) M {
+	for k, v := range src.Entries() {
+		dst.Put(v, k)
+	}
+	return dst
+}
+
+// config holds the values for configuring a multimap.
+type config[K any] struct {
+	insertionOrder bool
+	comparator     comparator.Comparator[K]
+}
+
+// Option configures a multimap config.
+type Option[K any] func(*config[K])
+
+// WithInsertionOrder configures the multimap to iterate keys in the order they
+// were first added, holding them in a linkedhashmap.
+func WithInsertionOrder[K any]() Option[K] {
+	return func(c *config[K]) {
+		c.insertionOrder = true
+	}
+}
+
+// WithKeyComparator configures the multimap to iterate keys in the order
+// imposed by the given comparator, holding them in a treemap.
+func WithKeyComparator[K any](comp comparator.Comparator[K]) Option[K] {
+	return func(c *config[K]) {
+		c.comparator = comp
+	}
+}
+
+// base implements the operations shared by every multimap. The value
+// collection of a key is created on its first Put and dropped with its last
+// value, so every collection in m is non-empty.
+type base[K comparable, V any, C collections.MutableCollection[V]] struct {
+	m         collections.MutableMap[K, C]
+	newValues func() C
+	contains  func(C, V) bool
+	remove    func(C, V) bool
+	size      int
+}
+
+func newBase[K comparable, V any, C collections.MutableCollection[V]](opts []Option[K]) base[K, V, C] {
+	c := &config[K]{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	var m collections.MutableMap[K, C]
+	switch {
+	case c.comparator != nil:
+		m = treemap.New[K, C](treemap.WithComparator(c.comparator))
+	case c.insertionOrder:
+		m = linkedhashmap.New[K, C]()
+	default:
+		m = hashmap.New[K, C]()
+	}
+	return base[K, V, C]{m: m}
+}
+
+// Put associates the specified value with the specified key.
+func (b *base[K, V, C]) Put(key K, value V) {
+	values, ok := b.m.Get(key)
+	if !ok {
+		values = b.newValues()
+		b.m.Put(key, values)
+	}
+	n := values.Size()
+	values.Add(value)
+	b.size += values.Size() - n
+}
+
+// PutAll associates all values of the given sequence with the specified key.
+func (b *base[K, V, C]) PutAll(key K, sequence iter.Seq[V]) {
+	for v := range sequence {
+		b.Put(key, v)
+	}
+}
+
+// RemoveValue removes a single association of the specified value with the
+// specified key, and removes the key if it has no values left.
+func (b *base[K, V, C]) RemoveValue(key K, value V) {
+	values, ok := b.m.Get(key)
+	if !ok || !b.remove(values, value) {
+		return
+	}
+	b.size--
+	if values.Empty() {
+		b.m.Remove(key)
+	}
+}
+
+// RemoveAll removes the specified key and all values associated with it.
+func (b *base[K, V, C]) RemoveAll(key K) {
+	if values, ok := b.m.Get(key); ok {
+		b.size -= values.Size()
+		b.m.Remove(key)
+	}
+}
+
+// Clear removes all key-value pairs from the multimap.
+func (b *base[K, V, C]) Clear() {
+	b.m.Clear()
+	b.size = 0
+}
+
+// Size returns the number of key-value pairs in the multimap.
+func (b *base[K, V, C]) Size() int {
+	return b.size
+}
+
+// Empty returns true if the multimap contains no key-value pairs.
+func (b *base[K, V, C]) Empty() bool {
+	return b.size == 0
+}
+
+// ContainsKey returns true if the multimap has at least one value for the
+// specified key.
+func (b *base[K, V, C]) ContainsKey(key K) bool {
+	return b.m.ContainsKey(key)
+}
+
+// ContainsEntry returns true if the multimap associates the specified value
+// with the specified key.
+func (b *base[K, V, C]) ContainsEntry(key K, value V) bool {
+	values, ok := b.m.Get(key)
+	return ok && b.contains(values, value)
+}
+
+// Entries returns an iterator over all key-value pairs in the multimap, in key
+// order and then in the order of the values of each key.
+func (b *base[K, V, C]) Entries() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, values := range b.m.All() {
+			for v := range values.All() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// KeySet returns a read-only view of the distinct keys in the multimap. The
+// view reflects later changes to the multimap.
+func (b *base[K, V, C]) KeySet() collections.ReadOnlySet[K] {
+	return collections.NewReadOnlySet[K](keySet[K, V, C]{b})
+}
+
+// String returns a string representation of the multimap.
+func (b *base[K, V, C]) String() string {
+	entries := make([]string, 0, b.m.Size())
+	for k, values := range b.m.All() {
+		entries = append(entries, fmt.Sprintf("%+v: %v", k, values))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// MarshalJSON encodes the multimap as a JSON object mapping each key to an
+// array of its values, or as an array of [key, values] pairs if K cannot be an
+// object key.
+func (b *base[K, V, C]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(b.m.All())
+}
+
+// UnmarshalJSON replaces the contents of the multimap with the entries of a
+// JSON object or array of [key, values] pairs.
+func (b *base[K, V, C]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalMap(data, b.Clear, func(k K, values []V) {
+		b.PutAll(k, slices.Values(values))
+	})
+}
+
+// values returns the values of key, which are missing if the key is absent.
+func (b *base[K, V, C]) values(key K) (C, bool) {
+	return b.m.Get(key)
+}
+
+// keySet is a live collections.Set of the keys of a multimap.
+type keySet[K comparable, V any, C collections.MutableCollection[V]] struct {
+	b *base[K, V, C]
+}
+
+func (s keySet[K, V, C]) All() iter.Seq[K] {
+	return s.b.m.Keys()
+}
+
+func (s keySet[K, V, C]) Size() int {
+	return s.b.m.Size()
+}
+
+func (s keySet[K, V, C]) Empty() bool {
+	return s.b.m.Empty()
+}
+
+func (s keySet[K, V, C]) Contains(key K) bool {
+	return s.b.m.ContainsKey(key)
+}
+
+func (s keySet[K, V, C]) ContainsAll(other collections.Collection[K]) bool {
+	for k := range other.All() {
+		if !s.Contains(k) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s keySet[K, V, C]) String() string {
+	return format(s.All())
+}
+
+// valuesView is a live collections.Collection of the values of one key. It
+// looks the key up on every call, so it stays valid as the key is removed and
+// added again.
+type valuesView[K comparable, V any, C collections.MutableCollection[V]] struct {
+	b   *base[K, V, C]
+	key K
+}
+
+func (v valuesView[K, V, C]) All() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if values, ok := v.b.values(v.key); ok {
+			for t := range values.All() {
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (v valuesView[K, V, C]) Size() int {
+	values, ok := v.b.values(v.key)
+	if !ok {
+		return 0
+	}
+	return values.Size()
+}
+
+func (v valuesView[K, V, C]) Empty() bool {
+	return !v.b.m.ContainsKey(v.key)
+}
+
+func (v valuesView[K, V, C]) String() string {
+	return format(v.All())
+}
+
+func format[T any](seq iter.Seq[T]) string {
+	vals := make([]string, 0)
+	for t := range seq {
+		vals = append(vals, fmt.Sprintf("%+v", t))
+	}
+	return "[" + strings.Join(vals, ", ") + "]"
+}
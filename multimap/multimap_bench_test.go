@@ -0,0 +1,55 @@
+package multimap_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections/multimap"
+)
+
+func BenchmarkListMultimap_Put(b *testing.B) {
+	mm := multimap.NewList[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mm.Put(i%1000, i)
+	}
+}
+
+func BenchmarkSetMultimap_Put(b *testing.B) {
+	mm := multimap.NewSet[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mm.Put(i%1000, i)
+	}
+}
+
+func BenchmarkSortedSetMultimap_Put(b *testing.B) {
+	mm := multimap.NewSortedSet[int](cmp.Compare[int])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mm.Put(i%1000, i)
+	}
+}
+
+func BenchmarkSetMultimap_ContainsEntry(b *testing.B) {
+	mm := multimap.NewSet[int, int]()
+	for i := 0; i < 10000; i++ {
+		mm.Put(i%1000, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mm.ContainsEntry(i%1000, i%10000)
+	}
+}
+
+func BenchmarkListMultimap_Entries(b *testing.B) {
+	mm := multimap.NewList[int, int]()
+	for i := 0; i < 10000; i++ {
+		mm.Put(i%1000, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range mm.Entries() {
+		}
+	}
+}
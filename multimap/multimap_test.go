@@ -0,0 +1,299 @@
+package multimap
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections/pair"
+)
+
+type entry = pair.Pair[string, int]
+
+func entries[K any, V any](m Multimap[K, V]) []pair.Pair[K, V] {
+	var es []pair.Pair[K, V]
+	for k, v := range m.Entries() {
+		es = append(es, pair.New(k, v))
+	}
+	return es
+}
+
+func TestKeyOrder(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		opts []Option[string]
+		want []string
+	}{
+		{name: "insertion_order", opts: []Option[string]{WithInsertionOrder[string]()}, want: []string{"b", "c", "a"}},
+		{name: "key_comparator", opts: []Option[string]{WithKeyComparator(cmp.Compare[string])}, want: []string{"a", "b", "c"}},
+		{name: "reversed", opts: []Option[string]{WithKeyComparator(func(a, b string) int { return cmp.Compare(b, a) })}, want: []string{"c", "b", "a"}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mm := NewList[string, int](tc.opts...)
+			for i, k := range []string{"b", "c", "b", "a"} {
+				mm.Put(k, i)
+			}
+			if got := slices.Collect(mm.KeySet().All()); !slices.Equal(got, tc.want) {
+				t.Errorf("expected keys %v, got %v", tc.want, got)
+			}
+		})
+	}
+	mm := NewSet[string, int]()
+	mm.PutAll("a", slices.Values([]int{1, 2}))
+	mm.Put("b", 1)
+	got := slices.Collect(mm.KeySet().All())
+	slices.Sort(got)
+	if want := []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+}
+
+func TestListMultimap(t *testing.T) {
+	t.Parallel()
+	mm := NewList[string, int](WithInsertionOrder[string]())
+	mm.Put("a", 1)
+	mm.Put("b", 2)
+	mm.PutAll("a", slices.Values([]int{3, 1}))
+	if got, want := entries[string, int](mm), []entry{pair.New("a", 1), pair.New("a", 3), pair.New("a", 1), pair.New("b", 2)}; !slices.Equal(got, want) {
+		t.Errorf("expected entries %v, got %v", want, got)
+	}
+	if mm.Size() != 4 || mm.Empty() {
+		t.Errorf("expected size 4, got %d", mm.Size())
+	}
+
+	a := mm.Get("a")
+	if got, want := slices.Collect(a.All()), []int{1, 3, 1}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := a.Get(1); got != 3 {
+		t.Errorf("expected 3 at index 1, got %d", got)
+	}
+
+	mm.RemoveValue("a", 1)
+	if got, want := slices.Collect(a.All()), []int{3, 1}; !slices.Equal(got, want) {
+		t.Errorf("expected the view to reflect removal of the first occurrence, got %v, want %v", got, want)
+	}
+	mm.RemoveValue("a", 7)
+	mm.RemoveValue("z", 1)
+	if mm.Size() != 3 {
+		t.Errorf("expected removing absent entries to leave size 3, got %d", mm.Size())
+	}
+	if !mm.ContainsEntry("a", 3) || mm.ContainsEntry("a", 2) || mm.ContainsEntry("z", 3) {
+		t.Errorf("wrong ContainsEntry results for %v", mm)
+	}
+
+	mm.RemoveAll("a")
+	if mm.ContainsKey("a") || !a.Empty() || a.Size() != 0 || mm.Size() != 1 {
+		t.Errorf("expected a to be removed, got %v with size %d", mm, mm.Size())
+	}
+	mm.Put("a", 5)
+	if got, want := slices.Collect(a.All()), []int{5}; !slices.Equal(got, want) {
+		t.Errorf("expected the view to see the key added again, got %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(mm.KeySet().All()), []string{"b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+
+	mm.Clear()
+	if !mm.Empty() || mm.Size() != 0 || mm.KeySet().Size() != 0 {
+		t.Errorf("expected an empty multimap, got %v", mm)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected Get on an absent key's view to panic")
+		}
+	}()
+	a.Get(0)
+}
+
+func TestRemoveLastValueRemovesKey(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		mm   MutableMultimap[string, int]
+	}{
+		{name: "list", mm: NewList[string, int]()},
+		{name: "set", mm: NewSet[string, int]()},
+		{name: "sorted_set", mm: NewSortedSet[string](cmp.Compare[int])},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.mm.PutAll("a", slices.Values([]int{1, 2}))
+			tc.mm.RemoveValue("a", 1)
+			if !tc.mm.ContainsKey("a") {
+				t.Errorf("expected a to remain with one value")
+			}
+			tc.mm.RemoveValue("a", 2)
+			if tc.mm.ContainsKey("a") || tc.mm.KeySet().Contains("a") || !tc.mm.Empty() {
+				t.Errorf("expected removing the last value to remove the key, got %v", tc.mm)
+			}
+		})
+	}
+}
+
+func TestSetMultimap(t *testing.T) {
+	t.Parallel()
+	mm := NewSet[string, int]()
+	mm.PutAll("a", slices.Values([]int{1, 2, 1}))
+	mm.Put("a", 2)
+	if mm.Size() != 2 {
+		t.Errorf("expected duplicate entries to be ignored, got size %d", mm.Size())
+	}
+	a := mm.Get("a")
+	if !a.Contains(1) || a.Contains(3) || !a.ContainsAll(mm.Get("a")) {
+		t.Errorf("wrong Contains results for %v", a)
+	}
+	if b := mm.Get("b"); !b.Empty() || b.Contains(1) || !b.ContainsAll(b) {
+		t.Errorf("expected an empty view for an absent key, got %v", b)
+	}
+	mm.RemoveValue("a", 3)
+	if mm.Size() != 2 {
+		t.Errorf("expected removing an absent value to leave size 2, got %d", mm.Size())
+	}
+}
+
+func TestSortedSetMultimap(t *testing.T) {
+	t.Parallel()
+	mm := NewSortedSet[string](cmp.Compare[int], WithKeyComparator(cmp.Compare[string]))
+	mm.PutAll("b", slices.Values([]int{5, 1, 3, 1}))
+	mm.Put("a", 9)
+	if got, want := entries[string, int](mm), []entry{pair.New("a", 9), pair.New("b", 1), pair.New("b", 3), pair.New("b", 5)}; !slices.Equal(got, want) {
+		t.Errorf("expected entries %v, got %v", want, got)
+	}
+
+	b := mm.Get("b")
+	if b.First() != 1 || b.Last() != 5 {
+		t.Errorf("expected first 1 and last 5, got %d and %d", b.First(), b.Last())
+	}
+	if got, want := slices.Collect(b.Backward()), []int{5, 3, 1}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(b.Between(2, 5)), []int{3}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(b.From(3)), []int{3, 5}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(b.To(3)), []int{1}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if v, ok := b.Lower(3); !ok || v != 1 {
+		t.Errorf("expected Lower(3) = 1, got %d, %t", v, ok)
+	}
+	if v, ok := b.Floor(4); !ok || v != 3 {
+		t.Errorf("expected Floor(4) = 3, got %d, %t", v, ok)
+	}
+	if v, ok := b.Ceiling(4); !ok || v != 5 {
+		t.Errorf("expected Ceiling(4) = 5, got %d, %t", v, ok)
+	}
+	if v, ok := b.Higher(5); ok {
+		t.Errorf("expected no value higher than 5, got %d", v)
+	}
+
+	c := mm.Get("c")
+	if _, ok := c.Floor(1); ok || c.Contains(1) || len(slices.Collect(c.From(0))) != 0 {
+		t.Errorf("expected an empty view for an absent key, got %v", c)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected First on an empty view to panic")
+		}
+	}()
+	c.First()
+}
+
+func TestInvert(t *testing.T) {
+	t.Parallel()
+	mm := NewList[string, int](WithInsertionOrder[string]())
+	mm.PutAll("a", slices.Values([]int{1, 2, 2}))
+	mm.PutAll("b", slices.Values([]int{2}))
+	inv := Invert(NewSortedSet[int](cmp.Compare[string], WithKeyComparator(cmp.Compare[int])), mm)
+	want := []pair.Pair[int, string]{pair.New(1, "a"), pair.New(2, "a"), pair.New(2, "b")}
+	if got := entries[int, string](inv); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if inv.Size() != 3 {
+		t.Errorf("expected size 3, got %d", inv.Size())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	mm := NewList[string, int](WithKeyComparator(cmp.Compare[string]))
+	mm.PutAll("b", slices.Values([]int{2, 2}))
+	mm.Put("a", 1)
+	data, err := json.Marshal(mm)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":[1],"b":[2,2]}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	got := NewList[string, int]()
+	got.Put("stale", 0)
+	if err := json.Unmarshal([]byte(`{"a":[1],"b":[2,2],"c":[]}`), got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Size() != 3 || got.ContainsKey("stale") || got.ContainsKey("c") {
+		t.Errorf("expected {a: [1], b: [2, 2]}, got %v", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+	mm := NewSortedSet[string](cmp.Compare[int], WithKeyComparator(cmp.Compare[string]))
+	mm.PutAll("b", slices.Values([]int{2, 1}))
+	mm.Put("a", 3)
+	if got, want := mm.String(), "{a: [3], b: [1, 2]}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got, want := mm.KeySet().String(), "[a, b]"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got, want := mm.Get("b").String(), "[1, 2]"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestUnmarshalJSONZeroValue(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"a":[2,1,2]}`)
+	var list ListMultimap[string, int]
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatalf("Unmarshal ListMultimap: %v", err)
+	}
+	if got := slices.Collect(list.Get("a").All()); !slices.Equal(got, []int{2, 1, 2}) {
+		t.Errorf("expected a: [2 1 2], got %v", &list)
+	}
+	var set SetMultimap[string, int]
+	if err := json.Unmarshal(data, &set); err != nil {
+		t.Fatalf("Unmarshal SetMultimap: %v", err)
+	}
+	if set.Size() != 2 || !set.ContainsEntry("a", 1) {
+		t.Errorf("expected a: {1, 2}, got %v", &set)
+	}
+	var sorted SortedSetMultimap[string, int]
+	if err := json.Unmarshal(data, &sorted); err != nil {
+		t.Fatalf("Unmarshal SortedSetMultimap: %v", err)
+	}
+	if got := slices.Collect(sorted.Get("a").All()); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("expected a: [1 2], got %v", &sorted)
+	}
+	var unordered SortedSetMultimap[string, struct{}]
+	if err := json.Unmarshal([]byte(`{"a":[{}]}`), &unordered); err == nil {
+		t.Error("expected an error decoding into a SortedSetMultimap without a comparator")
+	}
+	var fields struct {
+		Tags *ListMultimap[string, int] `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(`{"tags":{"x":[1]}}`), &fields); err != nil || !fields.Tags.ContainsEntry("x", 1) {
+		t.Errorf("expected a struct field to decode, got %v, %v", fields.Tags, err)
+	}
+}
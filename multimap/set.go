@@ -0,0 +1,71 @@
+package multimap
+
+import (
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/hashset"
+)
+
+var _ MutableMultimap[int, int] = (*SetMultimap[int, int])(nil)
+
+// SetMultimap is a multimap that keeps the values of each key in a hashset, so
+// a key is associated with each value at most once. Putting an entry that is
+// already present has no effect.
+type SetMultimap[K comparable, V comparable] struct {
+	base[K, V, *hashset.HashSet[V]]
+}
+
+// NewSet creates an empty SetMultimap.
+func NewSet[K comparable, V comparable](opts ...Option[K]) *SetMultimap[K, V] {
+	mm := &SetMultimap[K, V]{base: newBase[K, V, *hashset.HashSet[V]](opts)}
+	mm.initValues()
+	return mm
+}
+
+// UnmarshalJSON replaces the contents of the multimap with the entries of a
+// JSON object or array of [key, values] pairs. When called on the zero value,
+// the multimap holds its keys in a hashmap.
+func (mm *SetMultimap[K, V]) UnmarshalJSON(data []byte) error {
+	if mm.m == nil {
+		mm.base = newBase[K, V, *hashset.HashSet[V]](nil)
+		mm.initValues()
+	}
+	return mm.base.UnmarshalJSON(data)
+}
+
+// initValues configures how the multimap creates and searches the set of
+// values of each key.
+func (mm *SetMultimap[K, V]) initValues() {
+	mm.newValues = func() *hashset.HashSet[V] {
+		return hashset.New[V]()
+	}
+	mm.contains = (*hashset.HashSet[V]).Contains
+	mm.remove = func(s *hashset.HashSet[V], v V) bool {
+		n := s.Size()
+		s.RemoveElement(v)
+		return s.Size() < n
+	}
+}
+
+// Get returns a read-only view of the values associated with the specified
+// key. The view is empty if the key is absent, and reflects later changes to
+// the multimap.
+func (mm *SetMultimap[K, V]) Get(key K) collections.ReadOnlySet[V] {
+	return collections.NewReadOnlySet[V](setView[K, V]{valuesView[K, V, *hashset.HashSet[V]]{&mm.base, key}})
+}
+
+type setView[K comparable, V comparable] struct {
+	valuesView[K, V, *hashset.HashSet[V]]
+}
+
+func (v setView[K, V]) Contains(t V) bool {
+	return v.b.ContainsEntry(v.key, t)
+}
+
+func (v setView[K, V]) ContainsAll(other collections.Collection[V]) bool {
+	for t := range other.All() {
+		if !v.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
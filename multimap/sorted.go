@@ -0,0 +1,150 @@
+package multimap
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/treeset"
+)
+
+var _ MutableMultimap[int, int] = (*SortedSetMultimap[int, int])(nil)
+
+// SortedSetMultimap is a multimap that keeps the values of each key in a
+// treeset ordered by a comparator, so a key is associated with each value at
+// most once. Putting an entry that is already present has no effect.
+type SortedSetMultimap[K comparable, V any] struct {
+	base[K, V, *treeset.TreeSet[V]]
+}
+
+// NewSortedSet creates an empty SortedSetMultimap that orders the values of
+// each key with the given comparator.
+func NewSortedSet[K comparable, V any](comp comparator.Comparator[V], opts ...Option[K]) *SortedSetMultimap[K, V] {
+	mm := &SortedSetMultimap[K, V]{base: newBase[K, V, *treeset.TreeSet[V]](opts)}
+	mm.initValues(comp)
+	return mm
+}
+
+// UnmarshalJSON replaces the contents of the multimap with the entries of a
+// JSON object or array of [key, values] pairs. When called on the zero value,
+// the multimap holds its keys in a hashmap and orders the values of ordered
+// types by their natural order.
+func (mm *SortedSetMultimap[K, V]) UnmarshalJSON(data []byte) error {
+	if mm.m == nil {
+		comp, ok := codec.NaturalOrder[V]()
+		if !ok {
+			return fmt.Errorf("multimap: cannot unmarshal into a SortedSetMultimap without a comparator")
+		}
+		mm.base = newBase[K, V, *treeset.TreeSet[V]](nil)
+		mm.initValues(comp)
+	}
+	return mm.base.UnmarshalJSON(data)
+}
+
+// initValues configures how the multimap creates and searches the sorted set
+// of values of each key.
+func (mm *SortedSetMultimap[K, V]) initValues(comp comparator.Comparator[V]) {
+	mm.newValues = func() *treeset.TreeSet[V] {
+		return treeset.New[V](treeset.WithComparator(comp))
+	}
+	mm.contains = (*treeset.TreeSet[V]).Contains
+	mm.remove = func(s *treeset.TreeSet[V], v V) bool {
+		n := s.Size()
+		s.RemoveElement(v)
+		return s.Size() < n
+	}
+}
+
+// Get returns a read-only view of the values associated with the specified
+// key, in ascending order. The view is empty if the key is absent, and reflects
+// later changes to the multimap.
+func (mm *SortedSetMultimap[K, V]) Get(key K) collections.ReadOnlyNavigableSet[V] {
+	return collections.NewReadOnlyNavigableSet[V](sortedView[K, V]{valuesView[K, V, *treeset.TreeSet[V]]{&mm.base, key}})
+}
+
+type sortedView[K comparable, V any] struct {
+	valuesView[K, V, *treeset.TreeSet[V]]
+}
+
+func (v sortedView[K, V]) Contains(t V) bool {
+	return v.b.ContainsEntry(v.key, t)
+}
+
+func (v sortedView[K, V]) ContainsAll(other collections.Collection[V]) bool {
+	for t := range other.All() {
+		if !v.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v sortedView[K, V]) First() V {
+	return v.mustValues("First").First()
+}
+
+func (v sortedView[K, V]) Last() V {
+	return v.mustValues("Last").Last()
+}
+
+func (v sortedView[K, V]) Backward() iter.Seq[V] {
+	return v.seq(func(s *treeset.TreeSet[V]) iter.Seq[V] { return s.Backward() })
+}
+
+func (v sortedView[K, V]) From(from V) iter.Seq[V] {
+	return v.seq(func(s *treeset.TreeSet[V]) iter.Seq[V] { return s.From(from) })
+}
+
+func (v sortedView[K, V]) To(to V) iter.Seq[V] {
+	return v.seq(func(s *treeset.TreeSet[V]) iter.Seq[V] { return s.To(to) })
+}
+
+func (v sortedView[K, V]) Between(from, to V) iter.Seq[V] {
+	return v.seq(func(s *treeset.TreeSet[V]) iter.Seq[V] { return s.Between(from, to) })
+}
+
+func (v sortedView[K, V]) Lower(t V) (V, bool) {
+	return v.find((*treeset.TreeSet[V]).Lower, t)
+}
+
+func (v sortedView[K, V]) Floor(t V) (V, bool) {
+	return v.find((*treeset.TreeSet[V]).Floor, t)
+}
+
+func (v sortedView[K, V]) Ceiling(t V) (V, bool) {
+	return v.find((*treeset.TreeSet[V]).Ceiling, t)
+}
+
+func (v sortedView[K, V]) Higher(t V) (V, bool) {
+	return v.find((*treeset.TreeSet[V]).Higher, t)
+}
+
+func (v sortedView[K, V]) mustValues(op string) *treeset.TreeSet[V] {
+	values, ok := v.b.values(v.key)
+	if !ok {
+		panic(op + " called on empty set")
+	}
+	return values
+}
+
+func (v sortedView[K, V]) seq(f func(*treeset.TreeSet[V]) iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if values, ok := v.b.values(v.key); ok {
+			for t := range f(values) {
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (v sortedView[K, V]) find(f func(*treeset.TreeSet[V], V) (V, bool), t V) (V, bool) {
+	if values, ok := v.b.values(v.key); ok {
+		return f(values, t)
+	}
+	var zero V
+	return zero, false
+}
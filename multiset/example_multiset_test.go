@@ -0,0 +1,45 @@
+package multiset_test
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/lock14/collections/multiset"
+)
+
+func ExampleHashMultiset_MostCommon() {
+	// A multiset counts occurrences without manual increment and cleanup.
+	words := multiset.NewHash[string]()
+	for _, w := range strings.Fields("the cat and the dog and the bird") {
+		words.Add(w, 1)
+	}
+
+	for _, e := range words.MostCommon(2) {
+		fmt.Printf("%s: %d\n", e.Fst(), e.Snd())
+	}
+	fmt.Println("Words:", words.Size())
+	fmt.Println("Distinct:", words.ElementSet().Size())
+
+	// Output:
+	// the: 3
+	// and: 2
+	// Words: 8
+	// Distinct: 5
+}
+
+func ExampleTreeMultiset_Subtract() {
+	stock := multiset.NewTree(cmp.Compare[string])
+	stock.Add("apple", 5)
+	stock.Add("pear", 2)
+
+	order := multiset.NewHash[string]()
+	order.Add("apple", 2)
+	order.Add("pear", 3)
+
+	stock.Subtract(order)
+	fmt.Println(stock)
+
+	// Output:
+	// {apple: 3}
+}
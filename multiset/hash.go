@@ -0,0 +1,28 @@
+package multiset
+
+import (
+	"github.com/lock14/collections/hashmap"
+)
+
+var _ MutableMultiset[int] = (*HashMultiset[int])(nil)
+
+// HashMultiset is a multiset that holds the count of each element in a
+// hashmap. Its iteration order is unspecified.
+type HashMultiset[T comparable] struct {
+	base[T]
+}
+
+// NewHash creates an empty HashMultiset.
+func NewHash[T comparable]() *HashMultiset[T] {
+	return &HashMultiset[T]{base[T]{m: hashmap.New[T, int]()}}
+}
+
+// UnmarshalJSON replaces the contents of the multiset with the counts of a JSON
+// object or array of [element, count] pairs. The zero value is ready to decode
+// into.
+func (ms *HashMultiset[T]) UnmarshalJSON(data []byte) error {
+	if ms.m == nil {
+		ms.m = hashmap.New[T, int]()
+	}
+	return ms.base.UnmarshalJSON(data)
+}
@@ -0,0 +1,297 @@
+// Package multiset provides collections that count occurrences of their
+// elements, also known as bags.
+//
+// A multiset is a collections.Collection in which each element has a positive
+// count: All yields every element as many times as its count, and Size is the
+// sum of the counts. HashMultiset holds the counts in a hashmap and TreeMultiset
+// holds them in a treemap, which iterates elements in sorted order.
+package multiset
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/pair"
+)
+
+// Multiset represents a collection of elements with a count of occurrences of
+// each.
+type Multiset[T any] interface {
+	collections.Collection[T]
+	// Count returns the number of occurrences of the specified element.
+	Count(T) int
+	// Contains returns true if the multiset contains at least one occurrence of the specified element.
+	Contains(T) bool
+	// Entries returns an iterator over the distinct elements and their counts.
+	Entries() iter.Seq2[T, int]
+	// ElementSet returns a read-only view of the distinct elements.
+	ElementSet() collections.ReadOnlySet[T]
+}
+
+// MutableMultiset represents a multiset that can be modified.
+type MutableMultiset[T any] interface {
+	Multiset[T]
+	// Add adds n occurrences of the specified element.
+	Add(t T, n int)
+	// Remove removes up to n occurrences of the specified element.
+	Remove(t T, n int)
+	// SetCount sets the number of occurrences of the specified element.
+	SetCount(t T, n int)
+	// Clear removes all elements from the multiset.
+	Clear()
+	// Union sets the count of each element to the larger of its counts in this multiset and other.
+	Union(other Multiset[T])
+	// Intersect sets the count of each element to the smaller of its counts in this multiset and other.
+	Intersect(other Multiset[T])
+	// Sum adds the count of each element in other to its count in this multiset.
+	Sum(other Multiset[T])
+	// Subtract subtracts the count of each element in other from its count in this multiset.
+	Subtract(other Multiset[T])
+}
+
+// base implements the operations shared by every multiset. Elements with a
+// count of zero are removed from m.
+type base[T any] struct {
+	m    collections.MutableMap[T, int]
+	size int
+}
+
+// Add adds n occurrences of the specified element. Add panics if n is
+// negative.
+func (b *base[T]) Add(t T, n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("negative count %d", n))
+	}
+	if n > 0 {
+		b.SetCount(t, b.Count(t)+n)
+	}
+}
+
+// Remove removes up to n occurrences of the specified element. Remove panics if
+// n is negative.
+func (b *base[T]) Remove(t T, n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("negative count %d", n))
+	}
+	if count := b.Count(t); count > 0 {
+		b.SetCount(t, max(count-n, 0))
+	}
+}
+
+// SetCount sets the number of occurrences of the specified element to n,
+// removing the element if n is zero. SetCount panics if n is negative.
+func (b *base[T]) SetCount(t T, n int) {
+	if n < 0 {
+		panic(fmt.Sprintf("negative count %d", n))
+	}
+	b.size += n - b.Count(t)
+	if n == 0 {
+		b.m.Remove(t)
+	} else {
+		b.m.Put(t, n)
+	}
+}
+
+// Count returns the number of occurrences of the specified element.
+func (b *base[T]) Count(t T) int {
+	n, _ := b.m.Get(t)
+	return n
+}
+
+// Contains returns true if the multiset contains at least one occurrence of the
+// specified element.
+func (b *base[T]) Contains(t T) bool {
+	return b.m.ContainsKey(t)
+}
+
+// Size returns the total number of occurrences of all elements.
+func (b *base[T]) Size() int {
+	return b.size
+}
+
+// Empty returns true if the multiset contains no elements.
+func (b *base[T]) Empty() bool {
+	return b.size == 0
+}
+
+// Clear removes all elements from the multiset.
+func (b *base[T]) Clear() {
+	b.m.Clear()
+	b.size = 0
+}
+
+// All returns an iterator that yields each distinct element as many times as
+// its count.
+func (b *base[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t, n := range b.m.All() {
+			for range n {
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Entries returns an iterator over the distinct elements and their counts.
+func (b *base[T]) Entries() iter.Seq2[T, int] {
+	return b.m.All()
+}
+
+// ElementSet returns a read-only view of the distinct elements. The view
+// reflects later changes to the multiset.
+func (b *base[T]) ElementSet() collections.ReadOnlySet[T] {
+	return collections.NewReadOnlySet[T](elementSet[T]{b})
+}
+
+// MostCommon returns the k elements with the highest counts, with their
+// counts, from most to least common. Elements with equal counts are returned in
+// iteration order. If k is negative or exceeds the number of distinct elements,
+// all elements are returned.
+func (b *base[T]) MostCommon(k int) []pair.Pair[T, int] {
+	entries := make([]pair.Pair[T, int], 0, b.m.Size())
+	for t, n := range b.m.All() {
+		entries = append(entries, pair.New(t, n))
+	}
+	slices.SortStableFunc(entries, func(x, y pair.Pair[T, int]) int {
+		return cmp.Compare(y.Snd(), x.Snd())
+	})
+	if k >= 0 && k < len(entries) {
+		entries = entries[:k]
+	}
+	return entries
+}
+
+// Union sets the count of each element to the larger of its counts in this
+// multiset and other.
+func (b *base[T]) Union(other Multiset[T]) {
+	for t, n := range snapshot(other) {
+		if n > b.Count(t) {
+			b.SetCount(t, n)
+		}
+	}
+}
+
+// Intersect sets the count of each element to the smaller of its counts in
+// this multiset and other.
+func (b *base[T]) Intersect(other Multiset[T]) {
+	for t, n := range snapshot[T](b) {
+		if m := other.Count(t); m < n {
+			b.SetCount(t, m)
+		}
+	}
+}
+
+// Sum adds the count of each element in other to its count in this multiset.
+func (b *base[T]) Sum(other Multiset[T]) {
+	for t, n := range snapshot(other) {
+		b.Add(t, n)
+	}
+}
+
+// Subtract subtracts the count of each element in other from its count in this
+// multiset, removing elements whose count drops to zero or below.
+func (b *base[T]) Subtract(other Multiset[T]) {
+	for t, n := range snapshot(other) {
+		b.Remove(t, n)
+	}
+}
+
+// String returns a string representation of the multiset.
+func (b *base[T]) String() string {
+	entries := make([]string, 0, b.m.Size())
+	for t, n := range b.m.All() {
+		entries = append(entries, fmt.Sprintf("%+v: %d", t, n))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// MarshalJSON encodes the multiset as a JSON object mapping each distinct
+// element to its count, or as an array of [element, count] pairs if T cannot be
+// an object key.
+func (b *base[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(b.m.All())
+}
+
+// UnmarshalJSON replaces the contents of the multiset with the counts of a JSON
+// object or array of [element, count] pairs.
+func (b *base[T]) UnmarshalJSON(data []byte) error {
+	var entries []pair.Pair[T, int]
+	decoded := false
+	err := codec.UnmarshalMap(data, func() { decoded = true }, func(t T, n int) {
+		entries = append(entries, pair.New(t, n))
+	})
+	if err != nil || !decoded {
+		return err
+	}
+	for _, e := range entries {
+		if e.Snd() < 0 {
+			return fmt.Errorf("multiset: cannot unmarshal negative count %d", e.Snd())
+		}
+	}
+	b.Clear()
+	for _, e := range entries {
+		b.Add(e.Unwrap())
+	}
+	return nil
+}
+
+// snapshot returns the entries of m, so that m can be modified while they are
+// applied.
+func snapshot[T any](m Multiset[T]) iter.Seq2[T, int] {
+	var entries []pair.Pair[T, int]
+	for t, n := range m.Entries() {
+		entries = append(entries, pair.New(t, n))
+	}
+	return func(yield func(T, int) bool) {
+		for _, e := range entries {
+			if !yield(e.Unwrap()) {
+				return
+			}
+		}
+	}
+}
+
+// elementSet is a live collections.Set of the distinct elements of a multiset.
+type elementSet[T any] struct {
+	b *base[T]
+}
+
+func (s elementSet[T]) All() iter.Seq[T] {
+	return s.b.m.Keys()
+}
+
+func (s elementSet[T]) Size() int {
+	return s.b.m.Size()
+}
+
+func (s elementSet[T]) Empty() bool {
+	return s.b.m.Empty()
+}
+
+func (s elementSet[T]) Contains(t T) bool {
+	return s.b.m.ContainsKey(t)
+}
+
+func (s elementSet[T]) ContainsAll(other collections.Collection[T]) bool {
+	for t := range other.All() {
+		if !s.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s elementSet[T]) String() string {
+	vals := make([]string, 0, s.Size())
+	for t := range s.All() {
+		vals = append(vals, fmt.Sprintf("%+v", t))
+	}
+	return "[" + strings.Join(vals, ", ") + "]"
+}
@@ -0,0 +1,46 @@
+package multiset_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections/multiset"
+)
+
+func BenchmarkHashMultiset_Add(b *testing.B) {
+	ms := multiset.NewHash[int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ms.Add(i%1000, 1)
+	}
+}
+
+func BenchmarkTreeMultiset_Add(b *testing.B) {
+	ms := multiset.NewTree(cmp.Compare[int])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ms.Add(i%1000, 1)
+	}
+}
+
+func BenchmarkHashMultiset_Count(b *testing.B) {
+	ms := multiset.NewHash[int]()
+	for i := 0; i < 1000; i++ {
+		ms.Add(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ms.Count(i % 1000)
+	}
+}
+
+func BenchmarkHashMultiset_MostCommon(b *testing.B) {
+	ms := multiset.NewHash[int]()
+	for i := 0; i < 1000; i++ {
+		ms.Add(i, i%17)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ms.MostCommon(10)
+	}
+}
@@ -0,0 +1,260 @@
+package multiset
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections/pair"
+)
+
+func counts[T comparable](ms Multiset[T]) map[T]int {
+	return maps.Collect(ms.Entries())
+}
+
+func TestAddRemove(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		ms   func() MutableMultiset[string]
+	}{
+		{name: "hash", ms: func() MutableMultiset[string] {
+			return NewHash[string]()
+		}},
+		{name: "tree", ms: func() MutableMultiset[string] {
+			return NewTree(cmp.Compare[string])
+		}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ms := tc.ms()
+			ms.Add("a", 2)
+			ms.Add("b", 1)
+			ms.Add("a", 1)
+			ms.Add("c", 0)
+			if got, want := counts(ms), map[string]int{"a": 3, "b": 1}; !maps.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+			if ms.Size() != 4 || ms.Empty() || len(slices.Collect(ms.All())) != 4 {
+				t.Errorf("expected size 4, got %d", ms.Size())
+			}
+			if ms.Count("c") != 0 || ms.Contains("c") || !ms.Contains("a") {
+				t.Errorf("wrong Count or Contains results for %v", ms)
+			}
+
+			ms.Remove("a", 2)
+			ms.Remove("b", 5)
+			ms.Remove("z", 1)
+			if got, want := counts(ms), map[string]int{"a": 1}; !maps.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+			if ms.Size() != 1 || ms.ElementSet().Contains("b") {
+				t.Errorf("expected b to be removed with its last occurrence, got %v", ms)
+			}
+
+			ms.SetCount("d", 4)
+			ms.SetCount("a", 0)
+			if got, want := counts(ms), map[string]int{"d": 4}; !maps.Equal(got, want) || ms.Size() != 4 {
+				t.Errorf("expected %v with size 4, got %v with size %d", want, got, ms.Size())
+			}
+
+			ms.Clear()
+			if !ms.Empty() || ms.Size() != 0 || ms.ElementSet().Size() != 0 {
+				t.Errorf("expected an empty multiset, got %v", ms)
+			}
+		})
+	}
+}
+
+func TestNegativeCountPanics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		op   func(*HashMultiset[int])
+	}{
+		{name: "add", op: func(ms *HashMultiset[int]) { ms.Add(1, -1) }},
+		{name: "remove", op: func(ms *HashMultiset[int]) { ms.Remove(1, -1) }},
+		{name: "set_count", op: func(ms *HashMultiset[int]) { ms.SetCount(1, -1) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for a negative count")
+				}
+			}()
+			tc.op(NewHash[int]())
+		})
+	}
+}
+
+func TestTreeMultiset(t *testing.T) {
+	t.Parallel()
+	ms := NewTree(cmp.Compare[int])
+	for _, n := range []int{5, 1, 3, 1, 5, 5} {
+		ms.Add(n, 1)
+	}
+	if got, want := slices.Collect(ms.All()), []int{1, 1, 3, 5, 5, 5}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(ms.ElementSet().All()), []int{1, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if e, n := ms.First(); e != 1 || n != 2 {
+		t.Errorf("expected first (1, 2), got (%d, %d)", e, n)
+	}
+	if e, n := ms.Last(); e != 5 || n != 3 {
+		t.Errorf("expected last (5, 3), got (%d, %d)", e, n)
+	}
+	if got, want := ms.String(), "{1: 2, 3: 1, 5: 3}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestMostCommon(t *testing.T) {
+	t.Parallel()
+	ms := NewTree(cmp.Compare[string])
+	for _, s := range []string{"c", "a", "b", "c", "b", "c", "d"} {
+		ms.Add(s, 1)
+	}
+	cases := []struct {
+		name string
+		k    int
+		want []pair.Pair[string, int]
+	}{
+		{name: "zero", k: 0, want: []pair.Pair[string, int]{}},
+		{name: "one", k: 1, want: []pair.Pair[string, int]{pair.New("c", 3)}},
+		{name: "ties_in_order", k: 3, want: []pair.Pair[string, int]{pair.New("c", 3), pair.New("b", 2), pair.New("a", 1)}},
+		{name: "all", k: -1, want: []pair.Pair[string, int]{pair.New("c", 3), pair.New("b", 2), pair.New("a", 1), pair.New("d", 1)}},
+		{name: "more_than_size", k: 10, want: []pair.Pair[string, int]{pair.New("c", 3), pair.New("b", 2), pair.New("a", 1), pair.New("d", 1)}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ms.MostCommon(tc.k); !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	t.Parallel()
+	of := func(m map[string]int) *HashMultiset[string] {
+		ms := NewHash[string]()
+		for s, n := range m {
+			ms.Add(s, n)
+		}
+		return ms
+	}
+	a := map[string]int{"x": 3, "y": 1}
+	b := map[string]int{"x": 1, "y": 2, "z": 1}
+	cases := []struct {
+		name string
+		op   func(ms *HashMultiset[string], other Multiset[string])
+		want map[string]int
+		self map[string]int
+	}{
+		{name: "union", op: (*HashMultiset[string]).Union, want: map[string]int{"x": 3, "y": 2, "z": 1}, self: a},
+		{name: "intersect", op: (*HashMultiset[string]).Intersect, want: map[string]int{"x": 1, "y": 1}, self: a},
+		{name: "sum", op: (*HashMultiset[string]).Sum, want: map[string]int{"x": 4, "y": 3, "z": 1}, self: map[string]int{"x": 6, "y": 2}},
+		{name: "subtract", op: (*HashMultiset[string]).Subtract, want: map[string]int{"x": 2}, self: map[string]int{}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ms := of(a)
+			tc.op(ms, of(b))
+			if got := counts(ms); !maps.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+			if want := sum(tc.want); ms.Size() != want {
+				t.Errorf("expected size %d, got %d", want, ms.Size())
+			}
+			ms = of(a)
+			tc.op(ms, ms)
+			if got := counts(ms); !maps.Equal(got, tc.self) {
+				t.Errorf("expected %v with itself, got %v", tc.self, got)
+			}
+		})
+	}
+	tree := NewTree(cmp.Compare[string])
+	tree.Add("y", 5)
+	ms := of(a)
+	ms.Intersect(tree)
+	if got, want := counts(ms), map[string]int{"y": 1}; !maps.Equal(got, want) {
+		t.Errorf("expected %v across implementations, got %v", want, got)
+	}
+}
+
+func sum(m map[string]int) int {
+	n := 0
+	for _, c := range m {
+		n += c
+	}
+	return n
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	ms := NewTree(cmp.Compare[string])
+	ms.Add("b", 2)
+	ms.Add("a", 1)
+	data, err := json.Marshal(ms)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":1,"b":2}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	got := NewHash[string]()
+	got.Add("stale", 1)
+	if err := json.Unmarshal([]byte(`{"a":1,"b":2,"c":0}`), got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if want := map[string]int{"a": 1, "b": 2}; !maps.Equal(counts(got), want) || got.Size() != 3 {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if err := json.Unmarshal([]byte(`{"a":-1}`), got); err == nil {
+		t.Errorf("expected an error for a negative count")
+	}
+	if err := json.Unmarshal([]byte(`null`), got); err != nil || got.Size() != 3 {
+		t.Errorf("expected null to leave the multiset unchanged, got %v, %v", got, err)
+	}
+}
+
+func TestUnmarshalJSONZeroValue(t *testing.T) {
+	t.Parallel()
+	var hm HashMultiset[string]
+	if err := json.Unmarshal([]byte(`{"a":1,"b":2}`), &hm); err != nil {
+		t.Fatalf("Unmarshal HashMultiset: %v", err)
+	}
+	if want := map[string]int{"a": 1, "b": 2}; !maps.Equal(counts(&hm), want) || hm.Size() != 3 {
+		t.Errorf("expected %v, got %v", want, &hm)
+	}
+	var tm TreeMultiset[string]
+	if err := json.Unmarshal([]byte(`{"b":2,"a":1}`), &tm); err != nil {
+		t.Fatalf("Unmarshal TreeMultiset: %v", err)
+	}
+	if first, n := tm.First(); first != "a" || n != 1 || tm.Size() != 3 {
+		t.Errorf("expected a sorted multiset starting with a: 1, got %v", &tm)
+	}
+	var unordered TreeMultiset[struct{}]
+	if err := json.Unmarshal([]byte(`[[{}, 1]]`), &unordered); err == nil {
+		t.Error("expected an error decoding into a TreeMultiset without a comparator")
+	}
+	var fields struct {
+		Counts *HashMultiset[string] `json:"counts"`
+	}
+	if err := json.Unmarshal([]byte(`{"counts":{"x":4}}`), &fields); err != nil || fields.Counts.Count("x") != 4 {
+		t.Errorf("expected a struct field to decode, got %v, %v", fields.Counts, err)
+	}
+}
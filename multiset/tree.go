@@ -0,0 +1,52 @@
+package multiset
+
+import (
+	"fmt"
+
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/treemap"
+)
+
+var _ MutableMultiset[int] = (*TreeMultiset[int])(nil)
+
+// TreeMultiset is a multiset that holds the count of each element in a treemap,
+// so it iterates elements in the order imposed by its comparator.
+type TreeMultiset[T any] struct {
+	base[T]
+	tm *treemap.TreeMap[T, int]
+}
+
+// NewTree creates an empty TreeMultiset that orders elements with the given
+// comparator.
+func NewTree[T any](comp comparator.Comparator[T]) *TreeMultiset[T] {
+	tm := treemap.New[T, int](treemap.WithComparator(comp))
+	return &TreeMultiset[T]{base: base[T]{m: tm}, tm: tm}
+}
+
+// First returns the least element and its count. First panics if the multiset
+// is empty.
+func (ms *TreeMultiset[T]) First() (T, int) {
+	return ms.tm.First()
+}
+
+// Last returns the greatest element and its count. Last panics if the multiset
+// is empty.
+func (ms *TreeMultiset[T]) Last() (T, int) {
+	return ms.tm.Last()
+}
+
+// UnmarshalJSON replaces the contents of the multiset with the counts of a JSON
+// object or array of [element, count] pairs. When called on the zero value, the
+// multiset orders elements of ordered types by their natural order.
+func (ms *TreeMultiset[T]) UnmarshalJSON(data []byte) error {
+	if ms.tm == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("multiset: cannot unmarshal into a TreeMultiset without a comparator")
+		}
+		ms.tm = treemap.New[T, int](treemap.WithComparator(c))
+		ms.m = ms.tm
+	}
+	return ms.base.UnmarshalJSON(data)
+}
@@ -2,6 +2,8 @@
 package optional
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 )
 
@@ -142,3 +144,27 @@ func (o Option[T]) String() string {
 	}
 	return "None"
 }
+
+// MarshalJSON encodes the Option as its value if present, or as null if empty.
+// Use the omitzero struct tag option to leave empty Options out entirely.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes null into an empty Option, and any other value into an
+// Option containing it.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*o = Option[T]{}
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*o = Of(value)
+	return nil
+}
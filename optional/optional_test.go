@@ -1,6 +1,7 @@
 package optional_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"testing"
@@ -525,3 +526,39 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	type doc struct {
+		Name optional.Option[string] `json:"name"`
+		Age  optional.Option[int]    `json:"age,omitzero"`
+	}
+	tests := []struct {
+		name string
+		doc  doc
+		json string
+	}{
+		{name: "present", doc: doc{Name: optional.Of("ann"), Age: optional.Of(0)}, json: `{"name":"ann","age":0}`},
+		{name: "empty", doc: doc{}, json: `{"name":null}`},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(tc.doc)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			got := doc{Name: optional.Of("stale")}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got != tc.doc {
+				t.Errorf("expected %v, got %v", tc.doc, got)
+			}
+		})
+	}
+}
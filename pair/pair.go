@@ -1,7 +1,10 @@
 // Package pair provides a generic 2-element tuple.
 package pair
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/lock14/collections/internal/codec"
+)
 
 // Pair represents a generic 2-element tuple.
 type Pair[T1 any, T2 any] struct {
@@ -32,3 +35,13 @@ func (p Pair[T1, T2]) Unwrap() (T1, T2) {
 func (p Pair[T1, T2]) String() string {
 	return fmt.Sprintf("(%v, %v)", p.fst, p.snd)
 }
+
+// MarshalJSON encodes the Pair as a two-element JSON array.
+func (p Pair[T1, T2]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalPair(p.fst, p.snd)
+}
+
+// UnmarshalJSON decodes a two-element JSON array into the Pair.
+func (p *Pair[T1, T2]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalPair(data, &p.fst, &p.snd)
+}
@@ -1,6 +1,7 @@
 package pair
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -44,3 +45,24 @@ func TestPair(t *testing.T) {
 		})
 	}
 }
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(New("a", 1))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `["a",1]`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	var got Pair[string, int]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got != New("a", 1) {
+		t.Errorf("expected (a, 1), got %v", got)
+	}
+	if err := json.Unmarshal([]byte(`["a",1,2]`), &got); err == nil {
+		t.Errorf("expected an error for a three-element array")
+	}
+}
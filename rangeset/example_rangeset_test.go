@@ -0,0 +1,48 @@
+package rangeset_test
+
+import (
+	"fmt"
+
+	"github.com/lock14/collections/rangeset"
+)
+
+func ExampleRangeSet() {
+	// Reserve port ranges; touching and overlapping reservations coalesce.
+	reserved := rangeset.NewOrdered[int]()
+	reserved.Add(rangeset.ClosedOpen(8000, 8100))
+	reserved.Add(rangeset.ClosedOpen(8100, 8200))
+	reserved.Add(rangeset.Closed(9000, 9010))
+	fmt.Println(reserved)
+
+	fmt.Println("8150 reserved:", reserved.Contains(8150))
+	fmt.Println("8190-8199 reserved:", reserved.Encloses(rangeset.Closed(8190, 8199)))
+
+	// The complement, limited to the user ports, is what is still free.
+	free := reserved.Complement().SubRangeSet(rangeset.Closed(1024, 49151))
+	fmt.Println(free)
+
+	// Output:
+	// {[8000, 8200), [9000, 9010]}
+	// 8150 reserved: true
+	// 8190-8199 reserved: true
+	// {[1024, 8000), [8200, 9000), (9010, 49151]}
+}
+
+func ExampleRangeMap() {
+	// Putting a range splits the ranges it overlaps.
+	tiers := rangeset.NewOrderedMap[int, string]()
+	tiers.Put(rangeset.AtLeast(0), "standard")
+	tiers.Put(rangeset.ClosedOpen(100, 200), "premium")
+
+	for r, tier := range tiers.All() {
+		fmt.Println(r, tier)
+	}
+	tier, _ := tiers.Get(150)
+	fmt.Println("150:", tier)
+
+	// Output:
+	// [0, 100) standard
+	// [100, 200) premium
+	// [200, +∞) standard
+	// 150: premium
+}
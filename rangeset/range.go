@@ -0,0 +1,335 @@
+// Package rangeset provides sets and maps of ranges over ordered values.
+//
+// A Range is an interval whose endpoints are each inclusive, exclusive or
+// unbounded. Ranges carry no ordering of their own, so methods that compare
+// endpoints take a comparator.Comparator. A RangeSet holds the union of the
+// ranges added to it as disjoint, unconnected ranges, merging each range added
+// with any range it overlaps or touches. A RangeMap associates values with
+// disjoint ranges, splitting the ranges already present when a new range
+// overlaps them. Both are backed by a treemap keyed by the lower endpoints of
+// their ranges.
+package rangeset
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lock14/collections/comparator"
+)
+
+// Bound describes an endpoint of a range.
+type Bound int
+
+const (
+	// Unbounded means the range extends indefinitely in the direction of the endpoint.
+	Unbounded Bound = iota
+	// Inclusive means the range contains its endpoint.
+	Inclusive
+	// Exclusive means the range does not contain its endpoint.
+	Exclusive
+)
+
+// String returns the name of the bound.
+func (b Bound) String() string {
+	switch b {
+	case Unbounded:
+		return "unbounded"
+	case Inclusive:
+		return "inclusive"
+	case Exclusive:
+		return "exclusive"
+	}
+	return fmt.Sprintf("Bound(%d)", int(b))
+}
+
+// cutKind orders the positions a cut can take relative to a value.
+type cutKind int8
+
+const (
+	belowAll cutKind = iota
+	belowValue
+	aboveValue
+	aboveAll
+)
+
+// cut is a point between values: just below a value, just above it, or beyond
+// every value. Representing both endpoints of a range as cuts reduces every
+// comparison of endpoints to one ordering, whatever their bounds.
+type cut[T any] struct {
+	value T
+	kind  cutKind
+}
+
+func (c cut[T]) compare(comp comparator.Comparator[T], o cut[T]) int {
+	if c.kind == belowAll || c.kind == aboveAll || o.kind == belowAll || o.kind == aboveAll {
+		return cmp.Compare(c.rank(), o.rank())
+	}
+	if n := comp(c.value, o.value); n != 0 {
+		return n
+	}
+	return cmp.Compare(c.kind, o.kind)
+}
+
+func (c cut[T]) rank() int {
+	switch c.kind {
+	case belowAll:
+		return -1
+	case aboveAll:
+		return 1
+	}
+	return 0
+}
+
+func minCut[T any](comp comparator.Comparator[T], a, b cut[T]) cut[T] {
+	if a.compare(comp, b) <= 0 {
+		return a
+	}
+	return b
+}
+
+func maxCut[T any](comp comparator.Comparator[T], a, b cut[T]) cut[T] {
+	if a.compare(comp, b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// Range is an interval of values of type T. Use the constructors in this
+// package to create one.
+type Range[T any] struct {
+	lower cut[T]
+	upper cut[T]
+}
+
+// NewRange creates a range with the given endpoints. The value of an Unbounded
+// endpoint is ignored. Ranges whose lower endpoint is greater than their upper
+// endpoint are invalid, and panic when given to a RangeSet or RangeMap.
+func NewRange[T any](lower T, lowerBound Bound, upper T, upperBound Bound) Range[T] {
+	r := Range[T]{lower: cut[T]{kind: belowAll}, upper: cut[T]{kind: aboveAll}}
+	switch lowerBound {
+	case Inclusive:
+		r.lower = cut[T]{lower, belowValue}
+	case Exclusive:
+		r.lower = cut[T]{lower, aboveValue}
+	}
+	switch upperBound {
+	case Inclusive:
+		r.upper = cut[T]{upper, aboveValue}
+	case Exclusive:
+		r.upper = cut[T]{upper, belowValue}
+	}
+	return r
+}
+
+// Closed creates the range of values greater than or equal to lower and less
+// than or equal to upper.
+func Closed[T any](lower, upper T) Range[T] {
+	return NewRange(lower, Inclusive, upper, Inclusive)
+}
+
+// Open creates the range of values strictly greater than lower and strictly
+// less than upper.
+func Open[T any](lower, upper T) Range[T] {
+	return NewRange(lower, Exclusive, upper, Exclusive)
+}
+
+// ClosedOpen creates the range of values greater than or equal to lower and
+// strictly less than upper.
+func ClosedOpen[T any](lower, upper T) Range[T] {
+	return NewRange(lower, Inclusive, upper, Exclusive)
+}
+
+// OpenClosed creates the range of values strictly greater than lower and less
+// than or equal to upper.
+func OpenClosed[T any](lower, upper T) Range[T] {
+	return NewRange(lower, Exclusive, upper, Inclusive)
+}
+
+// AtLeast creates the range of values greater than or equal to lower.
+func AtLeast[T any](lower T) Range[T] {
+	var zero T
+	return NewRange(lower, Inclusive, zero, Unbounded)
+}
+
+// GreaterThan creates the range of values strictly greater than lower.
+func GreaterThan[T any](lower T) Range[T] {
+	var zero T
+	return NewRange(lower, Exclusive, zero, Unbounded)
+}
+
+// AtMost creates the range of values less than or equal to upper.
+func AtMost[T any](upper T) Range[T] {
+	var zero T
+	return NewRange(zero, Unbounded, upper, Inclusive)
+}
+
+// LessThan creates the range of values strictly less than upper.
+func LessThan[T any](upper T) Range[T] {
+	var zero T
+	return NewRange(zero, Unbounded, upper, Exclusive)
+}
+
+// Singleton creates the range containing only the given value.
+func Singleton[T any](t T) Range[T] {
+	return Closed(t, t)
+}
+
+// All returns the range of all values.
+func All[T any]() Range[T] {
+	var zero T
+	return NewRange(zero, Unbounded, zero, Unbounded)
+}
+
+// Lower returns the lower endpoint of the range and its bound. The endpoint is
+// the zero value if the range has no lower bound.
+func (r Range[T]) Lower() (T, Bound) {
+	switch r.lower.kind {
+	case belowValue:
+		return r.lower.value, Inclusive
+	case aboveValue:
+		return r.lower.value, Exclusive
+	}
+	var zero T
+	return zero, Unbounded
+}
+
+// Upper returns the upper endpoint of the range and its bound. The endpoint is
+// the zero value if the range has no upper bound.
+func (r Range[T]) Upper() (T, Bound) {
+	switch r.upper.kind {
+	case aboveValue:
+		return r.upper.value, Inclusive
+	case belowValue:
+		return r.upper.value, Exclusive
+	}
+	var zero T
+	return zero, Unbounded
+}
+
+// IsEmpty returns true if the range contains no values, such as [a, a).
+func (r Range[T]) IsEmpty(comp comparator.Comparator[T]) bool {
+	return r.lower.compare(comp, r.upper) == 0
+}
+
+// Contains returns true if the range contains the given value.
+func (r Range[T]) Contains(comp comparator.Comparator[T], t T) bool {
+	return r.lower.compare(comp, cut[T]{t, belowValue}) <= 0 && r.upper.compare(comp, cut[T]{t, aboveValue}) >= 0
+}
+
+// Encloses returns true if every value in other is in this range.
+func (r Range[T]) Encloses(comp comparator.Comparator[T], other Range[T]) bool {
+	return r.lower.compare(comp, other.lower) <= 0 && r.upper.compare(comp, other.upper) >= 0
+}
+
+// IsConnected returns true if there is a range, possibly empty, enclosed by both
+// this range and other. Ranges are connected if they overlap or touch, as
+// [1, 3) and [3, 5] do, but not if a single value separates them, as (1, 3)
+// and (3, 5) are.
+func (r Range[T]) IsConnected(comp comparator.Comparator[T], other Range[T]) bool {
+	return r.lower.compare(comp, other.upper) <= 0 && other.lower.compare(comp, r.upper) <= 0
+}
+
+// Intersection returns the largest range enclosed by both this range and
+// other, and false if the ranges are not connected.
+func (r Range[T]) Intersection(comp comparator.Comparator[T], other Range[T]) (Range[T], bool) {
+	if !r.IsConnected(comp, other) {
+		return Range[T]{}, false
+	}
+	return Range[T]{maxCut(comp, r.lower, other.lower), minCut(comp, r.upper, other.upper)}, true
+}
+
+// Span returns the smallest range that encloses both this range and other.
+func (r Range[T]) Span(comp comparator.Comparator[T], other Range[T]) Range[T] {
+	return Range[T]{minCut(comp, r.lower, other.lower), maxCut(comp, r.upper, other.upper)}
+}
+
+// String returns a string representation of the range in interval notation,
+// such as [1, 5) or (-∞, 3].
+func (r Range[T]) String() string {
+	var lower, upper string
+	switch r.lower.kind {
+	case belowValue:
+		lower = fmt.Sprintf("[%+v", r.lower.value)
+	case aboveValue:
+		lower = fmt.Sprintf("(%+v", r.lower.value)
+	default:
+		lower = "(-∞"
+	}
+	switch r.upper.kind {
+	case aboveValue:
+		upper = fmt.Sprintf("%+v]", r.upper.value)
+	case belowValue:
+		upper = fmt.Sprintf("%+v)", r.upper.value)
+	default:
+		upper = "+∞)"
+	}
+	return lower + ", " + upper
+}
+
+// endpointJSON is the JSON encoding of a bounded endpoint.
+type endpointJSON[T any] struct {
+	Value     T    `json:"value"`
+	Inclusive bool `json:"inclusive"`
+}
+
+// rangeJSON is the JSON encoding of a range, which omits unbounded endpoints.
+type rangeJSON[T any] struct {
+	Lower *endpointJSON[T] `json:"lower,omitempty"`
+	Upper *endpointJSON[T] `json:"upper,omitempty"`
+}
+
+// MarshalJSON encodes the range as a JSON object with a lower and an upper
+// endpoint, each holding a value and whether it is inclusive. Unbounded
+// endpoints are omitted.
+func (r Range[T]) MarshalJSON() ([]byte, error) {
+	var j rangeJSON[T]
+	if t, b := r.Lower(); b != Unbounded {
+		j.Lower = &endpointJSON[T]{t, b == Inclusive}
+	}
+	if t, b := r.Upper(); b != Unbounded {
+		j.Upper = &endpointJSON[T]{t, b == Inclusive}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a range encoded by MarshalJSON.
+func (r *Range[T]) UnmarshalJSON(data []byte) error {
+	var j rangeJSON[T]
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	var lower, upper T
+	lowerBound, upperBound := Unbounded, Unbounded
+	if j.Lower != nil {
+		lower, lowerBound = j.Lower.Value, bound(j.Lower.Inclusive)
+	}
+	if j.Upper != nil {
+		upper, upperBound = j.Upper.Value, bound(j.Upper.Inclusive)
+	}
+	*r = NewRange(lower, lowerBound, upper, upperBound)
+	return nil
+}
+
+func bound(inclusive bool) Bound {
+	if inclusive {
+		return Inclusive
+	}
+	return Exclusive
+}
+
+// valid returns an error if the lower endpoint of r is greater than its upper
+// endpoint.
+func valid[T any](comp comparator.Comparator[T], r Range[T]) error {
+	if r.lower.compare(comp, r.upper) > 0 {
+		return fmt.Errorf("rangeset: invalid range %v", r)
+	}
+	return nil
+}
+
+// mustBeValid panics if r is invalid.
+func mustBeValid[T any](comp comparator.Comparator[T], r Range[T]) {
+	if err := valid(comp, r); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,190 @@
+package rangeset
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/pair"
+	"github.com/lock14/collections/treemap"
+)
+
+// entry is a range and the value associated with it.
+type entry[K any, V any] struct {
+	r     Range[K]
+	value V
+}
+
+// RangeMap is a map from disjoint, non-empty ranges to values. Putting a range
+// replaces the values of any part of the ranges it overlaps, so each value of
+// K is in at most one range of the map. Ranges that touch are not merged, even
+// if they have equal values.
+type RangeMap[K any, V any] struct {
+	m    *treemap.TreeMap[cut[K], entry[K, V]]
+	comp comparator.Comparator[K]
+}
+
+// NewMap creates an empty RangeMap that orders keys with the given comparator.
+func NewMap[K any, V any](comp comparator.Comparator[K]) *RangeMap[K, V] {
+	return &RangeMap[K, V]{
+		m: treemap.New[cut[K], entry[K, V]](treemap.WithComparator(func(a, b cut[K]) int {
+			return a.compare(comp, b)
+		})),
+		comp: comp,
+	}
+}
+
+// NewOrderedMap creates an empty RangeMap for keys that satisfy cmp.Ordered
+// using natural ordering.
+func NewOrderedMap[K cmp.Ordered, V any]() *RangeMap[K, V] {
+	return NewMap[K, V](comparator.NaturalOrder[K]())
+}
+
+// Get returns the value associated with the range containing the specified
+// key, and a boolean indicating if it was found.
+func (rm *RangeMap[K, V]) Get(key K) (V, bool) {
+	_, v, ok := rm.GetEntry(key)
+	return v, ok
+}
+
+// GetEntry returns the range containing the specified key and its value, and a
+// boolean indicating if it was found.
+func (rm *RangeMap[K, V]) GetEntry(key K) (Range[K], V, bool) {
+	if _, e, ok := rm.m.Floor(cut[K]{key, belowValue}); ok && e.r.Contains(rm.comp, key) {
+		return e.r, e.value, true
+	}
+	var zero V
+	return Range[K]{}, zero, false
+}
+
+// Put associates the specified value with every key in the specified range,
+// replacing the values of any ranges it overlaps. Putting an empty range has no
+// effect. Put panics if the range is invalid.
+func (rm *RangeMap[K, V]) Put(r Range[K], value V) {
+	mustBeValid(rm.comp, r)
+	if r.IsEmpty(rm.comp) {
+		return
+	}
+	rm.Remove(r)
+	rm.m.Put(r.lower, entry[K, V]{r, value})
+}
+
+// Remove removes every key in the specified range from the map, shrinking or
+// splitting the ranges it overlaps. Remove panics if the range is invalid.
+func (rm *RangeMap[K, V]) Remove(r Range[K]) {
+	mustBeValid(rm.comp, r)
+	if r.IsEmpty(rm.comp) {
+		return
+	}
+	for _, e := range rm.overlapping(r) {
+		rm.m.Remove(e.r.lower)
+		if e.r.lower.compare(rm.comp, r.lower) < 0 {
+			rm.m.Put(e.r.lower, entry[K, V]{Range[K]{e.r.lower, r.lower}, e.value})
+		}
+		if e.r.upper.compare(rm.comp, r.upper) > 0 {
+			rm.m.Put(r.upper, entry[K, V]{Range[K]{r.upper, e.r.upper}, e.value})
+		}
+	}
+}
+
+// overlapping returns the entries whose ranges share at least one value with
+// r, in order.
+func (rm *RangeMap[K, V]) overlapping(r Range[K]) []entry[K, V] {
+	var entries []entry[K, V]
+	if _, e, ok := rm.m.Lower(r.lower); ok && e.r.upper.compare(rm.comp, r.lower) > 0 {
+		entries = append(entries, e)
+	}
+	for lower, e := range rm.m.From(r.lower) {
+		if lower.compare(rm.comp, r.upper) >= 0 {
+			break
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// Clear removes all ranges from the map.
+func (rm *RangeMap[K, V]) Clear() {
+	rm.m.Clear()
+}
+
+// Size returns the number of ranges in the map.
+func (rm *RangeMap[K, V]) Size() int {
+	return rm.m.Size()
+}
+
+// Empty returns true if the map contains no ranges.
+func (rm *RangeMap[K, V]) Empty() bool {
+	return rm.m.Empty()
+}
+
+// All returns an iterator over the ranges of the map and their values, in
+// ascending order.
+func (rm *RangeMap[K, V]) All() iter.Seq2[Range[K], V] {
+	return func(yield func(Range[K], V) bool) {
+		for _, e := range rm.m.All() {
+			if !yield(e.r, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Span returns the smallest range that encloses every range in the map. Panics
+// if the map is empty.
+func (rm *RangeMap[K, V]) Span() Range[K] {
+	if rm.m.Empty() {
+		panic("Span called on empty map")
+	}
+	_, first := rm.m.First()
+	_, last := rm.m.Last()
+	return Range[K]{first.r.lower, last.r.upper}
+}
+
+// String returns a string representation of the map.
+func (rm *RangeMap[K, V]) String() string {
+	entries := make([]string, 0, rm.m.Size())
+	for r, v := range rm.All() {
+		entries = append(entries, fmt.Sprintf("%v: %+v", r, v))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// MarshalJSON encodes the map as a JSON array of [range, value] pairs.
+func (rm *RangeMap[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(rm.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with a JSON array of [range,
+// value] pairs, putting them in order. When called on the zero value, the map
+// orders keys of ordered types by their natural order.
+func (rm *RangeMap[K, V]) UnmarshalJSON(data []byte) error {
+	if rm.m == nil {
+		c, ok := codec.NaturalOrder[K]()
+		if !ok {
+			return fmt.Errorf("rangeset: cannot unmarshal into a RangeMap without a comparator")
+		}
+		*rm = *NewMap[K, V](c)
+	}
+	var entries []pair.Pair[Range[K], V]
+	decoded := false
+	err := codec.UnmarshalMap(data, func() { decoded = true }, func(r Range[K], v V) {
+		entries = append(entries, pair.New(r, v))
+	})
+	if err != nil || !decoded {
+		return err
+	}
+	for _, e := range entries {
+		if err := valid(rm.comp, e.Fst()); err != nil {
+			return err
+		}
+	}
+	rm.Clear()
+	for _, e := range entries {
+		rm.Put(e.Unwrap())
+	}
+	return nil
+}
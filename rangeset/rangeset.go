@@ -0,0 +1,216 @@
+package rangeset
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/internal/codec"
+)
+
+var _ collections.Collection[Range[int]] = (*RangeSet[int])(nil)
+
+// RangeSet is a set of values represented as the union of disjoint, non-empty
+// ranges that are not connected to each other. Adding a range merges it with
+// every range it is connected to, so adding [1, 3) and then [3, 5] leaves the
+// single range [1, 5].
+type RangeSet[T any] struct {
+	m *RangeMap[T, struct{}]
+}
+
+// New creates an empty RangeSet that orders values with the given comparator.
+func New[T any](comp comparator.Comparator[T]) *RangeSet[T] {
+	return &RangeSet[T]{m: NewMap[T, struct{}](comp)}
+}
+
+// NewOrdered creates an empty RangeSet for values that satisfy cmp.Ordered
+// using natural ordering.
+func NewOrdered[T cmp.Ordered]() *RangeSet[T] {
+	return New(comparator.NaturalOrder[T]())
+}
+
+// FromSeq creates a RangeSet containing the union of the ranges of the given
+// sequence.
+func FromSeq[T any](sequence iter.Seq[Range[T]], comp comparator.Comparator[T]) *RangeSet[T] {
+	rs := New(comp)
+	rs.AddAll(sequence)
+	return rs
+}
+
+// Add adds every value of the specified range to the set, merging it with the
+// ranges it is connected to. Adding an empty range has no effect. Add panics
+// if the range is invalid.
+func (rs *RangeSet[T]) Add(r Range[T]) {
+	comp := rs.m.comp
+	mustBeValid(comp, r)
+	if r.IsEmpty(comp) {
+		return
+	}
+	if _, e, ok := rs.m.m.Floor(r.lower); ok && e.r.IsConnected(comp, r) {
+		r = r.Span(comp, e.r)
+	}
+	for lower, e := range rs.m.m.From(r.lower) {
+		if lower.compare(comp, r.upper) > 0 {
+			break
+		}
+		r = r.Span(comp, e.r)
+	}
+	rs.m.Put(r, struct{}{})
+}
+
+// AddAll adds every range of the given sequence to the set.
+func (rs *RangeSet[T]) AddAll(sequence iter.Seq[Range[T]]) {
+	for r := range sequence {
+		rs.Add(r)
+	}
+}
+
+// Remove removes every value of the specified range from the set, shrinking or
+// splitting the ranges it overlaps. Remove panics if the range is invalid.
+func (rs *RangeSet[T]) Remove(r Range[T]) {
+	rs.m.Remove(r)
+}
+
+// Clear removes all ranges from the set.
+func (rs *RangeSet[T]) Clear() {
+	rs.m.Clear()
+}
+
+// Size returns the number of disjoint ranges in the set.
+func (rs *RangeSet[T]) Size() int {
+	return rs.m.Size()
+}
+
+// Empty returns true if the set contains no values.
+func (rs *RangeSet[T]) Empty() bool {
+	return rs.m.Empty()
+}
+
+// All returns an iterator over the disjoint ranges of the set, in ascending
+// order.
+func (rs *RangeSet[T]) All() iter.Seq[Range[T]] {
+	return func(yield func(Range[T]) bool) {
+		for r := range rs.m.All() {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if the set contains the specified value.
+func (rs *RangeSet[T]) Contains(t T) bool {
+	_, ok := rs.m.Get(t)
+	return ok
+}
+
+// RangeContaining returns the range of the set that contains the specified
+// value, and a boolean indicating if it was found.
+func (rs *RangeSet[T]) RangeContaining(t T) (Range[T], bool) {
+	r, _, ok := rs.m.GetEntry(t)
+	return r, ok
+}
+
+// Encloses returns true if a single range of the set encloses every value of
+// the specified range.
+func (rs *RangeSet[T]) Encloses(r Range[T]) bool {
+	_, e, ok := rs.m.m.Floor(r.lower)
+	return ok && e.r.Encloses(rs.m.comp, r)
+}
+
+// Intersects returns true if the set contains at least one value of the
+// specified range.
+func (rs *RangeSet[T]) Intersects(r Range[T]) bool {
+	return !r.IsEmpty(rs.m.comp) && len(rs.m.overlapping(r)) > 0
+}
+
+// Span returns the smallest range that encloses every range in the set. Panics
+// if the set is empty.
+func (rs *RangeSet[T]) Span() Range[T] {
+	if rs.m.Empty() {
+		panic("Span called on empty set")
+	}
+	return rs.m.Span()
+}
+
+// Complement returns a new RangeSet containing every value that is not in this
+// set.
+func (rs *RangeSet[T]) Complement() *RangeSet[T] {
+	comp := rs.m.comp
+	complement := New(comp)
+	lower := cut[T]{kind: belowAll}
+	for r := range rs.All() {
+		if lower.compare(comp, r.lower) < 0 {
+			complement.m.m.Put(lower, entry[T, struct{}]{r: Range[T]{lower, r.lower}})
+		}
+		lower = r.upper
+	}
+	if lower.kind != aboveAll {
+		complement.m.m.Put(lower, entry[T, struct{}]{r: Range[T]{lower, cut[T]{kind: aboveAll}}})
+	}
+	return complement
+}
+
+// SubRangeSet returns a new RangeSet containing the values of this set that are
+// in the specified range. SubRangeSet panics if the range is invalid.
+func (rs *RangeSet[T]) SubRangeSet(r Range[T]) *RangeSet[T] {
+	comp := rs.m.comp
+	mustBeValid(comp, r)
+	sub := New(comp)
+	if r.IsEmpty(comp) {
+		return sub
+	}
+	for _, e := range rs.m.overlapping(r) {
+		i, _ := e.r.Intersection(comp, r)
+		sub.m.m.Put(i.lower, entry[T, struct{}]{r: i})
+	}
+	return sub
+}
+
+// String returns a string representation of the set.
+func (rs *RangeSet[T]) String() string {
+	ranges := make([]string, 0, rs.Size())
+	for r := range rs.All() {
+		ranges = append(ranges, r.String())
+	}
+	return "{" + strings.Join(ranges, ", ") + "}"
+}
+
+// MarshalJSON encodes the set as a JSON array of its disjoint ranges.
+func (rs *RangeSet[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(rs.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the union of a JSON array
+// of ranges. When called on the zero value, the set orders values of ordered
+// types by their natural order.
+func (rs *RangeSet[T]) UnmarshalJSON(data []byte) error {
+	if rs.m == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("rangeset: cannot unmarshal into a RangeSet without a comparator")
+		}
+		*rs = *New(c)
+	}
+	var ranges []Range[T]
+	decoded := false
+	err := codec.UnmarshalSeq(data, func() { decoded = true }, func(r Range[T]) {
+		ranges = append(ranges, r)
+	})
+	if err != nil || !decoded {
+		return err
+	}
+	for _, r := range ranges {
+		if err := valid(rs.m.comp, r); err != nil {
+			return err
+		}
+	}
+	rs.Clear()
+	for _, r := range ranges {
+		rs.Add(r)
+	}
+	return nil
+}
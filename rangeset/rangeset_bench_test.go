@@ -0,0 +1,36 @@
+package rangeset_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections/rangeset"
+)
+
+func BenchmarkRangeSet_Add(b *testing.B) {
+	rs := rangeset.NewOrdered[int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := (i * 7919) % 100000
+		rs.Add(rangeset.ClosedOpen(n, n+3))
+	}
+}
+
+func BenchmarkRangeSet_Contains(b *testing.B) {
+	rs := rangeset.NewOrdered[int]()
+	for i := 0; i < 1000; i++ {
+		rs.Add(rangeset.ClosedOpen(i*10, i*10+5))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rs.Contains(i % 10000)
+	}
+}
+
+func BenchmarkRangeMap_Put(b *testing.B) {
+	rm := rangeset.NewOrderedMap[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := (i * 7919) % 100000
+		rm.Put(rangeset.ClosedOpen(n, n+50), i)
+	}
+}
@@ -0,0 +1,329 @@
+package rangeset
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+var intComp = cmp.Compare[int]
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		r        Range[int]
+		contains []int
+		excludes []int
+		str      string
+	}{
+		{name: "closed", r: Closed(1, 3), contains: []int{1, 2, 3}, excludes: []int{0, 4}, str: "[1, 3]"},
+		{name: "open", r: Open(1, 3), contains: []int{2}, excludes: []int{1, 3}, str: "(1, 3)"},
+		{name: "closed open", r: ClosedOpen(1, 3), contains: []int{1, 2}, excludes: []int{0, 3}, str: "[1, 3)"},
+		{name: "open closed", r: OpenClosed(1, 3), contains: []int{2, 3}, excludes: []int{1, 4}, str: "(1, 3]"},
+		{name: "at least", r: AtLeast(1), contains: []int{1, 100}, excludes: []int{0}, str: "[1, +∞)"},
+		{name: "greater than", r: GreaterThan(1), contains: []int{2}, excludes: []int{1}, str: "(1, +∞)"},
+		{name: "at most", r: AtMost(1), contains: []int{-100, 1}, excludes: []int{2}, str: "(-∞, 1]"},
+		{name: "less than", r: LessThan(1), contains: []int{0}, excludes: []int{1}, str: "(-∞, 1)"},
+		{name: "singleton", r: Singleton(1), contains: []int{1}, excludes: []int{0, 2}, str: "[1, 1]"},
+		{name: "all", r: All[int](), contains: []int{-100, 0, 100}, str: "(-∞, +∞)"},
+		{name: "empty", r: ClosedOpen(1, 1), excludes: []int{0, 1, 2}, str: "[1, 1)"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for _, v := range tc.contains {
+				if !tc.r.Contains(intComp, v) {
+					t.Errorf("expected %v to contain %d", tc.r, v)
+				}
+			}
+			for _, v := range tc.excludes {
+				if tc.r.Contains(intComp, v) {
+					t.Errorf("expected %v not to contain %d", tc.r, v)
+				}
+			}
+			if got := tc.r.String(); got != tc.str {
+				t.Errorf("expected %s, got %s", tc.str, got)
+			}
+			if got, want := tc.r.IsEmpty(intComp), len(tc.contains) == 0; got != want {
+				t.Errorf("expected IsEmpty to be %t, got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestRangeRelations(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name         string
+		a, b         Range[int]
+		connected    bool
+		encloses     bool
+		intersection Range[int]
+		span         Range[int]
+	}{
+		{name: "overlapping", a: Closed(1, 5), b: Open(3, 8), connected: true, intersection: OpenClosed(3, 5), span: ClosedOpen(1, 8)},
+		{name: "touching", a: ClosedOpen(1, 3), b: Closed(3, 5), connected: true, intersection: ClosedOpen(3, 3), span: Closed(1, 5)},
+		{name: "separated by a value", a: Open(1, 3), b: Open(3, 5), span: Open(1, 5)},
+		{name: "disjoint", a: Closed(1, 2), b: Closed(4, 5), span: Closed(1, 5)},
+		{name: "enclosing", a: AtLeast(0), b: Closed(2, 3), connected: true, encloses: true, intersection: Closed(2, 3), span: AtLeast(0)},
+		{name: "same", a: Closed(2, 3), b: Closed(2, 3), connected: true, encloses: true, intersection: Closed(2, 3), span: Closed(2, 3)},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tc.a.IsConnected(intComp, tc.b); got != tc.connected {
+				t.Errorf("expected IsConnected to be %t", tc.connected)
+			}
+			if got := tc.b.IsConnected(intComp, tc.a); got != tc.connected {
+				t.Errorf("expected IsConnected to be symmetric")
+			}
+			if got := tc.a.Encloses(intComp, tc.b); got != tc.encloses {
+				t.Errorf("expected Encloses to be %t", tc.encloses)
+			}
+			i, ok := tc.a.Intersection(intComp, tc.b)
+			if ok != tc.connected || (ok && i != tc.intersection) {
+				t.Errorf("expected intersection %v, %t, got %v, %t", tc.intersection, tc.connected, i, ok)
+			}
+			if got := tc.a.Span(intComp, tc.b); got != tc.span {
+				t.Errorf("expected span %v, got %v", tc.span, got)
+			}
+		})
+	}
+}
+
+func TestRangeSetAdd(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		ranges []Range[int]
+		want   string
+	}{
+		{name: "disjoint", ranges: []Range[int]{Closed(5, 6), Closed(1, 2)}, want: "{[1, 2], [5, 6]}"},
+		{name: "overlapping", ranges: []Range[int]{Closed(1, 4), Closed(3, 6)}, want: "{[1, 6]}"},
+		{name: "touching", ranges: []Range[int]{ClosedOpen(1, 3), Closed(3, 5)}, want: "{[1, 5]}"},
+		{name: "separated by a value", ranges: []Range[int]{Open(1, 3), Open(3, 5)}, want: "{(1, 3), (3, 5)}"},
+		{name: "bridging", ranges: []Range[int]{Closed(1, 2), Closed(4, 5), Closed(7, 8), Open(2, 7)}, want: "{[1, 8]}"},
+		{name: "enclosed", ranges: []Range[int]{Closed(1, 10), Closed(3, 4)}, want: "{[1, 10]}"},
+		{name: "enclosing", ranges: []Range[int]{Closed(3, 4), Closed(6, 7), AtMost(10)}, want: "{(-∞, 10]}"},
+		{name: "empty", ranges: []Range[int]{ClosedOpen(1, 1)}, want: "{}"},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rs := FromSeq(slices.Values(tc.ranges), intComp)
+			if got := rs.String(); got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRangeSetRemove(t *testing.T) {
+	t.Parallel()
+	rs := NewOrdered[int]()
+	rs.Add(Closed(1, 10))
+	rs.Add(Closed(20, 30))
+	rs.Remove(Open(3, 5))
+	rs.Remove(Closed(8, 22))
+	rs.Remove(Closed(40, 50))
+	if got, want := rs.String(), "{[1, 3], [5, 8), (22, 30]}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if rs.Size() != 3 {
+		t.Errorf("expected 3 ranges, got %d", rs.Size())
+	}
+	for _, v := range []int{1, 3, 5, 7, 23, 30} {
+		if !rs.Contains(v) {
+			t.Errorf("expected %v to contain %d", rs, v)
+		}
+	}
+	for _, v := range []int{0, 4, 8, 15, 22, 31} {
+		if rs.Contains(v) {
+			t.Errorf("expected %v not to contain %d", rs, v)
+		}
+	}
+	if r, ok := rs.RangeContaining(6); !ok || r != ClosedOpen(5, 8) {
+		t.Errorf("expected [5, 8), got %v, %t", r, ok)
+	}
+	rs.Remove(All[int]())
+	if !rs.Empty() {
+		t.Errorf("expected removing all values to empty the set, got %v", rs)
+	}
+}
+
+func TestRangeSetQueries(t *testing.T) {
+	t.Parallel()
+	rs := FromSeq(slices.Values([]Range[int]{ClosedOpen(1, 5), Closed(8, 10)}), intComp)
+	cases := []struct {
+		r          Range[int]
+		encloses   bool
+		intersects bool
+	}{
+		{r: Closed(2, 4), encloses: true, intersects: true},
+		{r: ClosedOpen(1, 5), encloses: true, intersects: true},
+		{r: Closed(1, 5), intersects: true},
+		{r: Closed(4, 9), intersects: true},
+		{r: Open(5, 8)},
+		{r: Closed(5, 5)},
+		{r: ClosedOpen(2, 2), encloses: true},
+	}
+	for _, tc := range cases {
+		if got := rs.Encloses(tc.r); got != tc.encloses {
+			t.Errorf("expected Encloses(%v) to be %t", tc.r, tc.encloses)
+		}
+		if got := rs.Intersects(tc.r); got != tc.intersects {
+			t.Errorf("expected Intersects(%v) to be %t", tc.r, tc.intersects)
+		}
+	}
+	if got, want := rs.Span(), Closed(1, 10); got != want {
+		t.Errorf("expected span %v, got %v", want, got)
+	}
+	if got, want := rs.Complement().String(), "{(-∞, 1), [5, 8), (10, +∞)}"; got != want {
+		t.Errorf("expected complement %s, got %s", want, got)
+	}
+	if got, want := rs.SubRangeSet(Closed(3, 9)).String(), "{[3, 5), [8, 9]}"; got != want {
+		t.Errorf("expected sub range set %s, got %s", want, got)
+	}
+	if got := rs.SubRangeSet(Open(5, 8)); !got.Empty() {
+		t.Errorf("expected an empty sub range set, got %v", got)
+	}
+
+	all := NewOrdered[int]()
+	all.Add(All[int]())
+	if !all.Complement().Empty() {
+		t.Errorf("expected the complement of all values to be empty")
+	}
+	if got, want := NewOrdered[int]().Complement().String(), "{(-∞, +∞)}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got, want := all.Complement().Complement().String(), "{(-∞, +∞)}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected Span of an empty set to panic")
+		}
+	}()
+	NewOrdered[int]().Span()
+}
+
+func TestInvalidRange(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{name: "add", f: func() { NewOrdered[int]().Add(Closed(3, 1)) }},
+		{name: "open singleton", f: func() { NewOrdered[int]().Add(Open(1, 1)) }},
+		{name: "remove", f: func() { NewOrdered[int]().Remove(Closed(3, 1)) }},
+		{name: "put", f: func() { NewOrderedMap[int, int]().Put(Closed(3, 1), 0) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestRangeMap(t *testing.T) {
+	t.Parallel()
+	rm := NewOrderedMap[int, string]()
+	rm.Put(Closed(1, 10), "a")
+	rm.Put(Closed(3, 5), "b")
+	rm.Put(ClosedOpen(9, 12), "c")
+	rm.Put(Closed(20, 20), "d")
+	if got, want := rm.String(), "{[1, 3): a, [3, 5]: b, (5, 9): a, [9, 12): c, [20, 20]: d}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	cases := []struct {
+		key  int
+		want string
+		ok   bool
+	}{
+		{key: 0}, {key: 1, want: "a", ok: true}, {key: 3, want: "b", ok: true}, {key: 6, want: "a", ok: true},
+		{key: 9, want: "c", ok: true}, {key: 12}, {key: 20, want: "d", ok: true},
+	}
+	for _, tc := range cases {
+		if got, ok := rm.Get(tc.key); got != tc.want || ok != tc.ok {
+			t.Errorf("expected Get(%d) = %q, %t, got %q, %t", tc.key, tc.want, tc.ok, got, ok)
+		}
+	}
+	if r, v, ok := rm.GetEntry(7); !ok || r != Open(5, 9) || v != "a" {
+		t.Errorf("expected (5, 9): a, got %v: %s, %t", r, v, ok)
+	}
+	if got, want := rm.Span(), Closed(1, 20); got != want {
+		t.Errorf("expected span %v, got %v", want, got)
+	}
+
+	rm.Remove(Open(2, 10))
+	if got, want := rm.String(), "{[1, 2]: a, [10, 12): c, [20, 20]: d}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	rm.Put(All[int](), "z")
+	if rm.Size() != 1 {
+		t.Errorf("expected a single range, got %v", rm)
+	}
+	rm.Clear()
+	if !rm.Empty() {
+		t.Errorf("expected an empty map, got %v", rm)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	rs := FromSeq(slices.Values([]Range[int]{AtMost(0), ClosedOpen(2, 4)}), intComp)
+	data, err := json.Marshal(rs)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `[{"upper":{"value":0,"inclusive":true}},{"lower":{"value":2,"inclusive":true},"upper":{"value":4,"inclusive":false}}]`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	var got RangeSet[int]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.String() != rs.String() {
+		t.Errorf("expected %v, got %v", rs, &got)
+	}
+	if err := json.Unmarshal([]byte(`[{"lower":{"value":3,"inclusive":true},"upper":{"value":1,"inclusive":true}}]`), &got); err == nil {
+		t.Errorf("expected an error for an invalid range")
+	}
+	if got.String() != rs.String() {
+		t.Errorf("expected a failed decode to leave %v, got %v", rs, &got)
+	}
+
+	rm := NewOrderedMap[int, string]()
+	rm.Put(Closed(1, 2), "a")
+	rm.Put(GreaterThan(5), "b")
+	data, err = json.Marshal(rm)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var gotMap RangeMap[int, string]
+	if err := json.Unmarshal(data, &gotMap); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if gotMap.String() != rm.String() {
+		t.Errorf("expected %v, got %v", rm, &gotMap)
+	}
+
+	var noOrder RangeSet[struct{ x int }]
+	if err := json.Unmarshal([]byte(`[]`), &noOrder); err == nil {
+		t.Errorf("expected an error decoding a type without a natural order")
+	}
+}
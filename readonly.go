@@ -0,0 +1,874 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/lock14/collections/internal/codec"
+)
+
+// The ReadOnly types below are views that expose only the read methods of a
+// backing collection. Each view holds the backing collection in an unexported
+// field, so a view cannot be type-asserted back to a mutable interface, while
+// every read is forwarded to the backing collection and therefore reflects its
+// current contents. Views are small values and are cheap to construct and copy.
+
+var (
+	_ Collection[int]        = ReadOnlyCollection[int]{}
+	_ List[int]              = ReadOnlyList[int]{}
+	_ Queue[int]             = ReadOnlyQueue[int]{}
+	_ Stack[int]             = ReadOnlyStack[int]{}
+	_ Deque[int]             = ReadOnlyDeque[int]{}
+	_ Set[int]               = ReadOnlySet[int]{}
+	_ SequencedSet[int]      = ReadOnlySequencedSet[int]{}
+	_ SortedSet[int]         = ReadOnlySortedSet[int]{}
+	_ NavigableSet[int]      = ReadOnlyNavigableSet[int]{}
+	_ Map[int, int]          = ReadOnlyMap[int, int]{}
+	_ SequencedMap[int, int] = ReadOnlySequencedMap[int, int]{}
+	_ SortedMap[int, int]    = ReadOnlySortedMap[int, int]{}
+	_ NavigableMap[int, int] = ReadOnlyNavigableMap[int, int]{}
+)
+
+// ReadOnlyCollection is a read-only view of a Collection.
+type ReadOnlyCollection[T any] struct {
+	c Collection[T]
+}
+
+// NewReadOnlyCollection returns a read-only view of the given collection.
+func NewReadOnlyCollection[T any](c Collection[T]) ReadOnlyCollection[T] {
+	return ReadOnlyCollection[T]{c: c}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlyCollection[T]) String() string {
+	return fmt.Sprint(v.c)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlyCollection[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.c)
+}
+
+// All returns an iterator over all the elements of the backing collection.
+func (v ReadOnlyCollection[T]) All() iter.Seq[T] {
+	return v.c.All()
+}
+
+// Size returns the number of elements in the backing collection.
+func (v ReadOnlyCollection[T]) Size() int {
+	return v.c.Size()
+}
+
+// Empty returns true if the backing collection contains no elements.
+func (v ReadOnlyCollection[T]) Empty() bool {
+	return v.c.Empty()
+}
+
+// ReadOnlyList is a read-only view of a List.
+type ReadOnlyList[T any] struct {
+	l List[T]
+}
+
+// NewReadOnlyList returns a read-only view of the given list.
+func NewReadOnlyList[T any](l List[T]) ReadOnlyList[T] {
+	return ReadOnlyList[T]{l: l}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlyList[T]) String() string {
+	return fmt.Sprint(v.l)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlyList[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.l)
+}
+
+// All returns an iterator over all the elements of the backing list.
+func (v ReadOnlyList[T]) All() iter.Seq[T] {
+	return v.l.All()
+}
+
+// Size returns the number of elements in the backing list.
+func (v ReadOnlyList[T]) Size() int {
+	return v.l.Size()
+}
+
+// Empty returns true if the backing list contains no elements.
+func (v ReadOnlyList[T]) Empty() bool {
+	return v.l.Empty()
+}
+
+// Get returns the element at the specified index of the backing list.
+func (v ReadOnlyList[T]) Get(idx int) T {
+	return v.l.Get(idx)
+}
+
+// ReadOnlyQueue is a read-only view of a Queue.
+type ReadOnlyQueue[T any] struct {
+	q Queue[T]
+}
+
+// NewReadOnlyQueue returns a read-only view of the given queue.
+func NewReadOnlyQueue[T any](q Queue[T]) ReadOnlyQueue[T] {
+	return ReadOnlyQueue[T]{q: q}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlyQueue[T]) String() string {
+	return fmt.Sprint(v.q)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlyQueue[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.q)
+}
+
+// All returns an iterator over all the elements of the backing queue.
+func (v ReadOnlyQueue[T]) All() iter.Seq[T] {
+	return v.q.All()
+}
+
+// Size returns the number of elements in the backing queue.
+func (v ReadOnlyQueue[T]) Size() int {
+	return v.q.Size()
+}
+
+// Empty returns true if the backing queue contains no elements.
+func (v ReadOnlyQueue[T]) Empty() bool {
+	return v.q.Empty()
+}
+
+// Peek returns the element at the front of the backing queue without removing it.
+func (v ReadOnlyQueue[T]) Peek() T {
+	return v.q.Peek()
+}
+
+// ReadOnlyStack is a read-only view of a Stack.
+type ReadOnlyStack[T any] struct {
+	s Stack[T]
+}
+
+// NewReadOnlyStack returns a read-only view of the given stack.
+func NewReadOnlyStack[T any](s Stack[T]) ReadOnlyStack[T] {
+	return ReadOnlyStack[T]{s: s}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlyStack[T]) String() string {
+	return fmt.Sprint(v.s)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlyStack[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.s)
+}
+
+// All returns an iterator over all the elements of the backing stack.
+func (v ReadOnlyStack[T]) All() iter.Seq[T] {
+	return v.s.All()
+}
+
+// Size returns the number of elements in the backing stack.
+func (v ReadOnlyStack[T]) Size() int {
+	return v.s.Size()
+}
+
+// Empty returns true if the backing stack contains no elements.
+func (v ReadOnlyStack[T]) Empty() bool {
+	return v.s.Empty()
+}
+
+// Peek returns the element at the top of the backing stack without removing it.
+func (v ReadOnlyStack[T]) Peek() T {
+	return v.s.Peek()
+}
+
+// ReadOnlyDeque is a read-only view of a Deque.
+type ReadOnlyDeque[T any] struct {
+	d Deque[T]
+}
+
+// NewReadOnlyDeque returns a read-only view of the given deque.
+func NewReadOnlyDeque[T any](d Deque[T]) ReadOnlyDeque[T] {
+	return ReadOnlyDeque[T]{d: d}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlyDeque[T]) String() string {
+	return fmt.Sprint(v.d)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlyDeque[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.d)
+}
+
+// All returns an iterator over all the elements of the backing deque.
+func (v ReadOnlyDeque[T]) All() iter.Seq[T] {
+	return v.d.All()
+}
+
+// Size returns the number of elements in the backing deque.
+func (v ReadOnlyDeque[T]) Size() int {
+	return v.d.Size()
+}
+
+// Empty returns true if the backing deque contains no elements.
+func (v ReadOnlyDeque[T]) Empty() bool {
+	return v.d.Empty()
+}
+
+// Peek returns the element at the front of the backing deque without removing it.
+func (v ReadOnlyDeque[T]) Peek() T {
+	return v.d.Peek()
+}
+
+// PeekFront returns the element at the front of the backing deque without removing it.
+func (v ReadOnlyDeque[T]) PeekFront() T {
+	return v.d.PeekFront()
+}
+
+// PeekBack returns the element at the back of the backing deque without removing it.
+func (v ReadOnlyDeque[T]) PeekBack() T {
+	return v.d.PeekBack()
+}
+
+// ReadOnlySet is a read-only view of a Set.
+type ReadOnlySet[T any] struct {
+	s Set[T]
+}
+
+// NewReadOnlySet returns a read-only view of the given set.
+func NewReadOnlySet[T any](s Set[T]) ReadOnlySet[T] {
+	return ReadOnlySet[T]{s: s}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlySet[T]) String() string {
+	return fmt.Sprint(v.s)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlySet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.s)
+}
+
+// All returns an iterator over all the elements of the backing set.
+func (v ReadOnlySet[T]) All() iter.Seq[T] {
+	return v.s.All()
+}
+
+// Size returns the number of elements in the backing set.
+func (v ReadOnlySet[T]) Size() int {
+	return v.s.Size()
+}
+
+// Empty returns true if the backing set contains no elements.
+func (v ReadOnlySet[T]) Empty() bool {
+	return v.s.Empty()
+}
+
+// Contains returns true if the backing set contains the specified element.
+func (v ReadOnlySet[T]) Contains(t T) bool {
+	return v.s.Contains(t)
+}
+
+// ContainsAll returns true if the backing set contains all elements of the specified collection.
+func (v ReadOnlySet[T]) ContainsAll(c Collection[T]) bool {
+	return v.s.ContainsAll(c)
+}
+
+// ReadOnlySequencedSet is a read-only view of a SequencedSet.
+type ReadOnlySequencedSet[T any] struct {
+	s SequencedSet[T]
+}
+
+// NewReadOnlySequencedSet returns a read-only view of the given sequenced set.
+func NewReadOnlySequencedSet[T any](s SequencedSet[T]) ReadOnlySequencedSet[T] {
+	return ReadOnlySequencedSet[T]{s: s}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlySequencedSet[T]) String() string {
+	return fmt.Sprint(v.s)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlySequencedSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.s)
+}
+
+// All returns an iterator over all the elements of the backing set.
+func (v ReadOnlySequencedSet[T]) All() iter.Seq[T] {
+	return v.s.All()
+}
+
+// Size returns the number of elements in the backing set.
+func (v ReadOnlySequencedSet[T]) Size() int {
+	return v.s.Size()
+}
+
+// Empty returns true if the backing set contains no elements.
+func (v ReadOnlySequencedSet[T]) Empty() bool {
+	return v.s.Empty()
+}
+
+// Contains returns true if the backing set contains the specified element.
+func (v ReadOnlySequencedSet[T]) Contains(t T) bool {
+	return v.s.Contains(t)
+}
+
+// ContainsAll returns true if the backing set contains all elements of the specified collection.
+func (v ReadOnlySequencedSet[T]) ContainsAll(c Collection[T]) bool {
+	return v.s.ContainsAll(c)
+}
+
+// First returns the first element in the backing set. Panics if empty.
+func (v ReadOnlySequencedSet[T]) First() T {
+	return v.s.First()
+}
+
+// Last returns the last element in the backing set. Panics if empty.
+func (v ReadOnlySequencedSet[T]) Last() T {
+	return v.s.Last()
+}
+
+// Backward returns an iterator over the elements of the backing set in reverse order.
+func (v ReadOnlySequencedSet[T]) Backward() iter.Seq[T] {
+	return v.s.Backward()
+}
+
+// ReadOnlySortedSet is a read-only view of a SortedSet.
+type ReadOnlySortedSet[T any] struct {
+	s SortedSet[T]
+}
+
+// NewReadOnlySortedSet returns a read-only view of the given sorted set.
+func NewReadOnlySortedSet[T any](s SortedSet[T]) ReadOnlySortedSet[T] {
+	return ReadOnlySortedSet[T]{s: s}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlySortedSet[T]) String() string {
+	return fmt.Sprint(v.s)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlySortedSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.s)
+}
+
+// All returns an iterator over all the elements of the backing set.
+func (v ReadOnlySortedSet[T]) All() iter.Seq[T] {
+	return v.s.All()
+}
+
+// Size returns the number of elements in the backing set.
+func (v ReadOnlySortedSet[T]) Size() int {
+	return v.s.Size()
+}
+
+// Empty returns true if the backing set contains no elements.
+func (v ReadOnlySortedSet[T]) Empty() bool {
+	return v.s.Empty()
+}
+
+// Contains returns true if the backing set contains the specified element.
+func (v ReadOnlySortedSet[T]) Contains(t T) bool {
+	return v.s.Contains(t)
+}
+
+// ContainsAll returns true if the backing set contains all elements of the specified collection.
+func (v ReadOnlySortedSet[T]) ContainsAll(c Collection[T]) bool {
+	return v.s.ContainsAll(c)
+}
+
+// First returns the first element in the backing set. Panics if empty.
+func (v ReadOnlySortedSet[T]) First() T {
+	return v.s.First()
+}
+
+// Last returns the last element in the backing set. Panics if empty.
+func (v ReadOnlySortedSet[T]) Last() T {
+	return v.s.Last()
+}
+
+// Backward returns an iterator over the elements of the backing set in reverse order.
+func (v ReadOnlySortedSet[T]) Backward() iter.Seq[T] {
+	return v.s.Backward()
+}
+
+// From returns an iterator over the elements greater than or equal to the given element.
+func (v ReadOnlySortedSet[T]) From(from T) iter.Seq[T] {
+	return v.s.From(from)
+}
+
+// To returns an iterator over the elements less than the given element.
+func (v ReadOnlySortedSet[T]) To(to T) iter.Seq[T] {
+	return v.s.To(to)
+}
+
+// Between returns an iterator over the elements greater than or equal to 'from' and less than 'to'.
+func (v ReadOnlySortedSet[T]) Between(from, to T) iter.Seq[T] {
+	return v.s.Between(from, to)
+}
+
+// ReadOnlyNavigableSet is a read-only view of a NavigableSet.
+type ReadOnlyNavigableSet[T any] struct {
+	s NavigableSet[T]
+}
+
+// NewReadOnlyNavigableSet returns a read-only view of the given navigable set.
+func NewReadOnlyNavigableSet[T any](s NavigableSet[T]) ReadOnlyNavigableSet[T] {
+	return ReadOnlyNavigableSet[T]{s: s}
+}
+
+// String returns the string representation of the backing collection.
+func (v ReadOnlyNavigableSet[T]) String() string {
+	return fmt.Sprint(v.s)
+}
+
+// MarshalJSON encodes the backing collection.
+func (v ReadOnlyNavigableSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection(v.s)
+}
+
+// All returns an iterator over all the elements of the backing set.
+func (v ReadOnlyNavigableSet[T]) All() iter.Seq[T] {
+	return v.s.All()
+}
+
+// Size returns the number of elements in the backing set.
+func (v ReadOnlyNavigableSet[T]) Size() int {
+	return v.s.Size()
+}
+
+// Empty returns true if the backing set contains no elements.
+func (v ReadOnlyNavigableSet[T]) Empty() bool {
+	return v.s.Empty()
+}
+
+// Contains returns true if the backing set contains the specified element.
+func (v ReadOnlyNavigableSet[T]) Contains(t T) bool {
+	return v.s.Contains(t)
+}
+
+// ContainsAll returns true if the backing set contains all elements of the specified collection.
+func (v ReadOnlyNavigableSet[T]) ContainsAll(c Collection[T]) bool {
+	return v.s.ContainsAll(c)
+}
+
+// First returns the first element in the backing set. Panics if empty.
+func (v ReadOnlyNavigableSet[T]) First() T {
+	return v.s.First()
+}
+
+// Last returns the last element in the backing set. Panics if empty.
+func (v ReadOnlyNavigableSet[T]) Last() T {
+	return v.s.Last()
+}
+
+// Backward returns an iterator over the elements of the backing set in reverse order.
+func (v ReadOnlyNavigableSet[T]) Backward() iter.Seq[T] {
+	return v.s.Backward()
+}
+
+// From returns an iterator over the elements greater than or equal to the given element.
+func (v ReadOnlyNavigableSet[T]) From(from T) iter.Seq[T] {
+	return v.s.From(from)
+}
+
+// To returns an iterator over the elements less than the given element.
+func (v ReadOnlyNavigableSet[T]) To(to T) iter.Seq[T] {
+	return v.s.To(to)
+}
+
+// Between returns an iterator over the elements greater than or equal to 'from' and less than 'to'.
+func (v ReadOnlyNavigableSet[T]) Between(from, to T) iter.Seq[T] {
+	return v.s.Between(from, to)
+}
+
+// Lower returns the greatest element strictly less than the given element.
+func (v ReadOnlyNavigableSet[T]) Lower(t T) (T, bool) {
+	return v.s.Lower(t)
+}
+
+// Floor returns the greatest element less than or equal to the given element.
+func (v ReadOnlyNavigableSet[T]) Floor(t T) (T, bool) {
+	return v.s.Floor(t)
+}
+
+// Ceiling returns the least element greater than or equal to the given element.
+func (v ReadOnlyNavigableSet[T]) Ceiling(t T) (T, bool) {
+	return v.s.Ceiling(t)
+}
+
+// Higher returns the least element strictly greater than the given element.
+func (v ReadOnlyNavigableSet[T]) Higher(t T) (T, bool) {
+	return v.s.Higher(t)
+}
+
+// ReadOnlyMap is a read-only view of a Map.
+type ReadOnlyMap[K any, V any] struct {
+	m Map[K, V]
+}
+
+// NewReadOnlyMap returns a read-only view of the given map.
+func NewReadOnlyMap[K any, V any](m Map[K, V]) ReadOnlyMap[K, V] {
+	return ReadOnlyMap[K, V]{m: m}
+}
+
+// String returns the string representation of the backing map.
+func (v ReadOnlyMap[K, V]) String() string {
+	return fmt.Sprint(v.m)
+}
+
+// MarshalJSON encodes the backing map.
+func (v ReadOnlyMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalMap(v.m)
+}
+
+// Get returns the value associated with the specified key in the backing map, and a boolean indicating if it was found.
+func (v ReadOnlyMap[K, V]) Get(k K) (V, bool) {
+	return v.m.Get(k)
+}
+
+// Size returns the number of key-value pairs in the backing map.
+func (v ReadOnlyMap[K, V]) Size() int {
+	return v.m.Size()
+}
+
+// Empty returns true if the backing map contains no key-value pairs.
+func (v ReadOnlyMap[K, V]) Empty() bool {
+	return v.m.Empty()
+}
+
+// ContainsKey returns true if the backing map contains a mapping for the specified key.
+func (v ReadOnlyMap[K, V]) ContainsKey(k K) bool {
+	return v.m.ContainsKey(k)
+}
+
+// All returns an iterator over all key-value pairs in the backing map.
+func (v ReadOnlyMap[K, V]) All() iter.Seq2[K, V] {
+	return v.m.All()
+}
+
+// Keys returns an iterator over all keys in the backing map.
+func (v ReadOnlyMap[K, V]) Keys() iter.Seq[K] {
+	return v.m.Keys()
+}
+
+// Values returns an iterator over all values in the backing map.
+func (v ReadOnlyMap[K, V]) Values() iter.Seq[V] {
+	return v.m.Values()
+}
+
+// ReadOnlySequencedMap is a read-only view of a SequencedMap.
+type ReadOnlySequencedMap[K any, V any] struct {
+	m SequencedMap[K, V]
+}
+
+// NewReadOnlySequencedMap returns a read-only view of the given sequenced map.
+func NewReadOnlySequencedMap[K any, V any](m SequencedMap[K, V]) ReadOnlySequencedMap[K, V] {
+	return ReadOnlySequencedMap[K, V]{m: m}
+}
+
+// String returns the string representation of the backing map.
+func (v ReadOnlySequencedMap[K, V]) String() string {
+	return fmt.Sprint(v.m)
+}
+
+// MarshalJSON encodes the backing map.
+func (v ReadOnlySequencedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalMap(v.m)
+}
+
+// Get returns the value associated with the specified key in the backing map, and a boolean indicating if it was found.
+func (v ReadOnlySequencedMap[K, V]) Get(k K) (V, bool) {
+	return v.m.Get(k)
+}
+
+// Size returns the number of key-value pairs in the backing map.
+func (v ReadOnlySequencedMap[K, V]) Size() int {
+	return v.m.Size()
+}
+
+// Empty returns true if the backing map contains no key-value pairs.
+func (v ReadOnlySequencedMap[K, V]) Empty() bool {
+	return v.m.Empty()
+}
+
+// ContainsKey returns true if the backing map contains a mapping for the specified key.
+func (v ReadOnlySequencedMap[K, V]) ContainsKey(k K) bool {
+	return v.m.ContainsKey(k)
+}
+
+// All returns an iterator over all key-value pairs in the backing map.
+func (v ReadOnlySequencedMap[K, V]) All() iter.Seq2[K, V] {
+	return v.m.All()
+}
+
+// Keys returns an iterator over all keys in the backing map.
+func (v ReadOnlySequencedMap[K, V]) Keys() iter.Seq[K] {
+	return v.m.Keys()
+}
+
+// Values returns an iterator over all values in the backing map.
+func (v ReadOnlySequencedMap[K, V]) Values() iter.Seq[V] {
+	return v.m.Values()
+}
+
+// First returns the first key-value pair in the backing map. Panics if empty.
+func (v ReadOnlySequencedMap[K, V]) First() (K, V) {
+	return v.m.First()
+}
+
+// Last returns the last key-value pair in the backing map. Panics if empty.
+func (v ReadOnlySequencedMap[K, V]) Last() (K, V) {
+	return v.m.Last()
+}
+
+// Backward returns an iterator over the key-value pairs of the backing map in reverse order.
+func (v ReadOnlySequencedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return v.m.Backward()
+}
+
+// BackwardKeys returns an iterator over the keys of the backing map in reverse order.
+func (v ReadOnlySequencedMap[K, V]) BackwardKeys() iter.Seq[K] {
+	return v.m.BackwardKeys()
+}
+
+// BackwardValues returns an iterator over the values of the backing map in reverse order.
+func (v ReadOnlySequencedMap[K, V]) BackwardValues() iter.Seq[V] {
+	return v.m.BackwardValues()
+}
+
+// ReadOnlySortedMap is a read-only view of a SortedMap.
+type ReadOnlySortedMap[K any, V any] struct {
+	m SortedMap[K, V]
+}
+
+// NewReadOnlySortedMap returns a read-only view of the given sorted map.
+func NewReadOnlySortedMap[K any, V any](m SortedMap[K, V]) ReadOnlySortedMap[K, V] {
+	return ReadOnlySortedMap[K, V]{m: m}
+}
+
+// String returns the string representation of the backing map.
+func (v ReadOnlySortedMap[K, V]) String() string {
+	return fmt.Sprint(v.m)
+}
+
+// MarshalJSON encodes the backing map.
+func (v ReadOnlySortedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalMap(v.m)
+}
+
+// Get returns the value associated with the specified key in the backing map, and a boolean indicating if it was found.
+func (v ReadOnlySortedMap[K, V]) Get(k K) (V, bool) {
+	return v.m.Get(k)
+}
+
+// Size returns the number of key-value pairs in the backing map.
+func (v ReadOnlySortedMap[K, V]) Size() int {
+	return v.m.Size()
+}
+
+// Empty returns true if the backing map contains no key-value pairs.
+func (v ReadOnlySortedMap[K, V]) Empty() bool {
+	return v.m.Empty()
+}
+
+// ContainsKey returns true if the backing map contains a mapping for the specified key.
+func (v ReadOnlySortedMap[K, V]) ContainsKey(k K) bool {
+	return v.m.ContainsKey(k)
+}
+
+// All returns an iterator over all key-value pairs in the backing map.
+func (v ReadOnlySortedMap[K, V]) All() iter.Seq2[K, V] {
+	return v.m.All()
+}
+
+// Keys returns an iterator over all keys in the backing map.
+func (v ReadOnlySortedMap[K, V]) Keys() iter.Seq[K] {
+	return v.m.Keys()
+}
+
+// Values returns an iterator over all values in the backing map.
+func (v ReadOnlySortedMap[K, V]) Values() iter.Seq[V] {
+	return v.m.Values()
+}
+
+// First returns the first key-value pair in the backing map. Panics if empty.
+func (v ReadOnlySortedMap[K, V]) First() (K, V) {
+	return v.m.First()
+}
+
+// Last returns the last key-value pair in the backing map. Panics if empty.
+func (v ReadOnlySortedMap[K, V]) Last() (K, V) {
+	return v.m.Last()
+}
+
+// Backward returns an iterator over the key-value pairs of the backing map in reverse order.
+func (v ReadOnlySortedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return v.m.Backward()
+}
+
+// BackwardKeys returns an iterator over the keys of the backing map in reverse order.
+func (v ReadOnlySortedMap[K, V]) BackwardKeys() iter.Seq[K] {
+	return v.m.BackwardKeys()
+}
+
+// BackwardValues returns an iterator over the values of the backing map in reverse order.
+func (v ReadOnlySortedMap[K, V]) BackwardValues() iter.Seq[V] {
+	return v.m.BackwardValues()
+}
+
+// From returns an iterator over the entries whose keys are greater than or equal to the given key.
+func (v ReadOnlySortedMap[K, V]) From(from K) iter.Seq2[K, V] {
+	return v.m.From(from)
+}
+
+// To returns an iterator over the entries whose keys are less than the given key.
+func (v ReadOnlySortedMap[K, V]) To(to K) iter.Seq2[K, V] {
+	return v.m.To(to)
+}
+
+// Between returns an iterator over the entries whose keys are greater than or equal to 'from' and less than 'to'.
+func (v ReadOnlySortedMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return v.m.Between(from, to)
+}
+
+// ReadOnlyNavigableMap is a read-only view of a NavigableMap.
+type ReadOnlyNavigableMap[K any, V any] struct {
+	m NavigableMap[K, V]
+}
+
+// NewReadOnlyNavigableMap returns a read-only view of the given navigable map.
+func NewReadOnlyNavigableMap[K any, V any](m NavigableMap[K, V]) ReadOnlyNavigableMap[K, V] {
+	return ReadOnlyNavigableMap[K, V]{m: m}
+}
+
+// String returns the string representation of the backing map.
+func (v ReadOnlyNavigableMap[K, V]) String() string {
+	return fmt.Sprint(v.m)
+}
+
+// MarshalJSON encodes the backing map.
+func (v ReadOnlyNavigableMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalMap(v.m)
+}
+
+// Get returns the value associated with the specified key in the backing map, and a boolean indicating if it was found.
+func (v ReadOnlyNavigableMap[K, V]) Get(k K) (V, bool) {
+	return v.m.Get(k)
+}
+
+// Size returns the number of key-value pairs in the backing map.
+func (v ReadOnlyNavigableMap[K, V]) Size() int {
+	return v.m.Size()
+}
+
+// Empty returns true if the backing map contains no key-value pairs.
+func (v ReadOnlyNavigableMap[K, V]) Empty() bool {
+	return v.m.Empty()
+}
+
+// ContainsKey returns true if the backing map contains a mapping for the specified key.
+func (v ReadOnlyNavigableMap[K, V]) ContainsKey(k K) bool {
+	return v.m.ContainsKey(k)
+}
+
+// All returns an iterator over all key-value pairs in the backing map.
+func (v ReadOnlyNavigableMap[K, V]) All() iter.Seq2[K, V] {
+	return v.m.All()
+}
+
+// Keys returns an iterator over all keys in the backing map.
+func (v ReadOnlyNavigableMap[K, V]) Keys() iter.Seq[K] {
+	return v.m.Keys()
+}
+
+// Values returns an iterator over all values in the backing map.
+func (v ReadOnlyNavigableMap[K, V]) Values() iter.Seq[V] {
+	return v.m.Values()
+}
+
+// First returns the first key-value pair in the backing map. Panics if empty.
+func (v ReadOnlyNavigableMap[K, V]) First() (K, V) {
+	return v.m.First()
+}
+
+// Last returns the last key-value pair in the backing map. Panics if empty.
+func (v ReadOnlyNavigableMap[K, V]) Last() (K, V) {
+	return v.m.Last()
+}
+
+// Backward returns an iterator over the key-value pairs of the backing map in reverse order.
+func (v ReadOnlyNavigableMap[K, V]) Backward() iter.Seq2[K, V] {
+	return v.m.Backward()
+}
+
+// BackwardKeys returns an iterator over the keys of the backing map in reverse order.
+func (v ReadOnlyNavigableMap[K, V]) BackwardKeys() iter.Seq[K] {
+	return v.m.BackwardKeys()
+}
+
+// BackwardValues returns an iterator over the values of the backing map in reverse order.
+func (v ReadOnlyNavigableMap[K, V]) BackwardValues() iter.Seq[V] {
+	return v.m.BackwardValues()
+}
+
+// From returns an iterator over the entries whose keys are greater than or equal to the given key.
+func (v ReadOnlyNavigableMap[K, V]) From(from K) iter.Seq2[K, V] {
+	return v.m.From(from)
+}
+
+// To returns an iterator over the entries whose keys are less than the given key.
+func (v ReadOnlyNavigableMap[K, V]) To(to K) iter.Seq2[K, V] {
+	return v.m.To(to)
+}
+
+// Between returns an iterator over the entries whose keys are greater than or equal to 'from' and less than 'to'.
+func (v ReadOnlyNavigableMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return v.m.Between(from, to)
+}
+
+// Lower returns the key-value pair for the greatest key strictly less than the given key.
+func (v ReadOnlyNavigableMap[K, V]) Lower(k K) (K, V, bool) {
+	return v.m.Lower(k)
+}
+
+// Floor returns the key-value pair for the greatest key less than or equal to the given key.
+func (v ReadOnlyNavigableMap[K, V]) Floor(k K) (K, V, bool) {
+	return v.m.Floor(k)
+}
+
+// Ceiling returns the key-value pair for the least key greater than or equal to the given key.
+func (v ReadOnlyNavigableMap[K, V]) Ceiling(k K) (K, V, bool) {
+	return v.m.Ceiling(k)
+}
+
+// Higher returns the key-value pair for the least key strictly greater than the given key.
+func (v ReadOnlyNavigableMap[K, V]) Higher(k K) (K, V, bool) {
+	return v.m.Higher(k)
+}
+
+// marshalCollection encodes c with its own MarshalJSON method if it has one, and
+// as a JSON array of its elements otherwise.
+func marshalCollection[T any](c Collection[T]) ([]byte, error) {
+	if m, ok := c.(json.Marshaler); ok {
+		return m.MarshalJSON()
+	}
+	return codec.MarshalSeq(c.All())
+}
+
+// marshalMap encodes m with its own MarshalJSON method if it has one, and as a
+// JSON object or array of [key, value] pairs otherwise.
+func marshalMap[K any, V any](m Map[K, V]) ([]byte, error) {
+	if marshaler, ok := m.(json.Marshaler); ok {
+		return marshaler.MarshalJSON()
+	}
+	return codec.MarshalMap(m.All())
+}
@@ -0,0 +1,181 @@
+package collections_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/hashset"
+	"github.com/lock14/collections/treemap"
+	"github.com/lock14/collections/treeset"
+)
+
+func TestReadOnlyCannotBeAssertedToMutable(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		view any
+	}{
+		{name: "collection", view: collections.NewReadOnlyCollection[int](arraylist.New[int]())},
+		{name: "list", view: collections.NewReadOnlyList[int](arraylist.New[int]())},
+		{name: "queue", view: collections.NewReadOnlyQueue[int](arraydeque.New[int]())},
+		{name: "stack", view: collections.NewReadOnlyStack[int](arraydeque.New[int]())},
+		{name: "deque", view: collections.NewReadOnlyDeque[int](arraydeque.New[int]())},
+		{name: "set", view: collections.NewReadOnlySet[int](hashset.New[int]())},
+		{name: "sequenced set", view: collections.NewReadOnlySequencedSet[int](treeset.NewOrdered[int]())},
+		{name: "sorted set", view: collections.NewReadOnlySortedSet[int](treeset.NewOrdered[int]())},
+		{name: "navigable set", view: collections.NewReadOnlyNavigableSet[int](treeset.NewOrdered[int]())},
+		{name: "map", view: collections.NewReadOnlyMap[int, int](hashmap.New[int, int]())},
+		{name: "sequenced map", view: collections.NewReadOnlySequencedMap[int, int](treemap.NewOrdered[int, int]())},
+		{name: "sorted map", view: collections.NewReadOnlySortedMap[int, int](treemap.NewOrdered[int, int]())},
+		{name: "navigable map", view: collections.NewReadOnlyNavigableMap[int, int](treemap.NewOrdered[int, int]())},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if _, ok := tc.view.(collections.MutableCollection[int]); ok {
+				t.Errorf("view is a MutableCollection")
+			}
+			if _, ok := tc.view.(collections.MutableMap[int, int]); ok {
+				t.Errorf("view is a MutableMap")
+			}
+		})
+	}
+}
+
+func TestReadOnlyList(t *testing.T) {
+	t.Parallel()
+	l := arraylist.New[int]()
+	view := collections.NewReadOnlyList[int](l)
+	if !view.Empty() {
+		t.Errorf("expected empty view")
+	}
+	l.AddAll(slices.Values([]int{1, 2, 3}))
+	if got, want := view.Size(), 3; got != want {
+		t.Errorf("wrong size: got %d, want %d", got, want)
+	}
+	if got, want := view.Get(1), 2; got != want {
+		t.Errorf("wrong element: got %d, want %d", got, want)
+	}
+	l.Set(1, 20)
+	if got, want := slices.Collect(view.All()), []int{1, 20, 3}; !slices.Equal(got, want) {
+		t.Errorf("wrong elements: got %v, want %v", got, want)
+	}
+}
+
+func TestReadOnlyDeque(t *testing.T) {
+	t.Parallel()
+	d := arraydeque.New[int]()
+	view := collections.NewReadOnlyDeque[int](d)
+	d.AddBack(1)
+	d.AddBack(2)
+	d.AddFront(0)
+	if got, want := view.PeekFront(), 0; got != want {
+		t.Errorf("wrong front: got %d, want %d", got, want)
+	}
+	if got, want := view.PeekBack(), 2; got != want {
+		t.Errorf("wrong back: got %d, want %d", got, want)
+	}
+	d.RemoveFront()
+	if got, want := view.Peek(), 1; got != want {
+		t.Errorf("wrong front after removal: got %d, want %d", got, want)
+	}
+}
+
+func TestReadOnlyNavigableSet(t *testing.T) {
+	t.Parallel()
+	s := treeset.NewOrdered[int]()
+	view := collections.NewReadOnlyNavigableSet[int](s)
+	for _, x := range []int{5, 1, 3} {
+		s.Add(x)
+	}
+	if !view.Contains(3) || view.Contains(4) {
+		t.Errorf("wrong membership")
+	}
+	if got, want := view.First(), 1; got != want {
+		t.Errorf("wrong first: got %d, want %d", got, want)
+	}
+	if got, ok := view.Ceiling(4); !ok || got != 5 {
+		t.Errorf("wrong ceiling: got %d, %t, want 5, true", got, ok)
+	}
+	s.RemoveElement(5)
+	if _, ok := view.Ceiling(4); ok {
+		t.Errorf("view did not reflect removal")
+	}
+	if got, want := slices.Collect(view.Backward()), []int{3, 1}; !slices.Equal(got, want) {
+		t.Errorf("wrong backward elements: got %v, want %v", got, want)
+	}
+}
+
+func TestReadOnlyNavigableMap(t *testing.T) {
+	t.Parallel()
+	m := treemap.NewOrdered[int, string]()
+	view := collections.NewReadOnlyNavigableMap[int, string](m)
+	m.Put(2, "b")
+	m.Put(1, "a")
+	if v, ok := view.Get(2); !ok || v != "b" {
+		t.Errorf("wrong value: got %q, %t, want \"b\", true", v, ok)
+	}
+	m.Put(3, "c")
+	if k, v, ok := view.Higher(2); !ok || k != 3 || v != "c" {
+		t.Errorf("wrong higher: got %d, %q, %t, want 3, \"c\", true", k, v, ok)
+	}
+	if got, want := slices.Collect(view.Keys()), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("wrong keys: got %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(view.BackwardValues()), []string{"c", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("wrong backward values: got %v, want %v", got, want)
+	}
+}
+
+func TestReadOnlyMap(t *testing.T) {
+	t.Parallel()
+	m := hashmap.New[string, int]()
+	view := collections.NewReadOnlyMap[string, int](m)
+	m.Put("a", 1)
+	if !view.ContainsKey("a") || view.Size() != 1 {
+		t.Errorf("view did not reflect put")
+	}
+	m.Remove("a")
+	if !view.Empty() {
+		t.Errorf("view did not reflect removal")
+	}
+}
+
+func TestReadOnlyMarshalJSON(t *testing.T) {
+	t.Parallel()
+	l := arraylist.New[int]()
+	l.AddAll(slices.Values([]int{3, 1, 2}))
+	s := treeset.NewOrdered[int]()
+	s.AddAll(slices.Values([]int{3, 1, 2}))
+	m := treemap.NewOrdered[string, int]()
+	m.Put("b", 2)
+	m.Put("a", 1)
+	tests := []struct {
+		name string
+		view any
+		want string
+	}{
+		{name: "list", view: collections.NewReadOnlyList[int](l), want: "[3,1,2]"},
+		{name: "navigable_set", view: collections.NewReadOnlyNavigableSet[int](s), want: "[1,2,3]"},
+		{name: "navigable_map", view: collections.NewReadOnlyNavigableMap[string, int](m), want: `{"a":1,"b":2}`},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(tc.view)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.want {
+				t.Errorf("got %s, want %s", data, tc.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@
 package result
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 )
 
 // Result represents either success (Ok) with value of type T or failure (Err) with error of type E.
@@ -165,3 +168,57 @@ func (r Result[T, E]) String() string {
 	}
 	return fmt.Sprintf("Err(%v)", r.err)
 }
+
+// MarshalJSON encodes the Result as {"ok": value} or {"err": err}. When E is an
+// interface type such as error, an error is encoded as the string returned by
+// its Error method, since its dynamic type cannot be recovered when decoding.
+func (r Result[T, E]) MarshalJSON() ([]byte, error) {
+	if r.isOk {
+		return json.Marshal(struct {
+			Ok T `json:"ok"`
+		}{r.value})
+	}
+	var err any = r.err
+	if e, ok := err.(error); ok && reflect.TypeFor[E]().Kind() == reflect.Interface {
+		err = e.Error()
+	}
+	return json.Marshal(struct {
+		Err any `json:"err"`
+	}{err})
+}
+
+// UnmarshalJSON decodes an object produced by MarshalJSON. When E is error, the
+// error is decoded as an error with the encoded message.
+func (r *Result[T, E]) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return err
+	}
+	okData, isOk := fields["ok"]
+	errData, isErr := fields["err"]
+	if isOk == isErr || len(fields) != 1 {
+		return errors.New(`result: expected an object with exactly one of "ok" and "err"`)
+	}
+	if isOk {
+		var value T
+		if err := json.Unmarshal(okData, &value); err != nil {
+			return err
+		}
+		*r = Ok[T, E](value)
+		return nil
+	}
+	var e E
+	if p, ok := any(&e).(*error); ok {
+		var msg *string
+		if err := json.Unmarshal(errData, &msg); err != nil {
+			return err
+		}
+		if msg != nil {
+			*p = errors.New(*msg)
+		}
+	} else if err := json.Unmarshal(errData, &e); err != nil {
+		return err
+	}
+	*r = Err[T](e)
+	return nil
+}
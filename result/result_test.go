@@ -1,6 +1,7 @@
 package result_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -577,3 +578,50 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+		data, err := json.Marshal(result.Ok[int, string](7))
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `{"ok":7}`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		var got result.Result[int, string]
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if v, ok := got.Ok(); !ok || v != 7 {
+			t.Errorf("expected Ok(7), got %v", got)
+		}
+	})
+	t.Run("err", func(t *testing.T) {
+		t.Parallel()
+		data, err := json.Marshal(result.Of(0, errors.New("boom")))
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `{"err":"boom"}`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		var got result.Result[int, error]
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if e, ok := got.Err(); !ok || e.Error() != "boom" {
+			t.Errorf("expected Err(boom), got %v", got)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, data := range []string{`{}`, `{"ok":1,"err":"x"}`, `{"value":1}`, `[1]`} {
+			var got result.Result[int, string]
+			if err := json.Unmarshal([]byte(data), &got); err == nil {
+				t.Errorf("expected an error decoding %s", data)
+			}
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package seq_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/seq"
+	"github.com/lock14/collections/treeset"
+)
+
+func Example() {
+	// Combinators are lazy and compose directly with the All method of any collection.
+	words := arraylist.Wrap([]string{"pear", "fig", "apple", "kiwi", "banana", "fig"})
+
+	long := seq.Filter(words.All(), func(w string) bool { return len(w) > 3 })
+	upper := seq.Map(long, strings.ToUpper)
+
+	// Terminal operations collect into any MutableCollection.
+	set := seq.Collect(upper, treeset.NewOrdered[string]())
+	fmt.Println(set)
+
+	// Output:
+	// [APPLE, BANANA, KIWI, PEAR]
+}
+
+func ExampleCollectMap() {
+	words := []string{"go", "rust", "zig"}
+
+	lengths := seq.CollectMap(seq.With(slices.Values(words), func(w string) int { return len(w) }), hashmap.New[string, int]())
+
+	v, _ := lengths.Get("rust")
+	fmt.Println("rust:", v)
+
+	// Output:
+	// rust: 4
+}
+
+func ExampleZip() {
+	names := slices.Values([]string{"Alice", "Bob", "Charlie"})
+	ages := slices.Values([]int{30, 25})
+
+	// Zip stops as soon as either sequence is exhausted.
+	for name, age := range seq.Zip(names, ages) {
+		fmt.Printf("%s: %d\n", name, age)
+	}
+
+	// Output:
+	// Alice: 30
+	// Bob: 25
+}
+
+func ExampleChunk() {
+	for chunk := range seq.Chunk(slices.Values([]int{1, 2, 3, 4, 5}), 2) {
+		fmt.Println(chunk)
+	}
+
+	// Output:
+	// [1 2]
+	// [3 4]
+	// [5]
+}
+
+func ExampleWindow() {
+	// Compute a moving sum over a window of three elements.
+	for w := range seq.Window(slices.Values([]int{1, 2, 3, 4, 5}), 3) {
+		sum, _ := seq.Reduce(slices.Values(w), func(a, b int) int { return a + b })
+		fmt.Println(w, sum)
+	}
+
+	// Output:
+	// [1 2 3] 6
+	// [2 3 4] 9
+	// [3 4 5] 12
+}
+
+func ExampleTakeWhile() {
+	s := seq.TakeWhile(slices.Values([]int{2, 4, 6, 7, 8}), func(i int) bool { return i%2 == 0 })
+	fmt.Println(slices.Collect(s))
+
+	// Output:
+	// [2 4 6]
+}
+
+func ExampleFold() {
+	csv := seq.Fold(slices.Values([]int{1, 2, 3}), "", func(acc string, i int) string {
+		if acc != "" {
+			acc += ","
+		}
+		return acc + fmt.Sprint(i)
+	})
+	fmt.Println(csv)
+
+	// Output:
+	// 1,2,3
+}
@@ -0,0 +1,412 @@
+// Package seq provides lazy combinators over iter.Seq and iter.Seq2.
+//
+// Every function that returns a sequence is lazy: no element of the source is
+// consumed until the returned sequence is ranged over, and ranging stops pulling
+// from the source as soon as the consumer stops. Terminal operations such as
+// Reduce, Collect and CollectMap consume the source immediately.
+package seq
+
+import (
+	"iter"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+)
+
+// Map returns a sequence of the results of applying mapper to each element of seq.
+func Map[T, U any](seq iter.Seq[T], mapper func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for t := range seq {
+			if !yield(mapper(t)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns a sequence of the elements of seq that satisfy predicate.
+func Filter[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t := range seq {
+			if predicate(t) && !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// FlatMap returns a sequence of the elements of each sequence produced by applying mapper to the elements of seq.
+func FlatMap[T, U any](seq iter.Seq[T], mapper func(T) iter.Seq[U]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for t := range seq {
+			for u := range mapper(t) {
+				if !yield(u) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Concat returns a sequence of the elements of each of the given sequences in order.
+func Concat[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, seq := range seqs {
+			for t := range seq {
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Take returns a sequence of at most the first n elements of seq.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		count := 0
+		for t := range seq {
+			if !yield(t) {
+				return
+			}
+			count++
+			if count == n {
+				return
+			}
+		}
+	}
+}
+
+// Drop returns a sequence of the elements of seq after the first n.
+func Drop[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		count := 0
+		for t := range seq {
+			if count < n {
+				count++
+				continue
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns a sequence of the leading elements of seq that satisfy predicate.
+func TakeWhile[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for t := range seq {
+			if !predicate(t) || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// DropWhile returns a sequence of the elements of seq starting with the first that does not satisfy predicate.
+func DropWhile[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		dropping := true
+		for t := range seq {
+			if dropping && predicate(t) {
+				continue
+			}
+			dropping = false
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Distinct returns a sequence of the elements of seq with later duplicates removed.
+func Distinct[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for t := range seq {
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns a sequence of the elements of seq paired with their zero-based index.
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for t := range seq {
+			if !yield(i, t) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Zip returns a sequence of pairs of corresponding elements of a and b.
+// The returned sequence ends as soon as either a or b is exhausted.
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+		for x := range a {
+			y, ok := next()
+			if !ok || !yield(x, y) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns a sequence of consecutive, non-overlapping slices of up to size elements of seq.
+// Only the last chunk may hold fewer than size elements. Each chunk is a newly allocated slice
+// that the caller may retain. Chunk panics if size is less than 1.
+func Chunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("cannot chunk with a size less than 1")
+	}
+	return func(yield func([]T) bool) {
+		var chunk []T
+		for t := range seq {
+			if chunk == nil {
+				chunk = make([]T, 0, size)
+			}
+			chunk = append(chunk, t)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window returns a sequence of the overlapping slices of size consecutive elements of seq,
+// advancing by one element at a time. If seq holds fewer than size elements, no windows are produced.
+// The yielded slice is reused between iterations; copy it to retain its contents.
+// Window panics if size is less than 1.
+func Window[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("cannot window with a size less than 1")
+	}
+	return func(yield func([]T) bool) {
+		// buf holds the window twice over so that each window is a contiguous
+		// slice of buf without shifting elements on every step.
+		buf := make([]T, 0, 2*size)
+		for t := range seq {
+			if len(buf) == cap(buf) {
+				n := copy(buf, buf[len(buf)-size+1:])
+				var zero T
+				for i := n; i < len(buf); i++ {
+					buf[i] = zero
+				}
+				buf = buf[:n]
+			}
+			buf = append(buf, t)
+			if len(buf) >= size {
+				if !yield(buf[len(buf)-size : len(buf) : len(buf)]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Reduce combines the elements of seq from left to right using reducer.
+// If seq is empty, Reduce returns the zero value and false.
+func Reduce[T any](seq iter.Seq[T], reducer func(T, T) T) (T, bool) {
+	var acc T
+	ok := false
+	for t := range seq {
+		if ok {
+			acc = reducer(acc, t)
+		} else {
+			acc = t
+			ok = true
+		}
+	}
+	return acc, ok
+}
+
+// Fold combines the elements of seq from left to right with folder, starting from initial.
+func Fold[T, A any](seq iter.Seq[T], initial A, folder func(A, T) A) A {
+	acc := initial
+	for t := range seq {
+		acc = folder(acc, t)
+	}
+	return acc
+}
+
+// Count returns the number of elements in seq.
+func Count[T any](seq iter.Seq[T]) int {
+	n := 0
+	for range seq {
+		n++
+	}
+	return n
+}
+
+// Any returns true if at least one element of seq satisfies predicate.
+func Any[T any](seq iter.Seq[T], predicate func(T) bool) bool {
+	for t := range seq {
+		if predicate(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Every returns true if every element of seq satisfies predicate.
+// Every returns true for an empty sequence.
+func Every[T any](seq iter.Seq[T], predicate func(T) bool) bool {
+	for t := range seq {
+		if !predicate(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// First returns the first element of seq and true, or the zero value and false if seq is empty.
+func First[T any](seq iter.Seq[T]) (T, bool) {
+	for t := range seq {
+		return t, true
+	}
+	var zero T
+	return zero, false
+}
+
+// Find returns the first element of seq that satisfies predicate and true,
+// or the zero value and false if there is no such element.
+func Find[T any](seq iter.Seq[T], predicate func(T) bool) (T, bool) {
+	for t := range seq {
+		if predicate(t) {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Min returns the least element of seq according to cmp and true,
+// or the zero value and false if seq is empty. Ties are resolved in favor of the earliest element.
+func Min[T any](seq iter.Seq[T], cmp comparator.Comparator[T]) (T, bool) {
+	return Reduce(seq, func(a, b T) T {
+		if cmp(b, a) < 0 {
+			return b
+		}
+		return a
+	})
+}
+
+// Max returns the greatest element of seq according to cmp and true,
+// or the zero value and false if seq is empty. Ties are resolved in favor of the earliest element.
+func Max[T any](seq iter.Seq[T], cmp comparator.Comparator[T]) (T, bool) {
+	return Reduce(seq, func(a, b T) T {
+		if cmp(b, a) > 0 {
+			return b
+		}
+		return a
+	})
+}
+
+// ForEach calls action for each element of seq.
+func ForEach[T any](seq iter.Seq[T], action func(T)) {
+	for t := range seq {
+		action(t)
+	}
+}
+
+// Collect adds every element of seq to c and returns c.
+func Collect[T any, C collections.MutableCollection[T]](seq iter.Seq[T], c C) C {
+	c.AddAll(seq)
+	return c
+}
+
+// Map2 returns a sequence of the results of applying mapper to each key-value pair of seq.
+func Map2[K, V, K2, V2 any](seq iter.Seq2[K, V], mapper func(K, V) (K2, V2)) iter.Seq2[K2, V2] {
+	return func(yield func(K2, V2) bool) {
+		for k, v := range seq {
+			if !yield(mapper(k, v)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter2 returns a sequence of the key-value pairs of seq that satisfy predicate.
+func Filter2[K, V any](seq iter.Seq2[K, V], predicate func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if predicate(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns a sequence of the keys of seq.
+func Keys[K, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns a sequence of the values of seq.
+func Values[K, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Swap returns a sequence of the key-value pairs of seq with each key and value exchanged.
+func Swap[K, V any](seq iter.Seq2[K, V]) iter.Seq2[V, K] {
+	return func(yield func(V, K) bool) {
+		for k, v := range seq {
+			if !yield(v, k) {
+				return
+			}
+		}
+	}
+}
+
+// With returns a sequence of the elements of seq paired with the result of applying mapper to each.
+func With[T, U any](seq iter.Seq[T], mapper func(T) U) iter.Seq2[T, U] {
+	return func(yield func(T, U) bool) {
+		for t := range seq {
+			if !yield(t, mapper(t)) {
+				return
+			}
+		}
+	}
+}
+
+// CollectMap puts every key-value pair of seq into m and returns m.
+// Later pairs overwrite earlier pairs with the same key.
+func CollectMap[K, V any, M collections.MutableMap[K, V]](seq iter.Seq2[K, V], m M) M {
+	for k, v := range seq {
+		m.Put(k, v)
+	}
+	return m
+}
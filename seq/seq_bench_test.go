@@ -0,0 +1,57 @@
+package seq_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/seq"
+)
+
+func BenchmarkSeq_MapFilter(b *testing.B) {
+	b.ReportAllocs()
+	l := arraylist.Wrap(make([]int, 1000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range seq.Filter(seq.Map(l.All(), func(x int) int { return x + 1 }), func(x int) bool { return x > 0 }) {
+		}
+	}
+}
+
+func BenchmarkSeq_Reduce(b *testing.B) {
+	b.ReportAllocs()
+	s := make([]int, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		seq.Reduce(slices.Values(s), func(x, y int) int { return x + y })
+	}
+}
+
+func BenchmarkSeq_Zip(b *testing.B) {
+	b.ReportAllocs()
+	s := make([]int, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range seq.Zip(slices.Values(s), slices.Values(s)) {
+		}
+	}
+}
+
+func BenchmarkSeq_Window(b *testing.B) {
+	b.ReportAllocs()
+	s := make([]int, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range seq.Window(slices.Values(s), 8) {
+		}
+	}
+}
+
+func BenchmarkSeq_Collect(b *testing.B) {
+	b.ReportAllocs()
+	s := make([]int, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		seq.Collect(slices.Values(s), arraylist.New[int](arraylist.WithCapacity(len(s))))
+	}
+}
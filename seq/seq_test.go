@@ -0,0 +1,405 @@
+package seq
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/treeset"
+)
+
+func values(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 1; i <= n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func isEven(i int) bool {
+	return i%2 == 0
+}
+
+func TestSeq_Transformations(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		seq      iter.Seq[int]
+		expected []int
+	}{
+		{
+			name:     "map",
+			seq:      Map(values(3), func(i int) int { return i * 10 }),
+			expected: []int{10, 20, 30},
+		},
+		{
+			name:     "filter",
+			seq:      Filter(values(6), isEven),
+			expected: []int{2, 4, 6},
+		},
+		{
+			name:     "flat_map",
+			seq:      FlatMap(values(3), func(i int) iter.Seq[int] { return Map(values(i), func(int) int { return i }) }),
+			expected: []int{1, 2, 2, 3, 3, 3},
+		},
+		{
+			name:     "concat",
+			seq:      Concat(values(2), values(0), values(3)),
+			expected: []int{1, 2, 1, 2, 3},
+		},
+		{
+			name:     "take",
+			seq:      Take(values(10), 3),
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "take_more_than_available",
+			seq:      Take(values(2), 5),
+			expected: []int{1, 2},
+		},
+		{
+			name:     "take_zero",
+			seq:      Take(values(2), 0),
+			expected: nil,
+		},
+		{
+			name:     "drop",
+			seq:      Drop(values(5), 3),
+			expected: []int{4, 5},
+		},
+		{
+			name:     "take_while",
+			seq:      TakeWhile(values(10), func(i int) bool { return i < 4 }),
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "drop_while",
+			seq:      DropWhile(slices.Values([]int{1, 2, 5, 1, 2}), func(i int) bool { return i < 4 }),
+			expected: []int{5, 1, 2},
+		},
+		{
+			name:     "distinct",
+			seq:      Distinct(slices.Values([]int{3, 1, 3, 2, 1})),
+			expected: []int{3, 1, 2},
+		},
+		{
+			name:     "keys",
+			seq:      Keys(Enumerate(Map(values(3), func(i int) int { return -i }))),
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:     "values",
+			seq:      Values(Enumerate(Map(values(3), func(i int) int { return -i }))),
+			expected: []int{-1, -2, -3},
+		},
+		{
+			name:     "infinite_source",
+			seq:      Take(Filter(naturals(), isEven), 3),
+			expected: []int{0, 2, 4},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			actual := slices.Collect(tc.seq)
+			if !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestSeq_EarlyTermination(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		seq  func(iter.Seq[int]) iter.Seq[int]
+	}{
+		{name: "map", seq: func(s iter.Seq[int]) iter.Seq[int] { return Map(s, func(i int) int { return i }) }},
+		{name: "filter", seq: func(s iter.Seq[int]) iter.Seq[int] { return Filter(s, func(int) bool { return true }) }},
+		{name: "flat_map", seq: func(s iter.Seq[int]) iter.Seq[int] { return FlatMap(s, func(i int) iter.Seq[int] { return values(2) }) }},
+		{name: "concat", seq: func(s iter.Seq[int]) iter.Seq[int] { return Concat(s, s) }},
+		{name: "drop", seq: func(s iter.Seq[int]) iter.Seq[int] { return Drop(s, 1) }},
+		{name: "take_while", seq: func(s iter.Seq[int]) iter.Seq[int] { return TakeWhile(s, func(int) bool { return true }) }},
+		{name: "drop_while", seq: func(s iter.Seq[int]) iter.Seq[int] { return DropWhile(s, func(int) bool { return false }) }},
+		{name: "distinct", seq: func(s iter.Seq[int]) iter.Seq[int] { return Distinct(s) }},
+		{name: "keys", seq: func(s iter.Seq[int]) iter.Seq[int] { return Keys(Enumerate(s)) }},
+		{name: "values", seq: func(s iter.Seq[int]) iter.Seq[int] { return Values(Zip(s, s)) }},
+		{name: "swap", seq: func(s iter.Seq[int]) iter.Seq[int] { return Values(Swap(With(s, isEven))) }},
+		{name: "map2", seq: func(s iter.Seq[int]) iter.Seq[int] {
+			return Keys(Map2(Enumerate(s), func(i, v int) (int, int) { return v, i }))
+		}},
+		{name: "filter2", seq: func(s iter.Seq[int]) iter.Seq[int] {
+			return Keys(Filter2(Enumerate(s), func(int, int) bool { return true }))
+		}},
+		{name: "chunk", seq: func(s iter.Seq[int]) iter.Seq[int] { return Map(Chunk(s, 1), func(c []int) int { return c[0] }) }},
+		{name: "window", seq: func(s iter.Seq[int]) iter.Seq[int] { return Map(Window(s, 1), func(w []int) int { return w[0] }) }},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			count := 0
+			for range tc.seq(naturals()) {
+				count++
+				if count == 3 {
+					break
+				}
+			}
+			if count != 3 {
+				t.Errorf("expected 3 elements before break, got %d", count)
+			}
+		})
+	}
+}
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name      string
+		a, b      iter.Seq[int]
+		expectedA []int
+		expectedB []int
+	}{
+		{
+			name:      "equal_length",
+			a:         values(3),
+			b:         Map(values(3), func(i int) int { return i * 2 }),
+			expectedA: []int{1, 2, 3},
+			expectedB: []int{2, 4, 6},
+		},
+		{
+			name:      "shorter_first",
+			a:         values(2),
+			b:         naturals(),
+			expectedA: []int{1, 2},
+			expectedB: []int{0, 1},
+		},
+		{
+			name:      "shorter_second",
+			a:         naturals(),
+			b:         values(1),
+			expectedA: []int{0},
+			expectedB: []int{1},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var as, bs []int
+			for a, b := range Zip(tc.a, tc.b) {
+				as = append(as, a)
+				bs = append(bs, b)
+			}
+			if !slices.Equal(as, tc.expectedA) || !slices.Equal(bs, tc.expectedB) {
+				t.Errorf("expected %v/%v, got %v/%v", tc.expectedA, tc.expectedB, as, bs)
+			}
+		})
+	}
+}
+
+func TestChunkAndWindow(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		seq      iter.Seq[[]int]
+		expected [][]int
+	}{
+		{
+			name:     "chunk_even",
+			seq:      Chunk(values(4), 2),
+			expected: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name:     "chunk_remainder",
+			seq:      Chunk(values(5), 2),
+			expected: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name:     "chunk_empty",
+			seq:      Chunk(values(0), 2),
+			expected: nil,
+		},
+		{
+			name:     "window",
+			seq:      Window(values(5), 3),
+			expected: [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}},
+		},
+		{
+			name:     "window_long_input",
+			seq:      Window(values(9), 2),
+			expected: [][]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}, {6, 7}, {7, 8}, {8, 9}},
+		},
+		{
+			name:     "window_too_short",
+			seq:      Window(values(2), 3),
+			expected: nil,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var actual [][]int
+			for s := range tc.seq {
+				actual = append(actual, slices.Clone(s))
+			}
+			if !slices.EqualFunc(actual, tc.expected, slices.Equal) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSeq_Terminals(t *testing.T) {
+	t.Parallel()
+	natural := comparator.NaturalOrder[int]()
+	cases := []struct {
+		name  string
+		check func(*testing.T)
+	}{
+		{
+			name: "reduce",
+			check: func(t *testing.T) {
+				sum, ok := Reduce(values(4), func(a, b int) int { return a + b })
+				if !ok || sum != 10 {
+					t.Errorf("expected 10, got %d", sum)
+				}
+				if _, ok := Reduce(values(0), func(a, b int) int { return a + b }); ok {
+					t.Errorf("expected reduce of empty sequence to report false")
+				}
+			},
+		},
+		{
+			name: "fold",
+			check: func(t *testing.T) {
+				s := Fold(values(3), "", func(acc string, i int) string { return acc + string(rune('a'+i-1)) })
+				if s != "abc" {
+					t.Errorf("expected abc, got %s", s)
+				}
+			},
+		},
+		{
+			name: "count_any_every",
+			check: func(t *testing.T) {
+				if Count(values(7)) != 7 {
+					t.Errorf("expected count 7")
+				}
+				if !Any(values(3), isEven) || Any(values(1), isEven) {
+					t.Errorf("unexpected Any result")
+				}
+				if Every(values(3), isEven) || !Every(Filter(values(6), isEven), isEven) || !Every(values(0), isEven) {
+					t.Errorf("unexpected Every result")
+				}
+			},
+		},
+		{
+			name: "first_find",
+			check: func(t *testing.T) {
+				if v, ok := First(naturals()); !ok || v != 0 {
+					t.Errorf("expected 0, got %d", v)
+				}
+				if _, ok := First(values(0)); ok {
+					t.Errorf("expected no first element")
+				}
+				if v, ok := Find(naturals(), func(i int) bool { return i > 4 }); !ok || v != 5 {
+					t.Errorf("expected 5, got %d", v)
+				}
+				if _, ok := Find(values(3), func(i int) bool { return i > 4 }); ok {
+					t.Errorf("expected no match")
+				}
+			},
+		},
+		{
+			name: "min_max",
+			check: func(t *testing.T) {
+				s := slices.Values([]int{3, 9, -2, 7})
+				if v, ok := Min(s, natural); !ok || v != -2 {
+					t.Errorf("expected -2, got %d", v)
+				}
+				if v, ok := Max(s, natural); !ok || v != 9 {
+					t.Errorf("expected 9, got %d", v)
+				}
+				if _, ok := Max(values(0), natural); ok {
+					t.Errorf("expected no max for empty sequence")
+				}
+			},
+		},
+		{
+			name: "for_each",
+			check: func(t *testing.T) {
+				sum := 0
+				ForEach(values(4), func(i int) { sum += i })
+				if sum != 10 {
+					t.Errorf("expected 10, got %d", sum)
+				}
+			},
+		},
+		{
+			name: "collect",
+			check: func(t *testing.T) {
+				l := Collect(Map(values(3), func(i int) int { return i * i }), arraylist.New[int]())
+				if !slices.Equal(slices.Collect(l.All()), []int{1, 4, 9}) {
+					t.Errorf("unexpected list %v", l)
+				}
+				s := Collect(slices.Values([]int{3, 1, 3, 2}), treeset.NewOrdered[int]())
+				if !slices.Equal(slices.Collect(s.All()), []int{1, 2, 3}) {
+					t.Errorf("unexpected set %v", s)
+				}
+			},
+		},
+		{
+			name: "collect_map",
+			check: func(t *testing.T) {
+				m := CollectMap(With(values(3), func(i int) int { return i * 10 }), hashmap.New[int, int]())
+				expected := map[int]int{1: 10, 2: 20, 3: 30}
+				if !maps.Equal(maps.Collect(m.All()), expected) {
+					t.Errorf("expected %v, got %v", expected, maps.Collect(m.All()))
+				}
+			},
+		},
+		{
+			name: "invalid_sizes_panic",
+			check: func(t *testing.T) {
+				for _, f := range []func(){
+					func() { Chunk(values(1), 0) },
+					func() { Window(values(1), 0) },
+				} {
+					func() {
+						defer func() {
+							if r := recover(); r == nil {
+								t.Errorf("expected panic")
+							}
+						}()
+						f()
+					}()
+				}
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.check(t)
+		})
+	}
+}
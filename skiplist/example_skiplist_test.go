@@ -0,0 +1,34 @@
+package skiplist_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lock14/collections/skiplist"
+)
+
+func ExampleSkipListMap() {
+	// An order book keyed by price, updated by several goroutines at once
+	// without a lock around the map.
+	bids := skiplist.NewOrderedMap[int, int]()
+	var wg sync.WaitGroup
+	for trader := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bids.Put(100+trader, 10*(trader+1))
+		}()
+	}
+	wg.Wait()
+
+	price, qty := bids.Last()
+	fmt.Println("Best bid:", price, qty)
+	for price, qty := range bids.Between(101, 103) {
+		fmt.Println(price, qty)
+	}
+
+	// Output:
+	// Best bid: 103 40
+	// 101 20
+	// 102 30
+}
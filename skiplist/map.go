@@ -0,0 +1,286 @@
+package skiplist
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+)
+
+var _ collections.MutableNavigableMap[int, int] = (*SkipListMap[int, int])(nil)
+
+// SkipListMap is a sorted map backed by a lock-free skip list, which is safe for
+// concurrent use by multiple goroutines.
+type SkipListMap[K any, V any] struct {
+	l *list[K, V]
+}
+
+// NewMap creates an empty SkipListMap with the given options.
+func NewMap[K any, V any](opts ...Option[K]) *SkipListMap[K, V] {
+	return &SkipListMap[K, V]{l: newList[K, V](opts)}
+}
+
+// NewOrderedMap creates an empty SkipListMap for keys that satisfy cmp.Ordered
+// using natural ordering.
+func NewOrderedMap[K cmp.Ordered, V any](opts ...Option[K]) *SkipListMap[K, V] {
+	return NewMap[K, V](append(opts, ordered[K]())...)
+}
+
+// Get returns the value associated with the specified key, and a boolean
+// indicating if it was found.
+func (m *SkipListMap[K, V]) Get(key K) (V, bool) {
+	return value(m.l.get(key))
+}
+
+// Put associates the specified value with the specified key.
+func (m *SkipListMap[K, V]) Put(key K, value V) {
+	m.l.put(key, &value, true)
+}
+
+// PutIfAbsent associates the specified value with the specified key if the key
+// is absent, as a single atomic operation. It returns the value associated with
+// the key afterwards, and true if that is the given value.
+func (m *SkipListMap[K, V]) PutIfAbsent(key K, value V) (V, bool) {
+	if old := m.l.put(key, &value, false); old != nil {
+		return *old, false
+	}
+	return value, true
+}
+
+// Remove removes the mapping for the specified key from the map if present.
+func (m *SkipListMap[K, V]) Remove(key K) {
+	m.l.removeKey(key)
+}
+
+// Size returns the number of key-value pairs in the map.
+func (m *SkipListMap[K, V]) Size() int {
+	return max(int(m.l.size.Load()), 0)
+}
+
+// Empty returns true if the map contains no key-value pairs.
+func (m *SkipListMap[K, V]) Empty() bool {
+	n, _ := m.l.first()
+	return n == nil
+}
+
+// Clear removes all key-value pairs from the map. Pairs put concurrently with
+// Clear may remain.
+func (m *SkipListMap[K, V]) Clear() {
+	for {
+		if n, _ := m.l.poll(m.l.first); n == nil {
+			return
+		}
+	}
+}
+
+// ContainsKey returns true if the map contains a mapping for the specified key.
+func (m *SkipListMap[K, V]) ContainsKey(key K) bool {
+	n, _ := m.l.get(key)
+	return n != nil
+}
+
+// All returns a weakly consistent iterator over all key-value pairs in the map
+// in ascending order of keys.
+func (m *SkipListMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.l.all(m.l.head.next[0].Load().node, always[K], yield)
+	}
+}
+
+// Keys returns a weakly consistent iterator over all keys in the map in
+// ascending order.
+func (m *SkipListMap[K, V]) Keys() iter.Seq[K] {
+	return keys(m.All())
+}
+
+// Values returns a weakly consistent iterator over all values in the map in
+// ascending order of keys.
+func (m *SkipListMap[K, V]) Values() iter.Seq[V] {
+	return values(m.All())
+}
+
+// First returns the key-value pair with the least key. Panics if empty.
+func (m *SkipListMap[K, V]) First() (K, V) {
+	return mustEntry(m.l.first, "First")
+}
+
+// Last returns the key-value pair with the greatest key. Panics if empty.
+func (m *SkipListMap[K, V]) Last() (K, V) {
+	return mustEntry(m.l.last, "Last")
+}
+
+// PollFirst removes and returns the key-value pair with the least key. Panics
+// if empty.
+func (m *SkipListMap[K, V]) PollFirst() (K, V) {
+	return mustEntry(func() (*node[K, V], *V) { return m.l.poll(m.l.first) }, "PollFirst")
+}
+
+// PollLast removes and returns the key-value pair with the greatest key.
+// Panics if empty.
+func (m *SkipListMap[K, V]) PollLast() (K, V) {
+	return mustEntry(func() (*node[K, V], *V) { return m.l.poll(m.l.last) }, "PollLast")
+}
+
+func (m *SkipListMap[K, V]) PutFirst(key K, value V) {
+	panic("PutFirst is not supported on SortedMap")
+}
+
+func (m *SkipListMap[K, V]) PutLast(key K, value V) {
+	panic("PutLast is not supported on SortedMap")
+}
+
+// Lower returns the key-value pair for the greatest key strictly less than the
+// given key.
+func (m *SkipListMap[K, V]) Lower(key K) (K, V, bool) {
+	return entry(m.l.lower(key))
+}
+
+// Floor returns the key-value pair for the greatest key less than or equal to
+// the given key.
+func (m *SkipListMap[K, V]) Floor(key K) (K, V, bool) {
+	return entry(m.l.floor(key))
+}
+
+// Ceiling returns the key-value pair for the least key greater than or equal to
+// the given key.
+func (m *SkipListMap[K, V]) Ceiling(key K) (K, V, bool) {
+	_, n := m.l.search(key)
+	return entry(m.l.ceiling(n))
+}
+
+// Higher returns the key-value pair for the least key strictly greater than the
+// given key.
+func (m *SkipListMap[K, V]) Higher(key K) (K, V, bool) {
+	return entry(m.l.higher(key))
+}
+
+// Backward returns a weakly consistent iterator over all key-value pairs in the
+// map in descending order of keys. Each step is a O(log n) search.
+func (m *SkipListMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		n, v := m.l.last()
+		m.l.backward(n, v, always[K], yield)
+	}
+}
+
+// BackwardKeys returns a weakly consistent iterator over all keys in the map in
+// descending order.
+func (m *SkipListMap[K, V]) BackwardKeys() iter.Seq[K] {
+	return keys(m.Backward())
+}
+
+// BackwardValues returns a weakly consistent iterator over all values in the
+// map in descending order of keys.
+func (m *SkipListMap[K, V]) BackwardValues() iter.Seq[V] {
+	return values(m.Backward())
+}
+
+// From returns a weakly consistent iterator over the entries whose keys are
+// greater than or equal to the given key.
+func (m *SkipListMap[K, V]) From(from K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		_, n := m.l.search(from)
+		m.l.all(n, always[K], yield)
+	}
+}
+
+// To returns a weakly consistent iterator over the entries whose keys are less
+// than the given key.
+func (m *SkipListMap[K, V]) To(to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.l.all(m.l.head.next[0].Load().node, m.before(to), yield)
+	}
+}
+
+// Between returns a weakly consistent iterator over the entries whose keys are
+// greater than or equal to 'from' and less than 'to'.
+func (m *SkipListMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		_, n := m.l.search(from)
+		m.l.all(n, m.before(to), yield)
+	}
+}
+
+func (m *SkipListMap[K, V]) before(to K) func(K) bool {
+	return func(k K) bool {
+		return m.l.comparator(k, to) < 0
+	}
+}
+
+// String returns a string representation of the map.
+func (m *SkipListMap[K, V]) String() string {
+	entries := make([]string, 0, m.Size())
+	for k, v := range m.All() {
+		entries = append(entries, fmt.Sprintf("%+v: %+v", k, v))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// MarshalJSON encodes the map as a JSON object, or as a JSON array of
+// [key, value] pairs if K cannot be an object key, in ascending order of keys.
+func (m *SkipListMap[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(m.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object or array of [key, value] pairs. When called on the zero value, the map
+// orders keys of ordered types by their natural order. Decoding into the zero
+// value is not safe for concurrent use.
+func (m *SkipListMap[K, V]) UnmarshalJSON(data []byte) error {
+	if m.l == nil {
+		c, ok := codec.NaturalOrder[K]()
+		if !ok {
+			return fmt.Errorf("skiplist: cannot unmarshal into a SkipListMap without a comparator")
+		}
+		m.l = newList[K, V]([]Option[K]{WithComparator(c)})
+	}
+	return codec.UnmarshalMap(data, m.Clear, m.Put)
+}
+
+func value[K any, V any](n *node[K, V], v *V) (V, bool) {
+	if n == nil {
+		var zero V
+		return zero, false
+	}
+	return *v, true
+}
+
+func entry[K any, V any](n *node[K, V], v *V) (K, V, bool) {
+	if n == nil {
+		var k K
+		var zero V
+		return k, zero, false
+	}
+	return n.key, *v, true
+}
+
+func mustEntry[K any, V any](f func() (*node[K, V], *V), op string) (K, V) {
+	n, v := f()
+	if n == nil {
+		panic(op + " called on empty map")
+	}
+	return n.key, *v
+}
+
+func keys[K any, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func values[K any, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package skiplist
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+)
+
+var _ collections.MutableNavigableSet[int] = (*SkipListSet[int])(nil)
+
+// SkipListSet is a sorted set backed by a lock-free skip list, which is safe
+// for concurrent use by multiple goroutines.
+type SkipListSet[T any] struct {
+	m *SkipListMap[T, struct{}]
+}
+
+// NewSet creates an empty SkipListSet with the given options.
+func NewSet[T any](opts ...Option[T]) *SkipListSet[T] {
+	return &SkipListSet[T]{m: NewMap[T, struct{}](opts...)}
+}
+
+// NewOrderedSet creates an empty SkipListSet for types that satisfy
+// cmp.Ordered using natural ordering.
+func NewOrderedSet[T cmp.Ordered](opts ...Option[T]) *SkipListSet[T] {
+	return NewSet(append(opts, ordered[T]())...)
+}
+
+// Add inserts the specified element into the set.
+func (s *SkipListSet[T]) Add(item T) {
+	s.m.l.put(item, &struct{}{}, false)
+}
+
+// Remove removes and returns the least element of the set. Panics if empty.
+func (s *SkipListSet[T]) Remove() T {
+	n, _ := s.m.l.poll(s.m.l.first)
+	if n == nil {
+		panic("cannot remove from an empty set")
+	}
+	return n.key
+}
+
+// RemoveElement removes the specified element from the set.
+func (s *SkipListSet[T]) RemoveElement(item T) {
+	s.m.Remove(item)
+}
+
+// Contains returns true if this set contains the specified element.
+func (s *SkipListSet[T]) Contains(item T) bool {
+	return s.m.ContainsKey(item)
+}
+
+// ContainsAll returns true if this set contains all elements of the specified collection.
+func (s *SkipListSet[T]) ContainsAll(other collections.Collection[T]) bool {
+	for item := range other.All() {
+		if !s.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddAll inserts all elements from the given sequence into the set.
+func (s *SkipListSet[T]) AddAll(sequence iter.Seq[T]) {
+	for t := range sequence {
+		s.Add(t)
+	}
+}
+
+// RemoveAll removes all elements of the specified collection from this set.
+func (s *SkipListSet[T]) RemoveAll(other collections.Collection[T]) {
+	for t := range other.All() {
+		s.RemoveElement(t)
+	}
+}
+
+// RetainAll retains only the elements in this set that are contained in the
+// specified collection.
+func (s *SkipListSet[T]) RetainAll(other collections.Collection[T]) {
+	keep := NewSet(WithComparator(s.m.l.comparator))
+	keep.AddAll(other.All())
+	for t := range s.All() {
+		if !keep.Contains(t) {
+			s.RemoveElement(t)
+		}
+	}
+}
+
+// Clear removes all elements from the set. Elements added concurrently with
+// Clear may remain.
+func (s *SkipListSet[T]) Clear() {
+	s.m.Clear()
+}
+
+// Size returns the number of elements in the set.
+func (s *SkipListSet[T]) Size() int {
+	return s.m.Size()
+}
+
+// Empty returns true if the set contains no elements.
+func (s *SkipListSet[T]) Empty() bool {
+	return s.m.Empty()
+}
+
+// All returns a weakly consistent iterator over all the elements of this set
+// in ascending order.
+func (s *SkipListSet[T]) All() iter.Seq[T] {
+	return s.m.Keys()
+}
+
+// First returns the least element in the set. Panics if empty.
+func (s *SkipListSet[T]) First() T {
+	return mustElement(s.m.l.first, "First")
+}
+
+// Last returns the greatest element in the set. Panics if empty.
+func (s *SkipListSet[T]) Last() T {
+	return mustElement(s.m.l.last, "Last")
+}
+
+// PollFirst removes and returns the least element in the set. Panics if empty.
+func (s *SkipListSet[T]) PollFirst() T {
+	return mustElement(func() (*node[T, struct{}], *struct{}) { return s.m.l.poll(s.m.l.first) }, "PollFirst")
+}
+
+// PollLast removes and returns the greatest element in the set. Panics if
+// empty.
+func (s *SkipListSet[T]) PollLast() T {
+	return mustElement(func() (*node[T, struct{}], *struct{}) { return s.m.l.poll(s.m.l.last) }, "PollLast")
+}
+
+func (s *SkipListSet[T]) AddFirst(item T) {
+	panic("AddFirst is not supported on SortedSet")
+}
+
+func (s *SkipListSet[T]) AddLast(item T) {
+	panic("AddLast is not supported on SortedSet")
+}
+
+func (s *SkipListSet[T]) Lower(item T) (T, bool) {
+	k, _, ok := s.m.Lower(item)
+	return k, ok
+}
+
+func (s *SkipListSet[T]) Floor(item T) (T, bool) {
+	k, _, ok := s.m.Floor(item)
+	return k, ok
+}
+
+func (s *SkipListSet[T]) Ceiling(item T) (T, bool) {
+	k, _, ok := s.m.Ceiling(item)
+	return k, ok
+}
+
+func (s *SkipListSet[T]) Higher(item T) (T, bool) {
+	k, _, ok := s.m.Higher(item)
+	return k, ok
+}
+
+func (s *SkipListSet[T]) Backward() iter.Seq[T] {
+	return s.m.BackwardKeys()
+}
+
+func (s *SkipListSet[T]) From(from T) iter.Seq[T] {
+	return keys(s.m.From(from))
+}
+
+func (s *SkipListSet[T]) To(to T) iter.Seq[T] {
+	return keys(s.m.To(to))
+}
+
+func (s *SkipListSet[T]) Between(from, to T) iter.Seq[T] {
+	return keys(s.m.Between(from, to))
+}
+
+// String returns a string representation of the set.
+func (s *SkipListSet[T]) String() string {
+	vals := make([]string, 0, s.Size())
+	for item := range s.All() {
+		vals = append(vals, fmt.Sprintf("%+v", item))
+	}
+	return "[" + strings.Join(vals, ", ") + "]"
+}
+
+// MarshalJSON encodes the set as a JSON array of its elements in ascending
+// order.
+func (s *SkipListSet[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(s.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array. When called on the zero value, the set orders elements of ordered
+// types by their natural order. Decoding into the zero value is not safe for
+// concurrent use.
+func (s *SkipListSet[T]) UnmarshalJSON(data []byte) error {
+	if s.m == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("skiplist: cannot unmarshal into a SkipListSet without a comparator")
+		}
+		s.m = NewMap[T, struct{}](WithComparator(c))
+	}
+	return codec.UnmarshalSeq(data, s.Clear, s.Add)
+}
+
+func mustElement[T any](f func() (*node[T, struct{}], *struct{}), op string) T {
+	n, _ := f()
+	if n == nil {
+		panic(op + " called on empty set")
+	}
+	return n.key
+}
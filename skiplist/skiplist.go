@@ -0,0 +1,378 @@
+// Package skiplist provides a sorted map and a sorted set that are safe for
+// concurrent use by multiple goroutines without a global lock.
+//
+// SkipListMap is a lock-free skip list in the style of Java's
+// ConcurrentSkipListMap. Reads never block, and writes coordinate with
+// compare-and-swap operations only, so a goroutine stalled in the middle of an
+// operation never prevents others from completing theirs. Get, Put, Remove and
+// the navigation methods take O(log n) expected time.
+//
+// Iterators are weakly consistent: they never panic because of concurrent
+// modification, they yield each key at most once in ascending (or, for the
+// Backward iterators, descending) order, and they reflect every change made
+// before iteration began and may or may not reflect changes made during it.
+// Size is a counter maintained alongside the list, so it is exact when no
+// writes are in flight but may briefly disagree with an iteration that runs
+// concurrently with writes.
+package skiplist
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"sync/atomic"
+
+	"github.com/lock14/collections/comparator"
+)
+
+// maxLevel bounds the height of the list, which holds up to 4^maxLevel keys in
+// O(log n) expected time.
+const maxLevel = 24
+
+// config holds the values for configuring a skip list.
+type config[K any] struct {
+	comparator comparator.Comparator[K]
+}
+
+// Option configures a skip list config.
+type Option[K any] func(*config[K])
+
+// WithComparator configures the comparator used to order the keys.
+func WithComparator[K any](comp comparator.Comparator[K]) Option[K] {
+	return func(c *config[K]) {
+		c.comparator = comp
+	}
+}
+
+// link is an immutable reference from one node to the next at some level.
+// A node is deleted from a level once its link at that level is marked, after
+// which the link never changes. Every unmarked link to a node is the node's
+// own ref, so a compare-and-swap against ref succeeds exactly when the
+// reference still points to that node and is unmarked.
+type link[K any, V any] struct {
+	node   *node[K, V]
+	marked bool
+}
+
+// node holds a key and its value. A nil value means the key has been removed,
+// or is being removed, from the list.
+type node[K any, V any] struct {
+	key   K
+	value atomic.Pointer[V]
+	next  []atomic.Pointer[link[K, V]]
+	ref   *link[K, V]
+}
+
+func newNode[K any, V any](key K, value *V, levels int) *node[K, V] {
+	n := &node[K, V]{key: key, next: make([]atomic.Pointer[link[K, V]], levels)}
+	n.value.Store(value)
+	n.ref = &link[K, V]{node: n}
+	return n
+}
+
+// list is the skip list shared by SkipListMap and SkipListSet. The head node
+// precedes every key, and a nil node follows every key.
+type list[K any, V any] struct {
+	head       *node[K, V]
+	end        *link[K, V]
+	size       atomic.Int64
+	comparator comparator.Comparator[K]
+}
+
+func newList[K any, V any](opts []Option[K]) *list[K, V] {
+	config := &config[K]{}
+	for _, option := range opts {
+		option(config)
+	}
+	if config.comparator == nil {
+		panic("comparator must be provided or use an Ordered constructor")
+	}
+	l := &list[K, V]{comparator: config.comparator, end: &link[K, V]{}}
+	var zero K
+	l.head = newNode[K, V](zero, nil, maxLevel)
+	for i := range l.head.next {
+		l.head.next[i].Store(l.end)
+	}
+	return l
+}
+
+func randomLevels() int {
+	levels := 1
+	for r := rand.Uint64(); r&3 == 0 && levels < maxLevel; r >>= 2 {
+		levels++
+	}
+	return levels
+}
+
+// ref returns the unmarked link to n, which may be nil.
+func (l *list[K, V]) ref(n *node[K, V]) *link[K, V] {
+	if n == nil {
+		return l.end
+	}
+	return n.ref
+}
+
+// find fills preds and succs with the nodes on either side of key at every
+// level, unlinking the deleted nodes it passes, and reports whether succs[0]
+// holds key.
+func (l *list[K, V]) find(key K, preds, succs *[maxLevel]*node[K, V]) bool {
+retry:
+	for {
+		pred := l.head
+		for level := maxLevel - 1; level >= 0; level-- {
+			curr := pred.next[level].Load().node
+			for curr != nil {
+				succ := curr.next[level].Load()
+				for succ.marked {
+					if !pred.next[level].CompareAndSwap(curr.ref, l.ref(succ.node)) {
+						continue retry
+					}
+					curr = succ.node
+					if curr == nil {
+						break
+					}
+					succ = curr.next[level].Load()
+				}
+				if curr == nil || l.comparator(curr.key, key) >= 0 {
+					break
+				}
+				pred, curr = curr, succ.node
+			}
+			preds[level], succs[level] = pred, curr
+		}
+		return succs[0] != nil && l.comparator(succs[0].key, key) == 0
+	}
+}
+
+// search returns the last node before key and the first node at or after it at
+// the bottom level, without modifying the list. pred is the head if no node
+// precedes key, and curr is nil if none follows it. search steps over deleted
+// nodes without unlinking them, and only stops on nodes that were not deleted
+// when it reached them.
+func (l *list[K, V]) search(key K) (pred, curr *node[K, V]) {
+	pred = l.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr = pred.next[level].Load().node
+		for curr != nil {
+			succ := curr.next[level].Load()
+			for succ.marked {
+				curr = succ.node
+				if curr == nil {
+					break
+				}
+				succ = curr.next[level].Load()
+			}
+			if curr == nil || l.comparator(curr.key, key) >= 0 {
+				break
+			}
+			pred, curr = curr, succ.node
+		}
+	}
+	return pred, curr
+}
+
+// searchLast returns the last node at the bottom level that was not deleted
+// when search reached it, or the head if there is none.
+func (l *list[K, V]) searchLast() *node[K, V] {
+	pred := l.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		for curr := pred.next[level].Load().node; curr != nil; {
+			succ := curr.next[level].Load()
+			if !succ.marked {
+				pred = curr
+			}
+			curr = succ.node
+		}
+	}
+	return pred
+}
+
+// put associates value with key. If the key is present, put replaces its value
+// when replace is true and leaves it alone otherwise. It returns the previous
+// value, or nil if the key was absent.
+func (l *list[K, V]) put(key K, value *V, replace bool) *V {
+	var preds, succs [maxLevel]*node[K, V]
+	for {
+		if l.find(key, &preds, &succs) {
+			n := succs[0]
+			old := n.value.Load()
+			if old != nil && (!replace || n.value.CompareAndSwap(old, value)) {
+				return old
+			}
+			if old == nil {
+				// The node is being removed; finish marking it so that the next
+				// find unlinks it.
+				l.mark(n)
+			}
+			continue
+		}
+		n := newNode(key, value, randomLevels())
+		for i := range n.next {
+			n.next[i].Store(l.ref(succs[i]))
+		}
+		if !preds[0].next[0].CompareAndSwap(l.ref(succs[0]), n.ref) {
+			continue
+		}
+		l.size.Add(1)
+		for i := 1; i < len(n.next); i++ {
+			for !preds[i].next[i].CompareAndSwap(l.ref(succs[i]), n.ref) {
+				if !l.find(key, &preds, &succs) || succs[0] != n {
+					return nil
+				}
+				next := n.next[i].Load()
+				if next.marked || !n.next[i].CompareAndSwap(next, l.ref(succs[i])) {
+					return nil
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// remove removes n if its value is still old, and reports whether it did.
+func (l *list[K, V]) remove(n *node[K, V], old *V) bool {
+	if !n.value.CompareAndSwap(old, nil) {
+		return false
+	}
+	l.size.Add(-1)
+	l.mark(n)
+	var preds, succs [maxLevel]*node[K, V]
+	l.find(n.key, &preds, &succs)
+	return true
+}
+
+// removeKey removes key from the list, and returns its value or nil if it was
+// absent.
+func (l *list[K, V]) removeKey(key K) *V {
+	_, n := l.search(key)
+	if n == nil || l.comparator(n.key, key) != 0 {
+		return nil
+	}
+	for {
+		old := n.value.Load()
+		if old == nil {
+			return nil
+		}
+		if l.remove(n, old) {
+			return old
+		}
+	}
+}
+
+// mark marks the links of n from the top level down, deleting it from every
+// level.
+func (l *list[K, V]) mark(n *node[K, V]) {
+	for i := len(n.next) - 1; i >= 0; i-- {
+		for {
+			next := n.next[i].Load()
+			if next.marked || n.next[i].CompareAndSwap(next, &link[K, V]{node: next.node, marked: true}) {
+				break
+			}
+		}
+	}
+}
+
+// get returns the live node holding key and its value.
+func (l *list[K, V]) get(key K) (*node[K, V], *V) {
+	_, n := l.search(key)
+	if n != nil && l.comparator(n.key, key) == 0 {
+		if v := n.value.Load(); v != nil {
+			return n, v
+		}
+	}
+	return nil, nil
+}
+
+// ceiling returns the first live node at or after n.
+func (l *list[K, V]) ceiling(n *node[K, V]) (*node[K, V], *V) {
+	for ; n != nil; n = n.next[0].Load().node {
+		if v := n.value.Load(); v != nil {
+			return n, v
+		}
+	}
+	return nil, nil
+}
+
+// higher returns the first live node with a key greater than key.
+func (l *list[K, V]) higher(key K) (*node[K, V], *V) {
+	_, n := l.search(key)
+	if n != nil && l.comparator(n.key, key) == 0 {
+		n = n.next[0].Load().node
+	}
+	return l.ceiling(n)
+}
+
+// lower returns the last live node with a key less than key.
+func (l *list[K, V]) lower(key K) (*node[K, V], *V) {
+	pred, _ := l.search(key)
+	for pred != l.head {
+		if v := pred.value.Load(); v != nil {
+			return pred, v
+		}
+		pred, _ = l.search(pred.key)
+	}
+	return nil, nil
+}
+
+// floor returns the last live node with a key less than or equal to key.
+func (l *list[K, V]) floor(key K) (*node[K, V], *V) {
+	if n, v := l.get(key); n != nil {
+		return n, v
+	}
+	return l.lower(key)
+}
+
+// first returns the first live node.
+func (l *list[K, V]) first() (*node[K, V], *V) {
+	return l.ceiling(l.head.next[0].Load().node)
+}
+
+// last returns the last live node.
+func (l *list[K, V]) last() (*node[K, V], *V) {
+	pred := l.searchLast()
+	if pred == l.head {
+		return nil, nil
+	}
+	if v := pred.value.Load(); v != nil {
+		return pred, v
+	}
+	return l.lower(pred.key)
+}
+
+// poll removes and returns the node returned by end, retrying if another
+// goroutine removes it first.
+func (l *list[K, V]) poll(end func() (*node[K, V], *V)) (*node[K, V], *V) {
+	for {
+		n, v := end()
+		if n == nil || l.remove(n, v) {
+			return n, v
+		}
+	}
+}
+
+// all yields the live nodes from n onwards while before reports true for
+// their keys.
+func (l *list[K, V]) all(n *node[K, V], before func(K) bool, yield func(K, V) bool) {
+	for n, v := l.ceiling(n); n != nil; n, v = l.ceiling(n.next[0].Load().node) {
+		if !before(n.key) || !yield(n.key, *v) {
+			return
+		}
+	}
+}
+
+// backward yields the live nodes from n backwards while after reports true for
+// their keys.
+func (l *list[K, V]) backward(n *node[K, V], v *V, after func(K) bool, yield func(K, V) bool) {
+	for ; n != nil; n, v = l.lower(n.key) {
+		if !after(n.key) || !yield(n.key, *v) {
+			return
+		}
+	}
+}
+
+func always[K any](K) bool {
+	return true
+}
+
+func ordered[K cmp.Ordered]() Option[K] {
+	return WithComparator(comparator.NaturalOrder[K]())
+}
@@ -0,0 +1,69 @@
+package skiplist_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/lock14/collections/skiplist"
+	"github.com/lock14/collections/synchronized"
+	"github.com/lock14/collections/treemap"
+)
+
+func BenchmarkSkipListMap_Put(b *testing.B) {
+	m := skiplist.NewOrderedMap[int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Put((i*7919)%100000, i)
+	}
+}
+
+func BenchmarkSkipListMap_Get(b *testing.B) {
+	m := skiplist.NewOrderedMap[int, int]()
+	for i := 0; i < 100000; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get((i * 7919) % 100000)
+	}
+}
+
+// BenchmarkParallelMixed compares the skip list with a treemap behind a
+// global lock under a write-heavy parallel workload.
+func BenchmarkParallelMixed(b *testing.B) {
+	b.Run("skiplist", func(b *testing.B) {
+		m := skiplist.NewOrderedMap[int, int]()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				k := rand.IntN(10000)
+				if k%2 == 0 {
+					m.Put(k, k)
+				} else {
+					m.Get(k)
+				}
+			}
+		})
+	})
+	b.Run("synchronized_treemap", func(b *testing.B) {
+		m := synchronized.NewNavigableMap[int, int](treemap.NewOrdered[int, int]())
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				k := rand.IntN(10000)
+				if k%2 == 0 {
+					m.Put(k, k)
+				} else {
+					m.Get(k)
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkSkipListSet_ParallelAdd(b *testing.B) {
+	s := skiplist.NewOrderedSet[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Add(rand.Int())
+		}
+	})
+}
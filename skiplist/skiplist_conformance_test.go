@@ -0,0 +1,20 @@
+package skiplist_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/skiplist"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableNavigableMap(t, func() collections.MutableNavigableMap[int, int] {
+		return skiplist.NewOrderedMap[int, int]()
+	}, cmp.Compare[int], collectionstest.Ints(50), collectionstest.Ints(10))
+	collectionstest.MutableNavigableSet(t, func() collections.MutableNavigableSet[int] {
+		return skiplist.NewOrderedSet[int]()
+	}, cmp.Compare[int], collectionstest.Ints(50))
+}
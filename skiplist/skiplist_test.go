@@ -0,0 +1,275 @@
+package skiplist
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+	m := NewOrderedMap[int, string]()
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		m.Put(k, fmt.Sprint(k))
+	}
+	m.Put(3, "three")
+	m.Remove(7)
+	m.Remove(8)
+	if got, want := m.String(), "{1: 1, 3: three, 5: 5, 9: 9}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if m.Size() != 4 || m.Empty() {
+		t.Errorf("expected size 4, got %d", m.Size())
+	}
+	if v, ok := m.PutIfAbsent(3, "x"); ok || v != "three" {
+		t.Errorf("expected PutIfAbsent to keep three, got %s, %t", v, ok)
+	}
+	if v, ok := m.PutIfAbsent(4, "four"); !ok || v != "four" {
+		t.Errorf("expected PutIfAbsent to add four, got %s, %t", v, ok)
+	}
+	cases := []struct {
+		name string
+		f    func(int) (int, string, bool)
+		key  int
+		want int
+		ok   bool
+	}{
+		{name: "lower", f: m.Lower, key: 5, want: 4, ok: true},
+		{name: "lower first", f: m.Lower, key: 1},
+		{name: "floor equal", f: m.Floor, key: 5, want: 5, ok: true},
+		{name: "floor between", f: m.Floor, key: 8, want: 5, ok: true},
+		{name: "floor below", f: m.Floor, key: 0},
+		{name: "ceiling equal", f: m.Ceiling, key: 9, want: 9, ok: true},
+		{name: "ceiling between", f: m.Ceiling, key: 6, want: 9, ok: true},
+		{name: "ceiling above", f: m.Ceiling, key: 10},
+		{name: "higher", f: m.Higher, key: 5, want: 9, ok: true},
+		{name: "higher last", f: m.Higher, key: 9},
+	}
+	for _, tc := range cases {
+		if k, _, ok := tc.f(tc.key); k != tc.want || ok != tc.ok {
+			t.Errorf("%s(%d): expected %d, %t, got %d, %t", tc.name, tc.key, tc.want, tc.ok, k, ok)
+		}
+	}
+	if got, want := slices.Collect(m.BackwardKeys()), []int{9, 5, 4, 3, 1}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(keys(m.Between(2, 9))), []int{3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if k, v := m.PollLast(); k != 9 || v != "9" {
+		t.Errorf("expected 9, got %d: %s", k, v)
+	}
+	if k, _ := m.PollFirst(); k != 1 {
+		t.Errorf("expected 1, got %d", k)
+	}
+	m.Clear()
+	if !m.Empty() || m.Size() != 0 {
+		t.Errorf("expected an empty map, got %v", m)
+	}
+	defer func() {
+		if r := recover(); r != "PollFirst called on empty map" {
+			t.Errorf("expected a panic, got %v", r)
+		}
+	}()
+	m.PollFirst()
+}
+
+// checkLevels verifies that every level of the list is in strictly ascending
+// order, and that the bottom level holds exactly the live keys once the list is
+// quiescent.
+func checkLevels[K any, V any](t *testing.T, l *list[K, V]) []K {
+	t.Helper()
+	var bottom []K
+	for level := maxLevel - 1; level >= 0; level-- {
+		var prev *node[K, V]
+		for n := l.head.next[level].Load().node; n != nil; n = n.next[level].Load().node {
+			if prev != nil && l.comparator(prev.key, n.key) >= 0 {
+				t.Fatalf("level %d out of order: %v before %v", level, prev.key, n.key)
+			}
+			if level == 0 {
+				if n.value.Load() == nil || n.next[0].Load().marked {
+					t.Fatalf("deleted key %v left in the bottom level", n.key)
+				}
+				bottom = append(bottom, n.key)
+			}
+			prev = n
+		}
+	}
+	if got := int(l.size.Load()); got != len(bottom) {
+		t.Fatalf("size is %d, but the list holds %d keys", got, len(bottom))
+	}
+	return bottom
+}
+
+func TestConcurrentPutRemove(t *testing.T) {
+	t.Parallel()
+	const goroutines, keys = 8, 2000
+	m := NewOrderedMap[int, int]()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := g; k < keys; k += goroutines {
+				m.Put(k, k)
+			}
+			for k := g; k < keys; k += goroutines {
+				if k%2 == 1 {
+					m.Remove(k)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	var want []int
+	for k := 0; k < keys; k += 2 {
+		want = append(want, k)
+	}
+	if got := checkLevels(t, m.l); !slices.Equal(got, want) {
+		t.Errorf("expected the even keys, got %v", got)
+	}
+}
+
+func TestContention(t *testing.T) {
+	t.Parallel()
+	const goroutines, ops, keys = 8, 5000, 32
+	s := NewOrderedSet[int]()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ops {
+				k := (i*31 + g*17) % keys
+				switch i % 4 {
+				case 0, 1:
+					s.Add(k)
+				case 2:
+					s.RemoveElement(k)
+				case 3:
+					var prev *int
+					for k := range s.All() {
+						if prev != nil && *prev >= k {
+							t.Errorf("iteration out of order: %d before %d", *prev, k)
+							return
+						}
+						prev = &k
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	got := checkLevels(t, s.m.l)
+	for _, k := range got {
+		if !s.Contains(k) {
+			t.Errorf("expected %d to be present", k)
+		}
+	}
+}
+
+func TestConcurrentPutIfAbsent(t *testing.T) {
+	t.Parallel()
+	const goroutines, keys = 8, 500
+	m := NewOrderedMap[int, int]()
+	wins := make([]int, goroutines)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range keys {
+				if _, ok := m.PutIfAbsent(k, g); ok {
+					wins[g]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	held := make([]int, goroutines)
+	for _, g := range m.All() {
+		held[g]++
+	}
+	if !slices.Equal(held, wins) {
+		t.Errorf("expected each goroutine to hold the keys it won, won %v, hold %v", wins, held)
+	}
+	total := 0
+	for _, n := range wins {
+		total += n
+	}
+	if total != keys || m.Size() != keys {
+		t.Errorf("expected exactly one winner for each of %d keys, got %d wins and size %d", keys, total, m.Size())
+	}
+}
+
+func TestConcurrentPoll(t *testing.T) {
+	t.Parallel()
+	const goroutines, keys = 8, 4000
+	m := NewOrderedMap[int, int]()
+	for k := range keys {
+		m.Put(k, k)
+	}
+	polled := make([][]int, goroutines)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range keys / goroutines {
+				var k int
+				if g%2 == 0 {
+					k, _ = m.PollFirst()
+				} else {
+					k, _ = m.PollLast()
+				}
+				polled[g] = append(polled[g], k)
+			}
+		}()
+	}
+	wg.Wait()
+	all := slices.Sorted(slices.Values(slices.Concat(polled...)))
+	for i, k := range all {
+		if k != i {
+			t.Fatalf("expected every key to be polled exactly once, got %d at position %d", k, i)
+		}
+	}
+	if !m.Empty() {
+		t.Errorf("expected an empty map, got %v", m)
+	}
+	checkLevels(t, m.l)
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	m := NewOrderedMap[string, int]()
+	m.Put("b", 2)
+	m.Put("a", 1)
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":1,"b":2}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	var got SkipListMap[string, int]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.String() != m.String() {
+		t.Errorf("expected %v, got %v", m, &got)
+	}
+
+	var s SkipListSet[int]
+	if err := json.Unmarshal([]byte(`[3,1,2,1]`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got, want := s.String(), "[1, 2, 3]"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	var noOrder SkipListSet[struct{}]
+	if err := json.Unmarshal([]byte(`[]`), &noOrder); err == nil {
+		t.Errorf("expected an error decoding a type without a natural order")
+	}
+}
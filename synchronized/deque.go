@@ -0,0 +1,101 @@
+package synchronized
+
+import (
+	"github.com/lock14/collections"
+)
+
+var _ collections.MutableDeque[int] = (*Deque[int])(nil)
+
+// Deque is a MutableDeque that is safe for concurrent use.
+type Deque[T any] struct {
+	collection[T]
+	d collections.MutableDeque[T]
+}
+
+// NewDeque returns a Deque that guards the given deque.
+func NewDeque[T any](d collections.MutableDeque[T], opts ...Option) *Deque[T] {
+	config := newConfig(opts)
+	deque := &Deque[T]{d: d}
+	deque.c = d
+	deque.exclusiveReads = config.exclusiveReads
+	return deque
+}
+
+// Peek returns the element at the front of the deque without removing it.
+func (d *Deque[T]) Peek() T {
+	d.rlock()
+	defer d.runlock()
+	return d.d.Peek()
+}
+
+// PeekFront returns the element at the front of the deque without removing it.
+func (d *Deque[T]) PeekFront() T {
+	d.rlock()
+	defer d.runlock()
+	return d.d.PeekFront()
+}
+
+// PeekBack returns the element at the back of the deque without removing it.
+func (d *Deque[T]) PeekBack() T {
+	d.rlock()
+	defer d.runlock()
+	return d.d.PeekBack()
+}
+
+// Push adds the specified element to the top of the deque.
+func (d *Deque[T]) Push(t T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.d.Push(t)
+}
+
+// Pop removes and returns the element at the top of the deque.
+func (d *Deque[T]) Pop() T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.d.Pop()
+}
+
+// AddFront inserts the specified element at the front of the deque.
+func (d *Deque[T]) AddFront(t T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.d.AddFront(t)
+}
+
+// RemoveFront removes and returns the element at the front of the deque.
+func (d *Deque[T]) RemoveFront() T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.d.RemoveFront()
+}
+
+// AddBack inserts the specified element at the back of the deque.
+func (d *Deque[T]) AddBack(t T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.d.AddBack(t)
+}
+
+// RemoveBack removes and returns the element at the back of the deque.
+func (d *Deque[T]) RemoveBack() T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.d.RemoveBack()
+}
+
+// Atomically calls f with the write lock held, passing it the backing deque.
+// The backing deque must not be retained after f returns.
+func (d *Deque[T]) Atomically(f func(d collections.MutableDeque[T])) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f(d.d)
+}
+
+// View calls f with the read lock held, passing it the backing deque.
+// f must not modify the deque or retain it after returning.
+func (d *Deque[T]) View(f func(d collections.Deque[T])) {
+	d.rlock()
+	defer d.runlock()
+	f(d.d)
+}
@@ -0,0 +1,77 @@
+package synchronized_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/synchronized"
+	"github.com/lock14/collections/treemap"
+)
+
+func ExampleMap() {
+	// A synchronized Map can be shared between goroutines without extra locking.
+	m := synchronized.NewMap[int, int](hashmap.New[int, int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.Put(i, i*i)
+		}(i)
+	}
+	wg.Wait()
+
+	fmt.Println("Size:", m.Size())
+
+	// Output:
+	// Size: 4
+}
+
+func ExampleMap_Atomically() {
+	// Atomically holds the write lock for a compound check-then-act operation.
+	m := synchronized.NewMap[string, int](hashmap.New[string, int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Atomically(func(m collections.MutableMap[string, int]) {
+				count, _ := m.Get("hits")
+				m.Put("hits", count+1)
+			})
+		}()
+	}
+	wg.Wait()
+
+	hits, _ := m.Get("hits")
+	fmt.Println("Hits:", hits)
+
+	// Output:
+	// Hits: 100
+}
+
+func ExampleNavigableMap_All() {
+	// Iteration walks a snapshot, so the loop body may modify the map.
+	m := synchronized.NewNavigableMap[int, string](treemap.NewOrdered[int, string]())
+	m.Put(1, "one")
+	m.Put(2, "two")
+	m.Put(3, "three")
+
+	for k, v := range m.All() {
+		if k%2 == 1 {
+			m.Remove(k)
+		}
+		fmt.Println(k, v)
+	}
+	fmt.Println("Remaining:", m.Size())
+
+	// Output:
+	// 1 one
+	// 2 two
+	// 3 three
+	// Remaining: 1
+}
@@ -0,0 +1,52 @@
+package synchronized
+
+import (
+	"github.com/lock14/collections"
+)
+
+var _ collections.MutableList[int] = (*List[int])(nil)
+
+// List is a MutableList that is safe for concurrent use.
+type List[T any] struct {
+	collection[T]
+	l collections.MutableList[T]
+}
+
+// NewList returns a List that guards the given list.
+func NewList[T any](l collections.MutableList[T], opts ...Option) *List[T] {
+	config := newConfig(opts)
+	list := &List[T]{l: l}
+	list.c = l
+	list.exclusiveReads = config.exclusiveReads
+	return list
+}
+
+// Get returns the element at the specified index.
+func (l *List[T]) Get(idx int) T {
+	l.rlock()
+	defer l.runlock()
+	return l.l.Get(idx)
+}
+
+// Set replaces the element at the specified index with the given element.
+func (l *List[T]) Set(idx int, t T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.l.Set(idx, t)
+}
+
+// Atomically calls f with the write lock held, passing it the backing list.
+// The backing list must not be retained after f returns.
+func (l *List[T]) Atomically(f func(l collections.MutableList[T])) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f(l.l)
+}
+
+// View calls f with the read lock held, passing it the backing list.
+// f must not modify the list or retain it after returning.
+func (l *List[T]) View(f func(l collections.List[T])) {
+	l.rlock()
+	defer l.runlock()
+	f(l.l)
+}
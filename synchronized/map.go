@@ -0,0 +1,180 @@
+package synchronized
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+)
+
+var _ collections.MutableMap[int, int] = (*Map[int, int])(nil)
+
+// Map is a MutableMap that is safe for concurrent use.
+type Map[K any, V any] struct {
+	guard
+	m collections.MutableMap[K, V]
+}
+
+// NewMap returns a Map that guards the given map.
+func NewMap[K any, V any](m collections.MutableMap[K, V], opts ...Option) *Map[K, V] {
+	config := newConfig(opts)
+	return &Map[K, V]{
+		guard: guard{exclusiveReads: config.exclusiveReads},
+		m:     m,
+	}
+}
+
+// Get returns the value associated with the specified key, and a boolean indicating if it was found.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	m.rlock()
+	defer m.runlock()
+	return m.m.Get(k)
+}
+
+// Size returns the number of key-value pairs in the map.
+func (m *Map[K, V]) Size() int {
+	m.rlock()
+	defer m.runlock()
+	return m.m.Size()
+}
+
+// Empty returns true if the map contains no key-value pairs.
+func (m *Map[K, V]) Empty() bool {
+	m.rlock()
+	defer m.runlock()
+	return m.m.Empty()
+}
+
+// ContainsKey returns true if the map contains a mapping for the specified key.
+func (m *Map[K, V]) ContainsKey(k K) bool {
+	m.rlock()
+	defer m.runlock()
+	return m.m.ContainsKey(k)
+}
+
+// All returns an iterator over a snapshot of the key-value pairs in the map
+// taken when iteration begins.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return m.snapshot2(m.m.All)
+}
+
+// Keys returns an iterator over a snapshot of the keys in the map taken when
+// iteration begins.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return snapshot(&m.guard, m.m.Keys, m.m.Size)
+}
+
+// Values returns an iterator over a snapshot of the values in the map taken
+// when iteration begins.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return snapshot(&m.guard, m.m.Values, m.m.Size)
+}
+
+// Put associates the specified value with the specified key in the map.
+func (m *Map[K, V]) Put(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m.Put(k, v)
+}
+
+// Remove removes the mapping for the specified key from the map if present.
+func (m *Map[K, V]) Remove(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m.Remove(k)
+}
+
+// Clear removes all key-value pairs from the map.
+func (m *Map[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m.Clear()
+}
+
+// Atomically calls f with the write lock held, passing it the backing map.
+// The backing map must not be retained after f returns.
+func (m *Map[K, V]) Atomically(f func(m collections.MutableMap[K, V])) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f(m.m)
+}
+
+// View calls f with the read lock held, passing it the backing map.
+// f must not modify the map or retain it after returning.
+func (m *Map[K, V]) View(f func(m collections.Map[K, V])) {
+	m.rlock()
+	defer m.runlock()
+	f(m.m)
+}
+
+// String returns the string representation of the map.
+func (m *Map[K, V]) String() string {
+	m.rlock()
+	defer m.runlock()
+	return fmt.Sprint(m.m)
+}
+
+// MarshalJSON encodes a snapshot of the map as a JSON object, or as a JSON
+// array of [key, value] pairs if K cannot be an object key.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(m.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object or array of [key, value] pairs. The input is decoded before the write
+// lock is taken, and the map is cleared and refilled atomically.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	locked := false
+	defer func() {
+		if locked {
+			m.mu.Unlock()
+		}
+	}()
+	return codec.UnmarshalMap(data, func() {
+		m.mu.Lock()
+		locked = true
+		m.m.Clear()
+	}, m.m.Put)
+}
+
+func (m *Map[K, V]) snapshot2(all func() iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := m.collect(all)
+		for i := range keys {
+			if !yield(keys[i], values[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Map[K, V]) collect(all func() iter.Seq2[K, V]) ([]K, []V) {
+	m.rlock()
+	defer m.runlock()
+	keys := make([]K, 0, m.m.Size())
+	values := make([]V, 0, m.m.Size())
+	for k, v := range all() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return keys, values
+}
+
+// snapshot returns an iterator that copies the given sequence under the read
+// lock of g when iteration begins, and then yields the copy without the lock.
+func snapshot[T any](g *guard, all func() iter.Seq[T], size func() int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		items := func() []T {
+			g.rlock()
+			defer g.runlock()
+			return slices.AppendSeq(make([]T, 0, size()), all())
+		}()
+		for _, t := range items {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package synchronized
+
+import (
+	"iter"
+
+	"github.com/lock14/collections"
+)
+
+var _ collections.MutableNavigableMap[int, int] = (*NavigableMap[int, int])(nil)
+
+// NavigableMap is a MutableNavigableMap that is safe for concurrent use.
+type NavigableMap[K any, V any] struct {
+	Map[K, V]
+	nm collections.MutableNavigableMap[K, V]
+}
+
+// NewNavigableMap returns a NavigableMap that guards the given map.
+func NewNavigableMap[K any, V any](m collections.MutableNavigableMap[K, V], opts ...Option) *NavigableMap[K, V] {
+	config := newConfig(opts)
+	nm := &NavigableMap[K, V]{nm: m}
+	nm.m = m
+	nm.exclusiveReads = config.exclusiveReads
+	return nm
+}
+
+// First returns the first key-value pair in the map. Panics if empty.
+func (m *NavigableMap[K, V]) First() (K, V) {
+	m.rlock()
+	defer m.runlock()
+	return m.nm.First()
+}
+
+// Last returns the last key-value pair in the map. Panics if empty.
+func (m *NavigableMap[K, V]) Last() (K, V) {
+	m.rlock()
+	defer m.runlock()
+	return m.nm.Last()
+}
+
+// Backward returns an iterator over a snapshot of the key-value pairs in
+// reverse order taken when iteration begins.
+func (m *NavigableMap[K, V]) Backward() iter.Seq2[K, V] {
+	return m.snapshot2(m.nm.Backward)
+}
+
+// BackwardKeys returns an iterator over a snapshot of the keys in reverse
+// order taken when iteration begins.
+func (m *NavigableMap[K, V]) BackwardKeys() iter.Seq[K] {
+	return snapshot(&m.guard, m.nm.BackwardKeys, m.nm.Size)
+}
+
+// BackwardValues returns an iterator over a snapshot of the values in reverse
+// order taken when iteration begins.
+func (m *NavigableMap[K, V]) BackwardValues() iter.Seq[V] {
+	return snapshot(&m.guard, m.nm.BackwardValues, m.nm.Size)
+}
+
+// From returns an iterator over a snapshot of the entries whose keys are
+// greater than or equal to the given key.
+func (m *NavigableMap[K, V]) From(from K) iter.Seq2[K, V] {
+	return m.snapshot2(func() iter.Seq2[K, V] { return m.nm.From(from) })
+}
+
+// To returns an iterator over a snapshot of the entries whose keys are less
+// than the given key.
+func (m *NavigableMap[K, V]) To(to K) iter.Seq2[K, V] {
+	return m.snapshot2(func() iter.Seq2[K, V] { return m.nm.To(to) })
+}
+
+// Between returns an iterator over a snapshot of the entries whose keys are
+// greater than or equal to 'from' and less than 'to'.
+func (m *NavigableMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return m.snapshot2(func() iter.Seq2[K, V] { return m.nm.Between(from, to) })
+}
+
+// Lower returns the key-value pair for the greatest key strictly less than the given key.
+func (m *NavigableMap[K, V]) Lower(k K) (K, V, bool) {
+	m.rlock()
+	defer m.runlock()
+	return m.nm.Lower(k)
+}
+
+// Floor returns the key-value pair for the greatest key less than or equal to the given key.
+func (m *NavigableMap[K, V]) Floor(k K) (K, V, bool) {
+	m.rlock()
+	defer m.runlock()
+	return m.nm.Floor(k)
+}
+
+// Ceiling returns the key-value pair for the least key greater than or equal to the given key.
+func (m *NavigableMap[K, V]) Ceiling(k K) (K, V, bool) {
+	m.rlock()
+	defer m.runlock()
+	return m.nm.Ceiling(k)
+}
+
+// Higher returns the key-value pair for the least key strictly greater than the given key.
+func (m *NavigableMap[K, V]) Higher(k K) (K, V, bool) {
+	m.rlock()
+	defer m.runlock()
+	return m.nm.Higher(k)
+}
+
+// PollFirst removes and returns the first key-value pair in the map. Panics if empty.
+func (m *NavigableMap[K, V]) PollFirst() (K, V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nm.PollFirst()
+}
+
+// PollLast removes and returns the last key-value pair in the map. Panics if empty.
+func (m *NavigableMap[K, V]) PollLast() (K, V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nm.PollLast()
+}
+
+// PutFirst inserts the specified key-value pair at the front of the map.
+func (m *NavigableMap[K, V]) PutFirst(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nm.PutFirst(k, v)
+}
+
+// PutLast inserts the specified key-value pair at the end of the map.
+func (m *NavigableMap[K, V]) PutLast(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nm.PutLast(k, v)
+}
+
+// Atomically calls f with the write lock held, passing it the backing map.
+// The backing map must not be retained after f returns.
+func (m *NavigableMap[K, V]) Atomically(f func(m collections.MutableNavigableMap[K, V])) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f(m.nm)
+}
+
+// View calls f with the read lock held, passing it the backing map.
+// f must not modify the map or retain it after returning.
+func (m *NavigableMap[K, V]) View(f func(m collections.NavigableMap[K, V])) {
+	m.rlock()
+	defer m.runlock()
+	f(m.nm)
+}
@@ -0,0 +1,76 @@
+package synchronized
+
+import (
+	"github.com/lock14/collections"
+)
+
+var _ collections.MutableSet[int] = (*Set[int])(nil)
+
+// Set is a MutableSet that is safe for concurrent use.
+type Set[T any] struct {
+	collection[T]
+	s collections.MutableSet[T]
+}
+
+// NewSet returns a Set that guards the given set.
+func NewSet[T any](s collections.MutableSet[T], opts ...Option) *Set[T] {
+	config := newConfig(opts)
+	set := &Set[T]{s: s}
+	set.c = s
+	set.exclusiveReads = config.exclusiveReads
+	return set
+}
+
+// Contains returns true if the set contains the specified element.
+func (s *Set[T]) Contains(t T) bool {
+	s.rlock()
+	defer s.runlock()
+	return s.s.Contains(t)
+}
+
+// ContainsAll returns true if the set contains all elements of the specified collection.
+func (s *Set[T]) ContainsAll(other collections.Collection[T]) bool {
+	other = copyOf(other)
+	s.rlock()
+	defer s.runlock()
+	return s.s.ContainsAll(other)
+}
+
+// RemoveElement removes the specified element from the set.
+func (s *Set[T]) RemoveElement(t T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.s.RemoveElement(t)
+}
+
+// RemoveAll removes all elements of the specified collection from the set.
+func (s *Set[T]) RemoveAll(other collections.Collection[T]) {
+	other = copyOf(other)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.s.RemoveAll(other)
+}
+
+// RetainAll retains only the elements in the set that are contained in the specified collection.
+func (s *Set[T]) RetainAll(other collections.Collection[T]) {
+	other = copyOf(other)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.s.RetainAll(other)
+}
+
+// Atomically calls f with the write lock held, passing it the backing set.
+// The backing set must not be retained after f returns.
+func (s *Set[T]) Atomically(f func(s collections.MutableSet[T])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s.s)
+}
+
+// View calls f with the read lock held, passing it the backing set.
+// f must not modify the set or retain it after returning.
+func (s *Set[T]) View(f func(s collections.Set[T])) {
+	s.rlock()
+	defer s.runlock()
+	f(s.s)
+}
@@ -0,0 +1,171 @@
+// Package synchronized provides wrappers that make the mutable collection
+// interfaces safe for concurrent use by multiple goroutines.
+//
+// Every wrapper guards its backing collection with a sync.RWMutex. Read
+// methods take the read lock and mutating methods take the write lock, so
+// each individual method call is atomic with respect to the others.
+//
+// Iterators returned by All, Keys, Values, Backward and the range methods
+// iterate over a snapshot of the backing collection. The snapshot is copied
+// under the read lock when iteration begins and the lock is released before
+// the first element is yielded, so the loop body may freely call back into
+// the wrapper, including mutating it. Later modifications are not reflected in
+// a running iteration.
+//
+// Compound operations that must observe a consistent state, such as
+// check-then-act sequences, should use Atomically, which runs a function with
+// the write lock held, or View, which runs a function with the read lock held
+// and iterates the backing collection in place without copying it.
+//
+// The backing collection must not be accessed directly once it is wrapped.
+package synchronized
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/internal/codec"
+)
+
+// config holds the values for configuring a synchronized wrapper.
+type config struct {
+	exclusiveReads bool
+}
+
+// Option configures a synchronized wrapper config.
+type Option func(*config)
+
+// WithExclusiveReads configures the wrapper to take the write lock for read
+// methods as well. This is required when reads mutate the backing collection,
+// as they do for an access-ordered linkedhashmap or linkedhashset.
+func WithExclusiveReads() Option {
+	return func(c *config) {
+		c.exclusiveReads = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	config := &config{}
+	for _, option := range opts {
+		option(config)
+	}
+	return config
+}
+
+// guard is a sync.RWMutex whose read lock may be promoted to the write lock.
+type guard struct {
+	mu             sync.RWMutex
+	exclusiveReads bool
+}
+
+func (g *guard) rlock() {
+	if g.exclusiveReads {
+		g.mu.Lock()
+	} else {
+		g.mu.RLock()
+	}
+}
+
+func (g *guard) runlock() {
+	if g.exclusiveReads {
+		g.mu.Unlock()
+	} else {
+		g.mu.RUnlock()
+	}
+}
+
+// collection holds the methods shared by all the collection wrappers.
+type collection[T any] struct {
+	guard
+	c collections.MutableCollection[T]
+}
+
+// Add inserts the specified element into the collection.
+func (c *collection[T]) Add(t T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.c.Add(t)
+}
+
+// Remove removes and returns a single element from the collection.
+func (c *collection[T]) Remove() T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.c.Remove()
+}
+
+// AddAll inserts all elements from the given sequence into the collection.
+// The sequence is consumed before the write lock is taken, so it may be
+// produced by this collection.
+func (c *collection[T]) AddAll(sequence iter.Seq[T]) {
+	items := slices.Collect(sequence)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.c.AddAll(slices.Values(items))
+}
+
+// Clear removes all elements from the collection.
+func (c *collection[T]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.c.Clear()
+}
+
+// Size returns the number of elements in the collection.
+func (c *collection[T]) Size() int {
+	c.rlock()
+	defer c.runlock()
+	return c.c.Size()
+}
+
+// Empty returns true if the collection contains no elements.
+func (c *collection[T]) Empty() bool {
+	c.rlock()
+	defer c.runlock()
+	return c.c.Empty()
+}
+
+// All returns an iterator over a snapshot of the elements of the collection
+// taken when iteration begins.
+func (c *collection[T]) All() iter.Seq[T] {
+	return snapshot(&c.guard, c.c.All, c.c.Size)
+}
+
+// String returns the string representation of the collection.
+func (c *collection[T]) String() string {
+	c.rlock()
+	defer c.runlock()
+	return fmt.Sprint(c.c)
+}
+
+// MarshalJSON encodes a snapshot of the collection as a JSON array.
+func (c *collection[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(c.All())
+}
+
+// UnmarshalJSON replaces the contents of the collection with the elements of a
+// JSON array. The array is decoded before the write lock is taken, and the
+// collection is cleared and refilled atomically.
+func (c *collection[T]) UnmarshalJSON(data []byte) error {
+	locked := false
+	defer func() {
+		if locked {
+			c.mu.Unlock()
+		}
+	}()
+	return codec.UnmarshalSeq(data, func() {
+		c.mu.Lock()
+		locked = true
+		c.c.Clear()
+	}, c.c.Add)
+}
+
+// copyOf copies the elements of the given collection so that it can be
+// consumed while a lock is held, even if the collection is itself a wrapper.
+func copyOf[T any](c collections.Collection[T]) collections.Collection[T] {
+	return arraylist.Wrap(slices.AppendSeq(make([]T, 0, c.Size()), c.All()))
+}
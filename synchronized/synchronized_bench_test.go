@@ -0,0 +1,67 @@
+package synchronized_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/synchronized"
+)
+
+func BenchmarkMap_Get_Parallel(b *testing.B) {
+	m := synchronized.NewMap[int, int](hashmap.New[int, int]())
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Get(i % 1000)
+			i++
+		}
+	})
+}
+
+func BenchmarkMap_Get_Parallel_ExclusiveReads(b *testing.B) {
+	m := synchronized.NewMap[int, int](hashmap.New[int, int](), synchronized.WithExclusiveReads())
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Get(i % 1000)
+			i++
+		}
+	})
+}
+
+func BenchmarkMap_PutGet_Parallel(b *testing.B) {
+	m := synchronized.NewMap[int, int](hashmap.New[int, int]())
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%10 == 0 {
+				m.Put(i%1000, i)
+			} else {
+				m.Get(i % 1000)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMap_All(b *testing.B) {
+	b.ReportAllocs()
+	m := synchronized.NewMap[int, int](hashmap.New[int, int]())
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range m.All() {
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package synchronized_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/hashset"
+	"github.com/lock14/collections/synchronized"
+	"github.com/lock14/collections/treemap"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableSet(t, func() collections.MutableSet[int] {
+		return synchronized.NewSet[int](hashset.New[int]())
+	}, collectionstest.Ints(20))
+	collectionstest.MutableList(t, func() collections.MutableList[int] {
+		return synchronized.NewList[int](arraylist.New[int]())
+	}, collectionstest.Ints(10))
+	collectionstest.MutableDeque(t, func() collections.MutableDeque[int] {
+		return synchronized.NewDeque[int](arraydeque.New[int]())
+	}, collectionstest.Ints(10))
+	collectionstest.MutableMap(t, func() collections.MutableMap[int, int] {
+		return synchronized.NewMap[int, int](hashmap.New[int, int]())
+	}, collectionstest.Ints(20), collectionstest.Ints(10))
+	collectionstest.MutableNavigableMap(t, func() collections.MutableNavigableMap[int, int] {
+		return synchronized.NewNavigableMap[int, int](treemap.NewOrdered[int, int]())
+	}, cmp.Compare[int], collectionstest.Ints(50), collectionstest.Ints(10))
+}
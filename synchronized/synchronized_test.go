@@ -0,0 +1,379 @@
+package synchronized
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/arraydeque"
+	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/hashset"
+	"github.com/lock14/collections/linkedhashmap"
+	"github.com/lock14/collections/treemap"
+)
+
+func TestNewOptions(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name      string
+		opts      []Option
+		exclusive bool
+	}{
+		{name: "default", exclusive: false},
+		{name: "exclusive reads", opts: []Option{WithExclusiveReads()}, exclusive: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewMap[int, int](hashmap.New[int, int](), tc.opts...).exclusiveReads; got != tc.exclusive {
+				t.Errorf("map: got exclusiveReads %t, want %t", got, tc.exclusive)
+			}
+			if got := NewSet[int](hashset.New[int](), tc.opts...).exclusiveReads; got != tc.exclusive {
+				t.Errorf("set: got exclusiveReads %t, want %t", got, tc.exclusive)
+			}
+			if got := NewList[int](arraylist.New[int](), tc.opts...).exclusiveReads; got != tc.exclusive {
+				t.Errorf("list: got exclusiveReads %t, want %t", got, tc.exclusive)
+			}
+			if got := NewDeque[int](arraydeque.New[int](), tc.opts...).exclusiveReads; got != tc.exclusive {
+				t.Errorf("deque: got exclusiveReads %t, want %t", got, tc.exclusive)
+			}
+			if got := NewNavigableMap[int, int](treemap.NewOrdered[int, int](), tc.opts...).exclusiveReads; got != tc.exclusive {
+				t.Errorf("navigable map: got exclusiveReads %t, want %t", got, tc.exclusive)
+			}
+		})
+	}
+}
+
+func TestMapConcurrentAccess(t *testing.T) {
+	t.Parallel()
+	const goroutines, perGoroutine = 8, 200
+	m := NewMap[int, int](hashmap.New[int, int]())
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				k := g*perGoroutine + i
+				m.Put(k, k)
+				if v, ok := m.Get(k); !ok || v != k {
+					t.Errorf("Get(%d) = %d, %t, want %d, true", k, v, ok, k)
+				}
+				for range m.Keys() {
+					break
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if got, want := m.Size(), goroutines*perGoroutine; got != want {
+		t.Errorf("wrong size: got %d, want %d", got, want)
+	}
+}
+
+func TestMapAtomically(t *testing.T) {
+	t.Parallel()
+	const goroutines, increments = 8, 500
+	m := NewMap[string, int](hashmap.New[string, int]())
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				m.Atomically(func(m collections.MutableMap[string, int]) {
+					v, _ := m.Get("count")
+					m.Put("count", v+1)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Get("count"); v != goroutines*increments {
+		t.Errorf("wrong count: got %d, want %d", v, goroutines*increments)
+	}
+}
+
+func TestMapIterationIsSnapshot(t *testing.T) {
+	t.Parallel()
+	m := NewMap[int, int](treemap.NewOrdered[int, int]())
+	for i := 0; i < 5; i++ {
+		m.Put(i, i*10)
+	}
+	var keys, values []int
+	for k, v := range m.All() {
+		// Mutating inside the loop must neither deadlock nor affect the iteration.
+		m.Remove(k)
+		m.Put(k+100, v)
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(keys, want) {
+		t.Errorf("wrong keys: got %v, want %v", keys, want)
+	}
+	if want := []int{0, 10, 20, 30, 40}; !slices.Equal(values, want) {
+		t.Errorf("wrong values: got %v, want %v", values, want)
+	}
+	if got, want := slices.Collect(m.Keys()), []int{100, 101, 102, 103, 104}; !slices.Equal(got, want) {
+		t.Errorf("wrong keys after loop: got %v, want %v", got, want)
+	}
+}
+
+func TestMapExclusiveReads(t *testing.T) {
+	t.Parallel()
+	// Get on an access-ordered LinkedHashMap reorders entries, so concurrent
+	// reads are only safe with exclusive reads.
+	m := NewMap[int, int](linkedhashmap.New[int, int](linkedhashmap.WithAccessOrder()), WithExclusiveReads())
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.Get((g + i) % 10)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if got := m.Size(); got != 10 {
+		t.Errorf("wrong size: got %d, want 10", got)
+	}
+}
+
+func TestMapView(t *testing.T) {
+	t.Parallel()
+	m := NewMap[int, int](hashmap.New[int, int]())
+	m.Put(1, 2)
+	m.View(func(m collections.Map[int, int]) {
+		sum := 0
+		for k, v := range m.All() {
+			sum += k + v
+		}
+		if sum != 3 {
+			t.Errorf("wrong sum: got %d, want 3", sum)
+		}
+	})
+}
+
+func TestSetSelfReferentialOperations(t *testing.T) {
+	t.Parallel()
+	s := NewSet[int](hashset.New[int]())
+	s.AddAll(slices.Values([]int{1, 2, 3}))
+	// Each of these would deadlock if the argument were consumed under the lock.
+	s.AddAll(s.All())
+	if !s.ContainsAll(s) {
+		t.Errorf("set does not contain itself")
+	}
+	s.RetainAll(s)
+	if got := s.Size(); got != 3 {
+		t.Errorf("wrong size: got %d, want 3", got)
+	}
+	s.RemoveAll(s)
+	if !s.Empty() {
+		t.Errorf("expected empty set, got %v", s)
+	}
+}
+
+func TestSetConcurrentAccess(t *testing.T) {
+	t.Parallel()
+	s := NewSet[int](hashset.New[int]())
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.Add(i)
+				s.Contains(i)
+				if g%2 == 0 {
+					s.RemoveElement(i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	for t2 := range s.All() {
+		if t2 < 0 || t2 >= 100 {
+			t.Errorf("unexpected element %d", t2)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+	l := NewList[int](arraylist.New[int]())
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Add(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := l.Size(); got != 400 {
+		t.Errorf("wrong size: got %d, want 400", got)
+	}
+	l.Atomically(func(l collections.MutableList[int]) {
+		for i := 0; i < l.Size(); i++ {
+			l.Set(i, i)
+		}
+	})
+	if got := l.Get(399); got != 399 {
+		t.Errorf("wrong element: got %d, want 399", got)
+	}
+	l.Clear()
+	if !l.Empty() {
+		t.Errorf("expected empty list")
+	}
+}
+
+func TestDequeProducersConsumers(t *testing.T) {
+	t.Parallel()
+	const producers, perProducer = 4, 250
+	d := NewDeque[int](arraydeque.New[int]())
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if p%2 == 0 {
+					d.AddBack(i)
+				} else {
+					d.AddFront(i)
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	removed := 0
+	var mu sync.Mutex
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for {
+				ok := false
+				d.Atomically(func(d collections.MutableDeque[int]) {
+					if d.Empty() {
+						return
+					}
+					if c%2 == 0 {
+						d.RemoveFront()
+					} else {
+						d.RemoveBack()
+					}
+					ok = true
+				})
+				if !ok {
+					return
+				}
+				mu.Lock()
+				removed++
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+	if removed != producers*perProducer {
+		t.Errorf("wrong removal count: got %d, want %d", removed, producers*perProducer)
+	}
+}
+
+func TestDeque(t *testing.T) {
+	t.Parallel()
+	d := NewDeque[int](arraydeque.New[int]())
+	d.Push(1)
+	d.AddBack(2)
+	d.AddFront(0)
+	if got := d.PeekFront(); got != 0 {
+		t.Errorf("wrong front: got %d, want 0", got)
+	}
+	if got := d.PeekBack(); got != 2 {
+		t.Errorf("wrong back: got %d, want 2", got)
+	}
+	if got := d.Pop(); got != 0 {
+		t.Errorf("wrong pop: got %d, want 0", got)
+	}
+	d.View(func(d collections.Deque[int]) {
+		if d.Size() != 2 {
+			t.Errorf("wrong size: got %d, want 2", d.Size())
+		}
+	})
+}
+
+func TestNavigableMap(t *testing.T) {
+	t.Parallel()
+	m := NewNavigableMap[int, string](treemap.NewOrdered[int, string]())
+	for i, s := range []string{"a", "b", "c", "d"} {
+		m.Put(i, s)
+	}
+	if k, v, ok := m.Ceiling(2); !ok || k != 2 || v != "c" {
+		t.Errorf("wrong ceiling: got %d, %q, %t", k, v, ok)
+	}
+	if k, v := m.First(); k != 0 || v != "a" {
+		t.Errorf("wrong first: got %d, %q", k, v)
+	}
+	var keys []int
+	for k := range m.Between(1, 3) {
+		m.Remove(k)
+		keys = append(keys, k)
+	}
+	if want := []int{1, 2}; !slices.Equal(keys, want) {
+		t.Errorf("wrong keys: got %v, want %v", keys, want)
+	}
+	if got, want := slices.Collect(m.BackwardValues()), []string{"d", "a"}; !slices.Equal(got, want) {
+		t.Errorf("wrong backward values: got %v, want %v", got, want)
+	}
+	m.Atomically(func(m collections.MutableNavigableMap[int, string]) {
+		if k, _, ok := m.Floor(2); ok {
+			m.Remove(k)
+		}
+	})
+	if got := m.Size(); got != 1 {
+		t.Errorf("wrong size: got %d, want 1", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	l := NewList[int](arraylist.New[int]())
+	if err := json.Unmarshal([]byte("[1,2,3]"), l); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := "[1,2,3]"; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	m := NewMap[string, int](hashmap.New[string, int]())
+	m.Put("stale", 0)
+	if err := json.Unmarshal([]byte(`{"a":1}`), m); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if data, err = json.Marshal(m); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":1}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	if err := json.Unmarshal([]byte(`{"a":`), m); err == nil {
+		t.Errorf("expected an error for malformed input")
+	}
+	if m.Size() != 1 {
+		t.Errorf("expected malformed input to leave the map unchanged, got %v", m)
+	}
+}
@@ -0,0 +1,33 @@
+package table_test
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/lock14/collections/table"
+)
+
+func ExampleTable() {
+	// A Table replaces nested maps: rows and columns are created by their first
+	// cell and removed with their last.
+	shifts := table.NewTree[string, int, string](cmp.Compare[string], cmp.Compare[int])
+	shifts.Put("alice", 1, "early")
+	shifts.Put("alice", 2, "late")
+	shifts.Put("bob", 2, "early")
+
+	fmt.Println(shifts)
+	fmt.Println("Day 2:", shifts.Column(2))
+
+	shifts.Column(2).Clear()
+	fmt.Println(shifts)
+
+	for cell := range shifts.Transpose().Cells() {
+		fmt.Println(cell.Row, cell.Column, cell.Value)
+	}
+
+	// Output:
+	// {alice: {1: early, 2: late}, bob: {2: early}}
+	// Day 2: {alice: late, bob: early}
+	// {alice: {1: early}}
+	// 1 alice early
+}
@@ -0,0 +1,382 @@
+// Package table provides a two-dimensional map, which associates a value with
+// each pair of a row key and a column key.
+//
+// A Table indexes its cells both by row and by column, so Row and Column are
+// equally cheap. A row or column is present exactly while it has at least one
+// cell: putting a cell adds its row and column, and removing the last cell of a
+// row or column removes it. Transpose returns a live view of the same cells
+// with rows and columns swapped.
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/comparator"
+	"github.com/lock14/collections/hashmap"
+	"github.com/lock14/collections/internal/anymap"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/pair"
+	"github.com/lock14/collections/treemap"
+)
+
+var _ collections.MutableMap[string, int] = line[int, string, int]{}
+
+// Cell is a single entry of a table.
+type Cell[R any, C any, V any] struct {
+	Row    R
+	Column C
+	Value  V
+}
+
+// Table is a map from a row key and a column key to a value, backed by a map
+// of rows and a map of columns.
+type Table[R any, C any, V any] struct {
+	rows   collections.MutableMap[R, collections.MutableMap[C, V]]
+	cols   collections.MutableMap[C, collections.MutableMap[R, V]]
+	newRow func() collections.MutableMap[C, V]
+	newCol func() collections.MutableMap[R, V]
+	size   int
+	tr     *Table[C, R, V]
+}
+
+func newTable[R any, C any, V any](
+	rows collections.MutableMap[R, collections.MutableMap[C, V]], newRow func() collections.MutableMap[C, V],
+	cols collections.MutableMap[C, collections.MutableMap[R, V]], newCol func() collections.MutableMap[R, V],
+) *Table[R, C, V] {
+	t := &Table[R, C, V]{rows: rows, cols: cols, newRow: newRow, newCol: newCol}
+	t.tr = &Table[C, R, V]{rows: cols, cols: rows, newRow: newCol, newCol: newRow, tr: t}
+	return t
+}
+
+// NewHash creates an empty Table backed by hashmaps.
+func NewHash[R comparable, C comparable, V any]() *Table[R, C, V] {
+	return newTable(
+		hashmap.New[R, collections.MutableMap[C, V]](), func() collections.MutableMap[C, V] { return hashmap.New[C, V]() },
+		hashmap.New[C, collections.MutableMap[R, V]](), func() collections.MutableMap[R, V] { return hashmap.New[R, V]() },
+	)
+}
+
+// NewTree creates an empty Table backed by treemaps, which iterates rows in the
+// order imposed by rowComp and columns in the order imposed by colComp.
+func NewTree[R any, C any, V any](rowComp comparator.Comparator[R], colComp comparator.Comparator[C]) *Table[R, C, V] {
+	return newTable(
+		treemap.New[R, collections.MutableMap[C, V]](treemap.WithComparator(rowComp)),
+		func() collections.MutableMap[C, V] { return treemap.New[C, V](treemap.WithComparator(colComp)) },
+		treemap.New[C, collections.MutableMap[R, V]](treemap.WithComparator(colComp)),
+		func() collections.MutableMap[R, V] { return treemap.New[R, V](treemap.WithComparator(rowComp)) },
+	)
+}
+
+// Transpose returns a view of this table with rows and columns swapped. The
+// view shares its cells with this table, so changes through either are visible
+// in both. Transpose of the view returns this table.
+func (t *Table[R, C, V]) Transpose() *Table[C, R, V] {
+	return t.tr
+}
+
+// Get returns the value of the cell at the specified row and column, and a
+// boolean indicating if it was found.
+func (t *Table[R, C, V]) Get(row R, col C) (V, bool) {
+	if r, ok := t.rows.Get(row); ok {
+		return r.Get(col)
+	}
+	var zero V
+	return zero, false
+}
+
+// Put sets the value of the cell at the specified row and column.
+func (t *Table[R, C, V]) Put(row R, col C, value V) {
+	r, ok := t.rows.Get(row)
+	if !ok {
+		r = t.newRow()
+		t.rows.Put(row, r)
+	}
+	if !r.ContainsKey(col) {
+		t.size++
+		t.tr.size++
+	}
+	r.Put(col, value)
+	c, ok := t.cols.Get(col)
+	if !ok {
+		c = t.newCol()
+		t.cols.Put(col, c)
+	}
+	c.Put(row, value)
+}
+
+// Remove removes the cell at the specified row and column if present, and
+// removes its row and column if they have no cells left.
+func (t *Table[R, C, V]) Remove(row R, col C) {
+	r, ok := t.rows.Get(row)
+	if !ok || !r.ContainsKey(col) {
+		return
+	}
+	r.Remove(col)
+	if r.Empty() {
+		t.rows.Remove(row)
+	}
+	c, _ := t.cols.Get(col)
+	c.Remove(row)
+	if c.Empty() {
+		t.cols.Remove(col)
+	}
+	t.size--
+	t.tr.size--
+}
+
+// RemoveRow removes every cell in the specified row.
+func (t *Table[R, C, V]) RemoveRow(row R) {
+	r, ok := t.rows.Get(row)
+	if !ok {
+		return
+	}
+	t.rows.Remove(row)
+	for col := range r.Keys() {
+		c, _ := t.cols.Get(col)
+		c.Remove(row)
+		if c.Empty() {
+			t.cols.Remove(col)
+		}
+	}
+	t.size -= r.Size()
+	t.tr.size -= r.Size()
+}
+
+// RemoveColumn removes every cell in the specified column.
+func (t *Table[R, C, V]) RemoveColumn(col C) {
+	t.tr.RemoveRow(col)
+}
+
+// Clear removes all cells from the table.
+func (t *Table[R, C, V]) Clear() {
+	t.rows.Clear()
+	t.cols.Clear()
+	t.size = 0
+	t.tr.size = 0
+}
+
+// Size returns the number of cells in the table.
+func (t *Table[R, C, V]) Size() int {
+	return t.size
+}
+
+// Empty returns true if the table contains no cells.
+func (t *Table[R, C, V]) Empty() bool {
+	return t.size == 0
+}
+
+// Contains returns true if the table has a cell at the specified row and
+// column.
+func (t *Table[R, C, V]) Contains(row R, col C) bool {
+	r, ok := t.rows.Get(row)
+	return ok && r.ContainsKey(col)
+}
+
+// ContainsRow returns true if the table has at least one cell in the specified
+// row.
+func (t *Table[R, C, V]) ContainsRow(row R) bool {
+	return t.rows.ContainsKey(row)
+}
+
+// ContainsColumn returns true if the table has at least one cell in the
+// specified column.
+func (t *Table[R, C, V]) ContainsColumn(col C) bool {
+	return t.cols.ContainsKey(col)
+}
+
+// Row returns a view of the specified row as a map from column keys to values.
+// The view is empty if the row is absent, and reflects later changes to the
+// table. Changes through the view are made to the table.
+func (t *Table[R, C, V]) Row(row R) collections.MutableMap[C, V] {
+	return line[R, C, V]{t, row}
+}
+
+// Column returns a view of the specified column as a map from row keys to
+// values. The view is empty if the column is absent, and reflects later changes
+// to the table. Changes through the view are made to the table.
+func (t *Table[R, C, V]) Column(col C) collections.MutableMap[R, V] {
+	return t.tr.Row(col)
+}
+
+// RowKeys returns an iterator over the keys of the rows that have at least one
+// cell.
+func (t *Table[R, C, V]) RowKeys() iter.Seq[R] {
+	return t.rows.Keys()
+}
+
+// ColumnKeys returns an iterator over the keys of the columns that have at
+// least one cell.
+func (t *Table[R, C, V]) ColumnKeys() iter.Seq[C] {
+	return t.cols.Keys()
+}
+
+// Cells returns an iterator over all cells in the table, row by row.
+func (t *Table[R, C, V]) Cells() iter.Seq[Cell[R, C, V]] {
+	return func(yield func(Cell[R, C, V]) bool) {
+		for row, r := range t.rows.All() {
+			for col, v := range r.All() {
+				if !yield(Cell[R, C, V]{Row: row, Column: col, Value: v}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// String returns a string representation of the table.
+func (t *Table[R, C, V]) String() string {
+	rows := make([]string, 0, t.rows.Size())
+	for row := range t.rows.Keys() {
+		rows = append(rows, fmt.Sprintf("%+v: %v", row, t.Row(row)))
+	}
+	return "{" + strings.Join(rows, ", ") + "}"
+}
+
+// MarshalJSON encodes the table as a JSON object mapping each row key to an
+// object of its cells. Row and column keys that cannot be object keys are
+// encoded as arrays of [key, value] pairs instead.
+func (t *Table[R, C, V]) MarshalJSON() ([]byte, error) {
+	// encode each row through its view, as the maps holding the rows need not
+	// implement json.Marshaler
+	return codec.MarshalMap(func(yield func(R, collections.MutableMap[C, V]) bool) {
+		for row := range t.RowKeys() {
+			if !yield(row, t.Row(row)) {
+				return
+			}
+		}
+	})
+}
+
+// UnmarshalJSON replaces the contents of the table with the cells of a JSON
+// object mapping each row key to an object of its cells. When called on the
+// zero value, the table is backed by hash maps, and decoding reports an error
+// if R or C is not comparable.
+func (t *Table[R, C, V]) UnmarshalJSON(data []byte) error {
+	if t.rows == nil {
+		rows, ok := anymap.New[R, collections.MutableMap[C, V]]()
+		cols, colsOK := anymap.New[C, collections.MutableMap[R, V]]()
+		if !ok || !colsOK {
+			return fmt.Errorf("table: cannot unmarshal into a Table whose row or column keys are not comparable")
+		}
+		t.rows, t.cols = rows, cols
+		t.newRow = func() collections.MutableMap[C, V] {
+			m, _ := anymap.New[C, V]()
+			return m
+		}
+		t.newCol = func() collections.MutableMap[R, V] {
+			m, _ := anymap.New[R, V]()
+			return m
+		}
+		t.tr = &Table[C, R, V]{rows: t.cols, cols: t.rows, newRow: t.newCol, newCol: t.newRow, tr: t}
+	}
+	var rows []pair.Pair[R, json.RawMessage]
+	decoded := false
+	err := codec.UnmarshalMap(data, func() { decoded = true }, func(row R, raw json.RawMessage) {
+		rows = append(rows, pair.New(row, raw))
+	})
+	if err != nil || !decoded {
+		return err
+	}
+	var cells []Cell[R, C, V]
+	for _, r := range rows {
+		err := codec.UnmarshalMap(r.Snd(), func() {}, func(col C, v V) {
+			cells = append(cells, Cell[R, C, V]{Row: r.Fst(), Column: col, Value: v})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	t.Clear()
+	for _, c := range cells {
+		t.Put(c.Row, c.Column, c.Value)
+	}
+	return nil
+}
+
+// line is a live collections.MutableMap of the cells in one row of a table. It
+// looks the row up on every call, so it stays valid as the row is removed and
+// added again.
+type line[R any, C any, V any] struct {
+	t   *Table[R, C, V]
+	row R
+}
+
+func (l line[R, C, V]) Get(col C) (V, bool) {
+	return l.t.Get(l.row, col)
+}
+
+func (l line[R, C, V]) Put(col C, value V) {
+	l.t.Put(l.row, col, value)
+}
+
+func (l line[R, C, V]) Remove(col C) {
+	l.t.Remove(l.row, col)
+}
+
+func (l line[R, C, V]) Clear() {
+	l.t.RemoveRow(l.row)
+}
+
+func (l line[R, C, V]) Size() int {
+	r, ok := l.t.rows.Get(l.row)
+	if !ok {
+		return 0
+	}
+	return r.Size()
+}
+
+func (l line[R, C, V]) Empty() bool {
+	return !l.t.rows.ContainsKey(l.row)
+}
+
+func (l line[R, C, V]) ContainsKey(col C) bool {
+	return l.t.Contains(l.row, col)
+}
+
+func (l line[R, C, V]) All() iter.Seq2[C, V] {
+	return func(yield func(C, V) bool) {
+		if r, ok := l.t.rows.Get(l.row); ok {
+			for col, v := range r.All() {
+				if !yield(col, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l line[R, C, V]) Keys() iter.Seq[C] {
+	return func(yield func(C) bool) {
+		for col := range l.All() {
+			if !yield(col) {
+				return
+			}
+		}
+	}
+}
+
+func (l line[R, C, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range l.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (l line[R, C, V]) String() string {
+	vals := make([]string, 0)
+	for col, v := range l.All() {
+		vals = append(vals, fmt.Sprintf("%+v: %+v", col, v))
+	}
+	return "{" + strings.Join(vals, ", ") + "}"
+}
+
+func (l line[R, C, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(l.All())
+}
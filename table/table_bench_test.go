@@ -0,0 +1,38 @@
+package table_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections/table"
+)
+
+func BenchmarkTable_Put(b *testing.B) {
+	t := table.NewHash[int, int, int]()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.Put(i%1000, i%997, i)
+	}
+}
+
+func BenchmarkTable_PutTree(b *testing.B) {
+	t := table.NewTree[int, int, int](cmp.Compare[int], cmp.Compare[int])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.Put(i%1000, i%997, i)
+	}
+}
+
+func BenchmarkTable_Column(b *testing.B) {
+	t := table.NewHash[int, int, int]()
+	for r := 0; r < 100; r++ {
+		for c := 0; c < 100; c++ {
+			t.Put(r, c, r*c)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range t.Column(i % 100).All() {
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package table
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		table func() *Table[string, int, float64]
+	}{
+		{name: "hash", table: NewHash[string, int, float64]},
+		{name: "tree", table: func() *Table[string, int, float64] {
+			return NewTree[string, int, float64](cmp.Compare[string], cmp.Compare[int])
+		}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tbl := tc.table()
+			tbl.Put("a", 1, 1.5)
+			tbl.Put("a", 2, 2.5)
+			tbl.Put("b", 1, 3.5)
+			tbl.Put("a", 1, 4.5)
+			if tbl.Size() != 3 || tbl.Transpose().Size() != 3 {
+				t.Errorf("expected size 3, got %d and transposed %d", tbl.Size(), tbl.Transpose().Size())
+			}
+			if v, ok := tbl.Get("a", 1); !ok || v != 4.5 {
+				t.Errorf("expected (a, 1) = 4.5, got %v, %t", v, ok)
+			}
+			if _, ok := tbl.Get("b", 2); ok || tbl.Contains("b", 2) || tbl.Contains("c", 1) {
+				t.Errorf("expected (b, 2) and (c, 1) to be absent")
+			}
+			if got, want := maps.Collect(tbl.Row("a").All()), map[int]float64{1: 4.5, 2: 2.5}; !maps.Equal(got, want) {
+				t.Errorf("expected row a %v, got %v", want, got)
+			}
+			if got, want := maps.Collect(tbl.Column(1).All()), map[string]float64{"a": 4.5, "b": 3.5}; !maps.Equal(got, want) {
+				t.Errorf("expected column 1 %v, got %v", want, got)
+			}
+
+			tbl.Remove("b", 1)
+			tbl.Remove("b", 1)
+			tbl.Remove("z", 1)
+			if tbl.ContainsRow("b") || tbl.Size() != 2 || tbl.Column(1).Size() != 1 {
+				t.Errorf("expected removing the last cell of b to remove the row, got %v", tbl)
+			}
+			tbl.RemoveColumn(2)
+			if tbl.ContainsColumn(2) || tbl.Row("a").ContainsKey(2) || tbl.Size() != 1 {
+				t.Errorf("expected column 2 to be removed, got %v", tbl)
+			}
+			tbl.RemoveRow("a")
+			if !tbl.Empty() || tbl.ContainsColumn(1) || !tbl.Transpose().Empty() {
+				t.Errorf("expected removing row a to empty the table, got %v", tbl)
+			}
+		})
+	}
+}
+
+func TestViews(t *testing.T) {
+	t.Parallel()
+	tbl := NewHash[string, string, int]()
+	row := tbl.Row("r")
+	col := tbl.Column("c")
+	if !row.Empty() || row.Size() != 0 || !col.Empty() {
+		t.Errorf("expected views of absent lines to be empty")
+	}
+	row.Put("c", 1)
+	row.Put("d", 2)
+	if v, ok := col.Get("r"); !ok || v != 1 {
+		t.Errorf("expected a Put through the row view to be visible in the column, got %d, %t", v, ok)
+	}
+	col.Put("s", 3)
+	if got, want := tbl.Size(), 3; got != want {
+		t.Errorf("expected size %d, got %d", want, got)
+	}
+	if got, want := slices.Sorted(col.Keys()), []string{"r", "s"}; !slices.Equal(got, want) {
+		t.Errorf("expected column keys %v, got %v", want, got)
+	}
+	if got, want := slices.Sorted(row.Values()), []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("expected row values %v, got %v", want, got)
+	}
+	row.Remove("d")
+	if tbl.ContainsColumn("d") {
+		t.Errorf("expected removing the last cell of column d to remove it")
+	}
+	col.Clear()
+	if !row.Empty() || !tbl.Empty() {
+		t.Errorf("expected clearing the column to empty the table, got %v", tbl)
+	}
+	tbl.Put("r", "e", 4)
+	if v, ok := row.Get("e"); !ok || v != 4 {
+		t.Errorf("expected the row view to see the row once it is added again")
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	t.Parallel()
+	tbl := NewTree[int, string, bool](cmp.Compare[int], cmp.Compare[string])
+	tr := tbl.Transpose()
+	if tr.Transpose() != tbl {
+		t.Errorf("expected the transpose of the transpose to be the table itself")
+	}
+	tbl.Put(2, "x", true)
+	tbl.Put(1, "y", false)
+	tr.Put("x", 1, true)
+	if got, want := slices.Collect(tbl.RowKeys()), []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("expected row keys %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(tr.RowKeys()), []string{"x", "y"}; !slices.Equal(got, want) {
+		t.Errorf("expected transposed row keys %v, got %v", want, got)
+	}
+	if got, want := slices.Collect(tbl.ColumnKeys()), []string{"x", "y"}; !slices.Equal(got, want) {
+		t.Errorf("expected column keys %v, got %v", want, got)
+	}
+	want := []Cell[string, int, bool]{{"x", 1, true}, {"x", 2, true}, {"y", 1, false}}
+	if got := slices.Collect(tr.Cells()); !slices.Equal(got, want) {
+		t.Errorf("expected cells %v, got %v", want, got)
+	}
+	tr.RemoveRow("x")
+	if got, want := tbl.String(), "{1: {y: false}}"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestCells(t *testing.T) {
+	t.Parallel()
+	tbl := NewTree[int, int, int](cmp.Compare[int], cmp.Compare[int])
+	for r := range 3 {
+		for c := range 3 {
+			tbl.Put(r, c, r*3+c)
+		}
+	}
+	var got []int
+	for cell := range tbl.Cells() {
+		if cell.Value == 5 {
+			break
+		}
+		got = append(got, cell.Value)
+	}
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	tbl := NewTree[string, int, string](cmp.Compare[string], cmp.Compare[int])
+	tbl.Put("b", 2, "b2")
+	tbl.Put("a", 1, "a1")
+	tbl.Put("a", 3, "a3")
+	data, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":{"1":"a1","3":"a3"},"b":{"2":"b2"}}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+	data, err = json.Marshal(tbl.Column(1))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"a":"a1"}`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	got := NewHash[string, int, string]()
+	got.Put("stale", 0, "")
+	if err := json.Unmarshal([]byte(`{"a":{"1":"a1","3":"a3"},"b":{"2":"b2"}}`), got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Size() != 3 || got.ContainsRow("stale") {
+		t.Errorf("expected 3 decoded cells, got %v", got)
+	}
+	if v, _ := got.Get("a", 3); v != "a3" {
+		t.Errorf("expected (a, 3) = a3, got %q", v)
+	}
+	if got.Column(2).Size() != 1 {
+		t.Errorf("expected decoding to fill the columns too")
+	}
+	if err := json.Unmarshal([]byte(`{"c":{"x":"c1"}}`), got); err == nil {
+		t.Errorf("expected an error for a malformed column key")
+	}
+	if got.Size() != 3 {
+		t.Errorf("expected a failed decode to leave the table unchanged, got %v", got)
+	}
+	if err := json.Unmarshal([]byte(`null`), got); err != nil || got.Size() != 3 {
+		t.Errorf("expected null to be a no-op, got %v, %v", err, got)
+	}
+}
+
+func TestUnmarshalJSONZeroValue(t *testing.T) {
+	t.Parallel()
+	var tb Table[string, string, int]
+	if err := json.Unmarshal([]byte(`{"r1":{"c1":1,"c2":2},"r2":{"c1":3}}`), &tb); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if v, ok := tb.Get("r2", "c1"); !ok || v != 3 || tb.Size() != 3 {
+		t.Errorf("expected 3 cells with r2, c1 = 3, got %v", &tb)
+	}
+	if want := map[string]int{"r1": 1, "r2": 3}; !maps.Equal(maps.Collect(tb.Column("c1").All()), want) {
+		t.Errorf("expected column c1 %v, got %v", want, maps.Collect(tb.Column("c1").All()))
+	}
+	if tb.Transpose().Transpose() != &tb {
+		t.Error("expected the transpose of the transpose to be the decoded table")
+	}
+	data, err := json.Marshal(&tb)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var again Table[string, string, int]
+	if err := json.Unmarshal(data, &again); err != nil || again.Size() != 3 {
+		t.Fatalf("expected the decoded table to marshal back to its 3 cells, got %s, %v", data, err)
+	}
+	for row := range tb.RowKeys() {
+		if got, want := maps.Collect(again.Row(row).All()), maps.Collect(tb.Row(row).All()); !maps.Equal(got, want) {
+			t.Errorf("row %s: expected %v after a round trip, got %v", row, want, got)
+		}
+	}
+	tb.Transpose().Put("c3", "r1", 4)
+	if v, ok := tb.Get("r1", "c3"); !ok || v != 4 {
+		t.Errorf("expected a put through the transpose to be visible, got %d, %t", v, ok)
+	}
+
+	var unhashable Table[string, []int, int]
+	if err := json.Unmarshal([]byte(`{"r":[[[1],1]]}`), &unhashable); err == nil {
+		t.Error("expected an error decoding into a Table with column keys that are not comparable")
+	}
+	var fields struct {
+		Grid *Table[string, string, int] `json:"grid"`
+	}
+	if err := json.Unmarshal([]byte(`{"grid":{"a":{"b":1}}}`), &fields); err != nil || fields.Grid.Size() != 1 {
+		t.Errorf("expected a struct field to decode, got %v, %v", fields.Grid, err)
+	}
+}
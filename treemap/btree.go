@@ -22,6 +22,75 @@ func (tm *TreeMap[K, V]) newNode(leaf bool) *node[K, V] {
 	}
 }
 
+// build replaces the contents of the B-Tree with the given pairs, which must be
+// in strictly ascending key order. The tree is assembled bottom-up one level at a
+// time: each level is split into as few nodes as possible, with the entries between
+// adjacent nodes reserved as separators for the level above.
+func (tm *TreeMap[K, V]) build(keys []K, values []V) {
+	tm.size = len(keys)
+	fanout := 2 * tm.degree
+	if len(keys) < fanout {
+		root := tm.newNode(true)
+		root.keys = append(root.keys, keys...)
+		root.values = append(root.values, values...)
+		tm.root = root
+		return
+	}
+
+	// Every leaf but the last is followed by a separator, so each leaf
+	// accounts for at most 2t entries.
+	leafCount := (len(keys) + fanout) / fanout
+	entries := len(keys) - (leafCount - 1)
+	level := make([]*node[K, V], 0, leafCount)
+	sepKeys := make([]K, 0, leafCount-1)
+	sepValues := make([]V, 0, leafCount-1)
+	i := 0
+	for j := 0; j < leafCount; j++ {
+		n := entries / leafCount
+		if j < entries%leafCount {
+			n++
+		}
+		leaf := tm.newNode(true)
+		leaf.keys = append(leaf.keys, keys[i:i+n]...)
+		leaf.values = append(leaf.values, values[i:i+n]...)
+		level = append(level, leaf)
+		i += n
+		if j < leafCount-1 {
+			sepKeys = append(sepKeys, keys[i])
+			sepValues = append(sepValues, values[i])
+			i++
+		}
+	}
+
+	for len(level) > 1 {
+		parentCount := (len(level) + fanout - 1) / fanout
+		parents := make([]*node[K, V], 0, parentCount)
+		nextSepKeys := make([]K, 0, parentCount-1)
+		nextSepValues := make([]V, 0, parentCount-1)
+		c := 0
+		for j := 0; j < parentCount; j++ {
+			n := len(level) / parentCount
+			if j < len(level)%parentCount {
+				n++
+			}
+			parent := tm.newNode(false)
+			parent.children = append(parent.children, level[c:c+n]...)
+			parent.keys = append(parent.keys, sepKeys[c:c+n-1]...)
+			parent.values = append(parent.values, sepValues[c:c+n-1]...)
+			parents = append(parents, parent)
+			c += n
+			if j < parentCount-1 {
+				nextSepKeys = append(nextSepKeys, sepKeys[c-1])
+				nextSepValues = append(nextSepValues, sepValues[c-1])
+			}
+		}
+		level = parents
+		sepKeys = nextSepKeys
+		sepValues = nextSepValues
+	}
+	tm.root = level[0]
+}
+
 // Get searches for a key in the B-Tree.
 func (tm *TreeMap[K, V]) get(n *node[K, V], key K) (V, bool) {
 	i, found := slices.BinarySearchFunc(n.keys, key, tm.comparator)
@@ -53,6 +122,7 @@ func (tm *TreeMap[K, V]) put(key K, value V) {
 		tm.insertNonFull(root, key, value)
 	}
 	tm.size++
+	tm.modCount++
 }
 
 func (tm *TreeMap[K, V]) updateExisting(n *node[K, V], key K, value V) bool {
@@ -133,6 +203,7 @@ func (tm *TreeMap[K, V]) remove(key K) {
 		}
 	}
 	tm.size--
+	tm.modCount++
 }
 
 func (tm *TreeMap[K, V]) deleteNode(x *node[K, V], key K) {
@@ -0,0 +1,111 @@
+package treemap
+
+import (
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+)
+
+var _ collections.MapCursor[int, int] = (*cursor[int, int])(nil)
+
+type position int
+
+const (
+	outside position = iota
+	entry
+	gap
+)
+
+// Cursor returns a cursor positioned outside the map. The cursor remembers
+// the key it is on rather than a location in the tree, so each move is a
+// O(log n) search and Remove does not disturb it.
+func (tm *TreeMap[K, V]) Cursor() collections.MapCursor[K, V] {
+	return &cursor[K, V]{
+		tm:       tm,
+		modCount: tm.modCount,
+	}
+}
+
+// cursor is a collections.MapCursor over a TreeMap. When the cursor is on an
+// entry, key and value hold it; in a gap, key holds the removed key.
+type cursor[K any, V any] struct {
+	tm       *TreeMap[K, V]
+	position position
+	key      K
+	value    V
+	modCount int
+}
+
+func (c *cursor[K, V]) Next() bool {
+	c.check()
+	if c.position == outside {
+		if c.tm.Empty() {
+			return false
+		}
+		c.key, c.value = c.tm.First()
+		c.position = entry
+		return true
+	}
+	return c.moveTo(c.tm.Higher(c.key))
+}
+
+func (c *cursor[K, V]) Prev() bool {
+	c.check()
+	if c.position == outside {
+		if c.tm.Empty() {
+			return false
+		}
+		c.key, c.value = c.tm.Last()
+		c.position = entry
+		return true
+	}
+	return c.moveTo(c.tm.Lower(c.key))
+}
+
+func (c *cursor[K, V]) Key() K {
+	c.checkEntry()
+	return c.key
+}
+
+func (c *cursor[K, V]) Value() V {
+	c.checkEntry()
+	return c.value
+}
+
+func (c *cursor[K, V]) SetValue(v V) {
+	c.checkEntry()
+	c.tm.updateExisting(c.tm.root, c.key, v)
+	c.value = v
+}
+
+func (c *cursor[K, V]) Remove() {
+	c.checkEntry()
+	c.tm.remove(c.key)
+	var zero V
+	c.value = zero
+	c.position = gap
+	c.modCount = c.tm.modCount
+}
+
+func (c *cursor[K, V]) moveTo(k K, v V, ok bool) bool {
+	if !ok {
+		var zeroK K
+		var zeroV V
+		c.key, c.value = zeroK, zeroV
+		c.position = outside
+		return false
+	}
+	c.key, c.value = k, v
+	c.position = entry
+	return true
+}
+
+func (c *cursor[K, V]) check() {
+	failfast.Check(c.modCount, c.tm.modCount)
+}
+
+func (c *cursor[K, V]) checkEntry() {
+	c.check()
+	if c.position != entry {
+		panic("cursor is not positioned on an entry")
+	}
+}
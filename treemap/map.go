@@ -1,7 +1,10 @@
 package treemap
 
 import (
+	"fmt"
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"slices"
 )
@@ -35,6 +38,7 @@ func (tm *TreeMap[K, V]) Empty() bool {
 func (tm *TreeMap[K, V]) Clear() {
 	tm.root = tm.newNode(true)
 	tm.size = 0
+	tm.modCount++
 }
 
 func (tm *TreeMap[K, V]) ContainsKey(key K) bool {
@@ -44,7 +48,73 @@ func (tm *TreeMap[K, V]) ContainsKey(key K) bool {
 
 func (tm *TreeMap[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		tm.inOrder(tm.root, yield)
+		tm.inOrder(tm.root, tm.checked(yield))
+	}
+}
+
+// MarshalJSON encodes the map as a JSON object with its entries in ascending
+// key order, or as a JSON array of [key, value] pairs if K cannot be an object
+// key.
+func (tm *TreeMap[K, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(tm.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object or array of [key, value] pairs. When called on the zero value, the map
+// orders keys of ordered types by their natural order.
+func (tm *TreeMap[K, V]) UnmarshalJSON(data []byte) error {
+	if err := tm.ensureComparator(); err != nil {
+		return err
+	}
+	return codec.UnmarshalMap(data, tm.Clear, tm.Put)
+}
+
+var binaryFormat = codec.Format{Name: "treemap", Magic: "TMAP", Version: 1}
+
+// MarshalBinary encodes the map in a versioned binary format built on
+// encoding/gob, so K and V must be encodable by gob. The comparator is not
+// encoded.
+func (tm *TreeMap[K, V]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinaryMap(binaryFormat, tm.All())
+}
+
+// UnmarshalBinary replaces the contents of the map with data produced by
+// MarshalBinary. When called on the zero value, the map orders keys of ordered
+// types by their natural order.
+func (tm *TreeMap[K, V]) UnmarshalBinary(data []byte) error {
+	if err := tm.ensureComparator(); err != nil {
+		return err
+	}
+	return codec.UnmarshalBinaryMap(binaryFormat, data, tm.Clear, tm.Put)
+}
+
+// ensureComparator initializes a zero-value map with the natural order of K so
+// that it can be decoded into.
+func (tm *TreeMap[K, V]) ensureComparator() error {
+	if tm.comparator != nil {
+		return nil
+	}
+	c, ok := codec.NaturalOrder[K]()
+	if !ok {
+		return fmt.Errorf("treemap: cannot unmarshal into a TreeMap without a comparator")
+	}
+	*tm = *New[K, V](WithComparator(c))
+	return nil
+}
+
+// checked wraps yield so that the iteration panics if the map is structurally
+// modified while the loop body runs.
+func (tm *TreeMap[K, V]) checked(yield func(K, V) bool) func(K, V) bool {
+	if !failfast.Enabled {
+		return yield
+	}
+	modCount := tm.modCount
+	return func(k K, v V) bool {
+		if !yield(k, v) {
+			return false
+		}
+		failfast.Check(modCount, tm.modCount)
+		return true
 	}
 }
 
@@ -237,7 +307,7 @@ func (tm *TreeMap[K, V]) Higher(key K) (K, V, bool) {
 
 func (tm *TreeMap[K, V]) Backward() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		tm.reverseInOrder(tm.root, yield)
+		tm.reverseInOrder(tm.root, tm.checked(yield))
 	}
 }
 
@@ -286,20 +356,20 @@ func (tm *TreeMap[K, V]) BackwardValues() iter.Seq[V] {
 func (tm *TreeMap[K, V]) From(from K) iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
 		var zero K
-		tm.rangeInOrder(tm.root, from, zero, true, false, yield)
+		tm.rangeInOrder(tm.root, from, zero, true, false, tm.checked(yield))
 	}
 }
 
 func (tm *TreeMap[K, V]) To(to K) iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
 		var zero K
-		tm.rangeInOrder(tm.root, zero, to, false, true, yield)
+		tm.rangeInOrder(tm.root, zero, to, false, true, tm.checked(yield))
 	}
 }
 
 func (tm *TreeMap[K, V]) Between(from K, to K) iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		tm.rangeInOrder(tm.root, from, to, true, true, yield)
+		tm.rangeInOrder(tm.root, from, to, true, true, tm.checked(yield))
 	}
 }
 
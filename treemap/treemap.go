@@ -4,6 +4,7 @@ package treemap
 import (
 	"cmp"
 	"github.com/lock14/collections/comparator"
+	"iter"
 )
 
 const (
@@ -35,11 +36,15 @@ func WithComparator[K any](cmpFunc comparator.Comparator[K]) Option[K] {
 }
 
 // TreeMap is a B-Tree backed map that implements collections.MutableMap.
+// Iterators panic with collections.ErrConcurrentModification if a key is
+// added or removed while they are running. Replacing the value of an existing
+// key is not a structural modification.
 type TreeMap[K any, V any] struct {
 	root       *node[K, V]
 	size       int
 	degree     int
 	comparator comparator.Comparator[K]
+	modCount   int
 }
 
 // New creates an empty TreeMap with the given options.
@@ -68,3 +73,40 @@ func New[K any, V any](opts ...Option[K]) *TreeMap[K, V] {
 func NewOrdered[K cmp.Ordered, V any](opts ...Option[K]) *TreeMap[K, V] {
 	return New[K, V](append(opts, WithComparator(comparator.NaturalOrder[K]()))...)
 }
+
+// FromSeq creates a TreeMap containing the key-value pairs of the given sequence.
+// Later pairs overwrite earlier pairs with equal keys. When the sequence is produced
+// in strictly ascending key order the B-Tree is built bottom-up in linear time;
+// otherwise the pairs are inserted one at a time.
+func FromSeq[K any, V any](sequence iter.Seq2[K, V], opts ...Option[K]) *TreeMap[K, V] {
+	tm := New[K, V](opts...)
+	var keys []K
+	var values []V
+	for k, v := range sequence {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if tm.strictlyAscending(keys) {
+		tm.build(keys, values)
+	} else {
+		for i := range keys {
+			tm.put(keys[i], values[i])
+		}
+	}
+	return tm
+}
+
+// FromSeqOrdered creates a TreeMap for keys that satisfy cmp.Ordered using natural ordering,
+// containing the key-value pairs of the given sequence.
+func FromSeqOrdered[K cmp.Ordered, V any](sequence iter.Seq2[K, V], opts ...Option[K]) *TreeMap[K, V] {
+	return FromSeq(sequence, append(opts, WithComparator(comparator.NaturalOrder[K]()))...)
+}
+
+func (tm *TreeMap[K, V]) strictlyAscending(keys []K) bool {
+	for i := 1; i < len(keys); i++ {
+		if tm.comparator(keys[i-1], keys[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
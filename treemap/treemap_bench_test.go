@@ -77,3 +77,34 @@ func BenchmarkBuiltinMap_Get(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkTreeMap_FromSeq(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		for _, sorted := range []bool{true, false} {
+			b.Run(fmt.Sprintf("size_%d_sorted_%t", size, sorted), func(b *testing.B) {
+				b.StopTimer()
+				keys := make([]int, size)
+				for i := 0; i < size; i++ {
+					keys[i] = i
+				}
+				if !sorted {
+					rand.Shuffle(size, func(i, j int) {
+						keys[i], keys[j] = keys[j], keys[i]
+					})
+				}
+				entries := func(yield func(int, int) bool) {
+					for _, k := range keys {
+						if !yield(k, k) {
+							return
+						}
+					}
+				}
+
+				b.StartTimer()
+				for i := 0; i < b.N; i++ {
+					treemap.FromSeqOrdered(entries)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package treemap_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/treemap"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableNavigableMap(t, func() collections.MutableNavigableMap[int, int] {
+		return treemap.NewOrdered[int, int]()
+	}, cmp.Compare[int], collectionstest.Ints(50), collectionstest.Ints(10))
+	collectionstest.MutableNavigableMap(t, func() collections.MutableNavigableMap[int, int] {
+		return treemap.NewOrdered[int, int](treemap.WithDegree[int](2))
+	}, cmp.Compare[int], collectionstest.Ints(50), collectionstest.Ints(10))
+}
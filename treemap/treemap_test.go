@@ -1,11 +1,15 @@
 package treemap
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
 	"iter"
 	"math/rand"
 	"runtime"
 	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -550,3 +554,264 @@ func TestTreeMap_MemoryLeak(t *testing.T) {
 		})
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		degree int
+		keys   []int
+	}{
+		{name: "empty", degree: 2, keys: nil},
+		{name: "single_leaf", degree: 3, keys: []int{1, 2, 3, 4, 5}},
+		{name: "two_leaves", degree: 2, keys: []int{1, 2, 3, 4}},
+		{name: "sorted_small_degree", degree: 2, keys: sequential(1000)},
+		{name: "sorted_default_degree", degree: DefaultDegree, keys: sequential(100000)},
+		{name: "sorted_exact_fanout", degree: 3, keys: sequential(36)},
+		{name: "unsorted", degree: 2, keys: rand.Perm(500)},
+		{name: "duplicates", degree: 2, keys: []int{5, 1, 5, 3, 1, 5, 2}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// Each pair's value is its position, so duplicates must keep the last position.
+			last := make(map[int]int)
+			for i, k := range tc.keys {
+				last[k] = i
+			}
+			tm := FromSeqOrdered(func(yield func(int, int) bool) {
+				for i, k := range tc.keys {
+					if !yield(k, i) {
+						return
+					}
+				}
+			}, WithDegree[int](tc.degree))
+
+			if tm.Size() != len(last) {
+				t.Fatalf("expected size %d, got %d", len(last), tm.Size())
+			}
+			checkInvariants(t, tm)
+			expectedKeys := slices.Sorted(func(yield func(int) bool) {
+				for k := range last {
+					if !yield(k) {
+						return
+					}
+				}
+			})
+			if !slices.Equal(slices.Collect(tm.Keys()), expectedKeys) {
+				t.Fatalf("keys out of order")
+			}
+			for k, i := range last {
+				if v, ok := tm.Get(k); !ok || v != i {
+					t.Fatalf("expected %d -> %d, got %d, %v", k, i, v, ok)
+				}
+			}
+
+			// The bulk-built tree must remain fully mutable.
+			for k := range last {
+				tm.Remove(k)
+				checkInvariants(t, tm)
+				if tm.Size() > 64 {
+					break
+				}
+			}
+			tm.Put(-1, -1)
+			if v, ok := tm.Get(-1); !ok || v != -1 {
+				t.Fatalf("expected to find inserted key")
+			}
+			checkInvariants(t, tm)
+		})
+	}
+}
+
+func sequential(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+// checkInvariants verifies the structural properties of the B-Tree: key counts
+// per node, uniform leaf depth and key ordering.
+func checkInvariants[V any](t *testing.T, tm *TreeMap[int, V]) {
+	t.Helper()
+	leafDepth := -1
+	var walk func(n *node[int, V], depth int, isRoot bool)
+	walk = func(n *node[int, V], depth int, isRoot bool) {
+		if len(n.keys) > 2*tm.degree-1 {
+			t.Fatalf("node has %d keys, more than the maximum %d", len(n.keys), 2*tm.degree-1)
+		}
+		if !isRoot && len(n.keys) < tm.degree-1 {
+			t.Fatalf("node has %d keys, fewer than the minimum %d", len(n.keys), tm.degree-1)
+		}
+		if len(n.keys) != len(n.values) {
+			t.Fatalf("node has %d keys but %d values", len(n.keys), len(n.values))
+		}
+		if !slices.IsSorted(n.keys) {
+			t.Fatalf("node keys are not sorted: %v", n.keys)
+		}
+		if n.leaf {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Fatalf("leaves at depths %d and %d", leafDepth, depth)
+			}
+			return
+		}
+		if len(n.children) != len(n.keys)+1 {
+			t.Fatalf("node has %d keys but %d children", len(n.keys), len(n.children))
+		}
+		for _, c := range n.children {
+			walk(c, depth+1, false)
+		}
+	}
+	walk(tm.root, 0, true)
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(m *TreeMap[int, int])
+		panics bool
+	}{
+		{name: "put new key", modify: func(m *TreeMap[int, int]) { m.Put(-1, 0) }, panics: true},
+		{name: "put existing key", modify: func(m *TreeMap[int, int]) { m.Put(5, 50) }, panics: false},
+		{name: "remove", modify: func(m *TreeMap[int, int]) { m.Remove(50) }, panics: true},
+		{name: "remove absent", modify: func(m *TreeMap[int, int]) { m.Remove(-1) }, panics: false},
+		{name: "poll first", modify: func(m *TreeMap[int, int]) { m.PollFirst() }, panics: true},
+		{name: "clear", modify: func(m *TreeMap[int, int]) { m.Clear() }, panics: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for _, it := range []struct {
+				name string
+				seq  func(m *TreeMap[int, int]) iter.Seq2[int, int]
+			}{
+				{name: "all", seq: (*TreeMap[int, int]).All},
+				{name: "backward", seq: (*TreeMap[int, int]).Backward},
+				{name: "from", seq: func(m *TreeMap[int, int]) iter.Seq2[int, int] { return m.From(10) }},
+				{name: "to", seq: func(m *TreeMap[int, int]) iter.Seq2[int, int] { return m.To(90) }},
+				{name: "between", seq: func(m *TreeMap[int, int]) iter.Seq2[int, int] { return m.Between(10, 90) }},
+			} {
+				m := FromSeqOrdered(slices.All(sequential(100)), WithDegree[int](2))
+				t.Run(it.name, func(t *testing.T) {
+					assertConcurrentModification(t, tc.panics, func() {
+						for range it.seq(m) {
+							tc.modify(m)
+						}
+					})
+				})
+			}
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+type celsius float64
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+		tm := NewOrdered[int, string]()
+		tm.Put(10, "b")
+		tm.Put(2, "a")
+		data, err := json.Marshal(tm)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `{"2":"a","10":"b"}`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		var got TreeMap[int, string]
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		got.Put(5, "c")
+		if keys, want := slices.Collect(got.Keys()), []int{2, 5, 10}; !slices.Equal(keys, want) {
+			t.Errorf("expected keys %v, got %v", want, keys)
+		}
+	})
+	t.Run("pairs", func(t *testing.T) {
+		t.Parallel()
+		tm := New[celsius, int](WithComparator(func(a, b celsius) int { return int(a - b) }))
+		tm.Put(1.5, 1)
+		tm.Put(-3, 2)
+		data, err := json.Marshal(tm)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `[[-3,2],[1.5,1]]`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		var got TreeMap[celsius, int]
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if keys, want := slices.Collect(got.Keys()), []celsius{-3, 1.5}; !slices.Equal(keys, want) {
+			t.Errorf("expected keys %v, got %v", want, keys)
+		}
+	})
+}
+
+func TestUnmarshalJSONWithoutComparator(t *testing.T) {
+	t.Parallel()
+	var tm TreeMap[struct{ X int }, int]
+	if err := json.Unmarshal([]byte(`[[{"X":1},1]]`), &tm); err == nil {
+		t.Errorf("expected an error for a key type without a natural order")
+	}
+}
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	tm := NewOrdered[string, int]()
+	for i, k := range []string{"c", "a", "b"} {
+		tm.Put(k, i)
+	}
+	data, err := tm.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	t.Run("zero_value", func(t *testing.T) {
+		t.Parallel()
+		var got TreeMap[string, int]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		if keys, want := slices.Collect(got.Keys()), []string{"a", "b", "c"}; !slices.Equal(keys, want) {
+			t.Errorf("expected keys %v, got %v", want, keys)
+		}
+	})
+	t.Run("comparator", func(t *testing.T) {
+		t.Parallel()
+		got := New[string, int](WithComparator(func(a, b string) int { return -strings.Compare(a, b) }))
+		got.Put("stale", 0)
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		if keys, want := slices.Collect(got.Keys()), []string{"c", "b", "a"}; !slices.Equal(keys, want) {
+			t.Errorf("expected keys %v, got %v", want, keys)
+		}
+	})
+}
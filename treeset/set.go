@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/cursor"
+	"github.com/lock14/collections/treemap"
 )
 
 var _ collections.MutableNavigableSet[int] = (*TreeSet[int])(nil)
@@ -53,6 +56,11 @@ func (s *TreeSet[T]) AddAll(sequence iter.Seq[T]) {
 
 // RemoveAll removes all elements of the specified collection from this set.
 func (s *TreeSet[T]) RemoveAll(other collections.Collection[T]) {
+	if o, ok := other.(*TreeSet[T]); ok && o == s {
+		// Removing elements while iterating over the same set is a concurrent modification.
+		s.Clear()
+		return
+	}
 	for t := range other.All() {
 		s.RemoveElement(t)
 	}
@@ -178,6 +186,12 @@ func (s *TreeSet[T]) Between(from, to T) iter.Seq[T] {
 	}
 }
 
+// Cursor returns a cursor positioned outside the set. Each move is a
+// O(log n) search.
+func (s *TreeSet[T]) Cursor() collections.Cursor[T] {
+	return cursor.Keys[T, struct{}]{MapCursor: s.m.Cursor()}
+}
+
 // String returns a string representation of the set.
 func (s *TreeSet[T]) String() string {
 	vals := make([]string, 0, s.Size())
@@ -186,3 +200,23 @@ func (s *TreeSet[T]) String() string {
 	}
 	return "[" + strings.Join(vals, ", ") + "]"
 }
+
+// MarshalJSON encodes the set as a JSON array of its elements in ascending
+// order.
+func (s *TreeSet[T]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(s.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array. When called on the zero value, the set orders elements of ordered
+// types by their natural order.
+func (s *TreeSet[T]) UnmarshalJSON(data []byte) error {
+	if s.m == nil {
+		c, ok := codec.NaturalOrder[T]()
+		if !ok {
+			return fmt.Errorf("treeset: cannot unmarshal into a TreeSet without a comparator")
+		}
+		s.m = treemap.New[T, struct{}](treemap.WithComparator(c))
+	}
+	return codec.UnmarshalSeq(data, s.Clear, s.Add)
+}
@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"github.com/lock14/collections/comparator"
 	"github.com/lock14/collections/treemap"
+	"iter"
 )
 
 // TreeSet represents a set of elements backed by a B-Tree.
@@ -36,6 +37,41 @@ func WithComparator[T any](comp comparator.Comparator[T]) Option[T] {
 
 // New creates an empty TreeSet.
 func New[T any](opts ...Option[T]) *TreeSet[T] {
+	return &TreeSet[T]{
+		m: treemap.New[T, struct{}](mapOptions(opts)...),
+	}
+}
+
+// FromSeq creates a TreeSet containing the elements of the given sequence.
+// The underlying B-Tree is built bottom-up in linear time when the sequence is
+// produced in strictly ascending order.
+func FromSeq[T any](sequence iter.Seq[T], opts ...Option[T]) *TreeSet[T] {
+	entries := func(yield func(T, struct{}) bool) {
+		for t := range sequence {
+			if !yield(t, struct{}{}) {
+				return
+			}
+		}
+	}
+	return &TreeSet[T]{
+		m: treemap.FromSeq(entries, mapOptions(opts)...),
+	}
+}
+
+// NewOrdered creates an empty TreeSet for types that implement cmp.Ordered.
+func NewOrdered[T cmp.Ordered](opts ...Option[T]) *TreeSet[T] {
+	opts = append([]Option[T]{WithComparator(comparator.NaturalOrder[T]())}, opts...)
+	return New(opts...)
+}
+
+// FromSeqOrdered creates a TreeSet for types that implement cmp.Ordered,
+// containing the elements of the given sequence.
+func FromSeqOrdered[T cmp.Ordered](sequence iter.Seq[T], opts ...Option[T]) *TreeSet[T] {
+	opts = append([]Option[T]{WithComparator(comparator.NaturalOrder[T]())}, opts...)
+	return FromSeq(sequence, opts...)
+}
+
+func mapOptions[T any](opts []Option[T]) []treemap.Option[T] {
 	config := &config[T]{}
 	for _, option := range opts {
 		option(config)
@@ -48,14 +84,5 @@ func New[T any](opts ...Option[T]) *TreeSet[T] {
 	if config.comparator != nil {
 		mapOpts = append(mapOpts, treemap.WithComparator[T](config.comparator))
 	}
-
-	return &TreeSet[T]{
-		m: treemap.New[T, struct{}](mapOpts...),
-	}
-}
-
-// NewOrdered creates an empty TreeSet for types that implement cmp.Ordered.
-func NewOrdered[T cmp.Ordered](opts ...Option[T]) *TreeSet[T] {
-	opts = append([]Option[T]{WithComparator(comparator.NaturalOrder[T]())}, opts...)
-	return New(opts...)
+	return mapOpts
 }
@@ -0,0 +1,20 @@
+package treeset_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/treeset"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableNavigableSet(t, func() collections.MutableNavigableSet[int] {
+		return treeset.NewOrdered[int]()
+	}, cmp.Compare[int], collectionstest.Ints(50))
+	collectionstest.MutableNavigableSet(t, func() collections.MutableNavigableSet[int] {
+		return treeset.NewOrdered[int](treeset.WithDegree[int](2))
+	}, cmp.Compare[int], collectionstest.Ints(50))
+}
@@ -1,10 +1,12 @@
 package treeset
 
 import (
+	"encoding/json"
 	"slices"
 	"testing"
 
 	"github.com/lock14/collections/arraylist"
+	"github.com/lock14/collections/comparator"
 )
 
 func TestTreeSet_Operations(t *testing.T) {
@@ -428,3 +430,106 @@ func TestTreeSet_Iterators_Bounds(t *testing.T) {
 		break
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		set      func() *TreeSet[int]
+		expected []int
+	}{
+		{
+			name:     "empty",
+			set:      func() *TreeSet[int] { return FromSeqOrdered(slices.Values([]int{})) },
+			expected: nil,
+		},
+		{
+			name:     "sorted",
+			set:      func() *TreeSet[int] { return FromSeqOrdered(slices.Values([]int{1, 2, 3, 4, 5}), WithDegree[int](2)) },
+			expected: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "unsorted_with_duplicates",
+			set:      func() *TreeSet[int] { return FromSeqOrdered(slices.Values([]int{4, 2, 4, 1, 2}), WithDegree[int](2)) },
+			expected: []int{1, 2, 4},
+		},
+		{
+			name: "custom_comparator",
+			set: func() *TreeSet[int] {
+				return FromSeq(slices.Values([]int{1, 3, 2}), WithComparator(comparator.Reverse(comparator.NaturalOrder[int]())))
+			},
+			expected: []int{3, 2, 1},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := tc.set()
+			if actual := slices.Collect(s.All()); !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+			s.Add(100)
+			if !s.Contains(100) || s.Size() != len(tc.expected)+1 {
+				t.Errorf("expected set to remain mutable")
+			}
+		})
+	}
+}
+
+func TestRemoveAllSelf(t *testing.T) {
+	t.Parallel()
+	s := FromSeqOrdered(slices.Values([]int{1, 2, 3}))
+	s.RemoveAll(s)
+	if !s.Empty() {
+		t.Errorf("expected empty set, got %v", s)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name string
+		set  func() *TreeSet[int]
+		json string
+		want []int
+	}{
+		{name: "zero_value_uses_natural_order", set: func() *TreeSet[int] { return &TreeSet[int]{} }, json: "[1,2,3]", want: []int{1, 2, 3}},
+		{
+			name: "keeps_comparator",
+			set: func() *TreeSet[int] {
+				return New(WithComparator(comparator.Reverse(comparator.NaturalOrder[int]())))
+			},
+			json: "[1,2,3]",
+			want: []int{3, 2, 1},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(FromSeqOrdered(slices.Values([]int{3, 1, 2})))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tc.json {
+				t.Errorf("expected %s, got %s", tc.json, data)
+			}
+			s := tc.set()
+			if err := json.Unmarshal(data, s); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := slices.Collect(s.All()); !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONWithoutComparator(t *testing.T) {
+	t.Parallel()
+	var s TreeSet[struct{ X int }]
+	if err := json.Unmarshal([]byte(`[{"X":1}]`), &s); err == nil {
+		t.Errorf("expected an error for an element type without a natural order")
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"strings"
 
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 )
 
-var _ Map[[]int, int] = (*sliceMap[int, int])(nil)
+var _ Map[[]int, int] = (*SliceMap[int, int])(nil)
 
 type sliceNode[E comparable, V any] struct {
 	children map[E]*sliceNode[E, V]
@@ -16,18 +18,22 @@ type sliceNode[E comparable, V any] struct {
 	hasValue bool
 }
 
-type sliceMap[E comparable, V any] struct {
-	root *sliceNode[E, V]
-	size int
+// SliceMap is a Map for slice keys, which holds one trie node per element of
+// its keys. Create one with NewSliceMap or SliceMapFromSeq. The zero value for
+// SliceMap is an empty map ready to use.
+type SliceMap[E comparable, V any] struct {
+	root     *sliceNode[E, V]
+	size     int
+	modCount int
 }
 
-func newSliceMap[E comparable, V any]() *sliceMap[E, V] {
-	return &sliceMap[E, V]{
+func newSliceMap[E comparable, V any]() *SliceMap[E, V] {
+	return &SliceMap[E, V]{
 		root: &sliceNode[E, V]{},
 	}
 }
 
-func (m *sliceMap[E, V]) Get(key []E) (V, bool) {
+func (m *SliceMap[E, V]) Get(key []E) (V, bool) {
 	node := m.getNode(key)
 	if node != nil {
 		return node.value, node.hasValue
@@ -36,7 +42,10 @@ func (m *sliceMap[E, V]) Get(key []E) (V, bool) {
 	return zero, false
 }
 
-func (m *sliceMap[E, V]) Put(key []E, value V) {
+func (m *SliceMap[E, V]) Put(key []E, value V) {
+	if m.root == nil {
+		m.root = &sliceNode[E, V]{}
+	}
 	node := m.root
 	for i := 0; i < len(key); i++ {
 		if node.children == nil {
@@ -52,18 +61,20 @@ func (m *sliceMap[E, V]) Put(key []E, value V) {
 	}
 	if !node.hasValue {
 		m.size++
+		m.modCount++
 		node.hasValue = true
 	}
 	node.value = value
 }
 
-func (m *sliceMap[E, V]) Remove(key []E) {
-	if m.removeNode(m.root, key, 0) {
+func (m *SliceMap[E, V]) Remove(key []E) {
+	if m.root != nil && m.removeNode(m.root, key, 0) {
 		m.size--
+		m.modCount++
 	}
 }
 
-func (m *sliceMap[E, V]) removeNode(node *sliceNode[E, V], key []E, depth int) bool {
+func (m *SliceMap[E, V]) removeNode(node *sliceNode[E, V], key []E, depth int) bool {
 	if depth == len(key) {
 		if !node.hasValue {
 			return false
@@ -90,31 +101,73 @@ func (m *sliceMap[E, V]) removeNode(node *sliceNode[E, V], key []E, depth int) b
 	return removed
 }
 
-func (m *sliceMap[E, V]) Size() int {
+func (m *SliceMap[E, V]) Size() int {
 	return m.size
 }
 
-func (m *sliceMap[E, V]) Empty() bool {
+func (m *SliceMap[E, V]) Empty() bool {
 	return m.size == 0
 }
 
-func (m *sliceMap[E, V]) Clear() {
+func (m *SliceMap[E, V]) Clear() {
 	m.root = &sliceNode[E, V]{}
 	m.size = 0
+	m.modCount++
 }
 
-func (m *sliceMap[E, V]) ContainsKey(key []E) bool {
+func (m *SliceMap[E, V]) ContainsKey(key []E) bool {
 	node := m.getNode(key)
 	return node != nil && node.hasValue
 }
 
-func (m *sliceMap[E, V]) All() iter.Seq2[[]E, V] {
+func (m *SliceMap[E, V]) All() iter.Seq2[[]E, V] {
 	return func(yield func([]E, V) bool) {
-		m.iterate(m.root, nil, yield)
+		m.iterate(m.root, nil, m.checked(yield))
+	}
+}
+
+// MarshalJSON encodes the map as a JSON array of [key, value] pairs.
+func (m *SliceMap[E, V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(m.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// array of [key, value] pairs.
+func (m *SliceMap[E, V]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalMap(data, m.Clear, m.Put)
+}
+
+var sliceMapFormat = codec.Format{Name: "trie", Magic: "TRLM", Version: 1}
+
+// MarshalBinary encodes the map in a versioned binary format built on
+// encoding/gob, so E and V must be encodable by gob.
+func (m *SliceMap[E, V]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinaryMap(sliceMapFormat, m.All())
+}
+
+// UnmarshalBinary replaces the contents of the map with data produced by
+// MarshalBinary.
+func (m *SliceMap[E, V]) UnmarshalBinary(data []byte) error {
+	return codec.UnmarshalBinaryMap(sliceMapFormat, data, m.Clear, m.Put)
+}
+
+// checked wraps yield so that the iteration panics if the trie is structurally
+// modified while the loop body runs.
+func (m *SliceMap[E, V]) checked(yield func([]E, V) bool) func([]E, V) bool {
+	if !failfast.Enabled {
+		return yield
+	}
+	modCount := m.modCount
+	return func(k []E, v V) bool {
+		if !yield(k, v) {
+			return false
+		}
+		failfast.Check(modCount, m.modCount)
+		return true
 	}
 }
 
-func (m *sliceMap[E, V]) Keys() iter.Seq[[]E] {
+func (m *SliceMap[E, V]) Keys() iter.Seq[[]E] {
 	return func(yield func([]E) bool) {
 		for k := range m.All() {
 			if !yield(k) {
@@ -124,7 +177,7 @@ func (m *sliceMap[E, V]) Keys() iter.Seq[[]E] {
 	}
 }
 
-func (m *sliceMap[E, V]) Values() iter.Seq[V] {
+func (m *SliceMap[E, V]) Values() iter.Seq[V] {
 	return func(yield func(V) bool) {
 		for _, v := range m.All() {
 			if !yield(v) {
@@ -144,7 +197,10 @@ func cloneSlice[E any](s []E) []E {
 }
 
 // iterate performs a DFS. Iteration order is not guaranteed because map iteration is random.
-func (m *sliceMap[E, V]) iterate(node *sliceNode[E, V], prefix []E, yield func([]E, V) bool) bool {
+func (m *SliceMap[E, V]) iterate(node *sliceNode[E, V], prefix []E, yield func([]E, V) bool) bool {
+	if node == nil {
+		return true
+	}
 	if node.hasValue {
 		// Yield a cloned slice so the caller cannot modify our internal state or see changes from subsequent iterations.
 		if !yield(cloneSlice(prefix), node.value) {
@@ -162,8 +218,11 @@ func (m *sliceMap[E, V]) iterate(node *sliceNode[E, V], prefix []E, yield func([
 	return true
 }
 
-func (m *sliceMap[E, V]) getNode(prefix []E) *sliceNode[E, V] {
+func (m *SliceMap[E, V]) getNode(prefix []E) *sliceNode[E, V] {
 	node := m.root
+	if node == nil {
+		return nil
+	}
 	for i := 0; i < len(prefix); i++ {
 		if node.children == nil {
 			return nil
@@ -177,7 +236,7 @@ func (m *sliceMap[E, V]) getNode(prefix []E) *sliceNode[E, V] {
 	return node
 }
 
-func (m *sliceMap[E, V]) HasPrefix(prefix []E) bool {
+func (m *SliceMap[E, V]) HasPrefix(prefix []E) bool {
 	node := m.getNode(prefix)
 	if node == nil {
 		return false
@@ -185,7 +244,7 @@ func (m *sliceMap[E, V]) HasPrefix(prefix []E) bool {
 	return m.hasAnyValue(node)
 }
 
-func (m *sliceMap[E, V]) hasAnyValue(node *sliceNode[E, V]) bool {
+func (m *SliceMap[E, V]) hasAnyValue(node *sliceNode[E, V]) bool {
 	if node.hasValue {
 		return true
 	}
@@ -197,17 +256,17 @@ func (m *sliceMap[E, V]) hasAnyValue(node *sliceNode[E, V]) bool {
 	return false
 }
 
-func (m *sliceMap[E, V]) EntriesWithPrefix(prefix []E) iter.Seq2[[]E, V] {
+func (m *SliceMap[E, V]) EntriesWithPrefix(prefix []E) iter.Seq2[[]E, V] {
 	return func(yield func([]E, V) bool) {
 		node := m.getNode(prefix)
 		if node == nil {
 			return
 		}
-		m.iterate(node, prefix, yield)
+		m.iterate(node, prefix, m.checked(yield))
 	}
 }
 
-func (m *sliceMap[E, V]) KeysWithPrefix(prefix []E) iter.Seq[[]E] {
+func (m *SliceMap[E, V]) KeysWithPrefix(prefix []E) iter.Seq[[]E] {
 	return func(yield func([]E) bool) {
 		for k := range m.EntriesWithPrefix(prefix) {
 			if !yield(k) {
@@ -217,7 +276,7 @@ func (m *sliceMap[E, V]) KeysWithPrefix(prefix []E) iter.Seq[[]E] {
 	}
 }
 
-func (m *sliceMap[E, V]) ValuesWithPrefix(prefix []E) iter.Seq[V] {
+func (m *SliceMap[E, V]) ValuesWithPrefix(prefix []E) iter.Seq[V] {
 	return func(yield func(V) bool) {
 		for _, v := range m.EntriesWithPrefix(prefix) {
 			if !yield(v) {
@@ -227,15 +286,17 @@ func (m *sliceMap[E, V]) ValuesWithPrefix(prefix []E) iter.Seq[V] {
 	}
 }
 
-func (m *sliceMap[E, V]) RemovePrefix(prefix []E) {
+func (m *SliceMap[E, V]) RemovePrefix(prefix []E) {
 	if len(prefix) == 0 {
 		m.Clear()
 		return
 	}
-	m.removePrefixNode(m.root, prefix, 0)
+	if m.root != nil {
+		m.removePrefixNode(m.root, prefix, 0)
+	}
 }
 
-func (m *sliceMap[E, V]) countValues(node *sliceNode[E, V]) int {
+func (m *SliceMap[E, V]) countValues(node *sliceNode[E, V]) int {
 	if node == nil {
 		return 0
 	}
@@ -249,7 +310,7 @@ func (m *sliceMap[E, V]) countValues(node *sliceNode[E, V]) int {
 	return count
 }
 
-func (m *sliceMap[E, V]) removePrefixNode(node *sliceNode[E, V], prefix []E, depth int) bool {
+func (m *SliceMap[E, V]) removePrefixNode(node *sliceNode[E, V], prefix []E, depth int) bool {
 	if depth == len(prefix)-1 {
 		if node.children == nil {
 			return false
@@ -261,6 +322,7 @@ func (m *sliceMap[E, V]) removePrefixNode(node *sliceNode[E, V], prefix []E, dep
 		}
 		removedCount := m.countValues(target)
 		m.size -= removedCount
+		m.modCount++
 		delete(node.children, b)
 		return true
 	}
@@ -281,11 +343,14 @@ func (m *sliceMap[E, V]) removePrefixNode(node *sliceNode[E, V], prefix []E, dep
 	return removed
 }
 
-func (m *sliceMap[E, V]) LongestPrefixOf(query []E) ([]E, V, bool) {
+func (m *SliceMap[E, V]) LongestPrefixOf(query []E) ([]E, V, bool) {
 	node := m.root
 	var longestKey []E
 	var longestVal V
 	var found bool
+	if node == nil {
+		return longestKey, longestVal, found
+	}
 
 	if node.hasValue {
 		longestKey = nil
@@ -311,8 +376,12 @@ func (m *sliceMap[E, V]) LongestPrefixOf(query []E) ([]E, V, bool) {
 	return longestKey, longestVal, found
 }
 
-func (m *sliceMap[E, V]) ShortestPrefixOf(query []E) ([]E, V, bool) {
+func (m *SliceMap[E, V]) ShortestPrefixOf(query []E) ([]E, V, bool) {
 	node := m.root
+	if node == nil {
+		var zero V
+		return nil, zero, false
+	}
 	if node.hasValue {
 		return nil, node.value, true
 	}
@@ -333,9 +402,13 @@ func (m *sliceMap[E, V]) ShortestPrefixOf(query []E) ([]E, V, bool) {
 	return nil, zero, false
 }
 
-func (m *sliceMap[E, V]) PrefixesOf(query []E) iter.Seq2[[]E, V] {
+func (m *SliceMap[E, V]) PrefixesOf(query []E) iter.Seq2[[]E, V] {
 	return func(yield func([]E, V) bool) {
+		yield = m.checked(yield)
 		node := m.root
+		if node == nil {
+			return
+		}
 		if node.hasValue {
 			if !yield(nil, node.value) {
 				return
@@ -363,37 +436,40 @@ func (m *sliceMap[E, V]) PrefixesOf(query []E) iter.Seq2[[]E, V] {
 // SliceSet Implementation
 // -----------------------------------------------------------------------------
 
-var _ Set[[]int] = (*sliceSet[int])(nil)
+var _ Set[[]int] = (*SliceSet[int])(nil)
 
-type sliceSet[E comparable] struct {
-	m *sliceMap[E, struct{}]
+// SliceSet is a Set for slice elements, backed by a SliceMap. Create one with
+// NewSliceSet or SliceSetFromSeq. The zero value for SliceSet is an empty set
+// ready to use.
+type SliceSet[E comparable] struct {
+	m SliceMap[E, struct{}]
 }
 
-func newSliceSet[E comparable]() *sliceSet[E] {
-	return &sliceSet[E]{m: newSliceMap[E, struct{}]()}
+func newSliceSet[E comparable]() *SliceSet[E] {
+	return &SliceSet[E]{}
 }
 
-func (s *sliceSet[E]) Size() int {
+func (s *SliceSet[E]) Size() int {
 	return s.m.Size()
 }
 
-func (s *sliceSet[E]) Empty() bool {
+func (s *SliceSet[E]) Empty() bool {
 	return s.m.Empty()
 }
 
-func (s *sliceSet[E]) Clear() {
+func (s *SliceSet[E]) Clear() {
 	s.m.Clear()
 }
 
-func (s *sliceSet[E]) All() iter.Seq[[]E] {
+func (s *SliceSet[E]) All() iter.Seq[[]E] {
 	return s.m.Keys()
 }
 
-func (s *sliceSet[E]) Add(item []E) {
+func (s *SliceSet[E]) Add(item []E) {
 	s.m.Put(item, struct{}{})
 }
 
-func (s *sliceSet[E]) Remove() []E {
+func (s *SliceSet[E]) Remove() []E {
 	for k := range s.m.Keys() {
 		s.m.Remove(k)
 		return k
@@ -401,24 +477,29 @@ func (s *sliceSet[E]) Remove() []E {
 	panic("cannot remove from an empty set")
 }
 
-func (s *sliceSet[E]) AddAll(sequence iter.Seq[[]E]) {
+func (s *SliceSet[E]) AddAll(sequence iter.Seq[[]E]) {
 	for t := range sequence {
 		s.Add(t)
 	}
 }
 
-func (s *sliceSet[E]) RemoveElement(item []E) {
+func (s *SliceSet[E]) RemoveElement(item []E) {
 	s.m.Remove(item)
 }
 
-func (s *sliceSet[E]) RemoveAll(other collections.Collection[[]E]) {
+func (s *SliceSet[E]) RemoveAll(other collections.Collection[[]E]) {
+	if o, ok := other.(*SliceSet[E]); ok && o == s {
+		// Removing elements while iterating over the same set is a concurrent modification.
+		s.Clear()
+		return
+	}
 	for t := range other.All() {
 		s.RemoveElement(t)
 	}
 }
 
-func (s *sliceSet[E]) RetainAll(other collections.Collection[[]E]) {
-	newMap := newSliceMap[E, struct{}]()
+func (s *SliceSet[E]) RetainAll(other collections.Collection[[]E]) {
+	var newMap SliceMap[E, struct{}]
 	for t := range other.All() {
 		if s.Contains(t) {
 			newMap.Put(t, struct{}{})
@@ -427,11 +508,11 @@ func (s *sliceSet[E]) RetainAll(other collections.Collection[[]E]) {
 	s.m = newMap
 }
 
-func (s *sliceSet[E]) Contains(item []E) bool {
+func (s *SliceSet[E]) Contains(item []E) bool {
 	return s.m.ContainsKey(item)
 }
 
-func (s *sliceSet[E]) ContainsAll(other collections.Collection[[]E]) bool {
+func (s *SliceSet[E]) ContainsAll(other collections.Collection[[]E]) bool {
 	for item := range other.All() {
 		if !s.Contains(item) {
 			return false
@@ -440,29 +521,29 @@ func (s *sliceSet[E]) ContainsAll(other collections.Collection[[]E]) bool {
 	return true
 }
 
-func (s *sliceSet[E]) HasPrefix(prefix []E) bool {
+func (s *SliceSet[E]) HasPrefix(prefix []E) bool {
 	return s.m.HasPrefix(prefix)
 }
 
-func (s *sliceSet[E]) ElementsWithPrefix(prefix []E) iter.Seq[[]E] {
+func (s *SliceSet[E]) ElementsWithPrefix(prefix []E) iter.Seq[[]E] {
 	return s.m.KeysWithPrefix(prefix)
 }
 
-func (s *sliceSet[E]) RemovePrefix(prefix []E) {
+func (s *SliceSet[E]) RemovePrefix(prefix []E) {
 	s.m.RemovePrefix(prefix)
 }
 
-func (s *sliceSet[E]) LongestPrefixOf(query []E) ([]E, bool) {
+func (s *SliceSet[E]) LongestPrefixOf(query []E) ([]E, bool) {
 	k, _, ok := s.m.LongestPrefixOf(query)
 	return k, ok
 }
 
-func (s *sliceSet[E]) ShortestPrefixOf(query []E) ([]E, bool) {
+func (s *SliceSet[E]) ShortestPrefixOf(query []E) ([]E, bool) {
 	k, _, ok := s.m.ShortestPrefixOf(query)
 	return k, ok
 }
 
-func (s *sliceSet[E]) PrefixesOf(query []E) iter.Seq[[]E] {
+func (s *SliceSet[E]) PrefixesOf(query []E) iter.Seq[[]E] {
 	return func(yield func([]E) bool) {
 		for k := range s.m.PrefixesOf(query) {
 			if !yield(k) {
@@ -472,10 +553,35 @@ func (s *sliceSet[E]) PrefixesOf(query []E) iter.Seq[[]E] {
 	}
 }
 
-func (s *sliceSet[E]) String() string {
+func (s *SliceSet[E]) String() string {
 	vals := make([]string, 0, s.Size())
 	for item := range s.All() {
 		vals = append(vals, fmt.Sprintf("%+v", item))
 	}
 	return "[" + strings.Join(vals, ", ") + "]"
 }
+
+// MarshalJSON encodes the set as a JSON array of its elements.
+func (s *SliceSet[E]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(s.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array.
+func (s *SliceSet[E]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalSeq(data, s.Clear, s.Add)
+}
+
+var sliceSetFormat = codec.Format{Name: "trie", Magic: "TRLS", Version: 1}
+
+// MarshalBinary encodes the set in a versioned binary format built on
+// encoding/gob, so E must be encodable by gob.
+func (s *SliceSet[E]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinarySeq(sliceSetFormat, s.All())
+}
+
+// UnmarshalBinary replaces the contents of the set with data produced by
+// MarshalBinary.
+func (s *SliceSet[E]) UnmarshalBinary(data []byte) error {
+	return codec.UnmarshalBinarySeq(sliceSetFormat, data, s.Clear, s.Add)
+}
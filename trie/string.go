@@ -6,9 +6,11 @@ import (
 	"strings"
 
 	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/codec"
+	"github.com/lock14/collections/internal/failfast"
 )
 
-var _ Map[string, int] = (*stringMap[int])(nil)
+var _ Map[string, int] = (*StringMap[int])(nil)
 
 type stringNode[V any] struct {
 	children map[byte]*stringNode[V]
@@ -16,18 +18,22 @@ type stringNode[V any] struct {
 	hasValue bool
 }
 
-type stringMap[V any] struct {
-	root *stringNode[V]
-	size int
+// StringMap is a Map for string keys, which holds one trie node per byte of
+// its keys. Create one with NewMap or MapFromSeq. The zero value for StringMap
+// is an empty map ready to use.
+type StringMap[V any] struct {
+	root     *stringNode[V]
+	size     int
+	modCount int
 }
 
-func newStringMap[V any]() *stringMap[V] {
-	return &stringMap[V]{
+func newStringMap[V any]() *StringMap[V] {
+	return &StringMap[V]{
 		root: &stringNode[V]{},
 	}
 }
 
-func (m *stringMap[V]) Get(key string) (V, bool) {
+func (m *StringMap[V]) Get(key string) (V, bool) {
 	node := m.getNode(key)
 	if node != nil {
 		return node.value, node.hasValue
@@ -36,7 +42,10 @@ func (m *stringMap[V]) Get(key string) (V, bool) {
 	return zero, false
 }
 
-func (m *stringMap[V]) Put(key string, value V) {
+func (m *StringMap[V]) Put(key string, value V) {
+	if m.root == nil {
+		m.root = &stringNode[V]{}
+	}
 	node := m.root
 	for i := 0; i < len(key); i++ {
 		if node.children == nil {
@@ -52,18 +61,20 @@ func (m *stringMap[V]) Put(key string, value V) {
 	}
 	if !node.hasValue {
 		m.size++
+		m.modCount++
 		node.hasValue = true
 	}
 	node.value = value
 }
 
-func (m *stringMap[V]) Remove(key string) {
-	if m.removeNode(m.root, key, 0) {
+func (m *StringMap[V]) Remove(key string) {
+	if m.root != nil && m.removeNode(m.root, key, 0) {
 		m.size--
+		m.modCount++
 	}
 }
 
-func (m *stringMap[V]) removeNode(node *stringNode[V], key string, depth int) bool {
+func (m *StringMap[V]) removeNode(node *stringNode[V], key string, depth int) bool {
 	if depth == len(key) {
 		if !node.hasValue {
 			return false
@@ -90,31 +101,74 @@ func (m *stringMap[V]) removeNode(node *stringNode[V], key string, depth int) bo
 	return removed
 }
 
-func (m *stringMap[V]) Size() int {
+func (m *StringMap[V]) Size() int {
 	return m.size
 }
 
-func (m *stringMap[V]) Empty() bool {
+func (m *StringMap[V]) Empty() bool {
 	return m.size == 0
 }
 
-func (m *stringMap[V]) Clear() {
+func (m *StringMap[V]) Clear() {
 	m.root = &stringNode[V]{}
 	m.size = 0
+	m.modCount++
 }
 
-func (m *stringMap[V]) ContainsKey(key string) bool {
+func (m *StringMap[V]) ContainsKey(key string) bool {
 	node := m.getNode(key)
 	return node != nil && node.hasValue
 }
 
-func (m *stringMap[V]) All() iter.Seq2[string, V] {
+func (m *StringMap[V]) All() iter.Seq2[string, V] {
 	return func(yield func(string, V) bool) {
-		m.iterate(m.root, nil, yield)
+		m.iterate(m.root, nil, m.checked(yield))
 	}
 }
 
-func (m *stringMap[V]) Keys() iter.Seq[string] {
+// MarshalJSON encodes the map as a JSON object with its keys in lexicographic
+// order.
+func (m *StringMap[V]) MarshalJSON() ([]byte, error) {
+	return codec.MarshalMap(m.All())
+}
+
+// UnmarshalJSON replaces the contents of the map with the entries of a JSON
+// object.
+func (m *StringMap[V]) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalMap(data, m.Clear, m.Put)
+}
+
+var stringMapFormat = codec.Format{Name: "trie", Magic: "TRSM", Version: 1}
+
+// MarshalBinary encodes the map in a versioned binary format built on
+// encoding/gob, so V must be encodable by gob.
+func (m *StringMap[V]) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinaryMap(stringMapFormat, m.All())
+}
+
+// UnmarshalBinary replaces the contents of the map with data produced by
+// MarshalBinary.
+func (m *StringMap[V]) UnmarshalBinary(data []byte) error {
+	return codec.UnmarshalBinaryMap(stringMapFormat, data, m.Clear, m.Put)
+}
+
+// checked wraps yield so that the iteration panics if the trie is structurally
+// modified while the loop body runs.
+func (m *StringMap[V]) checked(yield func(string, V) bool) func(string, V) bool {
+	if !failfast.Enabled {
+		return yield
+	}
+	modCount := m.modCount
+	return func(k string, v V) bool {
+		if !yield(k, v) {
+			return false
+		}
+		failfast.Check(modCount, m.modCount)
+		return true
+	}
+}
+
+func (m *StringMap[V]) Keys() iter.Seq[string] {
 	return func(yield func(string) bool) {
 		for k := range m.All() {
 			if !yield(k) {
@@ -124,7 +178,7 @@ func (m *stringMap[V]) Keys() iter.Seq[string] {
 	}
 }
 
-func (m *stringMap[V]) Values() iter.Seq[V] {
+func (m *StringMap[V]) Values() iter.Seq[V] {
 	return func(yield func(V) bool) {
 		for _, v := range m.All() {
 			if !yield(v) {
@@ -135,7 +189,10 @@ func (m *stringMap[V]) Values() iter.Seq[V] {
 }
 
 // iterate performs a DFS and iterates over the nodes in lexicographical order.
-func (m *stringMap[V]) iterate(node *stringNode[V], prefix []byte, yield func(string, V) bool) bool {
+func (m *StringMap[V]) iterate(node *stringNode[V], prefix []byte, yield func(string, V) bool) bool {
+	if node == nil {
+		return true
+	}
 	if node.hasValue {
 		if !yield(string(prefix), node.value) {
 			return false
@@ -154,8 +211,11 @@ func (m *stringMap[V]) iterate(node *stringNode[V], prefix []byte, yield func(st
 	return true
 }
 
-func (m *stringMap[V]) getNode(prefix string) *stringNode[V] {
+func (m *StringMap[V]) getNode(prefix string) *stringNode[V] {
 	node := m.root
+	if node == nil {
+		return nil
+	}
 	for i := 0; i < len(prefix); i++ {
 		if node.children == nil {
 			return nil
@@ -169,7 +229,7 @@ func (m *stringMap[V]) getNode(prefix string) *stringNode[V] {
 	return node
 }
 
-func (m *stringMap[V]) HasPrefix(prefix string) bool {
+func (m *StringMap[V]) HasPrefix(prefix string) bool {
 	node := m.getNode(prefix)
 	if node == nil {
 		return false
@@ -177,7 +237,7 @@ func (m *stringMap[V]) HasPrefix(prefix string) bool {
 	return m.hasAnyValue(node)
 }
 
-func (m *stringMap[V]) hasAnyValue(node *stringNode[V]) bool {
+func (m *StringMap[V]) hasAnyValue(node *stringNode[V]) bool {
 	if node.hasValue {
 		return true
 	}
@@ -189,17 +249,17 @@ func (m *stringMap[V]) hasAnyValue(node *stringNode[V]) bool {
 	return false
 }
 
-func (m *stringMap[V]) EntriesWithPrefix(prefix string) iter.Seq2[string, V] {
+func (m *StringMap[V]) EntriesWithPrefix(prefix string) iter.Seq2[string, V] {
 	return func(yield func(string, V) bool) {
 		node := m.getNode(prefix)
 		if node == nil {
 			return
 		}
-		m.iterate(node, []byte(prefix), yield)
+		m.iterate(node, []byte(prefix), m.checked(yield))
 	}
 }
 
-func (m *stringMap[V]) KeysWithPrefix(prefix string) iter.Seq[string] {
+func (m *StringMap[V]) KeysWithPrefix(prefix string) iter.Seq[string] {
 	return func(yield func(string) bool) {
 		for k := range m.EntriesWithPrefix(prefix) {
 			if !yield(k) {
@@ -209,7 +269,7 @@ func (m *stringMap[V]) KeysWithPrefix(prefix string) iter.Seq[string] {
 	}
 }
 
-func (m *stringMap[V]) ValuesWithPrefix(prefix string) iter.Seq[V] {
+func (m *StringMap[V]) ValuesWithPrefix(prefix string) iter.Seq[V] {
 	return func(yield func(V) bool) {
 		for _, v := range m.EntriesWithPrefix(prefix) {
 			if !yield(v) {
@@ -219,15 +279,17 @@ func (m *stringMap[V]) ValuesWithPrefix(prefix string) iter.Seq[V] {
 	}
 }
 
-func (m *stringMap[V]) RemovePrefix(prefix string) {
+func (m *StringMap[V]) RemovePrefix(prefix string) {
 	if prefix == "" {
 		m.Clear()
 		return
 	}
-	m.removePrefixNode(m.root, prefix, 0)
+	if m.root != nil {
+		m.removePrefixNode(m.root, prefix, 0)
+	}
 }
 
-func (m *stringMap[V]) countValues(node *stringNode[V]) int {
+func (m *StringMap[V]) countValues(node *stringNode[V]) int {
 	if node == nil {
 		return 0
 	}
@@ -241,7 +303,7 @@ func (m *stringMap[V]) countValues(node *stringNode[V]) int {
 	return count
 }
 
-func (m *stringMap[V]) removePrefixNode(node *stringNode[V], prefix string, depth int) bool {
+func (m *StringMap[V]) removePrefixNode(node *stringNode[V], prefix string, depth int) bool {
 	if depth == len(prefix)-1 {
 		if node.children == nil {
 			return false
@@ -253,6 +315,7 @@ func (m *stringMap[V]) removePrefixNode(node *stringNode[V], prefix string, dept
 		}
 		removedCount := m.countValues(target)
 		m.size -= removedCount
+		m.modCount++
 		delete(node.children, b)
 		return true
 	}
@@ -273,11 +336,14 @@ func (m *stringMap[V]) removePrefixNode(node *stringNode[V], prefix string, dept
 	return removed
 }
 
-func (m *stringMap[V]) LongestPrefixOf(query string) (string, V, bool) {
+func (m *StringMap[V]) LongestPrefixOf(query string) (string, V, bool) {
 	node := m.root
 	var longestKey string
 	var longestVal V
 	var found bool
+	if node == nil {
+		return longestKey, longestVal, found
+	}
 
 	if node.hasValue {
 		longestKey = ""
@@ -303,8 +369,12 @@ func (m *stringMap[V]) LongestPrefixOf(query string) (string, V, bool) {
 	return longestKey, longestVal, found
 }
 
-func (m *stringMap[V]) ShortestPrefixOf(query string) (string, V, bool) {
+func (m *StringMap[V]) ShortestPrefixOf(query string) (string, V, bool) {
 	node := m.root
+	if node == nil {
+		var zero V
+		return "", zero, false
+	}
 	if node.hasValue {
 		return "", node.value, true
 	}
@@ -325,9 +395,13 @@ func (m *stringMap[V]) ShortestPrefixOf(query string) (string, V, bool) {
 	return "", zero, false
 }
 
-func (m *stringMap[V]) PrefixesOf(query string) iter.Seq2[string, V] {
+func (m *StringMap[V]) PrefixesOf(query string) iter.Seq2[string, V] {
 	return func(yield func(string, V) bool) {
+		yield = m.checked(yield)
 		node := m.root
+		if node == nil {
+			return
+		}
 		if node.hasValue {
 			if !yield("", node.value) {
 				return
@@ -355,37 +429,40 @@ func (m *stringMap[V]) PrefixesOf(query string) iter.Seq2[string, V] {
 // StringSet Implementation
 // -----------------------------------------------------------------------------
 
-var _ Set[string] = (*stringSet)(nil)
+var _ Set[string] = (*StringSet)(nil)
 
-type stringSet struct {
-	m *stringMap[struct{}]
+// StringSet is a Set for string elements, backed by a StringMap. Create one
+// with NewSet or SetFromSeq. The zero value for StringSet is an empty set
+// ready to use.
+type StringSet struct {
+	m StringMap[struct{}]
 }
 
-func newStringSet() *stringSet {
-	return &stringSet{m: newStringMap[struct{}]()}
+func newStringSet() *StringSet {
+	return &StringSet{}
 }
 
-func (s *stringSet) Size() int {
+func (s *StringSet) Size() int {
 	return s.m.Size()
 }
 
-func (s *stringSet) Empty() bool {
+func (s *StringSet) Empty() bool {
 	return s.m.Empty()
 }
 
-func (s *stringSet) Clear() {
+func (s *StringSet) Clear() {
 	s.m.Clear()
 }
 
-func (s *stringSet) All() iter.Seq[string] {
+func (s *StringSet) All() iter.Seq[string] {
 	return s.m.Keys()
 }
 
-func (s *stringSet) Add(item string) {
+func (s *StringSet) Add(item string) {
 	s.m.Put(item, struct{}{})
 }
 
-func (s *stringSet) Remove() string {
+func (s *StringSet) Remove() string {
 	for k := range s.m.Keys() {
 		s.m.Remove(k)
 		return k
@@ -393,24 +470,29 @@ func (s *stringSet) Remove() string {
 	panic("cannot remove from an empty set")
 }
 
-func (s *stringSet) AddAll(sequence iter.Seq[string]) {
+func (s *StringSet) AddAll(sequence iter.Seq[string]) {
 	for t := range sequence {
 		s.Add(t)
 	}
 }
 
-func (s *stringSet) RemoveElement(item string) {
+func (s *StringSet) RemoveElement(item string) {
 	s.m.Remove(item)
 }
 
-func (s *stringSet) RemoveAll(other collections.Collection[string]) {
+func (s *StringSet) RemoveAll(other collections.Collection[string]) {
+	if o, ok := other.(*StringSet); ok && o == s {
+		// Removing elements while iterating over the same set is a concurrent modification.
+		s.Clear()
+		return
+	}
 	for t := range other.All() {
 		s.RemoveElement(t)
 	}
 }
 
-func (s *stringSet) RetainAll(other collections.Collection[string]) {
-	newMap := newStringMap[struct{}]()
+func (s *StringSet) RetainAll(other collections.Collection[string]) {
+	var newMap StringMap[struct{}]
 	for t := range other.All() {
 		if s.Contains(t) {
 			newMap.Put(t, struct{}{})
@@ -419,11 +501,11 @@ func (s *stringSet) RetainAll(other collections.Collection[string]) {
 	s.m = newMap
 }
 
-func (s *stringSet) Contains(item string) bool {
+func (s *StringSet) Contains(item string) bool {
 	return s.m.ContainsKey(item)
 }
 
-func (s *stringSet) ContainsAll(other collections.Collection[string]) bool {
+func (s *StringSet) ContainsAll(other collections.Collection[string]) bool {
 	for item := range other.All() {
 		if !s.Contains(item) {
 			return false
@@ -432,29 +514,29 @@ func (s *stringSet) ContainsAll(other collections.Collection[string]) bool {
 	return true
 }
 
-func (s *stringSet) HasPrefix(prefix string) bool {
+func (s *StringSet) HasPrefix(prefix string) bool {
 	return s.m.HasPrefix(prefix)
 }
 
-func (s *stringSet) ElementsWithPrefix(prefix string) iter.Seq[string] {
+func (s *StringSet) ElementsWithPrefix(prefix string) iter.Seq[string] {
 	return s.m.KeysWithPrefix(prefix)
 }
 
-func (s *stringSet) RemovePrefix(prefix string) {
+func (s *StringSet) RemovePrefix(prefix string) {
 	s.m.RemovePrefix(prefix)
 }
 
-func (s *stringSet) LongestPrefixOf(query string) (string, bool) {
+func (s *StringSet) LongestPrefixOf(query string) (string, bool) {
 	k, _, ok := s.m.LongestPrefixOf(query)
 	return k, ok
 }
 
-func (s *stringSet) ShortestPrefixOf(query string) (string, bool) {
+func (s *StringSet) ShortestPrefixOf(query string) (string, bool) {
 	k, _, ok := s.m.ShortestPrefixOf(query)
 	return k, ok
 }
 
-func (s *stringSet) PrefixesOf(query string) iter.Seq[string] {
+func (s *StringSet) PrefixesOf(query string) iter.Seq[string] {
 	return func(yield func(string) bool) {
 		for k := range s.m.PrefixesOf(query) {
 			if !yield(k) {
@@ -464,10 +546,36 @@ func (s *stringSet) PrefixesOf(query string) iter.Seq[string] {
 	}
 }
 
-func (s *stringSet) String() string {
+func (s *StringSet) String() string {
 	vals := make([]string, 0, s.Size())
 	for item := range s.All() {
 		vals = append(vals, fmt.Sprintf("%+v", item))
 	}
 	return "[" + strings.Join(vals, ", ") + "]"
 }
+
+// MarshalJSON encodes the set as a JSON array of its elements in lexicographic
+// order.
+func (s *StringSet) MarshalJSON() ([]byte, error) {
+	return codec.MarshalSeq(s.All())
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array.
+func (s *StringSet) UnmarshalJSON(data []byte) error {
+	return codec.UnmarshalSeq(data, s.Clear, s.Add)
+}
+
+var stringSetFormat = codec.Format{Name: "trie", Magic: "TRSS", Version: 1}
+
+// MarshalBinary encodes the set in a versioned binary format built on
+// encoding/gob.
+func (s *StringSet) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinarySeq(stringSetFormat, s.All())
+}
+
+// UnmarshalBinary replaces the contents of the set with data produced by
+// MarshalBinary.
+func (s *StringSet) UnmarshalBinary(data []byte) error {
+	return codec.UnmarshalBinarySeq(stringSetFormat, data, s.Clear, s.Add)
+}
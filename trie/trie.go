@@ -1,15 +1,39 @@
 // Package trie provides Trie backed map and set implementations.
+//
+// Map and Set include encoding.BinaryMarshaler and encoding.BinaryUnmarshaler,
+// and their implementations also implement json.Marshaler and
+// json.Unmarshaler, so they can be encoded with encoding/json and
+// encoding/gob. As encoding/json does, encoding/gob decodes into a struct field
+// of type Map or Set by calling UnmarshalBinary on the map or set the field
+// holds, so the field must be set, for example with NewMap, before decoding.
+// Fields of the concrete types StringMap, StringSet, SliceMap and SliceSet are
+// allocated as needed.
+//
+// Held in a field of another interface type, such as any, a map or set is
+// encoded with its concrete type's name, which must be registered with
+// encoding/gob. This package registers StringSet, and the generic types must be
+// registered for each instantiation used:
+//
+//	gob.Register(trie.NewMap[int]())
 package trie
 
 import (
+	"encoding"
+	"encoding/gob"
 	"iter"
 
 	"github.com/lock14/collections"
 )
 
+func init() {
+	gob.Register(newStringSet())
+}
+
 // Map is a Trie that implements collections.MutableMap and provides prefix operations.
 type Map[K any, V any] interface {
 	collections.MutableMap[K, V]
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
 
 	// HasPrefix returns true if there is at least one key in the map starting with the given prefix.
 	HasPrefix(prefix K) bool
@@ -39,6 +63,8 @@ type Map[K any, V any] interface {
 // Set is a Trie that implements collections.MutableSet and provides prefix operations.
 type Set[K any] interface {
 	collections.MutableSet[K]
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
 
 	// HasPrefix returns true if there is at least one element in the set starting with the given prefix.
 	HasPrefix(prefix K) bool
@@ -78,3 +104,35 @@ func NewSliceMap[E comparable, V any]() Map[[]E, V] {
 func NewSliceSet[E comparable]() Set[[]E] {
 	return newSliceSet[E]()
 }
+
+// MapFromSeq creates a Trie map for string keys containing the key-value pairs of the given sequence.
+func MapFromSeq[V any](sequence iter.Seq2[string, V]) Map[string, V] {
+	m := newStringMap[V]()
+	for k, v := range sequence {
+		m.Put(k, v)
+	}
+	return m
+}
+
+// SetFromSeq creates a Trie set for string elements containing the elements of the given sequence.
+func SetFromSeq(sequence iter.Seq[string]) Set[string] {
+	s := newStringSet()
+	s.AddAll(sequence)
+	return s
+}
+
+// SliceMapFromSeq creates a Trie map for slice keys containing the key-value pairs of the given sequence.
+func SliceMapFromSeq[E comparable, V any](sequence iter.Seq2[[]E, V]) Map[[]E, V] {
+	m := newSliceMap[E, V]()
+	for k, v := range sequence {
+		m.Put(k, v)
+	}
+	return m
+}
+
+// SliceSetFromSeq creates a Trie set for slice elements containing the elements of the given sequence.
+func SliceSetFromSeq[E comparable](sequence iter.Seq[[]E]) Set[[]E] {
+	s := newSliceSet[E]()
+	s.AddAll(sequence)
+	return s
+}
@@ -0,0 +1,19 @@
+package trie_test
+
+import (
+	"testing"
+
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/collectionstest"
+	"github.com/lock14/collections/trie"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	collectionstest.MutableSet(t, func() collections.MutableSet[string] {
+		return trie.NewSet()
+	}, collectionstest.Strings("ab", 4))
+	collectionstest.MutableMap(t, func() collections.MutableMap[string, int] {
+		return trie.NewMap[int]()
+	}, collectionstest.Strings("ab", 4), collectionstest.Ints(10))
+}
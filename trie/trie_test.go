@@ -1,6 +1,14 @@
 package trie
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/lock14/collections"
+	"github.com/lock14/collections/internal/failfast"
+	"iter"
+	"maps"
 	"reflect"
 	"slices"
 	"testing"
@@ -650,3 +658,395 @@ func TestMoreCoverage(t *testing.T) {
 		t.Errorf("Remove empty slice failed")
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	t.Parallel()
+	words := []string{"tea", "ten", "to", "tea"}
+	cases := []struct {
+		name  string
+		check func(*testing.T)
+	}{
+		{
+			name: "map",
+			check: func(t *testing.T) {
+				m := MapFromSeq(func(yield func(string, int) bool) {
+					for i, w := range words {
+						if !yield(w, i) {
+							return
+						}
+					}
+				})
+				if m.Size() != 3 {
+					t.Errorf("expected size 3, got %d", m.Size())
+				}
+				if v, ok := m.Get("tea"); !ok || v != 3 {
+					t.Errorf("expected tea -> 3, got %d", v)
+				}
+			},
+		},
+		{
+			name: "set",
+			check: func(t *testing.T) {
+				s := SetFromSeq(slices.Values(words))
+				if actual := slices.Collect(s.All()); !slices.Equal(actual, []string{"tea", "ten", "to"}) {
+					t.Errorf("unexpected elements %v", actual)
+				}
+			},
+		},
+		{
+			name: "slice_map",
+			check: func(t *testing.T) {
+				m := SliceMapFromSeq(func(yield func([]byte, int) bool) {
+					for i, w := range words {
+						if !yield([]byte(w), i) {
+							return
+						}
+					}
+				})
+				if v, ok := m.Get([]byte("to")); m.Size() != 3 || !ok || v != 2 {
+					t.Errorf("unexpected slice map contents")
+				}
+			},
+		},
+		{
+			name: "slice_set",
+			check: func(t *testing.T) {
+				s := SliceSetFromSeq(func(yield func([]byte) bool) {
+					for _, w := range words {
+						if !yield([]byte(w)) {
+							return
+						}
+					}
+				})
+				if s.Size() != 3 || !s.Contains([]byte("ten")) {
+					t.Errorf("unexpected slice set contents")
+				}
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.check(t)
+		})
+	}
+}
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		modify func(m Map[string, int])
+		panics bool
+	}{
+		{name: "put new key", modify: func(m Map[string, int]) { m.Put("zzz", 0) }, panics: true},
+		{name: "put existing key", modify: func(m Map[string, int]) { m.Put("ab", 0) }, panics: false},
+		{name: "remove", modify: func(m Map[string, int]) { m.Remove("abc") }, panics: true},
+		{name: "remove absent", modify: func(m Map[string, int]) { m.Remove("x") }, panics: false},
+		{name: "remove prefix", modify: func(m Map[string, int]) { m.RemovePrefix("abc") }, panics: true},
+		{name: "clear", modify: func(m Map[string, int]) { m.Clear() }, panics: true},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for _, it := range []struct {
+				name string
+				seq  func(m Map[string, int]) iter.Seq2[string, int]
+			}{
+				{name: "all", seq: func(m Map[string, int]) iter.Seq2[string, int] { return m.All() }},
+				{name: "entries with prefix", seq: func(m Map[string, int]) iter.Seq2[string, int] { return m.EntriesWithPrefix("a") }},
+				{name: "prefixes of", seq: func(m Map[string, int]) iter.Seq2[string, int] { return m.PrefixesOf("abcd") }},
+			} {
+				m := NewMap[int]()
+				for i, k := range []string{"a", "ab", "abc", "abcd"} {
+					m.Put(k, i)
+				}
+				t.Run(it.name, func(t *testing.T) {
+					assertConcurrentModification(t, tc.panics, func() {
+						for range it.seq(m) {
+							tc.modify(m)
+						}
+					})
+				})
+			}
+		})
+	}
+}
+
+func assertConcurrentModification(t *testing.T, want bool, f func()) {
+	t.Helper()
+	if want && !failfast.Enabled {
+		t.Skip("concurrent modification checks are disabled by the nofailfast build tag")
+	}
+	defer func() {
+		r := recover()
+		if want && r != any(collections.ErrConcurrentModification) {
+			t.Errorf("expected panic with ErrConcurrentModification, got %v", r)
+		}
+		if !want && r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+	f()
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	t.Run("string_map", func(t *testing.T) {
+		t.Parallel()
+		m := NewMap[int]()
+		want := `{"a":1,"ab":2,"b":3}`
+		if err := json.Unmarshal([]byte(want), m); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+	})
+	t.Run("string_set", func(t *testing.T) {
+		t.Parallel()
+		s := NewSet()
+		if err := json.Unmarshal([]byte(`["b","a","ab"]`), s); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `["a","ab","b"]`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+	})
+	t.Run("slice_map", func(t *testing.T) {
+		t.Parallel()
+		m := NewSliceMap[int, string]()
+		m.Put([]int{1, 2}, "x")
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if want := `[[[1,2],"x"]]`; string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+		got := NewSliceMap[int, string]()
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if v, ok := got.Get([]int{1, 2}); !ok || v != "x" {
+			t.Errorf("expected [1 2] -> x, got %q, %t", v, ok)
+		}
+	})
+	t.Run("slice_set", func(t *testing.T) {
+		t.Parallel()
+		s := NewSliceSet[int]()
+		if err := json.Unmarshal([]byte(`[[1,2],[3]]`), s); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if !s.Contains([]int{1, 2}) || !s.Contains([]int{3}) || s.Size() != 2 {
+			t.Errorf("expected [[1 2] [3]], got %v", s)
+		}
+	})
+}
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	sm := NewMap[int]()
+	sm.Put("", 0)
+	sm.Put("ab", 2)
+	ss := NewSet()
+	ss.Add("x")
+	lm := NewSliceMap[int, string]()
+	lm.Put([]int{1, 2}, "x")
+	lm.Put(nil, "root")
+	ls := NewSliceSet[byte]()
+	ls.Add([]byte("hi"))
+	cases := []struct {
+		name  string
+		from  any
+		to    any
+		check func(*testing.T, any)
+	}{
+		{name: "string_map", from: sm, to: NewMap[int](), check: func(t *testing.T, got any) {
+			m := got.(Map[string, int])
+			if keys, want := slices.Collect(m.Keys()), []string{"", "ab"}; !slices.Equal(keys, want) {
+				t.Errorf("expected keys %v, got %v", want, keys)
+			}
+			if v, _ := m.Get("ab"); v != 2 {
+				t.Errorf("expected ab -> 2, got %d", v)
+			}
+		}},
+		{name: "string_set", from: ss, to: NewSet(), check: func(t *testing.T, got any) {
+			if s := got.(Set[string]); s.Size() != 1 || !s.Contains("x") {
+				t.Errorf("expected [x], got %v", s)
+			}
+		}},
+		{name: "slice_map", from: lm, to: NewSliceMap[int, string](), check: func(t *testing.T, got any) {
+			m := got.(Map[[]int, string])
+			if v, ok := m.Get(nil); m.Size() != 2 || !ok || v != "root" {
+				t.Errorf("expected [] -> root in a map of size 2, got %q, %t, %d", v, ok, m.Size())
+			}
+		}},
+		{name: "slice_set", from: ls, to: NewSliceSet[byte](), check: func(t *testing.T, got any) {
+			if s := got.(Set[[]byte]); s.Size() != 1 || !s.Contains([]byte("hi")) {
+				t.Errorf("expected [hi], got %v", s)
+			}
+		}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := tc.from.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			if err := tc.to.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			tc.check(t, tc.to)
+		})
+	}
+	if err := NewSet().UnmarshalBinary(mustMarshal(t, sm)); err == nil {
+		t.Errorf("expected an error decoding a map encoding into a set")
+	}
+}
+
+func mustMarshal(t *testing.T, m encoding.BinaryMarshaler) []byte {
+	t.Helper()
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	return data
+}
+
+func TestGobStructFields(t *testing.T) {
+	t.Parallel()
+	// fields of type any need the concrete generic types registered
+	gob.Register(NewMap[int]())
+	type document struct {
+		Words  *StringMap[int]
+		Tags   *StringSet
+		Paths  *SliceSet[string]
+		Counts Map[string, int]
+		Seen   Set[string]
+		Extra  any
+		Labels any
+	}
+	words := NewMap[int]()
+	words.Put("go", 1)
+	tags := NewSet()
+	tags.Add("tag")
+	paths := NewSliceSet[string]()
+	paths.Add([]string{"a", "b"})
+	in := document{
+		Words:  words.(*StringMap[int]),
+		Tags:   tags.(*StringSet),
+		Paths:  paths.(*SliceSet[string]),
+		Counts: MapFromSeq(maps.All(map[string]int{"x": 2})),
+		Seen:   SetFromSeq(slices.Values([]string{"s"})),
+		Extra:  MapFromSeq(maps.All(map[string]int{"y": 3})),
+		Labels: SetFromSeq(slices.Values([]string{"l"})),
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	// fields of type Map and Set decode into the maps and sets they hold
+	out := document{Counts: NewMap[int](), Seen: NewSet()}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if v, ok := out.Words.Get("go"); !ok || v != 1 {
+		t.Errorf("Words: expected go -> 1, got %d, %t", v, ok)
+	}
+	if !out.Tags.Contains("tag") || !out.Paths.Contains([]string{"a", "b"}) {
+		t.Errorf("expected Tags [tag] and Paths [[a b]], got %v and %v", out.Tags, out.Paths)
+	}
+	if v, ok := out.Counts.Get("x"); !ok || v != 2 {
+		t.Errorf("Counts: expected x -> 2, got %d, %t", v, ok)
+	}
+	if out.Seen.Size() != 1 || !out.Seen.Contains("s") {
+		t.Errorf("Seen: expected [s], got %v", out.Seen)
+	}
+	if m, ok := out.Extra.(Map[string, int]); !ok || m.Size() != 1 {
+		t.Errorf("Extra: expected a map of y -> 3, got %v", out.Extra)
+	}
+	if s, ok := out.Labels.(Set[string]); !ok || !s.Contains("l") {
+		t.Errorf("Labels: expected a set of l, got %v", out.Labels)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	t.Parallel()
+	var sm StringMap[int]
+	if sm.ContainsKey("a") || sm.HasPrefix("") || !sm.Empty() {
+		t.Errorf("expected an empty StringMap, got %v", sm.Size())
+	}
+	if _, _, ok := sm.LongestPrefixOf("abc"); ok {
+		t.Errorf("StringMap.LongestPrefixOf: expected false")
+	}
+	if _, _, ok := sm.ShortestPrefixOf("abc"); ok {
+		t.Errorf("StringMap.ShortestPrefixOf: expected false")
+	}
+	for k := range sm.PrefixesOf("abc") {
+		t.Errorf("StringMap.PrefixesOf: unexpected key %q", k)
+	}
+	for k := range sm.All() {
+		t.Errorf("StringMap.All: unexpected key %q", k)
+	}
+	sm.Remove("a")
+	sm.RemovePrefix("a")
+	sm.Put("ab", 1)
+	if v, ok := sm.Get("ab"); !ok || v != 1 || sm.Size() != 1 {
+		t.Errorf("StringMap: expected ab -> 1, got %d, %t", v, ok)
+	}
+
+	var slm SliceMap[int, int]
+	if slm.ContainsKey([]int{1}) || slm.HasPrefix(nil) || !slm.Empty() {
+		t.Errorf("expected an empty SliceMap, got %v", slm.Size())
+	}
+	if _, _, ok := slm.LongestPrefixOf([]int{1, 2}); ok {
+		t.Errorf("SliceMap.LongestPrefixOf: expected false")
+	}
+	if _, _, ok := slm.ShortestPrefixOf([]int{1, 2}); ok {
+		t.Errorf("SliceMap.ShortestPrefixOf: expected false")
+	}
+	for k := range slm.PrefixesOf([]int{1, 2}) {
+		t.Errorf("SliceMap.PrefixesOf: unexpected key %v", k)
+	}
+	for k := range slm.All() {
+		t.Errorf("SliceMap.All: unexpected key %v", k)
+	}
+	slm.Remove([]int{1})
+	slm.RemovePrefix([]int{1})
+	slm.Put([]int{1, 2}, 3)
+	if v, ok := slm.Get([]int{1, 2}); !ok || v != 3 || slm.Size() != 1 {
+		t.Errorf("SliceMap: expected [1 2] -> 3, got %d, %t", v, ok)
+	}
+
+	var ss StringSet
+	if ss.Contains("a") || !ss.Empty() {
+		t.Errorf("expected an empty StringSet, got %v", &ss)
+	}
+	ss.Add("a")
+	ss.RetainAll(SetFromSeq(slices.Values([]string{"a", "b"})))
+	if !ss.Contains("a") || ss.Size() != 1 {
+		t.Errorf("StringSet: expected [a], got %v", &ss)
+	}
+
+	var sls SliceSet[string]
+	if sls.Contains([]string{"a"}) || !sls.Empty() {
+		t.Errorf("expected an empty SliceSet, got %v", &sls)
+	}
+	sls.Add([]string{"a"})
+	if !sls.Contains([]string{"a"}) || sls.Size() != 1 {
+		t.Errorf("SliceSet: expected [[a]], got %v", &sls)
+	}
+}